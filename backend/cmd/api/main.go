@@ -42,6 +42,11 @@ var (
 // @version        1.0
 // @description    Product catalog API for store77.net marketplace
 // @BasePath       /api/v1
+
+// @securityDefinitions.apikey  AdminToken
+// @in                          header
+// @name                        Authorization
+// @description                 Admin API token as "Bearer <token>"
 func main() {
 	fx.New(
 		fx.Provide(
@@ -53,12 +58,33 @@ func main() {
 			ProvideHTTPServer,
 			postgres.NewCategoryRepo,
 			postgres.NewProductRepo,
+			postgres.NewPricingRuleRepo,
+			postgres.NewSearchRepo,
+			postgres.NewScrapeRunRepo,
+			postgres.NewCategoryScheduleRepo,
+			postgres.NewScrapeRequestRepo,
+			postgres.NewProductGroupRepo,
+			postgres.NewProductMatchRepo,
 			service.NewCategoryService,
 			service.NewProductService,
+			service.NewPricingRuleService,
+			service.NewSearchService,
+			service.NewScrapeRunService,
+			service.NewCategoryScheduleService,
+			service.NewScrapeRequestService,
+			service.NewProductGroupService,
+			service.NewProductMatchService,
 			exchange.NewGrinexProvider,
 			handler.NewCategoryHandler,
 			handler.NewProductHandler,
 			handler.NewExchangeHandler,
+			handler.NewPricingRuleHandler,
+			handler.NewSearchHandler,
+			handler.NewScrapeRunHandler,
+			handler.NewCategoryScheduleHandler,
+			handler.NewScrapeRequestHandler,
+			handler.NewProductGroupHandler,
+			handler.NewProductMatchHandler,
 		),
 		fx.Invoke(SetupRoutes),
 		fx.Invoke(StartServer),
@@ -160,8 +186,8 @@ func ProvideHTTPServer(cfg *config.Config, router *gin.Engine) *http.Server {
 func corsMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Methods", "GET, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept")
+		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization")
 		c.Header("Access-Control-Max-Age", "43200")
 
 		if c.Request.Method == "OPTIONS" {
@@ -175,23 +201,67 @@ func corsMiddleware() gin.HandlerFunc {
 
 func SetupRoutes(
 	router *gin.Engine,
+	cfg *config.Config,
 	lg *zap.Logger,
 	ch *handler.CategoryHandler,
 	ph *handler.ProductHandler,
 	eh *handler.ExchangeHandler,
+	prh *handler.PricingRuleHandler,
+	sh *handler.SearchHandler,
+	srh *handler.ScrapeRunHandler,
+	csh *handler.CategoryScheduleHandler,
+	sqh *handler.ScrapeRequestHandler,
+	pgh *handler.ProductGroupHandler,
+	pmh *handler.ProductMatchHandler,
 ) {
 	apiV1 := router.Group("/api/v1")
 
 	apiV1.GET("/categories", ch.List)
+	apiV1.GET("/categories/tree", ch.Tree)
 	apiV1.GET("/categories/:id", ch.GetByID)
 
 	apiV1.GET("/products", ph.List)
+	apiV1.GET("/products/facets", ph.Facets)
 	apiV1.GET("/products/:id", ph.GetByID)
+	apiV1.GET("/products/:id/price-history", ph.GetPriceHistory)
+	apiV1.GET("/products/:id/offers", pmh.Offers)
+
+	apiV1.GET("/product-groups/:id", pgh.GetByID)
 
 	apiV1.GET("/brands", ph.GetBrands)
 
+	apiV1.GET("/search/suggest", sh.Suggest)
+
 	apiV1.GET("/exchange/rate", eh.GetRate)
 
+	if cfg.AdminToken == "" {
+		lg.Warn("ADMIN_API_TOKEN is not set, admin API is disabled")
+	}
+
+	admin := apiV1.Group("/admin", handler.AdminAuth(cfg.AdminToken))
+
+	admin.GET("/pricing-rules", prh.List)
+	admin.POST("/pricing-rules", prh.Create)
+	admin.POST("/pricing-rules/dry-run", prh.DryRun)
+	admin.GET("/pricing-rules/:id", prh.GetByID)
+	admin.PUT("/pricing-rules/:id", prh.Update)
+	admin.DELETE("/pricing-rules/:id", prh.Delete)
+
+	admin.GET("/scrape-runs", srh.List)
+	admin.GET("/scrape-runs/:id", srh.GetByID)
+
+	admin.GET("/category-schedules", csh.List)
+	admin.PUT("/category-schedules/:category_id", csh.Set)
+	admin.DELETE("/category-schedules/:category_id", csh.Delete)
+
+	admin.POST("/scrapes", sqh.Create)
+	admin.GET("/scrapes/:id", sqh.GetByID)
+	admin.DELETE("/scrapes/:id", sqh.Cancel)
+
+	admin.GET("/product-matches", pmh.List)
+	admin.POST("/product-matches/:id/confirm", pmh.Confirm)
+	admin.POST("/product-matches/:id/reject", pmh.Reject)
+
 	lg.Info("routes registered")
 }
 
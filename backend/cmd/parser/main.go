@@ -1,38 +1,62 @@
-// Parser is a periodic scraper that fetches product data from store77.net
-// and upserts it into the database.
+// Parser is a periodic scraper that fetches product data from the shops
+// enabled in SCRAPE_SOURCES (store77.net by default) and upserts it into the
+// database. Each shop is a source.Source registered in sourceFactories.
+//
+// Every minute the parser checks which categories are due: a category follows
+// its cron schedule from category_schedules, or SCRAPE_INTERVAL when it has
+// none. Due categories are scraped together as one run; runs never overlap,
+// and none is started during SCRAPE_QUIET_HOURS.
+//
+// With SCRAPE_QUEUE_ENABLED several parser instances share the work: the
+// instance holding the leader lock schedules each cycle by publishing one job
+// per category to a Redis queue, and every instance leases and scrapes jobs.
+// A category job publishes a further job for each page after the first.
+//
+// Runs requested through the admin API (scrape_requests) are picked up
+// between scheduled runs. Cancelling a request stops its run through the
+// run's context; in queue mode the cancellation reaches the other instances
+// through a Redis flag on the run.
+//
+// After every run that was not interrupted, products are regrouped into
+// color and storage variants of the same model (product_groups), and
+// products of different shops that look like the same device are suggested
+// as matches (product_matches) for price comparison.
 //
 // Possible improvements:
-//   - Rotate requests through a proxy pool (e.g. SOCKS5 / HTTP proxies) to avoid
-//     IP-based rate limiting and reduce the risk of being blocked.
-//   - Scale horizontally by splitting category ranges across multiple parser
-//     instances (e.g. via Redis-based task queue or message broker).
-//   - Use a shared rate limiter (e.g. golang.org/x/time/rate) instead of a fixed
-//     sleep between requests for more precise throughput control.
-//   - Cache fetched product descriptions in Redis to skip re-fetching on
-//     subsequent scrape cycles when the product URL hasn't changed.
-//   - Add retry logic with exponential backoff for transient HTTP errors
-//     instead of skipping failed pages entirely.
 //   - Emit Prometheus metrics (scrape duration, success/failure counts,
 //     products upserted) for monitoring and alerting.
 package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"os"
 	"os/signal"
 	"sync"
 	"syscall"
 	"time"
+	_ "time/tzdata"
 
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 
+	"github.com/burbble/marketplace/internal/attribute"
 	"github.com/burbble/marketplace/internal/config"
 	"github.com/burbble/marketplace/internal/domain"
+	"github.com/burbble/marketplace/internal/grouping"
+	"github.com/burbble/marketplace/internal/matching"
+	"github.com/burbble/marketplace/internal/pricing"
+	"github.com/burbble/marketplace/internal/repository/cache"
 	"github.com/burbble/marketplace/internal/repository/postgres"
-	"github.com/burbble/marketplace/internal/scraper/store77"
+	"github.com/burbble/marketplace/internal/scraper/proxy"
+	"github.com/burbble/marketplace/internal/scraper/queue"
+	"github.com/burbble/marketplace/internal/scraper/schedule"
+	"github.com/burbble/marketplace/internal/scraper/snapshot"
+	"github.com/burbble/marketplace/internal/scraper/source"
+	"github.com/burbble/marketplace/internal/scraper/throttle"
 	"github.com/burbble/marketplace/pkg/db"
 	"github.com/burbble/marketplace/pkg/zapx"
 )
@@ -53,11 +77,42 @@ type application struct {
 	cfg          *config.Config
 	conn         *db.Connection
 	rdb          *redis.Client
-	scraper      *store77.Scraper
+	sources      []source.Source
+	proxies      *proxy.Pool
 	categoryRepo postgres.CategoryRepository
 	productRepo  postgres.ProductRepository
+	pricingRepo  postgres.PricingRuleRepository
+	runRepo      postgres.ScrapeRunRepository
+	scheduleRepo postgres.CategoryScheduleRepository
+	requestRepo  postgres.ScrapeRequestRepository
+	groupRepo    postgres.ProductGroupRepository
+	matchRepo    postgres.ProductMatchRepository
+	descCache    cache.DescriptionCache
+	quiet        schedule.QuietHours
+	loc          *time.Location
+	// replay is set when the parser re-runs a recorded snapshot instead of
+	// fetching the shops.
+	replay bool
+	// fullRefresh makes the next run refetch every product page.
+	fullRefresh bool
+	// menuLeaves holds the leaf categories of each source's menu as last
+	// parsed. Only the scheduling loop touches it.
+	menuLeaves map[string][]uuid.UUID
+	// planned holds the categories discovered while planning the current
+	// run, so that the run does not parse the menus again.
+	planned []categoryTask
+	// jobs and leader are set in queue mode.
+	jobs   *queue.Queue
+	leader *queue.Lock
 }
 
+// errMenuDrift means the catalog menu no longer matches the parser.
+var errMenuDrift = errors.New("markup drift: no catalog menu entries matched")
+
+// scheduleTick is how often the parser checks for due categories; cron
+// schedules have minute resolution.
+const scheduleTick = time.Minute
+
 func main() {
 	os.Exit(run())
 }
@@ -66,6 +121,9 @@ func run() exitCode {
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
 	defer cancel()
 
+	fullRefresh := flag.Bool("full-refresh", false, "refetch every product page on the first run, ignoring cached descriptions")
+	reindex := flag.Bool("reindex-attributes", false, "rebuild the attribute filter index of stored products and exit")
+
 	cfg := &config.Config{}
 	if err := config.LoadFromFlags(cfg); err != nil {
 		fmt.Printf("failed to load config: %v\n", err)
@@ -99,28 +157,207 @@ func run() exitCode {
 
 	lg.Info("redis connected", zap.String("addr", cfg.Addr()))
 
+	proxies, err := newProxyPool(cfg)
+	if err != nil {
+		lg.Error("failed to load proxies", zap.Error(err))
+		return errLoadConfig
+	}
+	if proxies != nil {
+		lg.Info("proxy pool loaded", zap.Int("count", proxies.Len()), zap.String("strategy", cfg.ProxyStrategy))
+	}
+
+	hostRates, err := throttle.ParseHostRates(cfg.HostRateLimits)
+	if err != nil {
+		lg.Error("invalid host rate limits", zap.Error(err))
+		return errLoadConfig
+	}
+
+	deps := sourceDeps{
+		logger:  lg,
+		cfg:     cfg,
+		proxies: proxies,
+		limiter: throttle.New(throttle.Config{
+			RPS:     cfg.ScrapeRPS,
+			Burst:   cfg.ScrapeBurst,
+			HostRPS: hostRates,
+		}),
+	}
+
+	if cfg.ReplayDir != "" {
+		replayer, dir, err := openReplay(cfg.ReplayDir)
+		if err != nil {
+			lg.Error("failed to open snapshot", zap.Error(err))
+			return errLoadConfig
+		}
+		lg.Info("replaying snapshot", zap.String("dir", dir), zap.Int("urls", replayer.Len()))
+
+		deps.replay = replayer
+	}
+
+	quiet, err := schedule.ParseQuietHours(cfg.QuietHours)
+	if err != nil {
+		lg.Error("invalid quiet hours", zap.Error(err))
+		return errLoadConfig
+	}
+
+	loc, err := time.LoadLocation(cfg.ScheduleTimezone)
+	if err != nil {
+		lg.Error("invalid schedule timezone", zap.Error(err))
+		return errLoadConfig
+	}
+
+	sources, err := newSources(cfg.Sources, deps)
+	if err != nil {
+		lg.Error("invalid scrape sources", zap.Error(err))
+		return errLoadConfig
+	}
+
 	app := &application{
 		logger:       lg,
 		cfg:          cfg,
 		conn:         conn,
 		rdb:          rdb,
-		scraper:      store77.NewScraper(lg),
+		sources:      sources,
+		proxies:      proxies,
 		categoryRepo: postgres.NewCategoryRepo(conn),
 		productRepo:  postgres.NewProductRepo(conn),
+		pricingRepo:  postgres.NewPricingRuleRepo(conn),
+		runRepo:      postgres.NewScrapeRunRepo(conn),
+		scheduleRepo: postgres.NewCategoryScheduleRepo(conn),
+		requestRepo:  postgres.NewScrapeRequestRepo(conn),
+		groupRepo:    postgres.NewProductGroupRepo(conn),
+		matchRepo:    postgres.NewProductMatchRepo(conn),
+		descCache:    cache.NewDescriptionCache(rdb, cfg.DescriptionTTL),
+		quiet:        quiet,
+		loc:          loc,
+		fullRefresh:  *fullRefresh,
+		replay:       cfg.ReplayDir != "",
+	}
+
+	// A replay parses every product page of the snapshot and must not leave
+	// descriptions of recorded pages in the live cache.
+	if app.replay {
+		app.descCache = cache.NewNopDescriptionCache()
+		app.fullRefresh = true
+	}
+
+	if cfg.QueueEnabled && !app.replay {
+		app.jobs = queue.New(rdb, queue.Config{
+			Name:        jobQueueName,
+			Visibility:  cfg.QueueVisibility,
+			MaxAttempts: cfg.QueueMaxAttempts,
+		})
+		app.leader = queue.NewLock(rdb, leaderLockKey)
+
+		if cfg.SnapshotDir != "" {
+			lg.Warn("snapshots are not recorded in queue mode")
+		}
+	}
+
+	if *reindex {
+		return app.reindexAttributes(ctx)
 	}
 
 	return app.runScraper(ctx)
 }
 
+// reindexAttributes rebuilds the attribute index of the stored products with
+// the current normalizer, for products whose pages are not fetched again
+// soon after the normalization rules change.
+func (a *application) reindexAttributes(ctx context.Context) exitCode {
+	a.logger.Info("reindexing product attributes")
+
+	n, err := a.productRepo.ReindexAttributes(ctx, attribute.Index)
+	if err != nil {
+		a.logger.Error("failed to reindex product attributes", zap.Int("products", n), zap.Error(err))
+		return errScrape
+	}
+
+	a.logger.Info("product attributes reindexed", zap.Int("products", n))
+	return noErr
+}
+
+// newProxyPool combines SCRAPE_PROXIES and SCRAPE_PROXY_FILE. It returns nil
+// when neither lists a proxy, in which case the scraper connects directly.
+func newProxyPool(cfg *config.Config) (*proxy.Pool, error) {
+	if !proxy.IsValidStrategy(cfg.ProxyStrategy) {
+		return nil, fmt.Errorf("unknown proxy strategy %q", cfg.ProxyStrategy)
+	}
+
+	urls, err := proxy.ParseList(cfg.Proxies)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.ProxyFile != "" {
+		fromFile, err := proxy.LoadFile(cfg.ProxyFile)
+		if err != nil {
+			return nil, err
+		}
+		urls = append(urls, fromFile...)
+	}
+
+	if len(urls) == 0 {
+		return nil, nil
+	}
+
+	return proxy.NewPool(urls, proxy.Config{
+		Strategy:      cfg.ProxyStrategy,
+		MaxFailures:   cfg.ProxyMaxFailures,
+		BenchDuration: cfg.ProxyBenchDuration,
+	}), nil
+}
+
+// openReplay opens the snapshot in dir, or the most recent snapshot if dir is
+// a directory of snapshots.
+func openReplay(dir string) (*snapshot.Replayer, string, error) {
+	replayer, err := snapshot.OpenReplay(dir)
+	if err == nil {
+		return replayer, dir, nil
+	}
+
+	latest, lerr := snapshot.Latest(dir)
+	if lerr != nil {
+		return nil, "", err
+	}
+
+	replayer, err = snapshot.OpenReplay(latest)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return replayer, latest, nil
+}
+
 func (a *application) runScraper(ctx context.Context) exitCode {
-	a.logger.Info("starting scraper", zap.Duration("interval", a.cfg.ScrapeInterval))
+	if a.replay {
+		if err := a.scrape(ctx, nil); err != nil {
+			a.logger.Error("replay failed", zap.Error(err))
+			return errScrape
+		}
+		return noErr
+	}
+
+	if a.jobs != nil {
+		return a.runQueue(ctx)
+	}
 
-	ticker := time.NewTicker(a.cfg.ScrapeInterval)
+	a.logger.Info("starting scraper",
+		zap.Duration("interval", a.cfg.ScrapeInterval),
+		zap.String("quiet_hours", a.cfg.QuietHours),
+		zap.String("timezone", a.loc.String()),
+	)
+
+	// A run blocks the loop, so ticks that fire meanwhile are dropped rather
+	// than starting an overlapping run. Scrape requests wait for the current
+	// run in the same way.
+	ticker := time.NewTicker(scheduleTick)
 	defer ticker.Stop()
 
-	if err := a.scrape(ctx); err != nil {
-		a.logger.Error("scrape failed", zap.Error(err))
-	}
+	requests := time.NewTicker(commandPollInterval)
+	defer requests.Stop()
+
+	a.scrapeDue(ctx)
 
 	for {
 		select {
@@ -128,77 +365,397 @@ func (a *application) runScraper(ctx context.Context) exitCode {
 			a.logger.Info("scraper stopped")
 			return noErr
 		case <-ticker.C:
-			if err := a.scrape(ctx); err != nil {
-				a.logger.Error("scrape failed", zap.Error(err))
+			a.scrapeDue(ctx)
+		case <-requests.C:
+			a.runRequests(ctx)
+		}
+	}
+}
+
+// scrapeDue starts a run for the categories that are due, if any.
+func (a *application) scrapeDue(ctx context.Context) {
+	defer func() { a.planned = nil }()
+
+	now := time.Now()
+
+	due, err := a.dueCategories(ctx, now)
+	if err != nil {
+		a.logger.Error("failed to plan scrape", zap.Error(err))
+		return
+	}
+	if due == nil {
+		return
+	}
+
+	if err := a.scrape(ctx, due); err != nil {
+		a.logger.Error("scrape failed", zap.Error(err))
+	}
+}
+
+// dueCategories returns a filter matching the categories due at now, or nil
+// when nothing is due or now falls into the quiet hours. Only categories
+// listed in the sources' menus count, so a category a shop removed never
+// keeps the parser busy; the menus are parsed here until this instance has
+// seen them, and the run then scrapes the categories found.
+func (a *application) dueCategories(ctx context.Context, now time.Time) (func(uuid.UUID) bool, error) {
+	schedules, err := a.scheduleRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("load category schedules: %w", err)
+	}
+
+	planner, invalid := schedule.NewPlanner(a.cfg.ScrapeInterval, schedules, a.quiet, a.loc)
+	for _, s := range invalid {
+		a.logger.Warn("invalid category schedule, using the default interval",
+			zap.String("category_id", s.CategoryID.String()),
+			zap.String("cron", s.Cron),
+		)
+	}
+
+	if planner.Quiet(now) {
+		a.logger.Debug("quiet hours, not scraping")
+		return nil, nil
+	}
+
+	var menuErr error
+	if len(a.menuLeaves) == 0 {
+		if a.jobs == nil {
+			defer a.stopSources()
+		}
+		a.planned, menuErr = a.discoverCategories(ctx, nil)
+	}
+
+	last, err := a.runRepo.LastScraped(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	due := func(id uuid.UUID) bool {
+		return planner.Due(id, last[id], now)
+	}
+
+	if menuErr != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		// A run records the failure, with the markup drift alert if the menu
+		// no longer parses.
+		a.logger.Warn("failed to parse the catalog menus", zap.Error(menuErr))
+		return due, nil
+	}
+
+	for _, leaves := range a.menuLeaves {
+		for _, id := range leaves {
+			if due(id) {
+				return due, nil
 			}
 		}
 	}
+
+	return nil, nil
 }
 
-func (a *application) scrape(ctx context.Context) error {
-	a.logger.Info("scraping started")
+// scrape performs one run over the categories matched by due, or over every
+// category when due is nil.
+func (a *application) scrape(ctx context.Context, due func(uuid.UUID) bool) error {
+	ctx = a.takeFullRefresh(ctx)
 
-	if err := a.scraper.Start(); err != nil {
-		return fmt.Errorf("start browser: %w", err)
+	run, err := a.startRun(ctx)
+	if err != nil {
+		return err
 	}
-	defer a.scraper.Stop()
 
-	mainHTML, err := a.scraper.FetchMainPage(ctx)
+	return a.executeRun(ctx, run, due)
+}
+
+// takeFullRefresh applies the -full-refresh flag to the first run it is
+// asked for; a run already marked as a full refresh stays one.
+func (a *application) takeFullRefresh(ctx context.Context) context.Context {
+	full := a.fullRefresh || isFullRefresh(ctx)
+	a.fullRefresh = false
+	return withFullRefresh(ctx, full)
+}
+
+// errRunAbandoned is recorded on runs left running by a parser that stopped
+// without finishing them.
+var errRunAbandoned = errors.New("abandoned: the parser stopped before the run finished")
+
+// startRun records a new run. Runs never overlap, so any run still recorded
+// as running is abandoned and is marked failed first.
+func (a *application) startRun(ctx context.Context) (*domain.ScrapeRun, error) {
+	a.logger.Info("scraping started", zap.Bool("full_refresh", isFullRefresh(ctx)))
+
+	abandoned, err := a.runRepo.FailAbandoned(ctx, errRunAbandoned.Error())
 	if err != nil {
-		return fmt.Errorf("fetch main page: %w", err)
+		a.logger.Warn("failed to close abandoned scrape runs", zap.Error(err))
+	} else if abandoned > 0 {
+		a.logger.Warn("abandoned scrape runs marked failed", zap.Int64("count", abandoned))
+	}
+
+	run := &domain.ScrapeRun{Status: domain.ScrapeStatusRunning, StartedAt: time.Now()}
+	if err := a.runRepo.Create(ctx, run); err != nil {
+		return nil, fmt.Errorf("create scrape run: %w", err)
+	}
+
+	return run, nil
+}
+
+// executeRun scrapes the categories of a started run and records the outcome
+// in scrape_runs. Bookkeeping writes use a non-cancellable context so an
+// interrupted run is still recorded; a run cancelled with a cause records
+// the cause as its error.
+func (a *application) executeRun(ctx context.Context, run *domain.ScrapeRun, due func(uuid.UUID) bool) error {
+	if a.recordsSnapshots() {
+		rec, err := snapshot.NewRecorder(a.cfg.SnapshotDir, run.ID.String(), run.StartedAt)
+		if err != nil {
+			a.logger.Error("failed to start snapshot", zap.Error(err))
+		} else {
+			a.logger.Info("recording snapshot", zap.String("dir", rec.Dir()))
+			a.setRecorder(rec)
+			defer func() {
+				a.setRecorder(nil)
+				_ = rec.Close()
+			}()
+		}
 	}
 
-	parsedCategories, err := store77.ParseCategories(mainHTML)
+	var categories []domain.ScrapeRunCategory
+	var err error
+	if a.jobs != nil {
+		categories, err = a.distributeCategories(ctx, run, due)
+	} else {
+		categories, err = a.scrapeCategories(ctx, run, due)
+	}
+	if errors.Is(err, context.Canceled) && context.Cause(ctx) != nil {
+		err = context.Cause(ctx)
+	}
 	if err != nil {
-		return fmt.Errorf("parse categories: %w", err)
+		msg := err.Error()
+		run.Status = domain.ScrapeStatusFailed
+		run.Error = &msg
+	}
+	if errors.Is(err, errMenuDrift) {
+		run.MarkupDrift = true
+	}
+
+	switch {
+	case run.MarkupDrift:
+		a.logger.Error("ALERT: markup drift detected, products were not marked unavailable",
+			zap.String("run_id", run.ID.String()),
+			zap.Int("drift_categories", run.DriftCategories),
+			zap.Bool("alert", true),
+		)
+	case err == nil:
+		a.markUnavailable(ctx, categories)
+	}
+	if ctx.Err() == nil {
+		a.markDisabledSources(ctx, run.StartedAt)
 	}
 
-	a.logger.Info("categories parsed", zap.Int("count", len(parsedCategories)))
+	run.Finish(time.Now())
 
-	seen := make(map[string]struct{}, len(parsedCategories))
-	domainCategories := make([]domain.Category, 0, len(parsedCategories))
-	for _, c := range parsedCategories {
-		if _, exists := seen[c.Slug]; exists {
+	a.recordFailedPages(ctx, run.ID)
+
+	// Grouping and matching recompute the whole catalog, which only a run
+	// that wrote products can change. Matching pairs products of different
+	// sources, so it needs at least two of them enabled.
+	if ctx.Err() == nil && run.ProductsUpserted > 0 {
+		a.groupProducts(ctx)
+		if len(a.sources) > 1 {
+			a.matchProducts(ctx)
+		}
+	}
+
+	if ferr := a.runRepo.Finish(context.WithoutCancel(ctx), run); ferr != nil {
+		a.logger.Error("failed to record scrape run", zap.String("run_id", run.ID.String()), zap.Error(ferr))
+	}
+
+	a.logger.Info("scraping completed",
+		zap.String("run_id", run.ID.String()),
+		zap.String("status", run.Status),
+		zap.Int("categories", run.CategoriesTotal),
+		zap.Int("categories_failed", run.CategoriesFailed),
+		zap.Int("products_upserted", run.ProductsUpserted),
+	)
+
+	if a.proxies != nil {
+		for _, st := range a.proxies.Stats() {
+			a.logger.Info("proxy stats",
+				zap.String("proxy", st.URL),
+				zap.Int("successes", st.Successes),
+				zap.Int("failures", st.Failures),
+				zap.Bool("benched", st.BenchedUntil != nil),
+			)
+		}
+	}
+
+	return err
+}
+
+// recordsSnapshots reports whether runs archive the pages they fetch, which
+// the queue mode does not support.
+func (a *application) recordsSnapshots() bool {
+	return a.cfg.SnapshotDir != "" && !a.replay && a.jobs == nil
+}
+
+// markUnavailable flags the products that disappeared from the categories
+// the run scraped completely. It is called once the whole run is done and
+// free of markup drift, since drift in any category casts doubt on what the
+// others listed.
+func (a *application) markUnavailable(ctx context.Context, categories []domain.ScrapeRunCategory) {
+	for _, c := range categories {
+		if c.CategoryID == nil || !c.Complete() {
 			continue
 		}
-		seen[c.Slug] = struct{}{}
-		domainCategories = append(domainCategories, domain.Category{
-			Name: c.Name,
-			Slug: c.Slug,
-			URL:  c.URL,
-		})
+
+		marked, err := a.productRepo.MarkUnavailable(ctx, *c.CategoryID, c.StartedAt.Add(-a.cfg.UnavailableGracePeriod))
+		if err != nil {
+			a.logger.Error("failed to mark unavailable products", zap.String("category", c.CategoryName), zap.Error(err))
+			continue
+		}
+		if marked == 0 {
+			continue
+		}
+
+		a.logger.Info("products marked unavailable",
+			zap.String("category", c.CategoryName),
+			zap.Int64("count", marked),
+		)
+		if err := a.runRepo.SetMarkedUnavailable(context.WithoutCancel(ctx), c.ID, int(marked)); err != nil {
+			a.logger.Error("failed to record marked unavailable products", zap.String("category", c.CategoryName), zap.Error(err))
+		}
 	}
+}
 
-	if err := a.categoryRepo.Upsert(ctx, domainCategories); err != nil {
-		return fmt.Errorf("upsert categories: %w", err)
+// markDisabledSources flags the products of sources missing from
+// SCRAPE_SOURCES once they have not been seen for the grace period, since
+// no category scrape marks them any more.
+func (a *application) markDisabledSources(ctx context.Context, startedAt time.Time) {
+	enabled := make([]string, 0, len(a.sources))
+	for _, src := range a.sources {
+		enabled = append(enabled, src.Name())
 	}
 
-	dbCategories, err := a.categoryRepo.GetAll(ctx)
+	marked, err := a.productRepo.MarkSourcesUnavailable(ctx, enabled, startedAt.Add(-a.cfg.UnavailableGracePeriod))
 	if err != nil {
-		return fmt.Errorf("get categories: %w", err)
+		a.logger.Error("failed to mark products of disabled sources unavailable", zap.Error(err))
+		return
 	}
+	if marked > 0 {
+		a.logger.Info("products of disabled sources marked unavailable", zap.Int64("count", marked))
+	}
+}
 
-	slugToID := make(map[string]uuid.UUID, len(dbCategories))
-	for _, c := range dbCategories {
-		slugToID[c.Slug] = c.ID
+// groupProducts regroups the whole catalog into color and storage variants.
+// A failure keeps the previous grouping.
+func (a *application) groupProducts(ctx context.Context) {
+	members, err := a.groupRepo.GetMembers(ctx)
+	if err != nil {
+		a.logger.Error("failed to load products for grouping", zap.Error(err))
+		return
 	}
 
-	workers := a.cfg.ScrapeWorkers
-	if workers <= 0 {
-		workers = 5
+	groups := grouping.Cluster(members)
+	if err := a.groupRepo.Assign(ctx, groups); err != nil {
+		a.logger.Error("failed to save product groups", zap.Error(err))
+		return
 	}
 
-	sem := make(chan struct{}, workers)
-	var wg sync.WaitGroup
+	a.logger.Info("products grouped", zap.Int("products", len(members)), zap.Int("groups", len(groups)))
+}
 
-	a.logger.Info("scraping categories", zap.Int("concurrency", workers), zap.Int("total", len(parsedCategories)))
+// matchProducts links products of different sources that look like the same
+// device. Suggestions are recomputed from scratch; confirmed and rejected
+// matches are kept, and a failure keeps the previous suggestions.
+func (a *application) matchProducts(ctx context.Context) {
+	candidates, err := a.matchRepo.GetCandidates(ctx)
+	if err != nil {
+		a.logger.Error("failed to load products for matching", zap.Error(err))
+		return
+	}
 
-	for _, cat := range parsedCategories {
-		categoryID, ok := slugToID[cat.Slug]
-		if !ok {
-			continue
+	decided, err := a.matchRepo.GetDecided(ctx)
+	if err != nil {
+		a.logger.Error("failed to load decided product matches", zap.Error(err))
+		return
+	}
+
+	matches := matching.Match(candidates, decided, a.cfg.MatchMinConfidence)
+	if err := a.matchRepo.ReplaceSuggestions(ctx, matches); err != nil {
+		a.logger.Error("failed to save product matches", zap.Error(err))
+		return
+	}
+
+	a.logger.Info("products matched", zap.Int("products", len(candidates)), zap.Int("suggested", len(matches)))
+}
+
+// setRecorder makes the sources that support it archive the pages they
+// fetch. Passing nil stops recording.
+func (a *application) setRecorder(rec source.Recorder) {
+	for _, src := range a.sources {
+		if r, ok := src.(source.Recording); ok {
+			r.SetRecorder(rec)
 		}
+	}
+}
 
+// recordFailedPages stores the pages the sources gave up on during the run.
+func (a *application) recordFailedPages(ctx context.Context, runID uuid.UUID) {
+	var givenUp []source.GivenUpPage
+	for _, src := range a.sources {
+		givenUp = append(givenUp, src.DrainGivenUp()...)
+	}
+	a.saveFailedPages(ctx, runID, givenUp)
+}
+
+func (a *application) saveFailedPages(ctx context.Context, runID uuid.UUID, givenUp []source.GivenUpPage) {
+	if len(givenUp) == 0 {
+		return
+	}
+
+	pages := make([]domain.ScrapeRunFailedPage, 0, len(givenUp))
+	for _, p := range givenUp {
+		pages = append(pages, domain.ScrapeRunFailedPage{
+			URL:       p.URL,
+			Kind:      p.Kind,
+			Attempts:  p.Attempts,
+			Permanent: p.Permanent,
+			Error:     p.Err.Error(),
+		})
+	}
+
+	a.logger.Warn("pages given up on", zap.String("run_id", runID.String()), zap.Int("count", len(pages)))
+
+	if err := a.runRepo.AddFailedPages(context.WithoutCancel(ctx), runID, pages); err != nil {
+		a.logger.Error("failed to record failed pages", zap.String("run_id", runID.String()), zap.Error(err))
+	}
+}
+
+// scrapeCategories scrapes the categories of the run on this instance and
+// returns them as recorded.
+func (a *application) scrapeCategories(ctx context.Context, run *domain.ScrapeRun, due func(uuid.UUID) bool) ([]domain.ScrapeRunCategory, error) {
+	engine, err := a.loadPricing(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// The browser is started on demand for pages plain HTTP cannot load.
+	defer a.stopSources()
+
+	tasks, err := a.runTasks(ctx, due)
+	if err != nil {
+		return nil, err
+	}
+
+	workers := a.workers()
+
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	categories := make([]domain.ScrapeRunCategory, 0, len(tasks))
+
+	a.logger.Info("scraping categories", zap.Int("concurrency", workers), zap.Int("total", len(tasks)))
+
+	for _, task := range tasks {
 		if ctx.Err() != nil {
 			break
 		}
@@ -206,105 +763,412 @@ func (a *application) scrape(ctx context.Context) error {
 		sem <- struct{}{}
 
 		wg.Add(1)
-		go func(cat store77.Category, categoryID uuid.UUID) {
+		go func(task categoryTask) {
 			defer wg.Done()
 			defer func() { <-sem }()
 
-			if err := a.scrapeCategory(ctx, cat, categoryID); err != nil {
+			stats := domain.ScrapeRunCategory{
+				RunID:        run.ID,
+				CategoryID:   &task.CategoryID,
+				CategoryName: task.Category.Name,
+				StartedAt:    time.Now(),
+			}
+
+			err := a.scrapeCategory(ctx, engine, task, &stats)
+			if err != nil {
 				a.logger.Error("scrape category failed",
-					zap.String("category", cat.Name),
+					zap.String("category", task.Category.Name),
 					zap.Error(err),
 				)
 			}
-		}(cat, categoryID)
+
+			a.recordCategory(ctx, &stats, err)
+
+			mu.Lock()
+			run.AddCategory(stats)
+			categories = append(categories, stats)
+			mu.Unlock()
+		}(task)
 	}
 
 	wg.Wait()
 
-	a.logger.Info("scraping completed")
-	return nil
+	return categories, ctx.Err()
+}
+
+// categoryTask is a leaf category to scrape, together with its source and
+// database id.
+type categoryTask struct {
+	Source     string          `json:"source"`
+	Category   source.Category `json:"category"`
+	CategoryID uuid.UUID       `json:"category_id"`
+	// Ancestors lets pricing rules of the parent categories apply.
+	Ancestors []uuid.UUID `json:"ancestors"`
+}
+
+// runTasks returns the categories of the run matched by due, reusing the
+// ones discovered while planning it. A run recording a snapshot parses the
+// menus again so that the snapshot can be replayed.
+func (a *application) runTasks(ctx context.Context, due func(uuid.UUID) bool) ([]categoryTask, error) {
+	if a.planned == nil || a.recordsSnapshots() {
+		return a.discoverCategories(ctx, due)
+	}
+
+	tasks := make([]categoryTask, 0, len(a.planned))
+	for _, task := range a.planned {
+		if due == nil || due(task.CategoryID) {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, nil
+}
+
+// discoverCategories lists the categories of every source, stores the
+// category trees and returns the leaf categories matched by due, or all of
+// them if due is nil. A source that fails is skipped; the run fails only
+// when no source yields categories.
+func (a *application) discoverCategories(ctx context.Context, due func(uuid.UUID) bool) ([]categoryTask, error) {
+	var tasks []categoryTask
+	var errs []error
+	for _, src := range a.sources {
+		srcTasks, err := a.discoverSource(ctx, src, due)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			a.logger.Error("failed to discover categories",
+				zap.String("source", src.Name()),
+				zap.Error(err),
+				zap.Bool("alert", errors.Is(err, errMenuDrift)),
+			)
+			errs = append(errs, fmt.Errorf("%s: %w", src.Name(), err))
+			continue
+		}
+		tasks = append(tasks, srcTasks...)
+	}
+
+	if len(tasks) == 0 && len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return tasks, nil
+}
+
+// discoverSource parses the catalog menu of one source, stores its category
+// tree and remembers its leaf categories for scheduling.
+func (a *application) discoverSource(ctx context.Context, src source.Source, due func(uuid.UUID) bool) ([]categoryTask, error) {
+	parsedCategories, err := src.ListCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	a.logger.Info("categories parsed", zap.String("source", src.Name()), zap.Int("count", len(parsedCategories)))
+
+	if len(parsedCategories) == 0 {
+		return nil, errMenuDrift
+	}
+
+	slugToID, err := a.upsertCategoryTree(ctx, src.Name(), parsedCategories)
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := a.categoryRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get categories: %w", err)
+	}
+	ancestors := domain.CategoryAncestors(categories)
+
+	leaves := make([]uuid.UUID, 0, countLeaves(parsedCategories))
+	tasks := make([]categoryTask, 0, countLeaves(parsedCategories))
+	for _, cat := range parsedCategories {
+		if !cat.Leaf {
+			continue
+		}
+
+		categoryID, ok := slugToID[cat.Slug]
+		if !ok {
+			continue
+		}
+		leaves = append(leaves, categoryID)
+
+		if due != nil && !due(categoryID) {
+			continue
+		}
+
+		tasks = append(tasks, categoryTask{Source: src.Name(), Category: cat, CategoryID: categoryID, Ancestors: ancestors[categoryID]})
+	}
+
+	if a.menuLeaves == nil {
+		a.menuLeaves = make(map[string][]uuid.UUID)
+	}
+	a.menuLeaves[src.Name()] = leaves
+
+	return tasks, nil
+}
+
+func (a *application) loadPricing(ctx context.Context) (*pricing.Engine, error) {
+	rules, err := a.pricingRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("load pricing rules: %w", err)
+	}
+
+	a.logger.Debug("pricing rules loaded", zap.Int("count", len(rules)))
+
+	return pricing.NewEngine(rules), nil
+}
+
+func (a *application) workers() int {
+	if a.cfg.ScrapeWorkers <= 0 {
+		return 5
+	}
+	return a.cfg.ScrapeWorkers
+}
+
+func (a *application) recordCategory(ctx context.Context, stats *domain.ScrapeRunCategory, err error) {
+	stats.FinishedAt = time.Now()
+
+	switch {
+	case err != nil:
+		msg := err.Error()
+		stats.Status = domain.ScrapeStatusFailed
+		stats.Error = &msg
+	case stats.PagesFailed > 0:
+		stats.Status = domain.ScrapeStatusPartial
+	default:
+		stats.Status = domain.ScrapeStatusSucceeded
+	}
+
+	if err := a.runRepo.AddCategory(context.WithoutCancel(ctx), stats); err != nil {
+		a.logger.Error("failed to record scrape run category",
+			zap.String("category", stats.CategoryName),
+			zap.Error(err),
+		)
+	}
+}
+
+// upsertCategoryTree stores the categories of a source level by level so
+// that every parent has an id by the time its children reference it.
+func (a *application) upsertCategoryTree(ctx context.Context, sourceName string, parsed []source.Category) (map[string]uuid.UUID, error) {
+	seen := make(map[string]struct{}, len(parsed))
+	var levels [][]source.Category
+	for _, c := range parsed {
+		if _, exists := seen[c.Slug]; exists {
+			continue
+		}
+		seen[c.Slug] = struct{}{}
+
+		for len(levels) <= c.Depth {
+			levels = append(levels, nil)
+		}
+		levels[c.Depth] = append(levels[c.Depth], c)
+	}
+
+	slugToID := make(map[string]uuid.UUID, len(parsed))
+	for depth, level := range levels {
+		domainCategories := make([]domain.Category, 0, len(level))
+		for _, c := range level {
+			dc := domain.Category{
+				Source: sourceName,
+				Name:   c.Name,
+				Slug:   c.Slug,
+				URL:    c.URL,
+				Depth:  depth,
+			}
+			if parentID, ok := slugToID[c.ParentSlug]; ok {
+				dc.ParentID = &parentID
+			}
+			domainCategories = append(domainCategories, dc)
+		}
+
+		if err := a.categoryRepo.Upsert(ctx, domainCategories); err != nil {
+			return nil, fmt.Errorf("upsert categories at depth %d: %w", depth, err)
+		}
+
+		dbCategories, err := a.categoryRepo.GetAll(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("get categories: %w", err)
+		}
+
+		for _, c := range dbCategories {
+			if c.Source == sourceName {
+				slugToID[c.Slug] = c.ID
+			}
+		}
+	}
+
+	return slugToID, nil
 }
 
-func (a *application) scrapeCategory(ctx context.Context, cat store77.Category, categoryID uuid.UUID) error {
-	a.logger.Info("scraping category", zap.String("name", cat.Name), zap.String("url", cat.URL))
+// scrapeCategory upserts every listed product and checks the category for
+// markup drift. Progress is tallied in stats; products missing from the
+// category are marked unavailable only after the run, by markUnavailable.
+func (a *application) scrapeCategory(ctx context.Context, engine *pricing.Engine, task categoryTask, stats *domain.ScrapeRunCategory) error {
+	ctx, src, err := a.categorySource(ctx, task)
+	if err != nil {
+		return err
+	}
+
+	if err := a.scrapeFirstPage(ctx, engine, src, task, stats); err != nil {
+		return err
+	}
 
-	html, err := a.scraper.FetchCategoryPage(ctx, cat.URL, 1)
+	return a.scrapeRemainingPages(ctx, engine, src, task, stats)
+}
+
+// categorySource returns the source of the task, and a context that keeps
+// the category's requests on one proxy.
+func (a *application) categorySource(ctx context.Context, task categoryTask) (context.Context, source.Source, error) {
+	src, err := a.sourceByName(task.Source)
 	if err != nil {
-		return fmt.Errorf("fetch page 1: %w", err)
+		return ctx, nil, err
 	}
 
-	pagination, err := store77.ParsePagination(html)
+	return proxy.WithKey(ctx, src.Name()+":"+task.Category.Slug), src, nil
+}
+
+// scrapeFirstPage scrapes the first page of a category, which also tells how
+// many pages and products the category has. A failure fails the category.
+func (a *application) scrapeFirstPage(ctx context.Context, engine *pricing.Engine, src source.Source, task categoryTask, stats *domain.ScrapeRunCategory) error {
+	cat := task.Category
+
+	a.logger.Info("scraping category", zap.String("source", src.Name()), zap.String("name", cat.Name), zap.String("url", cat.URL))
+
+	first, err := src.ListProducts(ctx, cat, 1)
 	if err != nil {
-		return fmt.Errorf("parse pagination: %w", err)
+		stats.PagesFailed++
+		return fmt.Errorf("list page 1: %w", err)
 	}
+	stats.PagesTotal = first.TotalPages
+	stats.ProductsExpected = first.TotalProducts
 
 	a.logger.Info("category pagination",
 		zap.String("category", cat.Name),
-		zap.Int("total_pages", pagination.TotalPages),
+		zap.Int("total_pages", first.TotalPages),
 	)
 
-	if err := a.processPage(ctx, html, categoryID); err != nil {
+	upserted, changed, err := a.processPage(ctx, engine, src, first, task, &stats.ScrapeHealth)
+	if err != nil {
+		stats.PagesFailed++
 		return fmt.Errorf("process page 1: %w", err)
 	}
+	stats.PagesFetched++
+	stats.ProductsUpserted += upserted
+	stats.ProductsChanged += changed
+
+	return nil
+}
 
-	for page := 2; page <= pagination.TotalPages; page++ {
+// scrapeRemainingPages scrapes the pages after the first one and checks the
+// category for markup drift. Failed pages are counted, not returned.
+func (a *application) scrapeRemainingPages(ctx context.Context, engine *pricing.Engine, src source.Source, task categoryTask, stats *domain.ScrapeRunCategory) error {
+	for page := 2; page <= stats.PagesTotal; page++ {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
 		default:
 		}
 
-		pageHTML, err := a.scraper.FetchCategoryPage(ctx, cat.URL, page)
-		if err != nil {
-			a.logger.Error("fetch page failed",
-				zap.String("category", cat.Name),
-				zap.Int("page", page),
-				zap.Error(err),
-			)
-			continue
-		}
+		_ = a.scrapePage(ctx, engine, src, task, page, stats)
+	}
 
-		if err := a.processPage(ctx, pageHTML, categoryID); err != nil {
-			a.logger.Error("process page failed",
-				zap.String("category", cat.Name),
-				zap.Int("page", page),
-				zap.Error(err),
-			)
-			continue
-		}
+	a.checkDrift(stats, stats.PagesFailed == 0 && ctx.Err() == nil)
+
+	return ctx.Err()
+}
+
+// scrapePage scrapes a page after the first one. A failure is logged and
+// counted in stats.
+func (a *application) scrapePage(ctx context.Context, engine *pricing.Engine, src source.Source, task categoryTask, page int, stats *domain.ScrapeRunCategory) error {
+	cat := task.Category
+
+	listing, err := src.ListProducts(ctx, cat, page)
+	if err != nil {
+		stats.PagesFailed++
+		a.logger.Error("list page failed",
+			zap.String("category", cat.Name),
+			zap.Int("page", page),
+			zap.Error(err),
+		)
+		return fmt.Errorf("list page %d: %w", page, err)
 	}
 
+	upserted, changed, err := a.processPage(ctx, engine, src, listing, task, &stats.ScrapeHealth)
+	if err != nil {
+		stats.PagesFailed++
+		a.logger.Error("process page failed",
+			zap.String("category", cat.Name),
+			zap.Int("page", page),
+			zap.Error(err),
+		)
+		return fmt.Errorf("process page %d: %w", page, err)
+	}
+	stats.PagesFetched++
+	stats.ProductsUpserted += upserted
+	stats.ProductsChanged += changed
+
 	return nil
 }
 
-func (a *application) processPage(ctx context.Context, html string, categoryID uuid.UUID) error {
-	parsed, err := store77.ParseProducts(html)
-	if err != nil {
-		return fmt.Errorf("parse products: %w", err)
+// checkDrift flags the category when its health signals point to changed
+// markup; complete tells whether every page of it was scraped.
+func (a *application) checkDrift(stats *domain.ScrapeRunCategory, complete bool) {
+	reason := stats.Drift(complete)
+	if reason == "" {
+		return
 	}
 
+	stats.MarkupDrift = true
+	stats.DriftReason = &reason
+	a.logger.Error("markup drift detected, skipping availability check",
+		zap.String("category", stats.CategoryName),
+		zap.String("reason", reason),
+		zap.Any("health", stats.ScrapeHealth),
+		zap.Bool("alert", true),
+	)
+}
+
+// processPage upserts the products listed on a category page and returns how
+// many were stored and how many of those were new or changed price. Markup
+// health signals of the page are added to health.
+func (a *application) processPage(ctx context.Context, engine *pricing.Engine, src source.Source, page *source.Page, task categoryTask, health *domain.ScrapeHealth) (upserted, changed int, err error) {
+	parsed, pageHealth := page.Products, page.Health
+	health.Add(domain.ScrapeHealth{
+		CardsFound:   pageHealth.Cards,
+		CardsParsed:  pageHealth.Parsed,
+		MissingPrice: pageHealth.MissingPrice,
+		MissingBrand: pageHealth.MissingBrand,
+		MissingSKU:   pageHealth.MissingSKU,
+		MissingEcom:  pageHealth.MissingEcom,
+	})
+
 	if len(parsed) == 0 {
-		return nil
+		return 0, 0, nil
 	}
 
+	cached := a.cachedDescriptions(ctx, src.Name(), parsed)
+
 	products := make([]domain.Product, 0, len(parsed))
-	for i, p := range parsed {
+	var details []domain.ProductDetail
+	for _, p := range parsed {
 		if p.ExternalID == "" {
 			continue
 		}
 
-		if i > 0 {
-			time.Sleep(500 * time.Millisecond)
+		description, detail := a.productPage(ctx, src, p, cached, health)
+		if detail != nil {
+			details = append(details, productDetail(src.Name(), p.ExternalID, detail))
 		}
 
-		description := a.fetchProductDescription(ctx, p.ProductURL)
-
-		price := p.Price - 1000
-		if price < 0 {
-			price = 0
-		}
+		price, _ := engine.Apply(pricing.Input{
+			CategoryID: task.CategoryID,
+			Ancestors:  task.Ancestors,
+			Brand:      p.Brand,
+			Price:      p.Price,
+		})
 
 		products = append(products, domain.Product{
+			Source:        src.Name(),
 			ExternalID:    p.ExternalID,
 			SKU:           p.SKU,
 			Name:          p.Name,
@@ -314,32 +1178,148 @@ func (a *application) processPage(ctx context.Context, html string, categoryID u
 			ProductURL:    p.ProductURL,
 			Brand:         p.Brand,
 			Description:   description,
-			CategoryID:    categoryID,
+			CategoryID:    task.CategoryID,
 		})
 	}
 
 	if len(products) == 0 {
-		return nil
+		return 0, 0, nil
 	}
 
 	a.logger.Info("upserting products", zap.Int("count", len(products)))
 
-	return a.productRepo.Upsert(ctx, products)
+	changed, err = a.productRepo.Upsert(ctx, products)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if err := a.productRepo.SaveDetails(ctx, details); err != nil {
+		a.logger.Warn("failed to save product details", zap.Int("count", len(details)), zap.Error(err))
+	}
+
+	return len(products), changed, nil
+}
+
+// cachedDescriptions looks up the descriptions cached for the page's
+// products. A full refresh or a cache failure yields an empty map, so every
+// product page is fetched.
+func (a *application) cachedDescriptions(ctx context.Context, sourceName string, parsed []source.Product) map[string]cache.Description {
+	if isFullRefresh(ctx) {
+		return nil
+	}
+
+	ids := make([]string, 0, len(parsed))
+	for _, p := range parsed {
+		if p.ExternalID != "" {
+			ids = append(ids, p.ExternalID)
+		}
+	}
+
+	cached, err := a.descCache.GetMany(ctx, sourceName, ids)
+	if err != nil {
+		a.logger.Warn("failed to read description cache", zap.Error(err))
+		return nil
+	}
+
+	return cached
+}
+
+// productPage reuses the cached description while the product's listing is
+// unchanged and the page was fetched within SCRAPE_DETAIL_REFRESH_INTERVAL,
+// and fetches the product page otherwise, so that stock and attributes the
+// listing does not show are refreshed too. The page detail is returned only
+// when the page was fetched; stored details of a skipped product are kept
+// as they are.
+func (a *application) productPage(ctx context.Context, src source.Source, p source.Product, cached map[string]cache.Description, health *domain.ScrapeHealth) (string, *source.ProductDetail) {
+	fingerprint := p.Fingerprint()
+	if c, ok := cached[p.ExternalID]; ok && c.Fingerprint == fingerprint && c.Text != "" &&
+		time.Since(c.FetchedAt) < a.cfg.DetailRefreshInterval {
+		health.DescriptionsCached++
+		return c.Text, nil
+	}
+
+	detail := a.fetchProductDetail(ctx, src, p.ProductURL)
+	if detail == nil {
+		return "", nil
+	}
+
+	health.DescriptionsFetched++
+	if detail.Description == "" {
+		health.DescriptionsMissing++
+		return "", detail
+	}
+
+	if err := a.descCache.Set(ctx, src.Name(), p.ExternalID, cache.Description{Fingerprint: fingerprint, Text: detail.Description, FetchedAt: time.Now()}); err != nil {
+		a.logger.Warn("failed to cache description", zap.String("external_id", p.ExternalID), zap.Error(err))
+	}
+
+	return detail.Description, detail
 }
 
-func (a *application) fetchProductDescription(ctx context.Context, productURL string) string {
+// fetchProductDetail returns nil if the product page could not be fetched or
+// parsed, so that an empty page can be told apart from a failed fetch.
+func (a *application) fetchProductDetail(ctx context.Context, src source.Source, productURL string) *source.ProductDetail {
 	if productURL == "" {
-		return ""
+		return nil
 	}
 
-	html, err := a.scraper.FetchProductPage(ctx, productURL)
+	detail, err := src.FetchDetail(ctx, productURL)
 	if err != nil {
-		a.logger.Warn("failed to fetch product page for description",
+		a.logger.Warn("failed to load product page",
+			zap.String("source", src.Name()),
 			zap.String("url", productURL),
 			zap.Error(err),
 		)
-		return ""
+		return nil
+	}
+
+	return detail
+}
+
+func productDetail(sourceName, externalID string, d *source.ProductDetail) domain.ProductDetail {
+	detail := domain.ProductDetail{
+		Source:      sourceName,
+		ExternalID:  externalID,
+		StockStatus: d.StockStatus,
+		StockLabel:  d.StockLabel,
+		Attributes:  make([]domain.ProductAttribute, 0, len(d.Attributes)),
+		Images:      make([]domain.ProductImage, 0, len(d.Images)),
+		Variants:    make([]domain.ProductVariant, 0, len(d.Variants)),
 	}
 
-	return store77.ParseProductDescription(html)
+	for _, attr := range d.Attributes {
+		detail.Attributes = append(detail.Attributes, domain.ProductAttribute{Group: attr.Group, Name: attr.Name, Value: attr.Value})
+	}
+	detail.AttributeIndex = attribute.Index(detail.Attributes)
+	for i, url := range d.Images {
+		detail.Images = append(detail.Images, domain.ProductImage{URL: url, Position: i})
+	}
+	for _, v := range d.Variants {
+		detail.Variants = append(detail.Variants, domain.ProductVariant{Kind: v.Kind, Label: v.Label, URL: v.URL, Current: v.Current})
+	}
+
+	return detail
+}
+
+type fullRefreshKey struct{}
+
+// withFullRefresh marks ctx as belonging to a run that refetches every
+// product page.
+func withFullRefresh(ctx context.Context, full bool) context.Context {
+	return context.WithValue(ctx, fullRefreshKey{}, full)
+}
+
+func isFullRefresh(ctx context.Context) bool {
+	full, _ := ctx.Value(fullRefreshKey{}).(bool)
+	return full
+}
+
+func countLeaves(categories []source.Category) int {
+	n := 0
+	for _, c := range categories {
+		if c.Leaf {
+			n++
+		}
+	}
+	return n
 }
@@ -0,0 +1,481 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/burbble/marketplace/internal/config"
+	"github.com/burbble/marketplace/internal/domain"
+	"github.com/burbble/marketplace/internal/mocks"
+	"github.com/burbble/marketplace/internal/repository/cache"
+	"github.com/burbble/marketplace/internal/scraper/source"
+)
+
+// fakeSource serves a fixed catalog: one page per category slug.
+type fakeSource struct {
+	name       string
+	categories []source.Category
+	pages      map[string]*source.Page
+
+	menuFetches   atomic.Int32
+	detailFetches atomic.Int32
+}
+
+func (s *fakeSource) Name() string { return s.name }
+
+func (s *fakeSource) ListCategories(_ context.Context) ([]source.Category, error) {
+	s.menuFetches.Add(1)
+	return s.categories, nil
+}
+
+func (s *fakeSource) ListProducts(_ context.Context, cat source.Category, _ int) (*source.Page, error) {
+	return s.pages[cat.Slug], nil
+}
+
+func (s *fakeSource) FetchDetail(_ context.Context, _ string) (*source.ProductDetail, error) {
+	s.detailFetches.Add(1)
+	return &source.ProductDetail{Description: "Fetched description"}, nil
+}
+
+func (s *fakeSource) DrainGivenUp() []source.GivenUpPage { return nil }
+
+func (s *fakeSource) Stop() {}
+
+// healthyPage lists one product, as many as the category counts.
+func healthyPage(externalID string) *source.Page {
+	return &source.Page{
+		Products:      []source.Product{{ExternalID: externalID, Name: "Product " + externalID, Price: 1000}},
+		Health:        source.PageHealth{Cards: 1, Parsed: 1},
+		TotalPages:    1,
+		TotalProducts: 1,
+	}
+}
+
+// driftedPage counts products but no card matches the parser.
+func driftedPage() *source.Page {
+	return &source.Page{TotalPages: 1, TotalProducts: 5}
+}
+
+type testParser struct {
+	app        *application
+	categories *mocks.CategoryRepositoryMock
+	products   *mocks.ProductRepositoryMock
+	runs       *mocks.ScrapeRunRepositoryMock
+	groups     *mocks.ProductGroupRepositoryMock
+	matches    *mocks.ProductMatchRepositoryMock
+}
+
+// newTestParser wires an application around src with in-memory categories
+// and repositories that accept every write.
+func newTestParser(t *testing.T, sources ...source.Source) *testParser {
+	t.Helper()
+
+	var mu sync.Mutex
+	var stored []domain.Category
+	categories := &mocks.CategoryRepositoryMock{
+		UpsertFunc: func(_ context.Context, cats []domain.Category) error {
+			mu.Lock()
+			defer mu.Unlock()
+			for _, c := range cats {
+				found := false
+				for i := range stored {
+					if stored[i].Source == c.Source && stored[i].Slug == c.Slug {
+						found = true
+					}
+				}
+				if !found {
+					c.ID = uuid.New()
+					stored = append(stored, c)
+				}
+			}
+			return nil
+		},
+		GetAllFunc: func(_ context.Context) ([]domain.Category, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			return append([]domain.Category(nil), stored...), nil
+		},
+	}
+
+	products := &mocks.ProductRepositoryMock{
+		UpsertFunc: func(_ context.Context, p []domain.Product) (int, error) {
+			return len(p), nil
+		},
+		SaveDetailsFunc: func(_ context.Context, _ []domain.ProductDetail) error {
+			return nil
+		},
+		MarkUnavailableFunc: func(_ context.Context, _ uuid.UUID, _ time.Time) (int64, error) {
+			return 1, nil
+		},
+		MarkSourcesUnavailableFunc: func(_ context.Context, _ []string, _ time.Time) (int64, error) {
+			return 0, nil
+		},
+	}
+
+	var nextID int64
+	runs := &mocks.ScrapeRunRepositoryMock{
+		FailAbandonedFunc: func(_ context.Context, _ string) (int64, error) {
+			return 0, nil
+		},
+		CreateFunc: func(_ context.Context, run *domain.ScrapeRun) error {
+			run.ID = uuid.New()
+			return nil
+		},
+		AddCategoryFunc: func(_ context.Context, c *domain.ScrapeRunCategory) error {
+			mu.Lock()
+			defer mu.Unlock()
+			nextID++
+			c.ID = nextID
+			return nil
+		},
+		SetMarkedUnavailableFunc: func(_ context.Context, _ int64, _ int) error {
+			return nil
+		},
+		FinishFunc: func(_ context.Context, _ *domain.ScrapeRun) error {
+			return nil
+		},
+		LastScrapedFunc: func(_ context.Context) (map[uuid.UUID]time.Time, error) {
+			return nil, nil
+		},
+	}
+
+	groups := &mocks.ProductGroupRepositoryMock{
+		GetMembersFunc: func(_ context.Context) ([]domain.ProductGroupMember, error) {
+			return nil, nil
+		},
+		AssignFunc: func(_ context.Context, _ []domain.ProductGroupAssignment) error {
+			return nil
+		},
+	}
+	matches := &mocks.ProductMatchRepositoryMock{
+		GetCandidatesFunc: func(_ context.Context) ([]domain.MatchCandidate, error) {
+			return nil, nil
+		},
+		GetDecidedFunc: func(_ context.Context) ([]domain.ProductMatch, error) {
+			return nil, nil
+		},
+		ReplaceSuggestionsFunc: func(_ context.Context, _ []domain.ProductMatch) error {
+			return nil
+		},
+	}
+
+	app := &application{
+		logger: zap.NewNop(),
+		cfg: &config.Config{ParserConfig: config.ParserConfig{
+			ScrapeInterval:         time.Hour,
+			DetailRefreshInterval:  24 * time.Hour,
+			ScrapeWorkers:          1,
+			UnavailableGracePeriod: time.Hour,
+			MatchMinConfidence:     0.8,
+		}},
+		sources:      sources,
+		categoryRepo: categories,
+		productRepo:  products,
+		pricingRepo: &mocks.PricingRuleRepositoryMock{
+			GetAllFunc: func(_ context.Context) ([]domain.PricingRule, error) {
+				return nil, nil
+			},
+		},
+		runRepo: runs,
+		scheduleRepo: &mocks.CategoryScheduleRepositoryMock{
+			GetAllFunc: func(_ context.Context) ([]domain.CategorySchedule, error) {
+				return nil, nil
+			},
+		},
+		groupRepo: groups,
+		matchRepo: matches,
+		descCache: cache.NewNopDescriptionCache(),
+		loc:       time.UTC,
+	}
+
+	return &testParser{app: app, categories: categories, products: products, runs: runs, groups: groups, matches: matches}
+}
+
+func leafCategories(slugs ...string) []source.Category {
+	cats := make([]source.Category, 0, len(slugs))
+	for _, slug := range slugs {
+		cats = append(cats, source.Category{Name: slug, Slug: slug, URL: "/" + slug + "/", Leaf: true})
+	}
+	return cats
+}
+
+func TestExecuteRun_MarksUnavailableAfterRun(t *testing.T) {
+	src := &fakeSource{
+		name:       "store77",
+		categories: leafCategories("phones", "tablets"),
+		pages:      map[string]*source.Page{"phones": healthyPage("1"), "tablets": healthyPage("2")},
+	}
+	p := newTestParser(t, src)
+
+	run, err := p.app.startRun(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := p.app.executeRun(context.Background(), run, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if calls := p.products.MarkUnavailableCalls(); len(calls) != 2 {
+		t.Errorf("expected both categories to be checked for unavailable products, got %d", len(calls))
+	}
+	if calls := p.runs.SetMarkedUnavailableCalls(); len(calls) != 2 || calls[0].Marked != 1 {
+		t.Errorf("expected the marked products to be recorded per category, got %+v", calls)
+	}
+	if run.Status != domain.ScrapeStatusSucceeded {
+		t.Errorf("expected status %q, got %q", domain.ScrapeStatusSucceeded, run.Status)
+	}
+
+	calls := p.products.MarkSourcesUnavailableCalls()
+	if len(calls) != 1 || len(calls[0].Enabled) != 1 || calls[0].Enabled[0] != "store77" {
+		t.Errorf("expected products of every source but store77 to be checked, got %+v", calls)
+	}
+}
+
+func TestExecuteRun_DriftInLaterCategorySkipsAvailability(t *testing.T) {
+	src := &fakeSource{
+		name:       "store77",
+		categories: leafCategories("phones", "tablets"),
+		pages:      map[string]*source.Page{"phones": healthyPage("1"), "tablets": driftedPage()},
+	}
+	p := newTestParser(t, src)
+
+	run, err := p.app.startRun(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := p.app.executeRun(context.Background(), run, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !run.MarkupDrift || run.DriftCategories != 1 {
+		t.Fatalf("expected drift in the second category, got %v/%d", run.MarkupDrift, run.DriftCategories)
+	}
+	if calls := p.products.MarkUnavailableCalls(); len(calls) != 0 {
+		t.Errorf("expected no products marked unavailable in a drifted run, got %d calls", len(calls))
+	}
+	if run.Status != domain.ScrapeStatusPartial {
+		t.Errorf("expected status %q, got %q", domain.ScrapeStatusPartial, run.Status)
+	}
+}
+
+// scrapedNow makes the categories with the given slugs look scraped just now.
+func (p *testParser) scrapedNow(slugs ...string) {
+	p.runs.LastScrapedFunc = func(ctx context.Context) (map[uuid.UUID]time.Time, error) {
+		stored, _ := p.categories.GetAll(ctx)
+		last := make(map[uuid.UUID]time.Time)
+		for _, c := range stored {
+			for _, slug := range slugs {
+				if c.Slug == slug {
+					last[c.ID] = time.Now()
+				}
+			}
+		}
+		return last, nil
+	}
+}
+
+func TestExecuteRun_RegroupsOnlyAfterUpserts(t *testing.T) {
+	src := &fakeSource{
+		name:       "store77",
+		categories: leafCategories("phones"),
+		pages:      map[string]*source.Page{"phones": healthyPage("1")},
+	}
+	p := newTestParser(t, src)
+
+	for _, page := range []*source.Page{healthyPage("1"), driftedPage()} {
+		src.pages["phones"] = page
+		run, err := p.app.startRun(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		_ = p.app.executeRun(context.Background(), run, nil)
+	}
+
+	if calls := p.groups.GetMembersCalls(); len(calls) != 1 {
+		t.Errorf("expected only the run that upserted products to regroup, got %d regroupings", len(calls))
+	}
+}
+
+func TestExecuteRun_MatchesOnlyWithTwoSources(t *testing.T) {
+	newSource := func(name string) *fakeSource {
+		return &fakeSource{
+			name:       name,
+			categories: leafCategories("phones"),
+			pages:      map[string]*source.Page{"phones": healthyPage("1")},
+		}
+	}
+
+	for _, tt := range []struct {
+		name    string
+		sources []source.Source
+		matched int
+	}{
+		{"one source", []source.Source{newSource("store77")}, 0},
+		{"two sources", []source.Source{newSource("store77"), newSource("other")}, 1},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestParser(t, tt.sources...)
+
+			run, err := p.app.startRun(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err := p.app.executeRun(context.Background(), run, nil); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if calls := p.matches.GetCandidatesCalls(); len(calls) != tt.matched {
+				t.Errorf("expected %d matchings, got %d", tt.matched, len(calls))
+			}
+		})
+	}
+}
+
+func TestExecuteRun_PricesWithParentCategoryRules(t *testing.T) {
+	src := &fakeSource{
+		name: "store77",
+		categories: []source.Category{
+			{Name: "Телефоны", Slug: "phones", URL: "/phones/"},
+			{Name: "Apple", Slug: "apple", URL: "/apple/", ParentSlug: "phones", Depth: 1, Leaf: true},
+		},
+		pages: map[string]*source.Page{"apple": healthyPage("1")},
+	}
+	p := newTestParser(t, src)
+
+	if _, err := p.app.discoverCategories(context.Background(), nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	categories, _ := p.categories.GetAll(context.Background())
+	var phones uuid.UUID
+	for _, c := range categories {
+		if c.Slug == "phones" {
+			phones = c.ID
+		}
+	}
+	p.app.pricingRepo = &mocks.PricingRuleRepositoryMock{
+		GetAllFunc: func(_ context.Context) ([]domain.PricingRule, error) {
+			return []domain.PricingRule{{
+				Name: "phones", Enabled: true, CategoryID: &phones,
+				AdjustmentType: domain.AdjustmentFixed, AdjustmentValue: -100,
+				RoundingMode: domain.RoundingNone,
+			}}, nil
+		},
+	}
+
+	run, err := p.app.startRun(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := p.app.executeRun(context.Background(), run, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	calls := p.products.UpsertCalls()
+	if len(calls) != 1 || len(calls[0].Products) != 1 || calls[0].Products[0].Price != 900 {
+		t.Errorf("expected the Телефоны rule to price the Apple product at 900, got %+v", calls)
+	}
+}
+
+func TestScrapeDue_SkipsRunWhenNothingDue(t *testing.T) {
+	src := &fakeSource{
+		name:       "store77",
+		categories: leafCategories("phones", "tablets"),
+		pages:      map[string]*source.Page{"phones": healthyPage("1"), "tablets": healthyPage("2")},
+	}
+	p := newTestParser(t, src)
+
+	// A category the shop has since removed from its menu is never scraped
+	// again and must not count as due.
+	if err := p.categories.Upsert(context.Background(), []domain.Category{{Source: "store77", Slug: "removed"}}); err != nil {
+		t.Fatal(err)
+	}
+	p.scrapedNow("phones", "tablets")
+
+	p.app.scrapeDue(context.Background())
+
+	if calls := p.runs.CreateCalls(); len(calls) != 0 {
+		t.Errorf("expected no run to be started, got %d", len(calls))
+	}
+}
+
+func TestScrapeDue_ScrapesDueMenuCategories(t *testing.T) {
+	src := &fakeSource{
+		name:       "store77",
+		categories: leafCategories("phones", "tablets"),
+		pages:      map[string]*source.Page{"phones": healthyPage("1"), "tablets": healthyPage("2")},
+	}
+	p := newTestParser(t, src)
+	p.scrapedNow("phones")
+
+	p.app.scrapeDue(context.Background())
+
+	if calls := p.runs.CreateCalls(); len(calls) != 1 {
+		t.Fatalf("expected one run, got %d", len(calls))
+	}
+	calls := p.runs.AddCategoryCalls()
+	if len(calls) != 1 || calls[0].Category.CategoryName != "tablets" {
+		t.Errorf("expected only the due category to be scraped, got %+v", calls)
+	}
+	if n := src.menuFetches.Load(); n != 1 {
+		t.Errorf("expected the menu to be parsed once for planning and scraping, got %d", n)
+	}
+}
+
+func TestProductPage_RefreshesDetails(t *testing.T) {
+	product := source.Product{ExternalID: "1", Name: "Product 1", Price: 1000, ProductURL: "/product/1/"}
+
+	tests := []struct {
+		name      string
+		cached    cache.Description
+		wantFetch bool
+	}{
+		{"fresh entry", cache.Description{Fingerprint: product.Fingerprint(), Text: "Cached", FetchedAt: time.Now().Add(-time.Hour)}, false},
+		{"changed listing", cache.Description{Fingerprint: "other", Text: "Cached", FetchedAt: time.Now().Add(-time.Hour)}, true},
+		{"details due for refresh", cache.Description{Fingerprint: product.Fingerprint(), Text: "Cached", FetchedAt: time.Now().Add(-25 * time.Hour)}, true},
+		{"entry without fetch time", cache.Description{Fingerprint: product.Fingerprint(), Text: "Cached"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := &fakeSource{name: "store77"}
+			p := newTestParser(t, src)
+
+			var health domain.ScrapeHealth
+			description, detail := p.app.productPage(context.Background(), src, product, map[string]cache.Description{"1": tt.cached}, &health)
+
+			fetched := src.detailFetches.Load() == 1
+			if fetched != tt.wantFetch {
+				t.Fatalf("expected fetch %v, got %v", tt.wantFetch, fetched)
+			}
+			if fetched && (description != "Fetched description" || detail == nil) {
+				t.Errorf("expected the fetched page to be used, got %q/%v", description, detail)
+			}
+			if !fetched && (description != "Cached" || detail != nil) {
+				t.Errorf("expected the cached description to be reused, got %q/%v", description, detail)
+			}
+		})
+	}
+}
+
+func TestReindexAttributes_UsesNormalizer(t *testing.T) {
+	p := newTestParser(t)
+
+	var got map[string]string
+	p.products.ReindexAttributesFunc = func(_ context.Context, index func([]domain.ProductAttribute) map[string]string) (int, error) {
+		got = index([]domain.ProductAttribute{{Name: "Объём памяти", Value: "256GB"}})
+		return 1, nil
+	}
+
+	if code := p.app.reindexAttributes(context.Background()); code != noErr {
+		t.Fatalf("expected exit code %d, got %d", noErr, code)
+	}
+	if got["встроенная память"] != "256 гб" {
+		t.Errorf("expected attributes normalized by the parser's rules, got %v", got)
+	}
+}
@@ -0,0 +1,662 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"github.com/burbble/marketplace/internal/domain"
+	"github.com/burbble/marketplace/internal/pricing"
+	"github.com/burbble/marketplace/internal/scraper/queue"
+	"github.com/burbble/marketplace/internal/scraper/source"
+)
+
+const (
+	jobQueueName  = "scrape:jobs"
+	leaderLockKey = "scrape:leader"
+
+	// idlePollInterval is how long a worker waits before polling an empty
+	// queue again.
+	idlePollInterval = 2 * time.Second
+	// runPollInterval is how often the leader checks whether the jobs of its
+	// run are done.
+	runPollInterval = 5 * time.Second
+	// runKeyTTL bounds the lifetime of the per-run bookkeeping keys.
+	runKeyTTL = 24 * time.Hour
+)
+
+// categoryJob is the payload of a queued scrape. A category job scrapes the
+// first page of its category and publishes a page job, with Page set, for
+// every further page.
+type categoryJob struct {
+	RunID uuid.UUID `json:"run_id"`
+	Full  bool      `json:"full"`
+	Page  int       `json:"page,omitempty"`
+	categoryTask
+}
+
+// categoryStats starts the stats of the job's category.
+func (j categoryJob) categoryStats() domain.ScrapeRunCategory {
+	return domain.ScrapeRunCategory{
+		RunID:        j.RunID,
+		CategoryID:   &j.CategoryID,
+		CategoryName: j.Category.Name,
+		StartedAt:    time.Now(),
+	}
+}
+
+func runRemainingKey(runID uuid.UUID) string {
+	return "scrape:run:" + runID.String() + ":remaining"
+}
+
+func runCancelledKey(runID uuid.UUID) string {
+	return "scrape:run:" + runID.String() + ":cancelled"
+}
+
+// categoryPendingKey counts the page jobs of a split category that are not
+// done yet. It is kept at zero once the category is recorded, so a retried
+// category job does not split it again.
+func categoryPendingKey(runID, categoryID uuid.UUID) string {
+	return "scrape:run:" + runID.String() + ":category:" + categoryID.String() + ":pending"
+}
+
+// categoryPagesKey lists the stats of the pages of a split category as JSON,
+// the first page first.
+func categoryPagesKey(runID, categoryID uuid.UUID) string {
+	return "scrape:run:" + runID.String() + ":category:" + categoryID.String() + ":pages"
+}
+
+// runQueue runs the queue workers of this instance and, whenever it wins the
+// leader lock, schedules a scrape cycle or runs the pending scrape requests.
+func (a *application) runQueue(ctx context.Context) exitCode {
+	defer a.stopSources()
+
+	workers := a.workers()
+
+	a.logger.Info("starting queue workers",
+		zap.Duration("interval", a.cfg.ScrapeInterval),
+		zap.Int("workers", workers),
+		zap.Int("max_attempts", a.jobs.MaxAttempts()),
+		zap.Duration("visibility_timeout", a.jobs.Visibility()),
+	)
+
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			a.work(ctx)
+		}()
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		a.reapLoop(ctx)
+	}()
+
+	ticker := time.NewTicker(scheduleTick)
+	defer ticker.Stop()
+
+	requests := time.NewTicker(commandPollInterval)
+	defer requests.Stop()
+
+	a.lead(ctx, a.scrapeDue)
+
+	for {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			a.logger.Info("scraper stopped")
+			return noErr
+		case <-ticker.C:
+			a.lead(ctx, a.scrapeDue)
+		case <-requests.C:
+			a.lead(ctx, a.runRequests)
+		}
+	}
+}
+
+// lead calls fn if this instance wins the leader lock. The lock is held until
+// fn returns, so runs started by different instances never overlap.
+func (a *application) lead(ctx context.Context, fn func(context.Context)) {
+	ttl := a.cfg.LeaderLockTTL
+
+	leader, err := a.leader.Acquire(ctx, ttl)
+	if err != nil {
+		a.logger.Error("failed to acquire leader lock", zap.Error(err))
+		return
+	}
+	if !leader {
+		a.logger.Debug("another instance is scheduling")
+		return
+	}
+
+	defer func() {
+		if err := a.leader.Release(context.WithoutCancel(ctx)); err != nil {
+			a.logger.Warn("failed to release leader lock", zap.Error(err))
+		}
+	}()
+
+	cycleCtx, cancel := context.WithCancel(ctx)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		a.holdLeadership(cycleCtx, cancel, ttl)
+	}()
+
+	fn(cycleCtx)
+
+	cancel()
+	wg.Wait()
+}
+
+// holdLeadership refreshes the leader lock until ctx is done. Losing the lock
+// cancels the cycle, since another instance may already be scheduling.
+func (a *application) holdLeadership(ctx context.Context, cancel context.CancelFunc, ttl time.Duration) {
+	ticker := time.NewTicker(ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			held, err := a.leader.Refresh(ctx, ttl)
+			if err != nil {
+				a.logger.Warn("failed to refresh leader lock", zap.Error(err))
+				continue
+			}
+			if !held {
+				a.logger.Error("leader lock lost, abandoning cycle")
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+// distributeCategories publishes one job per category of the run and waits
+// until every category has been recorded by some instance; the workers split
+// categories with several pages into page jobs. The run's totals are then
+// rebuilt from the recorded categories, which are returned.
+func (a *application) distributeCategories(ctx context.Context, run *domain.ScrapeRun, due func(uuid.UUID) bool) ([]domain.ScrapeRunCategory, error) {
+	tasks, err := a.runTasks(ctx, due)
+	if err != nil {
+		return nil, err
+	}
+
+	full := isFullRefresh(ctx)
+	payloads := make([]any, 0, len(tasks))
+	for _, task := range tasks {
+		payloads = append(payloads, categoryJob{RunID: run.ID, Full: full, categoryTask: task})
+	}
+
+	if err := a.rdb.Set(ctx, runRemainingKey(run.ID), len(tasks), runKeyTTL).Err(); err != nil {
+		return nil, fmt.Errorf("track run jobs: %w", err)
+	}
+
+	if err := a.jobs.Enqueue(ctx, payloads...); err != nil {
+		return nil, err
+	}
+
+	a.logger.Info("category jobs published", zap.String("run_id", run.ID.String()), zap.Int("total", len(tasks)))
+
+	err = a.waitForRun(ctx, run.ID)
+	if errors.Is(context.Cause(ctx), errScrapeCancelled) {
+		// Jobs still queued or running elsewhere stop once they see the flag.
+		if cerr := a.rdb.Set(context.WithoutCancel(ctx), runCancelledKey(run.ID), 1, runKeyTTL).Err(); cerr != nil {
+			a.logger.Error("failed to flag run cancelled", zap.String("run_id", run.ID.String()), zap.Error(cerr))
+		}
+	}
+
+	categories, cerr := a.runRepo.GetCategories(context.WithoutCancel(ctx), run.ID)
+	if cerr != nil {
+		return nil, errors.Join(err, fmt.Errorf("load run categories: %w", cerr))
+	}
+	for _, c := range categories {
+		run.AddCategory(c)
+	}
+
+	return categories, err
+}
+
+func (a *application) waitForRun(ctx context.Context, runID uuid.UUID) error {
+	ticker := time.NewTicker(runPollInterval)
+	defer ticker.Stop()
+
+	for {
+		remaining, err := a.rdb.Get(ctx, runRemainingKey(runID)).Int()
+		switch {
+		case errors.Is(err, redis.Nil):
+			return fmt.Errorf("run %s is no longer tracked", runID)
+		case err != nil && ctx.Err() == nil:
+			a.logger.Warn("failed to check run progress", zap.String("run_id", runID.String()), zap.Error(err))
+		case err == nil && remaining <= 0:
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// work leases and runs scrape jobs until ctx is done.
+func (a *application) work(ctx context.Context) {
+	for ctx.Err() == nil {
+		job, err := a.jobs.Lease(ctx)
+		if err != nil {
+			if !errors.Is(err, queue.ErrEmpty) && ctx.Err() == nil {
+				a.logger.Error("failed to lease job", zap.Error(err))
+			}
+
+			select {
+			case <-ctx.Done():
+			case <-time.After(idlePollInterval):
+			}
+			continue
+		}
+
+		a.runJob(ctx, job)
+	}
+}
+
+// runJob scrapes the category or page of a leased job, extending the lease
+// while it runs. A failed job is handed back to the queue until it runs out
+// of attempts. The category is recorded once its job is finished or dead, or,
+// when it was split, once the last of its page jobs is; a page job that
+// failed for good counts as a failed page. Jobs of a cancelled run are
+// dropped, and a running one is stopped.
+func (a *application) runJob(ctx context.Context, job *queue.Job) {
+	bctx := context.WithoutCancel(ctx)
+
+	var j categoryJob
+	if err := json.Unmarshal(job.Payload, &j); err != nil {
+		a.logger.Error("dropping malformed job", zap.String("job_id", job.ID), zap.Error(err))
+		_ = a.jobs.Ack(bctx, job.ID)
+		return
+	}
+
+	if a.runCancelled(ctx, j.RunID) {
+		a.logger.Info("dropping job of cancelled run", zap.String("job_id", job.ID), zap.String("category", j.Category.Name))
+		_ = a.jobs.Ack(bctx, job.ID)
+		if j.Page > 0 {
+			a.finishPage(ctx, j, domain.ScrapeRunCategory{}, errScrapeCancelled)
+		}
+		a.finishJob(bctx, j.RunID)
+		return
+	}
+
+	// Jobs of other runs may be scraping next to this one, so the pages the
+	// job gives up on are collected apart from the sources' shared log.
+	givenUp := &source.GivenUpLog{}
+	jobCtx, cancel := context.WithCancelCause(source.WithGivenUpLog(ctx, givenUp))
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		a.extendLease(jobCtx, cancel, job.ID)
+	}()
+	go func() {
+		defer wg.Done()
+		a.watchRun(jobCtx, cancel, j.RunID)
+	}()
+
+	stats := j.categoryStats()
+
+	var split bool
+	engine, err := a.loadPricing(jobCtx)
+	if err == nil {
+		split, err = a.scrapeJob(withFullRefresh(jobCtx, j.Full), engine, j, &stats)
+	}
+
+	cancel(nil)
+	wg.Wait()
+
+	if errors.Is(context.Cause(jobCtx), errScrapeCancelled) {
+		err = errScrapeCancelled
+	}
+
+	a.saveFailedPages(ctx, j.RunID, givenUp.Drain())
+
+	logger := a.logger.With(
+		zap.String("job_id", job.ID),
+		zap.String("category", j.Category.Name),
+		zap.Int("page", j.Page),
+		zap.Int("attempt", job.Attempts),
+	)
+
+	switch {
+	case errors.Is(err, errScrapeCancelled):
+		logger.Info("scrape job cancelled")
+		if aerr := a.jobs.Ack(bctx, job.ID); aerr != nil {
+			logger.Warn("failed to acknowledge job", zap.Error(aerr))
+			return
+		}
+	case err != nil:
+		dead, nerr := a.jobs.Nack(bctx, job.ID, err.Error())
+		if nerr != nil {
+			logger.Warn("failed to hand back job", zap.NamedError("cause", err), zap.Error(nerr))
+			return
+		}
+		if !dead {
+			logger.Warn("scrape job failed, will be retried", zap.Error(err))
+			return
+		}
+		logger.Error("scrape job failed, giving up", zap.Error(err))
+	default:
+		if aerr := a.jobs.Ack(bctx, job.ID); aerr != nil {
+			logger.Warn("failed to acknowledge job", zap.Error(aerr))
+			return
+		}
+	}
+
+	switch {
+	case j.Page > 0:
+		a.finishPage(ctx, j, stats, err)
+	case !split:
+		a.recordCategory(ctx, &stats, err)
+	}
+	a.finishJob(bctx, j.RunID)
+}
+
+// scrapeJob scrapes the page of a page job, or the first page of a category
+// job. A category with more pages is then split into page jobs, and split is
+// set; if the page jobs cannot be published, the job scrapes the remaining
+// pages itself.
+func (a *application) scrapeJob(ctx context.Context, engine *pricing.Engine, j categoryJob, stats *domain.ScrapeRunCategory) (split bool, err error) {
+	ctx, src, err := a.categorySource(ctx, j.categoryTask)
+	if err != nil {
+		return false, err
+	}
+
+	if j.Page > 0 {
+		return false, a.scrapePage(ctx, engine, src, j.categoryTask, j.Page, stats)
+	}
+
+	if err := a.scrapeFirstPage(ctx, engine, src, j.categoryTask, stats); err != nil {
+		return false, err
+	}
+
+	if stats.PagesTotal > 1 {
+		err := a.splitCategory(ctx, j, stats)
+		if err == nil {
+			return true, nil
+		}
+		a.logger.Warn("failed to publish page jobs, scraping the pages in the category job",
+			zap.String("category", j.Category.Name),
+			zap.Error(err),
+		)
+	}
+
+	return false, a.scrapeRemainingPages(ctx, engine, src, j.categoryTask, stats)
+}
+
+// splitCategory publishes a page job for every page of the category after
+// the first and keeps the stats of the first page until the last page job
+// records the category. The run waits for the page jobs as well. A category
+// job retried after splitting its category publishes nothing again.
+func (a *application) splitCategory(ctx context.Context, j categoryJob, stats *domain.ScrapeRunCategory) error {
+	first, err := json.Marshal(stats)
+	if err != nil {
+		return fmt.Errorf("marshal page stats: %w", err)
+	}
+
+	payloads := make([]any, 0, stats.PagesTotal-1)
+	for page := 2; page <= stats.PagesTotal; page++ {
+		pageJob := j
+		pageJob.Page = page
+		payloads = append(payloads, pageJob)
+	}
+
+	pending, pages, remaining := categoryPendingKey(j.RunID, j.CategoryID), categoryPagesKey(j.RunID, j.CategoryID), runRemainingKey(j.RunID)
+
+	return a.rdb.Watch(ctx, func(tx *redis.Tx) error {
+		n, err := tx.Exists(ctx, pending).Result()
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			return nil
+		}
+
+		if n, err := tx.Exists(ctx, remaining).Result(); err != nil {
+			return err
+		} else if n == 0 {
+			return fmt.Errorf("run %s is no longer tracked", j.RunID)
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.RPush(ctx, pages, first)
+			pipe.Expire(ctx, pages, runKeyTTL)
+			pipe.Set(ctx, pending, len(payloads), runKeyTTL)
+			pipe.IncrBy(ctx, remaining, int64(len(payloads)))
+			return a.jobs.EnqueueTx(ctx, pipe, payloads...)
+		})
+		return err
+	}, pending, remaining)
+}
+
+// finishPageScript appends the stats of a page to its category and returns
+// how many page jobs of the category are left, or -1 when the category is no
+// longer tracked.
+var finishPageScript = redis.NewScript(`
+if tonumber(redis.call('GET', KEYS[1]) or '0') <= 0 then
+	return -1
+end
+redis.call('RPUSH', KEYS[2], ARGV[1])
+return redis.call('DECR', KEYS[1])
+`)
+
+// finishPage adds the stats of a page job to its category, counting a job
+// that failed as a failed page, and records the category once its last page
+// job is done.
+func (a *application) finishPage(ctx context.Context, j categoryJob, stats domain.ScrapeRunCategory, err error) {
+	bctx := context.WithoutCancel(ctx)
+
+	if err != nil {
+		stats.PagesFetched, stats.PagesFailed = 0, 1
+	}
+
+	b, merr := json.Marshal(stats)
+	if merr != nil {
+		a.logger.Error("failed to marshal page stats", zap.String("category", j.Category.Name), zap.Int("page", j.Page), zap.Error(merr))
+		return
+	}
+
+	left, serr := finishPageScript.Run(bctx, a.rdb,
+		[]string{categoryPendingKey(j.RunID, j.CategoryID), categoryPagesKey(j.RunID, j.CategoryID)},
+		b,
+	).Int()
+	if serr != nil {
+		a.logger.Error("failed to tally page", zap.String("category", j.Category.Name), zap.Int("page", j.Page), zap.Error(serr))
+		return
+	}
+	if left != 0 {
+		return
+	}
+
+	a.recordSplitCategory(ctx, j)
+}
+
+// recordSplitCategory records a split category from the stats of its pages.
+func (a *application) recordSplitCategory(ctx context.Context, j categoryJob) {
+	bctx := context.WithoutCancel(ctx)
+	pagesKey := categoryPagesKey(j.RunID, j.CategoryID)
+
+	stats := j.categoryStats()
+
+	raw, err := a.rdb.LRange(bctx, pagesKey, 0, -1).Result()
+	if err == nil && len(raw) == 0 {
+		err = errors.New("no page stats")
+	}
+	if err != nil {
+		a.recordCategory(ctx, &stats, fmt.Errorf("load page stats: %w", err))
+		return
+	}
+
+	for i, r := range raw {
+		var page domain.ScrapeRunCategory
+		if err := json.Unmarshal([]byte(r), &page); err != nil {
+			a.logger.Error("malformed page stats", zap.String("category", j.Category.Name), zap.Error(err))
+			stats.PagesFailed++
+			continue
+		}
+		if i == 0 {
+			stats.StartedAt = page.StartedAt
+			stats.PagesTotal = page.PagesTotal
+			stats.ProductsExpected = page.ProductsExpected
+		}
+		stats.AddPages(page)
+	}
+
+	a.checkDrift(&stats, stats.PagesFailed == 0)
+	a.recordCategory(ctx, &stats, nil)
+
+	if err := a.rdb.Del(bctx, pagesKey).Err(); err != nil {
+		a.logger.Warn("failed to clean up page stats", zap.String("category", j.Category.Name), zap.Error(err))
+	}
+}
+
+// categorySplit reports whether the category of a category job was split
+// into page jobs, which then record it.
+func (a *application) categorySplit(ctx context.Context, j categoryJob) bool {
+	n, err := a.rdb.Exists(ctx, categoryPendingKey(j.RunID, j.CategoryID)).Result()
+	if err != nil {
+		a.logger.Warn("failed to check whether a category was split", zap.String("category", j.Category.Name), zap.Error(err))
+		return false
+	}
+	return n > 0
+}
+
+// extendLease keeps the job's lease alive until ctx is done. A lost lease
+// cancels the job, since another worker may have taken it over.
+func (a *application) extendLease(ctx context.Context, cancel context.CancelCauseFunc, jobID string) {
+	ticker := time.NewTicker(a.jobs.Visibility() / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := a.jobs.Extend(ctx, jobID)
+			if errors.Is(err, queue.ErrLeaseLost) {
+				a.logger.Warn("job lease lost, abandoning job", zap.String("job_id", jobID))
+				cancel(err)
+				return
+			}
+			if err != nil && ctx.Err() == nil {
+				a.logger.Warn("failed to extend job lease", zap.String("job_id", jobID), zap.Error(err))
+			}
+		}
+	}
+}
+
+// watchRun cancels the job once its run is cancelled.
+func (a *application) watchRun(ctx context.Context, cancel context.CancelCauseFunc, runID uuid.UUID) {
+	ticker := time.NewTicker(commandPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if a.runCancelled(ctx, runID) {
+				cancel(errScrapeCancelled)
+				return
+			}
+		}
+	}
+}
+
+// runCancelled reports whether the run was cancelled through a scrape
+// request.
+func (a *application) runCancelled(ctx context.Context, runID uuid.UUID) bool {
+	n, err := a.rdb.Exists(ctx, runCancelledKey(runID)).Result()
+	if err != nil {
+		if ctx.Err() == nil {
+			a.logger.Warn("failed to read run cancellation flag", zap.String("run_id", runID.String()), zap.Error(err))
+		}
+		return false
+	}
+	return n > 0
+}
+
+// reapLoop hands jobs of crashed or stalled workers back to the queue and
+// records the categories or pages of jobs that ran out of attempts.
+func (a *application) reapLoop(ctx context.Context) {
+	ticker := time.NewTicker(a.jobs.Visibility() / 4)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		dead, err := a.jobs.Reap(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				a.logger.Error("failed to reap expired jobs", zap.Error(err))
+			}
+			continue
+		}
+
+		for _, d := range dead {
+			var j categoryJob
+			if err := json.Unmarshal(d.Payload, &j); err != nil {
+				a.logger.Error("dead job has malformed payload", zap.String("job_id", d.ID), zap.Error(err))
+				continue
+			}
+
+			a.logger.Error("scrape job expired, giving up",
+				zap.String("job_id", d.ID),
+				zap.String("category", j.Category.Name),
+				zap.Int("page", j.Page),
+				zap.Int("attempts", d.Attempts),
+			)
+
+			err := fmt.Errorf("%s after %d attempts", d.Reason, d.Attempts)
+			switch {
+			case j.Page > 0:
+				a.finishPage(ctx, j, domain.ScrapeRunCategory{}, err)
+			case !a.categorySplit(ctx, j):
+				stats := j.categoryStats()
+				a.recordCategory(ctx, &stats, err)
+			}
+			a.finishJob(context.WithoutCancel(ctx), j.RunID)
+		}
+	}
+}
+
+// finishJobScript decrements the remaining jobs of a run only while the
+// counter exists: a job finishing after its run expired must not recreate
+// the key without a TTL.
+var finishJobScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return 0
+end
+return redis.call('DECR', KEYS[1])
+`)
+
+// finishJob counts a job of the run as done.
+func (a *application) finishJob(ctx context.Context, runID uuid.UUID) {
+	if err := finishJobScript.Run(ctx, a.rdb, []string{runRemainingKey(runID)}).Err(); err != nil {
+		a.logger.Error("failed to update run progress", zap.String("run_id", runID.String()), zap.Error(err))
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"github.com/burbble/marketplace/internal/domain"
+	"github.com/burbble/marketplace/internal/mocks"
+	"github.com/burbble/marketplace/internal/scraper/queue"
+	"github.com/burbble/marketplace/internal/scraper/source"
+)
+
+func TestFinishJob(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+
+	a := &application{logger: zap.NewNop(), rdb: rdb}
+	ctx := context.Background()
+
+	t.Run("decrements the remaining jobs", func(t *testing.T) {
+		runID := uuid.New()
+		if err := rdb.Set(ctx, runRemainingKey(runID), 2, runKeyTTL).Err(); err != nil {
+			t.Fatal(err)
+		}
+
+		a.finishJob(ctx, runID)
+
+		remaining, err := rdb.Get(ctx, runRemainingKey(runID)).Int()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if remaining != 1 {
+			t.Errorf("expected 1 remaining job, got %d", remaining)
+		}
+		if ttl := mr.TTL(runRemainingKey(runID)); ttl <= 0 {
+			t.Errorf("expected the counter to keep its TTL, got %v", ttl)
+		}
+	})
+
+	t.Run("leaves an expired run alone", func(t *testing.T) {
+		runID := uuid.New()
+
+		a.finishJob(ctx, runID)
+
+		if mr.Exists(runRemainingKey(runID)) {
+			t.Error("expected no counter to be created for an expired run")
+		}
+	})
+}
+
+func TestSplitCategory_RecordsAfterLastPage(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+
+	var recorded []domain.ScrapeRunCategory
+	a := &application{
+		logger: zap.NewNop(),
+		rdb:    rdb,
+		jobs:   queue.New(rdb, queue.Config{Name: "test:jobs", Visibility: time.Minute}),
+		runRepo: &mocks.ScrapeRunRepositoryMock{
+			AddCategoryFunc: func(_ context.Context, c *domain.ScrapeRunCategory) error {
+				recorded = append(recorded, *c)
+				return nil
+			},
+		},
+	}
+	ctx := context.Background()
+
+	j := categoryJob{RunID: uuid.New(), categoryTask: categoryTask{Category: source.Category{Name: "phones"}, CategoryID: uuid.New()}}
+	if err := rdb.Set(ctx, runRemainingKey(j.RunID), 1, runKeyTTL).Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	first := j.categoryStats()
+	first.PagesTotal, first.PagesFetched, first.ProductsUpserted = 3, 1, 10
+
+	for range 2 {
+		if err := a.splitCategory(ctx, j, &first); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	stats, err := a.jobs.Stats(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Pending != 2 {
+		t.Fatalf("expected one job per further page, got %d", stats.Pending)
+	}
+	job, err := a.jobs.Lease(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var leased categoryJob
+	if err := json.Unmarshal(job.Payload, &leased); err != nil || leased.Page != 2 || leased.CategoryID != j.CategoryID {
+		t.Errorf("expected the job of page 2, got %+v (%v)", leased, err)
+	}
+	if remaining, _ := rdb.Get(ctx, runRemainingKey(j.RunID)).Int(); remaining != 3 {
+		t.Errorf("expected the run to wait for the page jobs, got %d remaining", remaining)
+	}
+
+	second := j
+	second.Page = 2
+	a.finishPage(ctx, second, domain.ScrapeRunCategory{PagesFetched: 1, ProductsUpserted: 10}, nil)
+	if len(recorded) != 0 {
+		t.Fatalf("expected the category to wait for its last page, got %+v", recorded)
+	}
+
+	third := j
+	third.Page = 3
+	a.finishPage(ctx, third, domain.ScrapeRunCategory{PagesFetched: 1}, errors.New("list page 3: timeout"))
+
+	if len(recorded) != 1 {
+		t.Fatalf("expected the category to be recorded once, got %d", len(recorded))
+	}
+	c := recorded[0]
+	if c.PagesTotal != 3 || c.PagesFetched != 2 || c.PagesFailed != 1 || c.ProductsUpserted != 20 || c.Status != domain.ScrapeStatusPartial {
+		t.Errorf("expected the pages folded into a partial category, got %+v", c)
+	}
+
+	if err := a.splitCategory(ctx, j, &first); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats, _ := a.jobs.Stats(ctx); stats.Pending != 1 {
+		t.Errorf("expected a retried category job not to split the category again, got %d pending", stats.Pending)
+	}
+}
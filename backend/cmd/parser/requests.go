@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/burbble/marketplace/internal/domain"
+)
+
+// commandPollInterval is how often the parser checks for new scrape requests
+// and for cancellation of the running one.
+const commandPollInterval = 5 * time.Second
+
+// errScrapeCancelled is the cause of a run stopped through the admin API.
+var errScrapeCancelled = errors.New("scrape cancelled by request")
+
+// errRequestAbandoned is recorded on running requests whose lease expired,
+// because the parser running them stopped.
+var errRequestAbandoned = errors.New("abandoned: the parser stopped before the request finished")
+
+// runRequests finishes the requests abandoned by stopped parsers, then runs
+// the pending scrape requests, oldest first. Requests are explicit, so they
+// are run during quiet hours too.
+func (a *application) runRequests(ctx context.Context) {
+	expired, err := a.requestRepo.FailExpired(ctx, errRequestAbandoned.Error())
+	if err != nil {
+		if ctx.Err() == nil {
+			a.logger.Warn("failed to close abandoned scrape requests", zap.Error(err))
+		}
+	} else if expired > 0 {
+		a.logger.Warn("abandoned scrape requests finished", zap.Int64("count", expired))
+	}
+
+	for ctx.Err() == nil {
+		req, err := a.requestRepo.ClaimPending(ctx, a.cfg.RequestLease)
+		if errors.Is(err, sql.ErrNoRows) {
+			return
+		}
+		if err != nil {
+			if ctx.Err() == nil {
+				a.logger.Error("failed to claim scrape request", zap.Error(err))
+			}
+			return
+		}
+
+		a.runRequest(ctx, req)
+	}
+}
+
+// runRequest runs one claimed request and records how it ended. The run is
+// cancelled as soon as the request is.
+func (a *application) runRequest(ctx context.Context, req *domain.ScrapeRequest) {
+	bctx := context.WithoutCancel(ctx)
+	logger := a.logger.With(zap.String("request_id", req.ID.String()))
+
+	logger.Info("scrape requested",
+		zap.Int("categories", len(req.CategoryIDs)),
+		zap.Bool("full_refresh", req.FullRefresh),
+	)
+
+	runCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	done := make(chan struct{})
+	defer func() { <-done }()
+	go func() {
+		defer close(done)
+		a.watchRequest(runCtx, cancel, req.ID)
+	}()
+
+	due, err := a.requestedCategories(runCtx, req.CategoryIDs)
+	if err == nil {
+		runCtx = a.takeFullRefresh(withFullRefresh(runCtx, req.FullRefresh))
+
+		var run *domain.ScrapeRun
+		run, err = a.startRun(runCtx)
+		if err == nil {
+			if serr := a.requestRepo.SetRun(bctx, req.ID, run.ID); serr != nil {
+				logger.Warn("failed to link scrape request to run", zap.Error(serr))
+			}
+			err = a.executeRun(runCtx, run, due)
+		}
+	}
+	cancel(nil)
+
+	now := time.Now()
+	req.FinishedAt = &now
+
+	switch {
+	case errors.Is(context.Cause(runCtx), errScrapeCancelled):
+		req.Status = domain.ScrapeRequestCancelled
+	case err != nil:
+		msg := err.Error()
+		req.Status = domain.ScrapeRequestFailed
+		req.Error = &msg
+	default:
+		req.Status = domain.ScrapeRequestCompleted
+	}
+
+	if ferr := a.requestRepo.Finish(bctx, req); ferr != nil {
+		logger.Error("failed to record scrape request", zap.Error(ferr))
+	}
+
+	logger.Info("scrape request finished", zap.String("status", req.Status))
+}
+
+// watchRequest extends the request's lease while it runs and cancels the
+// run once cancellation is requested.
+func (a *application) watchRequest(ctx context.Context, cancel context.CancelCauseFunc, id uuid.UUID) {
+	ticker := time.NewTicker(commandPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		cancelled, err := a.requestRepo.Heartbeat(ctx, id, a.cfg.RequestLease)
+		if err != nil {
+			if ctx.Err() == nil {
+				a.logger.Warn("failed to extend scrape request lease", zap.String("request_id", id.String()), zap.Error(err))
+			}
+			continue
+		}
+		if cancelled {
+			a.logger.Info("scrape request cancelled, stopping run", zap.String("request_id", id.String()))
+			cancel(errScrapeCancelled)
+			return
+		}
+	}
+}
+
+// requestedCategories returns a filter matching the given categories and
+// all their subcategories, or nil for the whole catalog when ids is empty.
+func (a *application) requestedCategories(ctx context.Context, ids []uuid.UUID) (func(uuid.UUID) bool, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	categories, err := a.categoryRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := descendants(categories, ids)
+	return func(id uuid.UUID) bool {
+		_, ok := selected[id]
+		return ok
+	}, nil
+}
+
+// descendants returns the given category ids together with the ids of all
+// categories below them.
+func descendants(categories []domain.Category, ids []uuid.UUID) map[uuid.UUID]struct{} {
+	children := make(map[uuid.UUID][]uuid.UUID, len(categories))
+	for _, c := range categories {
+		if c.ParentID != nil {
+			children[*c.ParentID] = append(children[*c.ParentID], c.ID)
+		}
+	}
+
+	selected := make(map[uuid.UUID]struct{}, len(ids))
+	stack := append([]uuid.UUID(nil), ids...)
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if _, ok := selected[id]; ok {
+			continue
+		}
+		selected[id] = struct{}{}
+		stack = append(stack, children[id]...)
+	}
+
+	return selected
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/burbble/marketplace/internal/domain"
+	"github.com/burbble/marketplace/internal/mocks"
+)
+
+func TestRunRequests_FinishesAbandonedRequestsFirst(t *testing.T) {
+	p := newTestParser(t)
+	p.app.cfg.RequestLease = time.Minute
+
+	var order []string
+	requests := &mocks.ScrapeRequestRepositoryMock{
+		FailExpiredFunc: func(_ context.Context, reason string) (int64, error) {
+			order = append(order, "fail expired")
+			if reason != errRequestAbandoned.Error() {
+				t.Errorf("expected reason %q, got %q", errRequestAbandoned, reason)
+			}
+			return 1, nil
+		},
+		ClaimPendingFunc: func(_ context.Context, lease time.Duration) (*domain.ScrapeRequest, error) {
+			order = append(order, "claim")
+			if lease != time.Minute {
+				t.Errorf("expected a 1m lease, got %v", lease)
+			}
+			return nil, sql.ErrNoRows
+		},
+	}
+	p.app.requestRepo = requests
+
+	p.app.runRequests(context.Background())
+
+	if len(order) != 2 || order[0] != "fail expired" || order[1] != "claim" {
+		t.Errorf("expected abandoned requests to be finished before claiming, got %v", order)
+	}
+}
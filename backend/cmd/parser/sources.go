@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/burbble/marketplace/internal/config"
+	"github.com/burbble/marketplace/internal/scraper/proxy"
+	"github.com/burbble/marketplace/internal/scraper/snapshot"
+	"github.com/burbble/marketplace/internal/scraper/source"
+	"github.com/burbble/marketplace/internal/scraper/store77"
+	"github.com/burbble/marketplace/internal/scraper/throttle"
+)
+
+// sourceDeps is what every source is built from. Sources share the proxy
+// pool and the rate limiter, so SCRAPE_RATE_LIMIT_RPS bounds the parser as a
+// whole.
+type sourceDeps struct {
+	logger  *zap.Logger
+	cfg     *config.Config
+	proxies *proxy.Pool
+	limiter *throttle.Limiter
+	// replay is set when pages are served from a recorded snapshot.
+	replay *snapshot.Replayer
+}
+
+// sourceFactories builds the sources SCRAPE_SOURCES can name. A new shop is
+// added by implementing source.Source and registering it here.
+var sourceFactories = map[string]func(d sourceDeps) source.Source{
+	store77.SourceName: newStore77,
+}
+
+func newStore77(d sourceDeps) source.Source {
+	cfg := store77.Config{
+		RetryAttempts:  d.cfg.RetryAttempts,
+		RetryBaseDelay: d.cfg.RetryBaseDelay,
+		RetryMaxDelay:  d.cfg.RetryMaxDelay,
+		Proxies:        d.proxies,
+		Limiter:        d.limiter,
+	}
+	if d.replay != nil {
+		cfg.HTTPFetcher = d.replay
+		cfg.BrowserFetcher = d.replay
+	}
+
+	return store77.NewScraper(d.logger.With(zap.String("source", store77.SourceName)), cfg)
+}
+
+// newSources builds the comma-separated sources of SCRAPE_SOURCES in the
+// order listed.
+func newSources(names string, d sourceDeps) ([]source.Source, error) {
+	var sources []source.Source
+	seen := make(map[string]bool)
+	for name := range strings.SplitSeq(names, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		factory, ok := sourceFactories[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown source %q", name)
+		}
+		sources = append(sources, factory(d))
+	}
+
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("no sources configured")
+	}
+
+	return sources, nil
+}
+
+// sourceByName returns the configured source with the given name. An empty
+// name is store77: jobs queued before sources were introduced carry none.
+func (a *application) sourceByName(name string) (source.Source, error) {
+	if name == "" {
+		name = store77.SourceName
+	}
+
+	for _, src := range a.sources {
+		if src.Name() == name {
+			return src, nil
+		}
+	}
+
+	return nil, fmt.Errorf("source %q is not enabled", name)
+}
+
+// stopSources releases what the sources hold between runs, such as browsers.
+func (a *application) stopSources() {
+	for _, src := range a.sources {
+		src.Stop()
+	}
+}
@@ -3,23 +3,30 @@ package main
 import (
 	"context"
 	"fmt"
-	"os"
 	"time"
 
 	"go.uber.org/zap"
 
+	"github.com/burbble/marketplace/internal/scraper/snapshot"
 	"github.com/burbble/marketplace/internal/scraper/store77"
 )
 
 func main() {
 	lg, _ := zap.NewDevelopment()
 
-	scraper := store77.NewScraper(lg)
+	scraper := store77.NewScraper(lg, store77.Config{RetryAttempts: 1})
 	if err := scraper.Start(); err != nil {
 		lg.Fatal("failed to start scraper", zap.Error(err))
 	}
 	defer scraper.Stop()
 
+	rec, err := snapshot.NewRecorder("snapshots", "scrapetest", time.Now())
+	if err != nil {
+		lg.Fatal("failed to create snapshot", zap.Error(err))
+	}
+	defer func() { _ = rec.Close() }()
+	scraper.SetRecorder(rec)
+
 	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
 	defer cancel()
 
@@ -28,7 +35,6 @@ func main() {
 	if err != nil {
 		lg.Fatal("failed to fetch main page", zap.Error(err))
 	}
-	_ = os.WriteFile("store77_dump.html", []byte(html), 0644)
 
 	categories, err := store77.ParseCategories(html)
 	if err != nil {
@@ -44,12 +50,10 @@ func main() {
 		fmt.Printf("  [%d] %s → %s\n", i+1, c.Name, c.URL)
 	}
 
-	catURL := "https://store77.net/telefony_apple/"
-	catHTML, err := scraper.FetchPageHTML(ctx, catURL)
+	catHTML, err := scraper.FetchCategoryPage(ctx, "/telefony_apple/", 1)
 	if err != nil {
 		lg.Fatal("failed to fetch category page", zap.Error(err))
 	}
-	_ = os.WriteFile("store77_category_dump.html", []byte(catHTML), 0644)
 
 	products, err := store77.ParseProducts(catHTML)
 	if err != nil {
@@ -69,4 +73,6 @@ func main() {
 		fmt.Printf("       URL: %s\n", p.ProductURL)
 		fmt.Printf("       Category: %s\n", p.Category)
 	}
+
+	fmt.Printf("\nsnapshot: %s\n", rec.Dir())
 }
@@ -0,0 +1,154 @@
+package attribute
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/burbble/marketplace/internal/domain"
+)
+
+// nameAliases maps spellings of the same specification to one name.
+var nameAliases = map[string]string{
+	"память":                  "встроенная память",
+	"объем памяти":            "встроенная память",
+	"объем встроенной памяти": "встроенная память",
+	"встроенная память (rom)": "встроенная память",
+	"rom": "встроенная память",
+	"ram": "оперативная память",
+	"объем оперативной памяти": "оперативная память",
+	"оперативная память (ram)": "оперативная память",
+	"цвет корпуса":             "цвет",
+	"цвет товара":              "цвет",
+	"диагональ":                "диагональ экрана",
+	"диагональ дисплея":        "диагональ экрана",
+	"аккумулятор":              "емкость аккумулятора",
+	"емкость батареи":          "емкость аккумулятора",
+}
+
+// unitAliases maps unit spellings to the one used in normalized values.
+var unitAliases = map[string]string{
+	"gb": "гб", "гб": "гб", "гбайт": "гб", "gbyte": "гб",
+	"tb": "тб", "тб": "тб", "тбайт": "тб",
+	"mb": "мб", "мб": "мб", "мбайт": "мб",
+	"mah": "мач", "мач": "мач",
+	"hz": "гц", "гц": "гц",
+	"ghz": "ггц", "ггц": "ггц",
+	"mp": "мп", "мп": "мп", "мпикс": "мп",
+	"w": "вт", "вт": "вт",
+	"mm": "мм", "мм": "мм",
+	`"`: `"`, "”": `"`, "''": `"`, "дюйм": `"`, "дюйма": `"`, "дюймов": `"`, "inch": `"`, "inches": `"`,
+}
+
+// valueAliases unifies whole values, mostly color names given in English.
+var valueAliases = map[string]string{
+	"black":      "черный",
+	"white":      "белый",
+	"blue":       "синий",
+	"red":        "красный",
+	"green":      "зеленый",
+	"yellow":     "желтый",
+	"pink":       "розовый",
+	"purple":     "фиолетовый",
+	"gold":       "золотой",
+	"золотистый": "золотой",
+	"silver":     "серебристый",
+	"серебряный": "серебристый",
+	"gray":       "серый",
+	"grey":       "серый",
+	"да":         "есть",
+	"yes":        "есть",
+	"no":         "нет",
+}
+
+var (
+	spacesRe       = regexp.MustCompile(`\s+`)
+	decimalCommaRe = regexp.MustCompile(`(\d),(\d)`)
+	quantityRe     = regexp.MustCompile(`(\d+(?:\.\d+)?)\s*(\p{L}+|"|”|'')`)
+)
+
+func clean(s string) string {
+	s = strings.ToLower(s)
+	s = strings.ReplaceAll(s, "ё", "е")
+	s = strings.ReplaceAll(s, "\u00a0", " ")
+	return strings.TrimSpace(spacesRe.ReplaceAllString(s, " "))
+}
+
+// Name returns the normalized name of a specification: lower case, "ё"
+// spelled "е", and known synonyms such as "Объём памяти" and "Встроенная
+// память" merged.
+func Name(s string) string {
+	n := strings.TrimSuffix(clean(s), ":")
+	n = strings.TrimSpace(n)
+	if alias, ok := nameAliases[n]; ok {
+		return alias
+	}
+	return n
+}
+
+// Value returns the normalized value of a specification: lower case, "ё"
+// spelled "е", decimal commas replaced with points, units written one way
+// ("256GB" and "256 Гб" both become "256 гб") and English color names
+// translated.
+func Value(s string) string {
+	v := clean(s)
+	v = decimalCommaRe.ReplaceAllString(v, "$1.$2")
+
+	v = quantityRe.ReplaceAllStringFunc(v, func(m string) string {
+		parts := quantityRe.FindStringSubmatch(m)
+		unit, ok := unitAliases[parts[2]]
+		if !ok {
+			return m
+		}
+		if unit == `"` {
+			return parts[1] + unit
+		}
+		return parts[1] + " " + unit
+	})
+
+	if alias, ok := valueAliases[v]; ok {
+		return alias
+	}
+	return v
+}
+
+// Index maps the normalized name of each attribute to its normalized value,
+// the form products are filtered by. When a name repeats, the first value is
+// kept.
+func Index(attrs []domain.ProductAttribute) map[string]string {
+	index := make(map[string]string, len(attrs))
+	for _, a := range attrs {
+		name, value := Name(a.Name), Value(a.Value)
+		if name == "" || value == "" {
+			continue
+		}
+		if _, ok := index[name]; !ok {
+			index[name] = value
+		}
+	}
+	return index
+}
+
+// Filter normalizes the names and values of an attribute filter, merging
+// names that normalize alike and dropping duplicate values.
+func Filter(filter map[string][]string) map[string][]string {
+	if len(filter) == 0 {
+		return nil
+	}
+
+	out := make(map[string][]string, len(filter))
+	for name, values := range filter {
+		n := Name(name)
+		if n == "" {
+			continue
+		}
+		for _, value := range values {
+			v := Value(value)
+			if v == "" || slices.Contains(out[n], v) {
+				continue
+			}
+			out[n] = append(out[n], v)
+		}
+	}
+	return out
+}
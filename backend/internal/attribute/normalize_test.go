@@ -0,0 +1,104 @@
+package attribute
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/burbble/marketplace/internal/domain"
+)
+
+func TestName(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Объём памяти", "встроенная память"},
+		{"Встроенная   память:", "встроенная память"},
+		{"Цвет корпуса", "цвет"},
+		{"Диагональ", "диагональ экрана"},
+		{"Процессор", "процессор"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := Name(tt.input); got != tt.expected {
+				t.Errorf("Name(%q) = %q, expected %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestValue(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"256GB", "256 гб"},
+		{"256 Гб", "256 гб"},
+		{"256 ГБ", "256 гб"},
+		{"1 TB", "1 тб"},
+		{`6,1"`, `6.1"`},
+		{"6.1 дюйма", `6.1"`},
+		{"4000 mAh", "4000 мач"},
+		{"Чёрный", "черный"},
+		{"Black", "черный"},
+		{"5G", "5g"},
+		{"Apple A17 Pro", "apple a17 pro"},
+		{"2556 x 1179", "2556 x 1179"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := Value(tt.input); got != tt.expected {
+				t.Errorf("Value(%q) = %q, expected %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIndex(t *testing.T) {
+	got := Index([]domain.ProductAttribute{
+		{Group: "Память", Name: "Объём памяти", Value: "256GB"},
+		{Name: "Встроенная память", Value: "512 ГБ"},
+		{Name: "Цвет", Value: "Чёрный"},
+		{Name: "Пусто", Value: " "},
+	})
+
+	expected := map[string]string{
+		"встроенная память": "256 гб",
+		"цвет":              "черный",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Index() = %v, expected %v", got, expected)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	got := Filter(map[string][]string{
+		"Объём памяти":      {"256GB", "256 гб"},
+		"Встроенная память": {"512 Гб"},
+		"Цвет":              {"black", ""},
+		" ":                 {"x"},
+	})
+
+	expected := map[string][]string{
+		"встроенная память": {"256 гб", "512 гб"},
+		"цвет":              {"черный"},
+	}
+	if len(got) != len(expected) || !reflect.DeepEqual(got["цвет"], expected["цвет"]) {
+		t.Fatalf("Filter() = %v, expected %v", got, expected)
+	}
+
+	// Both spellings of the memory name merge; map order decides which
+	// values come first.
+	memory := got["встроенная память"]
+	if len(memory) != 2 || !slices.Contains(memory, "256 гб") || !slices.Contains(memory, "512 гб") {
+		t.Errorf("unexpected memory values %v", memory)
+	}
+
+	if Filter(nil) != nil {
+		t.Error("expected nil for an empty filter")
+	}
+}
@@ -57,11 +57,37 @@ type HTTPConfig struct {
 	GinMode        string `mapstructure:"GIN_MODE"`
 	RateLimitRPS   int    `mapstructure:"RATE_LIMIT_RPS"`
 	RateLimitBurst int    `mapstructure:"RATE_LIMIT_BURST"`
+	AdminToken     string `mapstructure:"ADMIN_API_TOKEN"`
 }
 
 type ParserConfig struct {
-	ScrapeInterval time.Duration `mapstructure:"SCRAPE_INTERVAL"`
-	ScrapeWorkers  int           `mapstructure:"SCRAPE_WORKERS"`
+	Sources                string        `mapstructure:"SCRAPE_SOURCES"`
+	ScrapeInterval         time.Duration `mapstructure:"SCRAPE_INTERVAL"`
+	ScrapeWorkers          int           `mapstructure:"SCRAPE_WORKERS"`
+	UnavailableGracePeriod time.Duration `mapstructure:"UNAVAILABLE_GRACE_PERIOD"`
+	RetryAttempts          int           `mapstructure:"SCRAPE_RETRY_ATTEMPTS"`
+	RetryBaseDelay         time.Duration `mapstructure:"SCRAPE_RETRY_BASE_DELAY"`
+	RetryMaxDelay          time.Duration `mapstructure:"SCRAPE_RETRY_MAX_DELAY"`
+	Proxies                string        `mapstructure:"SCRAPE_PROXIES"`
+	ProxyFile              string        `mapstructure:"SCRAPE_PROXY_FILE"`
+	ProxyStrategy          string        `mapstructure:"SCRAPE_PROXY_STRATEGY"`
+	ProxyMaxFailures       int           `mapstructure:"SCRAPE_PROXY_MAX_FAILURES"`
+	ProxyBenchDuration     time.Duration `mapstructure:"SCRAPE_PROXY_BENCH_DURATION"`
+	ScrapeRPS              float64       `mapstructure:"SCRAPE_RATE_LIMIT_RPS"`
+	ScrapeBurst            int           `mapstructure:"SCRAPE_RATE_LIMIT_BURST"`
+	HostRateLimits         string        `mapstructure:"SCRAPE_HOST_RATE_LIMITS"`
+	SnapshotDir            string        `mapstructure:"SCRAPE_SNAPSHOT_DIR"`
+	ReplayDir              string        `mapstructure:"SCRAPE_REPLAY_DIR"`
+	DescriptionTTL         time.Duration `mapstructure:"SCRAPE_DESCRIPTION_TTL"`
+	DetailRefreshInterval  time.Duration `mapstructure:"SCRAPE_DETAIL_REFRESH_INTERVAL"`
+	QueueEnabled           bool          `mapstructure:"SCRAPE_QUEUE_ENABLED"`
+	QueueVisibility        time.Duration `mapstructure:"SCRAPE_QUEUE_VISIBILITY_TIMEOUT"`
+	QueueMaxAttempts       int           `mapstructure:"SCRAPE_QUEUE_MAX_ATTEMPTS"`
+	LeaderLockTTL          time.Duration `mapstructure:"SCRAPE_LEADER_LOCK_TTL"`
+	RequestLease           time.Duration `mapstructure:"SCRAPE_REQUEST_LEASE"`
+	QuietHours             string        `mapstructure:"SCRAPE_QUIET_HOURS"`
+	ScheduleTimezone       string        `mapstructure:"SCRAPE_TIMEZONE"`
+	MatchMinConfidence     float64       `mapstructure:"SCRAPE_MATCH_MIN_CONFIDENCE"`
 }
 
 func LoadFromFlags(cfg *Config) error {
@@ -120,9 +146,35 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("GIN_MODE", "debug")
 	v.SetDefault("RATE_LIMIT_RPS", 100)
 	v.SetDefault("RATE_LIMIT_BURST", 200)
+	v.SetDefault("ADMIN_API_TOKEN", "")
 
+	v.SetDefault("SCRAPE_SOURCES", "store77")
 	v.SetDefault("SCRAPE_INTERVAL", 10*time.Minute)
 	v.SetDefault("SCRAPE_WORKERS", 5)
+	v.SetDefault("UNAVAILABLE_GRACE_PERIOD", 24*time.Hour)
+	v.SetDefault("SCRAPE_RETRY_ATTEMPTS", 3)
+	v.SetDefault("SCRAPE_RETRY_BASE_DELAY", 2*time.Second)
+	v.SetDefault("SCRAPE_RETRY_MAX_DELAY", 30*time.Second)
+	v.SetDefault("SCRAPE_PROXIES", "")
+	v.SetDefault("SCRAPE_PROXY_FILE", "")
+	v.SetDefault("SCRAPE_PROXY_STRATEGY", "round_robin")
+	v.SetDefault("SCRAPE_PROXY_MAX_FAILURES", 3)
+	v.SetDefault("SCRAPE_PROXY_BENCH_DURATION", 5*time.Minute)
+	v.SetDefault("SCRAPE_RATE_LIMIT_RPS", 5.0)
+	v.SetDefault("SCRAPE_RATE_LIMIT_BURST", 5)
+	v.SetDefault("SCRAPE_HOST_RATE_LIMITS", "")
+	v.SetDefault("SCRAPE_SNAPSHOT_DIR", "")
+	v.SetDefault("SCRAPE_REPLAY_DIR", "")
+	v.SetDefault("SCRAPE_DESCRIPTION_TTL", 7*24*time.Hour)
+	v.SetDefault("SCRAPE_DETAIL_REFRESH_INTERVAL", 24*time.Hour)
+	v.SetDefault("SCRAPE_QUEUE_ENABLED", false)
+	v.SetDefault("SCRAPE_QUEUE_VISIBILITY_TIMEOUT", 10*time.Minute)
+	v.SetDefault("SCRAPE_QUEUE_MAX_ATTEMPTS", 3)
+	v.SetDefault("SCRAPE_LEADER_LOCK_TTL", 30*time.Second)
+	v.SetDefault("SCRAPE_REQUEST_LEASE", time.Minute)
+	v.SetDefault("SCRAPE_QUIET_HOURS", "")
+	v.SetDefault("SCRAPE_TIMEZONE", "Europe/Moscow")
+	v.SetDefault("SCRAPE_MATCH_MIN_CONFIDENCE", 0.8)
 }
 
 func (c *BaseConfig) IsDevEnv() bool {
@@ -53,12 +53,72 @@ func TestLoad_Defaults(t *testing.T) {
 	if cfg.RateLimitRPS != 100 {
 		t.Errorf("expected RateLimitRPS 100, got %d", cfg.RateLimitRPS)
 	}
+	if cfg.AdminToken != "" {
+		t.Errorf("expected empty AdminToken, got %q", cfg.AdminToken)
+	}
+	if cfg.Sources != "store77" {
+		t.Errorf("expected Sources 'store77', got %q", cfg.Sources)
+	}
 	if cfg.ScrapeInterval != 10*time.Minute {
 		t.Errorf("expected ScrapeInterval 10m, got %v", cfg.ScrapeInterval)
 	}
 	if cfg.ScrapeWorkers != 5 {
 		t.Errorf("expected ScrapeWorkers 5, got %d", cfg.ScrapeWorkers)
 	}
+	if cfg.UnavailableGracePeriod != 24*time.Hour {
+		t.Errorf("expected UnavailableGracePeriod 24h, got %v", cfg.UnavailableGracePeriod)
+	}
+	if cfg.RetryAttempts != 3 {
+		t.Errorf("expected RetryAttempts 3, got %d", cfg.RetryAttempts)
+	}
+	if cfg.RetryBaseDelay != 2*time.Second || cfg.RetryMaxDelay != 30*time.Second {
+		t.Errorf("expected retry delays 2s/30s, got %v/%v", cfg.RetryBaseDelay, cfg.RetryMaxDelay)
+	}
+	if cfg.Proxies != "" || cfg.ProxyFile != "" {
+		t.Errorf("expected no proxies by default, got %q / %q", cfg.Proxies, cfg.ProxyFile)
+	}
+	if cfg.ProxyStrategy != "round_robin" {
+		t.Errorf("expected ProxyStrategy 'round_robin', got %q", cfg.ProxyStrategy)
+	}
+	if cfg.ProxyMaxFailures != 3 || cfg.ProxyBenchDuration != 5*time.Minute {
+		t.Errorf("expected proxy bench after 3 failures for 5m, got %d / %v", cfg.ProxyMaxFailures, cfg.ProxyBenchDuration)
+	}
+	if cfg.ScrapeRPS != 5 || cfg.ScrapeBurst != 5 || cfg.HostRateLimits != "" {
+		t.Errorf("expected scrape rate limit 5 rps / burst 5, got %v / %d / %q", cfg.ScrapeRPS, cfg.ScrapeBurst, cfg.HostRateLimits)
+	}
+	if cfg.SnapshotDir != "" || cfg.ReplayDir != "" {
+		t.Errorf("expected snapshots disabled by default, got %q / %q", cfg.SnapshotDir, cfg.ReplayDir)
+	}
+	if cfg.DescriptionTTL != 7*24*time.Hour {
+		t.Errorf("expected DescriptionTTL 168h, got %v", cfg.DescriptionTTL)
+	}
+	if cfg.DetailRefreshInterval != 24*time.Hour {
+		t.Errorf("expected DetailRefreshInterval 24h, got %v", cfg.DetailRefreshInterval)
+	}
+	if cfg.QueueEnabled {
+		t.Error("expected queue mode disabled by default")
+	}
+	if cfg.QueueVisibility != 10*time.Minute {
+		t.Errorf("expected QueueVisibility 10m, got %v", cfg.QueueVisibility)
+	}
+	if cfg.QueueMaxAttempts != 3 {
+		t.Errorf("expected QueueMaxAttempts 3, got %d", cfg.QueueMaxAttempts)
+	}
+	if cfg.LeaderLockTTL != 30*time.Second {
+		t.Errorf("expected LeaderLockTTL 30s, got %v", cfg.LeaderLockTTL)
+	}
+	if cfg.RequestLease != time.Minute {
+		t.Errorf("expected RequestLease 1m, got %v", cfg.RequestLease)
+	}
+	if cfg.QuietHours != "" {
+		t.Errorf("expected no quiet hours by default, got %q", cfg.QuietHours)
+	}
+	if cfg.ScheduleTimezone != "Europe/Moscow" {
+		t.Errorf("expected ScheduleTimezone 'Europe/Moscow', got %q", cfg.ScheduleTimezone)
+	}
+	if cfg.MatchMinConfidence != 0.8 {
+		t.Errorf("expected MatchMinConfidence 0.8, got %v", cfg.MatchMinConfidence)
+	}
 	if cfg.LogMode != "dev" {
 		t.Errorf("expected LogMode 'dev', got %q", cfg.LogMode)
 	}
@@ -7,10 +7,82 @@ import (
 )
 
 type Category struct {
-	ID        uuid.UUID `db:"id" json:"id"`
-	Name      string    `db:"name" json:"name"`
-	Slug      string    `db:"slug" json:"slug"`
-	URL       string    `db:"url" json:"url"`
-	CreatedAt time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+	ID        uuid.UUID  `db:"id" json:"id"`
+	Source    string     `db:"source" json:"source"`
+	ParentID  *uuid.UUID `db:"parent_id" json:"parent_id"`
+	Depth     int        `db:"depth" json:"depth"`
+	Name      string     `db:"name" json:"name"`
+	Slug      string     `db:"slug" json:"slug"`
+	URL       string     `db:"url" json:"url"`
+	CreatedAt time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time  `db:"updated_at" json:"updated_at"`
+}
+
+type CategoryNode struct {
+	Category
+	Children []CategoryNode `json:"children"`
+}
+
+// BuildCategoryTree nests categories under their parents, keeping the input
+// order among siblings. Categories whose parent is missing become roots.
+func BuildCategoryTree(categories []Category) []CategoryNode {
+	known := make(map[uuid.UUID]struct{}, len(categories))
+	for _, c := range categories {
+		known[c.ID] = struct{}{}
+	}
+
+	children := make(map[uuid.UUID][]Category)
+	var roots []Category
+	for _, c := range categories {
+		if c.ParentID != nil {
+			if _, ok := known[*c.ParentID]; ok && *c.ParentID != c.ID {
+				children[*c.ParentID] = append(children[*c.ParentID], c)
+				continue
+			}
+		}
+		roots = append(roots, c)
+	}
+
+	visited := make(map[uuid.UUID]struct{}, len(categories))
+	var build func(cats []Category) []CategoryNode
+	build = func(cats []Category) []CategoryNode {
+		nodes := make([]CategoryNode, 0, len(cats))
+		for _, c := range cats {
+			if _, ok := visited[c.ID]; ok {
+				continue
+			}
+			visited[c.ID] = struct{}{}
+			nodes = append(nodes, CategoryNode{Category: c, Children: build(children[c.ID])})
+		}
+		return nodes
+	}
+
+	return build(roots)
+}
+
+// CategoryAncestors maps every category to the IDs of its parent, its
+// parent's parent and so on up to the root, nearest first.
+func CategoryAncestors(categories []Category) map[uuid.UUID][]uuid.UUID {
+	parents := make(map[uuid.UUID]uuid.UUID, len(categories))
+	for _, c := range categories {
+		if c.ParentID != nil {
+			parents[c.ID] = *c.ParentID
+		}
+	}
+
+	ancestors := make(map[uuid.UUID][]uuid.UUID, len(categories))
+	for _, c := range categories {
+		var ids []uuid.UUID
+		seen := map[uuid.UUID]struct{}{c.ID: {}}
+		for id, ok := parents[c.ID]; ok; id, ok = parents[id] {
+			if _, loop := seen[id]; loop {
+				break
+			}
+			seen[id] = struct{}{}
+			ids = append(ids, id)
+		}
+		ancestors[c.ID] = ids
+	}
+
+	return ancestors
 }
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+)
+
+var ErrInvalidSchedule = errors.New("invalid category schedule")
+
+// CategorySchedule overrides how often the parser scrapes a category. Cron
+// is a standard five-field expression or a descriptor such as "@hourly" or
+// "@every 15m". A disabled schedule stops the category from being scraped.
+type CategorySchedule struct {
+	CategoryID uuid.UUID `db:"category_id" json:"category_id"`
+	Cron       string    `db:"cron" json:"cron"`
+	Enabled    bool      `db:"enabled" json:"enabled"`
+	CreatedAt  time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (s CategorySchedule) Validate() error {
+	if s.CategoryID == uuid.Nil {
+		return fmt.Errorf("%w: category_id is required", ErrInvalidSchedule)
+	}
+	if _, err := cron.ParseStandard(s.Cron); err != nil {
+		return fmt.Errorf("%w: cron: %v", ErrInvalidSchedule, err)
+	}
+
+	return nil
+}
@@ -2,6 +2,8 @@ package domain
 
 import (
 	"encoding/json"
+	"errors"
+	"slices"
 	"testing"
 	"time"
 
@@ -201,11 +203,20 @@ func TestProductList_EmptyProducts(t *testing.T) {
 func TestProductFilter_ZeroValue(t *testing.T) {
 	f := ProductFilter{}
 
-	if f.CategoryID != nil {
-		t.Error("expected nil CategoryID")
+	if f.CategoryIDs != nil {
+		t.Error("expected nil CategoryIDs")
 	}
-	if f.Brand != nil {
-		t.Error("expected nil Brand")
+	if f.Brands != nil {
+		t.Error("expected nil Brands")
+	}
+	if f.ExcludeBrands != nil {
+		t.Error("expected nil ExcludeBrands")
+	}
+	if f.OnSale {
+		t.Error("expected OnSale false")
+	}
+	if f.UpdatedSince != nil {
+		t.Error("expected nil UpdatedSince")
 	}
 	if f.MinPrice != nil {
 		t.Error("expected nil MinPrice")
@@ -223,3 +234,270 @@ func TestProductFilter_ZeroValue(t *testing.T) {
 		t.Errorf("expected Offset 0, got %d", f.Offset)
 	}
 }
+
+func TestIsValidPriceInterval(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"hour", true},
+		{"day", true},
+		{"week", true},
+		{"month", true},
+		{"", false},
+		{"minute", false},
+		{"DAY", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := IsValidPriceInterval(tt.input); got != tt.expected {
+				t.Errorf("IsValidPriceInterval(%q) = %v, expected %v", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPricingRule_Validate(t *testing.T) {
+	intPtr := func(v int) *int { return &v }
+	valid := PricingRule{Name: "r", AdjustmentType: AdjustmentFixed, AdjustmentValue: -1000, RoundingMode: RoundingNone}
+
+	tests := []struct {
+		name    string
+		mutate  func(r *PricingRule)
+		wantErr bool
+	}{
+		{"valid", func(_ *PricingRule) {}, false},
+		{"missing name", func(r *PricingRule) { r.Name = "" }, true},
+		{"unknown adjustment", func(r *PricingRule) { r.AdjustmentType = "multiply" }, true},
+		{"percent at -100", func(r *PricingRule) { r.AdjustmentType = AdjustmentPercent; r.AdjustmentValue = -100 }, true},
+		{"unknown rounding", func(r *PricingRule) { r.RoundingMode = "bankers" }, true},
+		{"rounding without step", func(r *PricingRule) { r.RoundingMode = RoundingDown }, true},
+		{"ending not below step", func(r *PricingRule) { r.RoundingMode = RoundingDown; r.RoundingStep = 100; r.RoundingEnding = 100 }, true},
+		{"valid rounding", func(r *PricingRule) { r.RoundingMode = RoundingUp; r.RoundingStep = 1000; r.RoundingEnding = 990 }, false},
+		{"inverted band", func(r *PricingRule) { r.MinPrice = intPtr(10); r.MaxPrice = intPtr(5) }, true},
+		{"inverted clamp", func(r *PricingRule) { r.FloorPrice = intPtr(10); r.CeilingPrice = intPtr(5) }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := valid
+			tt.mutate(&r)
+			err := r.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidPricingRule) {
+				t.Errorf("expected ErrInvalidPricingRule, got %v", err)
+			}
+		})
+	}
+}
+
+func TestBuildCategoryTree(t *testing.T) {
+	rootID, phonesID, appleID, orphanParent := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+
+	tree := BuildCategoryTree([]Category{
+		{ID: rootID, Name: "Электроника"},
+		{ID: phonesID, ParentID: &rootID, Depth: 1, Name: "Телефоны"},
+		{ID: appleID, ParentID: &phonesID, Depth: 2, Name: "Apple"},
+		{ID: uuid.New(), ParentID: &orphanParent, Depth: 1, Name: "Без родителя"},
+	})
+
+	if len(tree) != 2 {
+		t.Fatalf("expected 2 roots, got %d", len(tree))
+	}
+	if tree[0].Name != "Электроника" || tree[1].Name != "Без родителя" {
+		t.Errorf("unexpected roots: %q, %q", tree[0].Name, tree[1].Name)
+	}
+	if len(tree[0].Children) != 1 || tree[0].Children[0].ID != phonesID {
+		t.Fatalf("expected Телефоны under Электроника")
+	}
+	if len(tree[0].Children[0].Children) != 1 || tree[0].Children[0].Children[0].ID != appleID {
+		t.Errorf("expected Apple under Телефоны")
+	}
+	if tree[1].Children == nil {
+		t.Error("expected non-nil empty children slice")
+	}
+}
+
+func TestCategoryAncestors(t *testing.T) {
+	rootID, phonesID, appleID := uuid.New(), uuid.New(), uuid.New()
+
+	ancestors := CategoryAncestors([]Category{
+		{ID: rootID, Name: "Электроника"},
+		{ID: phonesID, ParentID: &rootID, Depth: 1, Name: "Телефоны"},
+		{ID: appleID, ParentID: &phonesID, Depth: 2, Name: "Apple"},
+	})
+
+	if got := ancestors[appleID]; !slices.Equal(got, []uuid.UUID{phonesID, rootID}) {
+		t.Errorf("expected Телефоны and Электроника above Apple, got %v", got)
+	}
+	if got := ancestors[rootID]; len(got) != 0 {
+		t.Errorf("expected no ancestors of the root, got %v", got)
+	}
+}
+
+func TestScrapeRun_Finish(t *testing.T) {
+	tests := []struct {
+		name       string
+		categories []ScrapeRunCategory
+		expected   string
+	}{
+		{"all succeeded", []ScrapeRunCategory{{Status: ScrapeStatusSucceeded, PagesFetched: 3}}, ScrapeStatusSucceeded},
+		{"failed page", []ScrapeRunCategory{{Status: ScrapeStatusPartial, PagesFetched: 2, PagesFailed: 1}}, ScrapeStatusPartial},
+		{"one category failed", []ScrapeRunCategory{{Status: ScrapeStatusSucceeded}, {Status: ScrapeStatusFailed}}, ScrapeStatusPartial},
+		{"all categories failed", []ScrapeRunCategory{{Status: ScrapeStatusFailed}, {Status: ScrapeStatusFailed}}, ScrapeStatusFailed},
+		{"markup drift", []ScrapeRunCategory{{Status: ScrapeStatusSucceeded, PagesFetched: 3, MarkupDrift: true}}, ScrapeStatusPartial},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			run := ScrapeRun{Status: ScrapeStatusRunning}
+			for _, c := range tt.categories {
+				run.AddCategory(c)
+			}
+			run.Finish(time.Now())
+
+			if run.Status != tt.expected {
+				t.Errorf("expected status %q, got %q", tt.expected, run.Status)
+			}
+			if run.FinishedAt == nil {
+				t.Error("expected FinishedAt to be set")
+			}
+			if run.CategoriesTotal != len(tt.categories) {
+				t.Errorf("expected %d categories, got %d", len(tt.categories), run.CategoriesTotal)
+			}
+		})
+	}
+}
+
+func TestScrapeRun_FinishKeepsFailure(t *testing.T) {
+	run := ScrapeRun{Status: ScrapeStatusFailed}
+	run.AddCategory(ScrapeRunCategory{Status: ScrapeStatusSucceeded, ProductsUpserted: 10, ProductsChanged: 2})
+	run.Finish(time.Now())
+
+	if run.Status != ScrapeStatusFailed {
+		t.Errorf("expected status failed, got %q", run.Status)
+	}
+	if run.ProductsUpserted != 10 || run.ProductsChanged != 2 {
+		t.Errorf("expected product totals to be summed, got %d/%d", run.ProductsUpserted, run.ProductsChanged)
+	}
+}
+
+func TestScrapeRun_AddCategoryCountsDrift(t *testing.T) {
+	run := ScrapeRun{Status: ScrapeStatusRunning}
+	run.AddCategory(ScrapeRunCategory{Status: ScrapeStatusSucceeded})
+	run.AddCategory(ScrapeRunCategory{Status: ScrapeStatusSucceeded, MarkupDrift: true})
+
+	if !run.MarkupDrift || run.DriftCategories != 1 {
+		t.Errorf("expected drift in 1 category, got %v/%d", run.MarkupDrift, run.DriftCategories)
+	}
+}
+
+func TestScrapeRunCategory_Complete(t *testing.T) {
+	tests := []struct {
+		name     string
+		category ScrapeRunCategory
+		want     bool
+	}{
+		{"succeeded with products", ScrapeRunCategory{Status: ScrapeStatusSucceeded, ProductsUpserted: 3}, true},
+		{"succeeded empty", ScrapeRunCategory{Status: ScrapeStatusSucceeded}, false},
+		{"failed", ScrapeRunCategory{Status: ScrapeStatusFailed, ProductsUpserted: 3}, false},
+		{"drifted", ScrapeRunCategory{Status: ScrapeStatusSucceeded, ProductsUpserted: 3, MarkupDrift: true}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.category.Complete(); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestScrapeHealth_Drift(t *testing.T) {
+	tests := []struct {
+		name     string
+		health   ScrapeHealth
+		complete bool
+		drift    bool
+	}{
+		{"healthy", ScrapeHealth{ProductsExpected: 40, CardsFound: 40, CardsParsed: 40, MissingBrand: 3, DescriptionsFetched: 40, DescriptionsMissing: 5}, true, false},
+		{"empty category", ScrapeHealth{}, true, false},
+		{"no cards matched", ScrapeHealth{ProductsExpected: 3}, true, true},
+		{"no cards on partial scrape", ScrapeHealth{ProductsExpected: 3}, false, false},
+		{"too few cards", ScrapeHealth{ProductsExpected: 100, CardsFound: 30, CardsParsed: 30}, true, true},
+		{"too few cards on partial scrape", ScrapeHealth{ProductsExpected: 100, CardsFound: 30, CardsParsed: 30}, false, false},
+		{"cards unparsed", ScrapeHealth{CardsFound: 20, CardsParsed: 10}, false, true},
+		{"prices missing", ScrapeHealth{CardsFound: 20, CardsParsed: 20, MissingPrice: 10}, false, true},
+		{"ecom missing", ScrapeHealth{CardsFound: 20, CardsParsed: 20, MissingEcom: 15, MissingSKU: 15, MissingBrand: 15}, false, true},
+		{"small sample", ScrapeHealth{CardsFound: 4, CardsParsed: 4, MissingPrice: 4}, false, false},
+		{"descriptions missing", ScrapeHealth{CardsFound: 20, CardsParsed: 20, DescriptionsFetched: 20, DescriptionsMissing: 19}, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason := tt.health.Drift(tt.complete)
+			if (reason != "") != tt.drift {
+				t.Errorf("expected drift %v, got %q", tt.drift, reason)
+			}
+		})
+	}
+}
+
+func TestScrapeHealth_Add(t *testing.T) {
+	h := ScrapeHealth{ProductsExpected: 50}
+	h.Add(ScrapeHealth{ProductsExpected: 99, CardsFound: 20, CardsParsed: 19, MissingPrice: 1, DescriptionsFetched: 19})
+	h.Add(ScrapeHealth{CardsFound: 10, CardsParsed: 10, MissingBrand: 2, DescriptionsMissing: 1})
+
+	expected := ScrapeHealth{ProductsExpected: 50, CardsFound: 30, CardsParsed: 29, MissingPrice: 1, MissingBrand: 2, DescriptionsFetched: 19, DescriptionsMissing: 1}
+	if h != expected {
+		t.Errorf("expected %+v, got %+v", expected, h)
+	}
+}
+
+func TestCategorySchedule_Validate(t *testing.T) {
+	id := uuid.New()
+
+	tests := []struct {
+		name     string
+		schedule CategorySchedule
+		wantErr  bool
+	}{
+		{"five fields", CategorySchedule{CategoryID: id, Cron: "*/15 * * * *"}, false},
+		{"descriptor", CategorySchedule{CategoryID: id, Cron: "@every 6h"}, false},
+		{"missing category", CategorySchedule{Cron: "@hourly"}, true},
+		{"empty cron", CategorySchedule{CategoryID: id}, true},
+		{"seconds field", CategorySchedule{CategoryID: id, Cron: "0 */15 * * * *"}, true},
+		{"garbage", CategorySchedule{CategoryID: id, Cron: "every now and then"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.schedule.Validate()
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidSchedule) {
+				t.Errorf("expected ErrInvalidSchedule, got %v", err)
+			}
+		})
+	}
+}
+
+func TestScrapeRequest_Finished(t *testing.T) {
+	tests := map[string]bool{
+		ScrapeRequestPending:   false,
+		ScrapeRequestRunning:   false,
+		ScrapeRequestCompleted: true,
+		ScrapeRequestFailed:    true,
+		ScrapeRequestCancelled: true,
+	}
+
+	for status, want := range tests {
+		if got := (ScrapeRequest{Status: status}).Finished(); got != want {
+			t.Errorf("Finished() for %q = %v, want %v", status, got, want)
+		}
+	}
+}
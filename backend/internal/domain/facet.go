@@ -0,0 +1,62 @@
+package domain
+
+import "github.com/google/uuid"
+
+const (
+	DefaultPriceBuckets = 10
+	MaxPriceBuckets     = 50
+
+	// MaxAttributeFacetValues caps the values listed per attribute facet,
+	// most frequent first.
+	MaxAttributeFacetValues = 20
+)
+
+type BrandFacet struct {
+	Brand string `db:"brand" json:"brand"`
+	Count int    `db:"count" json:"count"`
+}
+
+type CategoryFacet struct {
+	CategoryID uuid.UUID `db:"category_id" json:"category_id"`
+	Name       string    `db:"name" json:"name"`
+	Count      int       `db:"count" json:"count"`
+}
+
+type AttributeValueCount struct {
+	Value string `db:"value" json:"value"`
+	Count int    `db:"count" json:"count"`
+}
+
+// AttributeFacet lists the values of one normalized attribute.
+type AttributeFacet struct {
+	Name   string                `json:"name"`
+	Values []AttributeValueCount `json:"values"`
+}
+
+type PriceRange struct {
+	Min int `db:"min" json:"min"`
+	Max int `db:"max" json:"max"`
+}
+
+// PriceBucket covers prices in [From, To); the last bucket also includes To.
+type PriceBucket struct {
+	From  int `json:"from"`
+	To    int `json:"to"`
+	Count int `json:"count"`
+}
+
+// ProductFacets are computed against the active filter, except that each
+// facet ignores its own constraint so the UI can offer alternatives: brand
+// counts ignore the brand filter, category counts the category filter and
+// the price range and histogram the price bounds, and each attribute facet
+// its own attribute. Attribute facets are only computed within a category,
+// since specifications differ from one category to another.
+type ProductFacets struct {
+	Total      int              `json:"total"`
+	Brands     []BrandFacet     `json:"brands"`
+	Categories []CategoryFacet  `json:"categories"`
+	Attributes []AttributeFacet `json:"attributes"`
+	Price      *PriceRange      `json:"price"`
+	Histogram  []PriceBucket    `json:"histogram"`
+	Fuzzy      bool             `json:"fuzzy"`
+}
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	PriceIntervalHour  = "hour"
+	PriceIntervalDay   = "day"
+	PriceIntervalWeek  = "week"
+	PriceIntervalMonth = "month"
+)
+
+// PricePoint is a single observation on a product price timeline. Without
+// downsampling every point is a recorded price change and MinPrice/MaxPrice
+// equal Price; with downsampling RecordedAt is the bucket start, Price is the
+// last price seen in the bucket and MinPrice/MaxPrice span the bucket.
+type PricePoint struct {
+	RecordedAt    time.Time `db:"recorded_at" json:"recorded_at"`
+	Price         int       `db:"price" json:"price"`
+	OriginalPrice int       `db:"original_price" json:"original_price"`
+	MinPrice      int       `db:"min_price" json:"min_price"`
+	MaxPrice      int       `db:"max_price" json:"max_price"`
+}
+
+type PriceHistoryFilter struct {
+	ProductID uuid.UUID
+	From      *time.Time
+	To        *time.Time
+	Interval  string
+}
+
+type PriceHistory struct {
+	ProductID uuid.UUID    `json:"product_id"`
+	Interval  string       `json:"interval,omitempty"`
+	Points    []PricePoint `json:"points"`
+}
+
+func IsValidPriceInterval(interval string) bool {
+	switch interval {
+	case PriceIntervalHour, PriceIntervalDay, PriceIntervalWeek, PriceIntervalMonth:
+		return true
+	}
+	return false
+}
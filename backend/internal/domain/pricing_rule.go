@@ -0,0 +1,112 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	AdjustmentFixed   = "fixed"
+	AdjustmentPercent = "percent"
+
+	RoundingNone    = "none"
+	RoundingDown    = "down"
+	RoundingUp      = "up"
+	RoundingNearest = "nearest"
+)
+
+var ErrInvalidPricingRule = errors.New("invalid pricing rule")
+
+// PricingRule turns a scraped source price into our selling price. Rules are
+// matched against the product's category, brand and source price band; the
+// matching rule with the highest priority wins. AdjustmentValue is signed:
+// negative values are markdowns, positive values are markups.
+type PricingRule struct {
+	ID              uuid.UUID  `db:"id" json:"id"`
+	Name            string     `db:"name" json:"name"`
+	Priority        int        `db:"priority" json:"priority"`
+	Enabled         bool       `db:"enabled" json:"enabled"`
+	CategoryID      *uuid.UUID `db:"category_id" json:"category_id,omitempty"`
+	Brand           *string    `db:"brand" json:"brand,omitempty"`
+	MinPrice        *int       `db:"min_price" json:"min_price,omitempty"`
+	MaxPrice        *int       `db:"max_price" json:"max_price,omitempty"`
+	AdjustmentType  string     `db:"adjustment_type" json:"adjustment_type"`
+	AdjustmentValue float64    `db:"adjustment_value" json:"adjustment_value"`
+	RoundingMode    string     `db:"rounding_mode" json:"rounding_mode"`
+	RoundingStep    int        `db:"rounding_step" json:"rounding_step"`
+	RoundingEnding  int        `db:"rounding_ending" json:"rounding_ending"`
+	FloorPrice      *int       `db:"floor_price" json:"floor_price,omitempty"`
+	CeilingPrice    *int       `db:"ceiling_price" json:"ceiling_price,omitempty"`
+	CreatedAt       time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time  `db:"updated_at" json:"updated_at"`
+}
+
+func (r PricingRule) Validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidPricingRule)
+	}
+
+	switch r.AdjustmentType {
+	case AdjustmentFixed:
+	case AdjustmentPercent:
+		if r.AdjustmentValue <= -100 {
+			return fmt.Errorf("%w: percent adjustment must be greater than -100", ErrInvalidPricingRule)
+		}
+	default:
+		return fmt.Errorf("%w: unknown adjustment_type %q", ErrInvalidPricingRule, r.AdjustmentType)
+	}
+
+	switch r.RoundingMode {
+	case RoundingNone:
+	case RoundingDown, RoundingUp, RoundingNearest:
+		if r.RoundingStep <= 0 {
+			return fmt.Errorf("%w: rounding_step must be positive", ErrInvalidPricingRule)
+		}
+		if r.RoundingEnding < 0 || r.RoundingEnding >= r.RoundingStep {
+			return fmt.Errorf("%w: rounding_ending must be in [0, rounding_step)", ErrInvalidPricingRule)
+		}
+	default:
+		return fmt.Errorf("%w: unknown rounding_mode %q", ErrInvalidPricingRule, r.RoundingMode)
+	}
+
+	if r.MinPrice != nil && r.MaxPrice != nil && *r.MinPrice > *r.MaxPrice {
+		return fmt.Errorf("%w: min_price must not exceed max_price", ErrInvalidPricingRule)
+	}
+	if r.FloorPrice != nil && r.CeilingPrice != nil && *r.FloorPrice > *r.CeilingPrice {
+		return fmt.Errorf("%w: floor_price must not exceed ceiling_price", ErrInvalidPricingRule)
+	}
+
+	return nil
+}
+
+// ProductPrice is the slice of a product the pricing engine needs.
+type ProductPrice struct {
+	ID            uuid.UUID `db:"id" json:"id"`
+	Name          string    `db:"name" json:"name"`
+	Brand         string    `db:"brand" json:"brand"`
+	CategoryID    uuid.UUID `db:"category_id" json:"category_id"`
+	OriginalPrice int       `db:"original_price" json:"original_price"`
+	Price         int       `db:"price" json:"price"`
+}
+
+type PricingDryRunItem struct {
+	ProductID     uuid.UUID  `json:"product_id"`
+	Name          string     `json:"name"`
+	OriginalPrice int        `json:"original_price"`
+	CurrentPrice  int        `json:"current_price"`
+	NewPrice      int        `json:"new_price"`
+	RuleID        *uuid.UUID `json:"rule_id,omitempty"`
+	RuleName      string     `json:"rule_name,omitempty"`
+}
+
+type PricingDryRun struct {
+	TotalProducts   int                 `json:"total_products"`
+	ChangedProducts int                 `json:"changed_products"`
+	Unmatched       int                 `json:"unmatched"`
+	CurrentTotal    int64               `json:"current_total"`
+	NewTotal        int64               `json:"new_total"`
+	Items           []PricingDryRunItem `json:"items"`
+}
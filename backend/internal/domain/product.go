@@ -8,6 +8,7 @@ import (
 
 type Product struct {
 	ID            uuid.UUID `db:"id" json:"id"`
+	Source        string    `db:"source" json:"source"`
 	ExternalID    string    `db:"external_id" json:"external_id"`
 	SKU           string    `db:"sku" json:"sku"`
 	Name          string    `db:"name" json:"name"`
@@ -18,24 +19,79 @@ type Product struct {
 	Brand         string    `db:"brand" json:"brand"`
 	Description   string    `db:"description" json:"description"`
 	CategoryID    uuid.UUID `db:"category_id" json:"category_id"`
+	Available     bool      `db:"available" json:"available"`
+	StockStatus   string    `db:"stock_status" json:"stock_status" enums:"in_stock,out_of_stock,preorder,unknown"`
+	StockLabel    string    `db:"stock_label" json:"stock_label"`
+	LastSeenAt    time.Time `db:"last_seen_at" json:"last_seen_at"`
 	CreatedAt     time.Time `db:"created_at" json:"created_at"`
 	UpdatedAt     time.Time `db:"updated_at" json:"updated_at"`
+
+	// HighlightName and HighlightSnippet are HTML-escaped text with the
+	// matched words wrapped in <mark>.
+	HighlightName    *string `db:"highlight_name" json:"highlight_name,omitempty"`
+	HighlightSnippet *string `db:"highlight_snippet" json:"highlight_snippet,omitempty"`
+
+	Rank *float64 `db:"rank" json:"-"`
+
+	// GroupID links the product to its other colors and storage sizes; nil
+	// when it has none.
+	GroupID *uuid.UUID `db:"group_id" json:"group_id,omitempty"`
+
+	// Attributes, Images and Variants are only loaded for a single product.
+	Attributes []ProductAttribute `db:"-" json:"attributes,omitempty"`
+	Images     []ProductImage     `db:"-" json:"images,omitempty"`
+	Variants   []ProductVariant   `db:"-" json:"variants,omitempty"`
+}
+
+const (
+	ProductSortRelevance = "relevance"
+
+	// TotalSkipped is reported as ProductList.Total when the count was not
+	// requested.
+	TotalSkipped = -1
+)
+
+// ProductCursor marks the last product of a page: its sort key values in
+// SortBy order followed by the id tie-breaker. A cursor taken from a fuzzy
+// result carries the search variant that matched so later pages stay on it.
+type ProductCursor struct {
+	SortBy      []string  `json:"s"`
+	Values      []any     `json:"v"`
+	ID          uuid.UUID `json:"id"`
+	FuzzySearch *string   `json:"f,omitempty"`
 }
 
 type ProductFilter struct {
-	CategoryID *uuid.UUID
-	Brand      *string
-	MinPrice   *int
-	MaxPrice   *int
-	Search     *string
-	Limit      uint64
-	Offset     uint64
-	SortBy     []string
+	CategoryIDs        []uuid.UUID
+	Brands             []string
+	ExcludeBrands      []string
+	MinPrice           *int
+	MaxPrice           *int
+	OnSale             bool
+	MinDiscount        *int
+	MaxDiscount        *int
+	UpdatedSince       *time.Time
+	IncludeUnavailable bool
+	Search             *string
+	FuzzySearch        bool
+	Limit              uint64
+	Offset             uint64
+	SortBy             []string
+	Cursor             *ProductCursor
+	SkipTotal          bool
+	// Attributes maps a normalized attribute name to the values a product
+	// may have; names are ANDed, values of one name ORed.
+	Attributes map[string][]string
+	// CollapseGroups keeps one product per variant group, the cheapest one
+	// matching the filter.
+	CollapseGroups bool
 }
 
 type ProductList struct {
-	Products []Product `json:"products"`
-	Total    int       `json:"total"`
-	Page     uint64    `json:"page"`
-	PageSize uint64    `json:"page_size"`
+	Products   []Product `json:"products"`
+	Total      int       `json:"total"`
+	Page       uint64    `json:"page,omitempty"`
+	PageSize   uint64    `json:"page_size"`
+	Fuzzy      bool      `json:"fuzzy"`
+	NextCursor string    `json:"next_cursor,omitempty"`
 }
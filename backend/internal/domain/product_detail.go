@@ -0,0 +1,46 @@
+package domain
+
+// Stock statuses of a product, as read from its product page.
+const (
+	StockInStock    = "in_stock"
+	StockOutOfStock = "out_of_stock"
+	StockPreorder   = "preorder"
+	StockUnknown    = "unknown"
+)
+
+// ProductAttribute is a specification of a product. Group is the section of
+// the specification table the attribute is listed under, if any.
+type ProductAttribute struct {
+	Group string `db:"attr_group" json:"group,omitempty"`
+	Name  string `db:"name" json:"name"`
+	Value string `db:"value" json:"value"`
+}
+
+type ProductImage struct {
+	URL      string `db:"url" json:"url"`
+	Position int    `db:"position" json:"position"`
+}
+
+// ProductVariant links to a sibling product of another color or memory
+// size. Current marks the product itself.
+type ProductVariant struct {
+	Kind    string `db:"kind" json:"kind" enums:"color,memory"`
+	Label   string `db:"label" json:"label"`
+	URL     string `db:"url" json:"url"`
+	Current bool   `db:"current" json:"current"`
+}
+
+// ProductDetail holds what the parser reads from a product page beyond the
+// listing card, keyed by the product's source and external id.
+type ProductDetail struct {
+	Source      string
+	ExternalID  string
+	StockStatus string
+	StockLabel  string
+	Attributes  []ProductAttribute
+	// AttributeIndex maps normalized attribute names to normalized values,
+	// the form products are filtered and faceted by.
+	AttributeIndex map[string]string
+	Images         []ProductImage
+	Variants       []ProductVariant
+}
@@ -0,0 +1,37 @@
+package domain
+
+import "github.com/google/uuid"
+
+// ProductGroup is one model sold in several colors or storage sizes; each
+// variant is a product of its own.
+type ProductGroup struct {
+	ID    uuid.UUID `db:"id" json:"id"`
+	Name  string    `db:"name" json:"name"`
+	Brand string    `db:"brand" json:"brand"`
+	// MinPrice and MaxPrice span the prices of the available variants; both
+	// are 0 when none is available.
+	MinPrice int       `db:"-" json:"min_price"`
+	MaxPrice int       `db:"-" json:"max_price"`
+	Variants []Product `db:"-" json:"variants"`
+}
+
+// ProductGroupMember is what grouping needs to know about a product: its
+// source, name and the product pages its variant links point to.
+type ProductGroupMember struct {
+	ProductID   uuid.UUID
+	Source      string
+	Brand       string
+	Name        string
+	ProductURL  string
+	VariantURLs []string
+}
+
+// ProductGroupAssignment is a computed group. Source and Key identify the
+// group across runs so that its id stays the same.
+type ProductGroupAssignment struct {
+	Source     string
+	Key        string
+	Name       string
+	Brand      string
+	ProductIDs []uuid.UUID
+}
@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Statuses of a product match. Matching suggests matches; an admin confirms
+// or rejects them, and decided matches are never changed by matching again.
+const (
+	ProductMatchSuggested = "suggested"
+	ProductMatchConfirmed = "confirmed"
+	ProductMatchRejected  = "rejected"
+)
+
+// ErrProductMatchConflict means a product of the match being confirmed
+// already has a confirmed match in the other product's shop.
+var ErrProductMatchConflict = errors.New("a product of the match already has a confirmed match in the other shop")
+
+// Methods by which two products were matched.
+const (
+	MatchMethodSKU   = "sku"
+	MatchMethodModel = "model"
+	MatchMethodName  = "name"
+)
+
+// ProductMatch links two products of different sources that are believed to
+// be the same device. ProductID is the smaller of the two ids, so a pair is
+// stored once. Confidence is between 0 and 1.
+type ProductMatch struct {
+	ID         uuid.UUID `db:"id" json:"id"`
+	ProductID  uuid.UUID `db:"product_id" json:"product_id"`
+	MatchedID  uuid.UUID `db:"matched_id" json:"matched_id"`
+	Confidence float64   `db:"confidence" json:"confidence"`
+	Method     string    `db:"method" json:"method" enums:"sku,model,name"`
+	Status     string    `db:"status" json:"status" enums:"suggested,confirmed,rejected"`
+	CreatedAt  time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at" json:"updated_at"`
+
+	// The names and sources of both products, filled in when listing.
+	ProductName   string `db:"product_name" json:"product_name,omitempty"`
+	ProductSource string `db:"product_source" json:"product_source,omitempty"`
+	MatchedName   string `db:"matched_name" json:"matched_name,omitempty"`
+	MatchedSource string `db:"matched_source" json:"matched_source,omitempty"`
+}
+
+type ProductMatchFilter struct {
+	Status    *string
+	ProductID *uuid.UUID
+	Limit     uint64
+	Offset    uint64
+}
+
+type ProductMatchList struct {
+	Matches  []ProductMatch `json:"matches"`
+	Total    int            `json:"total"`
+	Page     uint64         `json:"page"`
+	PageSize uint64         `json:"page_size"`
+}
+
+// MatchCandidate is what matching needs to know about a product.
+type MatchCandidate struct {
+	ProductID uuid.UUID `db:"id"`
+	Source    string    `db:"source"`
+	SKU       string    `db:"sku"`
+	Brand     string    `db:"brand"`
+	Name      string    `db:"name"`
+}
+
+// ProductOffer is a product as sold by one source. The offers of a product
+// are the product itself, without match fields, followed by the products
+// matched to it that were not rejected.
+type ProductOffer struct {
+	ProductID     uuid.UUID `db:"id" json:"product_id"`
+	Source        string    `db:"source" json:"source"`
+	Name          string    `db:"name" json:"name"`
+	Price         int       `db:"price" json:"price"`
+	OriginalPrice int       `db:"original_price" json:"original_price"`
+	ProductURL    string    `db:"product_url" json:"product_url"`
+	Available     bool      `db:"available" json:"available"`
+	StockStatus   string    `db:"stock_status" json:"stock_status" enums:"in_stock,out_of_stock,preorder,unknown"`
+	LastSeenAt    time.Time `db:"last_seen_at" json:"last_seen_at"`
+
+	MatchID     *uuid.UUID `db:"match_id" json:"match_id,omitempty"`
+	Confidence  *float64   `db:"confidence" json:"confidence,omitempty"`
+	MatchStatus *string    `db:"match_status" json:"match_status,omitempty" enums:"suggested,confirmed"`
+}
+
+func IsValidProductMatchStatus(s string) bool {
+	switch s {
+	case ProductMatchSuggested, ProductMatchConfirmed, ProductMatchRejected:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,97 @@
+package domain
+
+import "fmt"
+
+// Drift thresholds. Ratios are only judged once a category has at least
+// driftMinSample cards (or fetched descriptions), so a handful of odd
+// products cannot raise the alarm.
+const (
+	driftMinSample        = 10
+	driftMinFoundRatio    = 0.5
+	driftMaxUnparsed      = 0.2
+	driftMaxMissingPrice  = 0.2
+	driftMaxMissingField  = 0.5
+	driftMaxNoDescription = 0.8
+)
+
+// ScrapeHealth tallies how well store77 pages matched the markup the parser
+// expects. Missing* count parsed products lacking a field; ProductsExpected
+// comes from the category's product counter. DescriptionsCached counts
+// products whose page was not refetched because their listing was unchanged.
+type ScrapeHealth struct {
+	ProductsExpected    int `db:"products_expected" json:"products_expected"`
+	CardsFound          int `db:"cards_found" json:"cards_found"`
+	CardsParsed         int `db:"cards_parsed" json:"cards_parsed"`
+	MissingPrice        int `db:"missing_price" json:"missing_price"`
+	MissingBrand        int `db:"missing_brand" json:"missing_brand"`
+	MissingSKU          int `db:"missing_sku" json:"missing_sku"`
+	MissingEcom         int `db:"missing_ecom" json:"missing_ecom"`
+	DescriptionsFetched int `db:"descriptions_fetched" json:"descriptions_fetched"`
+	DescriptionsMissing int `db:"descriptions_missing" json:"descriptions_missing"`
+	DescriptionsCached  int `db:"descriptions_cached" json:"descriptions_cached"`
+}
+
+// Add accumulates page-level signals. ProductsExpected is not summed: it is
+// a per-category figure set once.
+func (h *ScrapeHealth) Add(o ScrapeHealth) {
+	h.CardsFound += o.CardsFound
+	h.CardsParsed += o.CardsParsed
+	h.MissingPrice += o.MissingPrice
+	h.MissingBrand += o.MissingBrand
+	h.MissingSKU += o.MissingSKU
+	h.MissingEcom += o.MissingEcom
+	h.DescriptionsFetched += o.DescriptionsFetched
+	h.DescriptionsMissing += o.DescriptionsMissing
+	h.DescriptionsCached += o.DescriptionsCached
+}
+
+// Drift returns why the signals point to changed store77 markup, or an empty
+// string if they look healthy. The found-versus-expected check needs every
+// page of the category, so it only runs when complete is set.
+func (h ScrapeHealth) Drift(complete bool) string {
+	if complete && h.ProductsExpected > 0 && h.CardsFound == 0 {
+		return fmt.Sprintf("no product cards matched, %d expected", h.ProductsExpected)
+	}
+	if complete && h.ProductsExpected >= driftMinSample &&
+		float64(h.CardsFound) < float64(h.ProductsExpected)*driftMinFoundRatio {
+		return fmt.Sprintf("found %d of %d expected product cards", h.CardsFound, h.ProductsExpected)
+	}
+
+	if h.CardsFound >= driftMinSample {
+		if unparsed := h.CardsFound - h.CardsParsed; ratio(unparsed, h.CardsFound) > driftMaxUnparsed {
+			return fmt.Sprintf("%d of %d product cards could not be parsed", unparsed, h.CardsFound)
+		}
+	}
+
+	if h.CardsParsed >= driftMinSample {
+		checks := []struct {
+			field   string
+			missing int
+			max     float64
+		}{
+			{"price", h.MissingPrice, driftMaxMissingPrice},
+			{"ecommerce data", h.MissingEcom, driftMaxMissingField},
+			{"SKU", h.MissingSKU, driftMaxMissingField},
+			{"brand", h.MissingBrand, driftMaxMissingField},
+		}
+		for _, c := range checks {
+			if ratio(c.missing, h.CardsParsed) > c.max {
+				return fmt.Sprintf("%d of %d products without %s", c.missing, h.CardsParsed, c.field)
+			}
+		}
+	}
+
+	if h.DescriptionsFetched >= driftMinSample &&
+		ratio(h.DescriptionsMissing, h.DescriptionsFetched) > driftMaxNoDescription {
+		return fmt.Sprintf("no description found on %d of %d product pages", h.DescriptionsMissing, h.DescriptionsFetched)
+	}
+
+	return ""
+}
+
+func ratio(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) / float64(total)
+}
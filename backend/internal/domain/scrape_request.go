@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ScrapeRequestPending   = "pending"
+	ScrapeRequestRunning   = "running"
+	ScrapeRequestCompleted = "completed"
+	ScrapeRequestFailed    = "failed"
+	ScrapeRequestCancelled = "cancelled"
+)
+
+var (
+	ErrInvalidScrapeRequest  = errors.New("invalid scrape request")
+	ErrScrapeRequestFinished = errors.New("scrape request already finished")
+)
+
+// ScrapeRequest asks the parser for an immediate run, limited to
+// CategoryIDs and their subcategories when the list is not empty. The parser
+// picks up pending requests between runs and extends LeaseExpiresAt while it
+// runs one; a running request whose lease expired was abandoned by a stopped
+// parser. Cancelling a running request sets CancelRequested; the parser then
+// stops the run, and an abandoned request is cancelled at once.
+type ScrapeRequest struct {
+	ID              uuid.UUID   `json:"id"`
+	Status          string      `json:"status" enums:"pending,running,completed,failed,cancelled"`
+	CategoryIDs     []uuid.UUID `json:"category_ids"`
+	FullRefresh     bool        `json:"full_refresh"`
+	RunID           *uuid.UUID  `json:"run_id"`
+	CancelRequested bool        `json:"cancel_requested"`
+	LeaseExpiresAt  *time.Time  `json:"lease_expires_at"`
+	Error           *string     `json:"error"`
+	CreatedAt       time.Time   `json:"created_at"`
+	StartedAt       *time.Time  `json:"started_at"`
+	FinishedAt      *time.Time  `json:"finished_at"`
+}
+
+func (r ScrapeRequest) Finished() bool {
+	switch r.Status {
+	case ScrapeRequestCompleted, ScrapeRequestFailed, ScrapeRequestCancelled:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,144 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ScrapeStatusRunning   = "running"
+	ScrapeStatusSucceeded = "succeeded"
+	ScrapeStatusPartial   = "partial"
+	ScrapeStatusFailed    = "failed"
+)
+
+type ScrapeRun struct {
+	ID               uuid.UUID  `db:"id" json:"id"`
+	Status           string     `db:"status" json:"status" enums:"running,succeeded,partial,failed"`
+	StartedAt        time.Time  `db:"started_at" json:"started_at"`
+	FinishedAt       *time.Time `db:"finished_at" json:"finished_at"`
+	CategoriesTotal  int        `db:"categories_total" json:"categories_total"`
+	CategoriesFailed int        `db:"categories_failed" json:"categories_failed"`
+	PagesFetched     int        `db:"pages_fetched" json:"pages_fetched"`
+	PagesFailed      int        `db:"pages_failed" json:"pages_failed"`
+	ProductsUpserted int        `db:"products_upserted" json:"products_upserted"`
+	ProductsChanged  int        `db:"products_changed" json:"products_changed"`
+	MarkupDrift      bool       `db:"markup_drift" json:"markup_drift"`
+	DriftCategories  int        `db:"drift_categories" json:"drift_categories"`
+	Error            *string    `db:"error" json:"error"`
+}
+
+// ScrapeRunCategory is the outcome of scraping one category within a run.
+// A partial category had some pages fail; a failed one produced nothing.
+// MarkupDrift is set when the health signals suggest store77 changed its
+// markup; such categories are never used to mark products unavailable.
+type ScrapeRunCategory struct {
+	ID                int64      `db:"id" json:"id"`
+	RunID             uuid.UUID  `db:"run_id" json:"run_id"`
+	CategoryID        *uuid.UUID `db:"category_id" json:"category_id"`
+	CategoryName      string     `db:"category_name" json:"category_name"`
+	Status            string     `db:"status" json:"status" enums:"succeeded,partial,failed"`
+	StartedAt         time.Time  `db:"started_at" json:"started_at"`
+	FinishedAt        time.Time  `db:"finished_at" json:"finished_at"`
+	PagesTotal        int        `db:"pages_total" json:"pages_total"`
+	PagesFetched      int        `db:"pages_fetched" json:"pages_fetched"`
+	PagesFailed       int        `db:"pages_failed" json:"pages_failed"`
+	ProductsUpserted  int        `db:"products_upserted" json:"products_upserted"`
+	ProductsChanged   int        `db:"products_changed" json:"products_changed"`
+	MarkedUnavailable int        `db:"marked_unavailable" json:"marked_unavailable"`
+	ScrapeHealth
+	MarkupDrift bool    `db:"markup_drift" json:"markup_drift"`
+	DriftReason *string `db:"drift_reason" json:"drift_reason"`
+	Error       *string `db:"error" json:"error"`
+}
+
+// ScrapeRunFailedPage is a page the parser gave up on after retrying, or
+// immediately when the error was permanent (e.g. 404).
+type ScrapeRunFailedPage struct {
+	ID        int64     `db:"id" json:"id"`
+	RunID     uuid.UUID `db:"run_id" json:"run_id"`
+	URL       string    `db:"url" json:"url"`
+	Kind      string    `db:"kind" json:"kind" enums:"main,category,product"`
+	Attempts  int       `db:"attempts" json:"attempts"`
+	Permanent bool      `db:"permanent" json:"permanent"`
+	Error     string    `db:"error" json:"error"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+type ScrapeRunDetail struct {
+	ScrapeRun
+	Categories  []ScrapeRunCategory   `json:"categories"`
+	FailedPages []ScrapeRunFailedPage `json:"failed_pages"`
+}
+
+type ScrapeRunFilter struct {
+	Status      *string
+	MarkupDrift *bool
+	Limit       uint64
+	Offset      uint64
+}
+
+// Complete reports whether every page of the category was fetched and parsed
+// without drift and listed some products, so that products missing from it
+// can be marked unavailable.
+func (c ScrapeRunCategory) Complete() bool {
+	return c.Status == ScrapeStatusSucceeded && !c.MarkupDrift && c.ProductsUpserted > 0
+}
+
+type ScrapeRunList struct {
+	Runs     []ScrapeRun `json:"runs"`
+	Total    int         `json:"total"`
+	Page     uint64      `json:"page"`
+	PageSize uint64      `json:"page_size"`
+}
+
+func IsValidScrapeStatus(s string) bool {
+	switch s {
+	case ScrapeStatusRunning, ScrapeStatusSucceeded, ScrapeStatusPartial, ScrapeStatusFailed:
+		return true
+	}
+	return false
+}
+
+// AddPages folds in the stats of pages of the category scraped apart from
+// the others, as the page jobs of the parser queue are.
+func (c *ScrapeRunCategory) AddPages(o ScrapeRunCategory) {
+	c.PagesFetched += o.PagesFetched
+	c.PagesFailed += o.PagesFailed
+	c.ProductsUpserted += o.ProductsUpserted
+	c.ProductsChanged += o.ProductsChanged
+	c.ScrapeHealth.Add(o.ScrapeHealth)
+}
+
+// AddCategory folds a finished category into the run totals.
+func (r *ScrapeRun) AddCategory(c ScrapeRunCategory) {
+	r.CategoriesTotal++
+	if c.Status == ScrapeStatusFailed {
+		r.CategoriesFailed++
+	}
+	r.PagesFetched += c.PagesFetched
+	r.PagesFailed += c.PagesFailed
+	r.ProductsUpserted += c.ProductsUpserted
+	r.ProductsChanged += c.ProductsChanged
+	if c.MarkupDrift {
+		r.DriftCategories++
+		r.MarkupDrift = true
+	}
+}
+
+// Finish sets the final status from the totals unless the run already
+// failed as a whole. Markup drift makes an otherwise clean run partial.
+func (r *ScrapeRun) Finish(at time.Time) {
+	r.FinishedAt = &at
+
+	switch {
+	case r.Status == ScrapeStatusFailed:
+	case r.CategoriesTotal > 0 && r.CategoriesFailed == r.CategoriesTotal:
+		r.Status = ScrapeStatusFailed
+	case r.CategoriesFailed > 0 || r.PagesFailed > 0 || r.MarkupDrift:
+		r.Status = ScrapeStatusPartial
+	default:
+		r.Status = ScrapeStatusSucceeded
+	}
+}
@@ -0,0 +1,20 @@
+package domain
+
+import "github.com/google/uuid"
+
+const (
+	SuggestionBrand   = "brand"
+	SuggestionProduct = "product"
+)
+
+type Suggestion struct {
+	Text      string     `db:"text" json:"text"`
+	Kind      string     `db:"kind" json:"kind"`
+	ProductID *uuid.UUID `db:"product_id" json:"product_id,omitempty"`
+	Score     float64    `db:"score" json:"score"`
+}
+
+type BrandAlias struct {
+	Alias string `db:"alias" json:"alias"`
+	Brand string `db:"brand" json:"brand"`
+}
@@ -0,0 +1,192 @@
+// Package grouping clusters products that are the same model in different
+// colors or storage sizes. store77 lists every combination as a product of
+// its own; products are grouped when their names match once color and
+// capacity are stripped, or when one product page links to another as a
+// variant. Products of different sources are never grouped together.
+package grouping
+
+import (
+	"cmp"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+
+	"github.com/burbble/marketplace/internal/domain"
+)
+
+var (
+	// capacityRe matches storage and memory tokens such as 256GB, 1Tb,
+	// 8/256GB or 12/512.
+	capacityRe = regexp.MustCompile(`^\d+(?:/\d+)?(?:gb|tb|гб|тб)$|^\d+/\d+$`)
+	numberRe   = regexp.MustCompile(`^\d+(?:/\d+)?$`)
+)
+
+var capacityUnits = map[string]bool{"gb": true, "tb": true, "гб": true, "тб": true}
+
+// colorWords are dropped from names wherever they appear. Multi-word colors
+// such as "Space Gray" or "Natural Titanium" are covered word by word.
+var colorWords = map[string]bool{
+	"black": true, "white": true, "blue": true, "red": true, "green": true,
+	"yellow": true, "purple": true, "pink": true, "gold": true, "silver": true,
+	"gray": true, "grey": true, "graphite": true, "midnight": true, "starlight": true,
+	"titanium": true, "natural": true, "desert": true, "space": true, "sierra": true,
+	"alpine": true, "violet": true, "orange": true, "cream": true, "lavender": true,
+	"mint": true, "ultramarine": true, "teal": true, "coral": true, "rose": true,
+	"beige": true, "bronze": true, "onyx": true, "marble": true, "cobalt": true,
+	"amber": true, "jade": true, "(product)red": true,
+
+	"черный": true, "белый": true, "синий": true, "голубой": true, "красный": true,
+	"зеленый": true, "желтый": true, "фиолетовый": true, "розовый": true,
+	"золотой": true, "золотистый": true, "серебристый": true, "серебряный": true,
+	"серый": true, "графитовый": true, "титановый": true, "натуральный": true,
+	"оранжевый": true, "бежевый": true, "бирюзовый": true, "лавандовый": true,
+	"мятный": true, "кремовый": true, "бронзовый": true, "песочный": true,
+}
+
+// Model strips color and capacity tokens from a product name, keeping the
+// remaining words as written: "Apple iPhone 15 Pro 256GB Natural Titanium"
+// becomes "Apple iPhone 15 Pro".
+func Model(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return unicode.IsSpace(r) || r == ',' || r == '(' || r == ')' || r == ';'
+	})
+
+	kept := make([]string, 0, len(words))
+	for _, w := range words {
+		lw := fold(w)
+		switch {
+		case colorWords[lw], capacityRe.MatchString(lw):
+		case capacityUnits[lw]:
+			// "256 GB": drop the number before the unit as well.
+			if n := len(kept); n > 0 && numberRe.MatchString(kept[n-1]) {
+				kept = kept[:n-1]
+			}
+		default:
+			kept = append(kept, w)
+		}
+	}
+
+	return strings.Join(kept, " ")
+}
+
+// Key identifies a model across products: the brand and the model name,
+// case-insensitive. It is empty when nothing is left of the name.
+func Key(brand, name string) string {
+	model := fold(Model(name))
+	if model == "" {
+		return ""
+	}
+
+	brand = fold(strings.TrimSpace(brand))
+	if brand == "" || strings.Contains(model, brand) {
+		return model
+	}
+
+	return brand + " " + model
+}
+
+// Cluster groups the members of a source sharing a model key or linked as
+// variants. Products without a sibling are left out. A group's key is the
+// smallest member key, so it stays the same while the members do not change.
+func Cluster(members []domain.ProductGroupMember) []domain.ProductGroupAssignment {
+	parent := make([]int, len(members))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	union := func(i, j int) {
+		if ri, rj := find(i), find(j); ri != rj {
+			parent[rj] = ri
+		}
+	}
+
+	keys := make([]string, len(members))
+	byKey := make(map[scoped]int, len(members))
+	byPath := make(map[scoped]int, len(members))
+	for i, m := range members {
+		keys[i] = Key(m.Brand, m.Name)
+		if keys[i] != "" {
+			if j, ok := byKey[scoped{m.Source, keys[i]}]; ok {
+				union(j, i)
+			} else {
+				byKey[scoped{m.Source, keys[i]}] = i
+			}
+		}
+		if p := urlPath(m.ProductURL); p != "" {
+			byPath[scoped{m.Source, p}] = i
+		}
+	}
+
+	for i, m := range members {
+		for _, v := range m.VariantURLs {
+			if j, ok := byPath[scoped{m.Source, urlPath(v)}]; ok && j != i {
+				union(i, j)
+			}
+		}
+	}
+
+	clusters := make(map[int][]int)
+	for i := range members {
+		if keys[i] != "" {
+			root := find(i)
+			clusters[root] = append(clusters[root], i)
+		}
+	}
+
+	groups := make([]domain.ProductGroupAssignment, 0, len(clusters))
+	for _, idx := range clusters {
+		if len(idx) < 2 {
+			continue
+		}
+
+		first := slices.MinFunc(idx, func(a, b int) int { return cmp.Compare(keys[a], keys[b]) })
+		g := domain.ProductGroupAssignment{
+			Source:     members[first].Source,
+			Key:        keys[first],
+			Name:       Model(members[first].Name),
+			Brand:      members[first].Brand,
+			ProductIDs: make([]uuid.UUID, 0, len(idx)),
+		}
+		for _, i := range idx {
+			g.ProductIDs = append(g.ProductIDs, members[i].ProductID)
+		}
+		groups = append(groups, g)
+	}
+
+	slices.SortFunc(groups, func(a, b domain.ProductGroupAssignment) int {
+		return cmp.Or(cmp.Compare(a.Source, b.Source), cmp.Compare(a.Key, b.Key))
+	})
+
+	return groups
+}
+
+// scoped is a model key or product path within a source.
+type scoped struct {
+	source string
+	value  string
+}
+
+func fold(s string) string {
+	return strings.ReplaceAll(strings.ToLower(s), "ё", "е")
+}
+
+// urlPath reduces a product link to its path so that absolute and relative
+// links to the same page compare equal.
+func urlPath(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSuffix(u.Path, "/")
+}
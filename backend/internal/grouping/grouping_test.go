@@ -0,0 +1,93 @@
+package grouping
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/burbble/marketplace/internal/domain"
+)
+
+func TestModel(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Apple iPhone 15 Pro 256GB Natural Titanium", "Apple iPhone 15 Pro"},
+		{"Apple iPhone 15 Pro 1Tb (Black Titanium)", "Apple iPhone 15 Pro"},
+		{"Смартфон Samsung Galaxy S24 Ultra 12/256 ГБ, Titanium Gray", "Смартфон Samsung Galaxy S24 Ultra"},
+		{"Xiaomi Redmi Note 13 8/256 Чёрный", "Xiaomi Redmi Note 13"},
+		{"Apple MacBook Air 13 M2 8/512GB Space Gray", "Apple MacBook Air 13 M2"},
+		{"AirPods Pro 2", "AirPods Pro 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := Model(tt.input); got != tt.expected {
+				t.Errorf("Model(%q) = %q, expected %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestKey(t *testing.T) {
+	if got := Key("Apple", "iPhone 15 Pro 128GB Blue"); got != "apple iphone 15 pro" {
+		t.Errorf("expected brand prefixed key, got %q", got)
+	}
+	if got := Key("Apple", "Apple iPhone 15 Pro 128GB Blue"); got != "apple iphone 15 pro" {
+		t.Errorf("expected brand not to repeat, got %q", got)
+	}
+	if got := Key("Apple", "256GB Black"); got != "" {
+		t.Errorf("expected empty key, got %q", got)
+	}
+}
+
+func TestCluster(t *testing.T) {
+	blue, black, white, pencil, lone := uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	members := []domain.ProductGroupMember{
+		{ProductID: blue, Brand: "Apple", Name: "Apple iPhone 15 Pro 128GB Blue Titanium", ProductURL: "/product/iphone-15-pro-128-blue/"},
+		{ProductID: black, Brand: "Apple", Name: "Apple iPhone 15 Pro 256GB Black Titanium", ProductURL: "/product/iphone-15-pro-256-black/"},
+		// Named differently, but linked from a variant selector.
+		{
+			ProductID:   white,
+			Brand:       "Apple",
+			Name:        "Apple iPhone 15 Pro eSIM 512GB White",
+			ProductURL:  "/product/iphone-15-pro-512-white/",
+			VariantURLs: []string{"https://store77.net/product/iphone-15-pro-128-blue/"},
+		},
+		{ProductID: pencil, Brand: "Apple", Name: "Apple Pencil Pro", ProductURL: "/product/pencil-pro/"},
+		{ProductID: lone, Brand: "Apple", Name: "Apple iPhone 15 Pro Max 256GB Blue"},
+	}
+
+	groups := Cluster(members)
+	if len(groups) != 1 {
+		t.Fatalf("expected 1 group, got %+v", groups)
+	}
+
+	g := groups[0]
+	if g.Key != "apple iphone 15 pro" || g.Name != "Apple iPhone 15 Pro" || g.Brand != "Apple" {
+		t.Errorf("unexpected group %+v", g)
+	}
+	if len(g.ProductIDs) != 3 || g.ProductIDs[0] != blue || g.ProductIDs[1] != black || g.ProductIDs[2] != white {
+		t.Errorf("expected the three iPhone 15 Pro variants, got %v", g.ProductIDs)
+	}
+}
+
+func TestCluster_SeparatesSources(t *testing.T) {
+	a, b, c := uuid.New(), uuid.New(), uuid.New()
+	members := []domain.ProductGroupMember{
+		{ProductID: a, Source: "store77", Brand: "Apple", Name: "Apple iPhone 15 128GB Blue", ProductURL: "/product/iphone-15/"},
+		{ProductID: b, Source: "other", Brand: "Apple", Name: "Apple iPhone 15 256GB Black", ProductURL: "/product/iphone-15/"},
+		{ProductID: c, Source: "other", Brand: "Apple", Name: "Apple iPhone 15 512GB Pink"},
+	}
+
+	groups := Cluster(members)
+	if len(groups) != 1 {
+		t.Fatalf("expected 1 group, got %+v", groups)
+	}
+
+	g := groups[0]
+	if g.Source != "other" || len(g.ProductIDs) != 2 || g.ProductIDs[0] != b || g.ProductIDs[1] != c {
+		t.Errorf("expected the two products of the other source, got %+v", g)
+	}
+}
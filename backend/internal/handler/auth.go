@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminAuth guards the admin API with a static bearer token. With no token
+// configured every admin request is refused.
+func AdminAuth(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			errorResponse(c, http.StatusForbidden, "admin API is disabled")
+			return
+		}
+
+		got, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			c.Header("WWW-Authenticate", `Bearer realm="admin"`)
+			errorResponse(c, http.StatusUnauthorized, "invalid admin token")
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -35,6 +35,23 @@ func (h *CategoryHandler) List(c *gin.Context) {
 	c.JSON(http.StatusOK, categories)
 }
 
+// @Summary      Category tree
+// @Description  Categories nested under their parents, top-level categories first
+// @Tags         categories
+// @Produce      json
+// @Success      200  {array}   domain.CategoryNode
+// @Failure      500  {object}  ErrorResponse
+// @Router       /categories/tree [get]
+func (h *CategoryHandler) Tree(c *gin.Context) {
+	tree, err := h.svc.GetTree(c.Request.Context())
+	if err != nil {
+		errorResponse(c, http.StatusInternalServerError, "failed to get category tree")
+		return
+	}
+
+	c.JSON(http.StatusOK, tree)
+}
+
 // @Summary      Get category by ID
 // @Tags         categories
 // @Produce      json
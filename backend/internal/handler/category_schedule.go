@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/burbble/marketplace/internal/domain"
+	"github.com/burbble/marketplace/internal/service"
+)
+
+type categoryScheduleRequest struct {
+	Cron    string `json:"cron" binding:"required" example:"*/15 * * * *"`
+	Enabled *bool  `json:"enabled"`
+}
+
+type CategoryScheduleHandler struct {
+	svc service.CategoryScheduleService
+}
+
+func NewCategoryScheduleHandler(svc service.CategoryScheduleService) *CategoryScheduleHandler {
+	return &CategoryScheduleHandler{svc: svc}
+}
+
+// @Summary      List category scrape schedules
+// @Tags         category-schedules
+// @Security     AdminToken
+// @Produce      json
+// @Success      200  {array}   domain.CategorySchedule
+// @Failure      401  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /admin/category-schedules [get]
+func (h *CategoryScheduleHandler) List(c *gin.Context) {
+	schedules, err := h.svc.GetAll(c.Request.Context())
+	if err != nil {
+		errorResponse(c, http.StatusInternalServerError, "failed to get category schedules")
+		return
+	}
+
+	c.JSON(http.StatusOK, schedules)
+}
+
+// @Summary      Set category scrape schedule
+// @Description  Creates or replaces the cron schedule of a category. Categories without a schedule are scraped every SCRAPE_INTERVAL.
+// @Tags         category-schedules
+// @Security     AdminToken
+// @Accept       json
+// @Produce      json
+// @Param        category_id  path      string                   true  "Category UUID"
+// @Param        schedule     body      categoryScheduleRequest  true  "Schedule"
+// @Success      200          {object}  domain.CategorySchedule
+// @Failure      400          {object}  ErrorResponse
+// @Failure      404          {object}  ErrorResponse
+// @Failure      401          {object}  ErrorResponse
+// @Failure      500          {object}  ErrorResponse
+// @Router       /admin/category-schedules/{category_id} [put]
+func (h *CategoryScheduleHandler) Set(c *gin.Context) {
+	categoryID, err := uuid.Parse(c.Param("category_id"))
+	if err != nil {
+		errorResponse(c, http.StatusBadRequest, "invalid category id")
+		return
+	}
+
+	var req categoryScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schedule := domain.CategorySchedule{CategoryID: categoryID, Cron: req.Cron, Enabled: true}
+	if req.Enabled != nil {
+		schedule.Enabled = *req.Enabled
+	}
+
+	if err := h.svc.Set(c.Request.Context(), &schedule); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidSchedule):
+			errorResponse(c, http.StatusBadRequest, err.Error())
+		case errors.Is(err, sql.ErrNoRows):
+			errorResponse(c, http.StatusNotFound, "category not found")
+		default:
+			errorResponse(c, http.StatusInternalServerError, "failed to set category schedule")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+// @Summary      Delete category scrape schedule
+// @Description  The category falls back to SCRAPE_INTERVAL.
+// @Tags         category-schedules
+// @Security     AdminToken
+// @Param        category_id  path  string  true  "Category UUID"
+// @Success      204
+// @Failure      400  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /admin/category-schedules/{category_id} [delete]
+func (h *CategoryScheduleHandler) Delete(c *gin.Context) {
+	categoryID, err := uuid.Parse(c.Param("category_id"))
+	if err != nil {
+		errorResponse(c, http.StatusBadRequest, "invalid category id")
+		return
+	}
+
+	if err := h.svc.Delete(c.Request.Context(), categoryID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			errorResponse(c, http.StatusNotFound, "category schedule not found")
+			return
+		}
+		errorResponse(c, http.StatusInternalServerError, "failed to delete category schedule")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
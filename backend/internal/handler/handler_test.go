@@ -7,13 +7,18 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	neturl "net/url"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 
 	"github.com/burbble/marketplace/internal/domain"
 	"github.com/burbble/marketplace/internal/mocks"
+	"github.com/burbble/marketplace/pkg/pagination"
 )
 
 func init() {
@@ -114,19 +119,331 @@ func TestProductHandler_List_WithFilters(t *testing.T) {
 	h := NewProductHandler(svc)
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
-	c.Request = httptest.NewRequest(http.MethodGet, "/products?brand=Apple&search=iphone&sort_fields=price:asc", nil)
+	c.Request = httptest.NewRequest(http.MethodGet, "/products?brand=Apple&search=iphone&sort_fields=price:asc&collapse_groups=true", nil)
 
 	h.List(c)
 
 	if w.Code != http.StatusOK {
 		t.Errorf("expected 200, got %d", w.Code)
 	}
-	if capturedFilter.Brand == nil || *capturedFilter.Brand != "Apple" {
+	if len(capturedFilter.Brands) != 1 || capturedFilter.Brands[0] != "Apple" {
 		t.Errorf("expected brand filter 'Apple'")
 	}
 	if capturedFilter.Search == nil || *capturedFilter.Search != "iphone" {
 		t.Errorf("expected search filter 'iphone'")
 	}
+	if capturedFilter.IncludeUnavailable {
+		t.Error("expected unavailable products to be hidden by default")
+	}
+	if !capturedFilter.CollapseGroups {
+		t.Error("expected collapse_groups to be passed")
+	}
+}
+
+func TestProductHandler_List_MultiValueFilters(t *testing.T) {
+	var capturedFilter domain.ProductFilter
+	svc := &mocks.ProductServiceMock{
+		GetByFilterFunc: func(_ context.Context, f domain.ProductFilter) (*domain.ProductList, error) {
+			capturedFilter = f
+			return &domain.ProductList{Products: []domain.Product{}, Page: 1, PageSize: 24}, nil
+		},
+	}
+
+	cat1, cat2 := uuid.New(), uuid.New()
+	url := "/products?brand=Apple&brand=Samsung&exclude_brand=Xiaomi" +
+		"&category_id=" + cat1.String() + "&category_id=" + cat2.String() +
+		"&on_sale=true&min_discount=10&max_discount=50&updated_since=2026-01-15&include_unavailable=true"
+
+	h := NewProductHandler(svc)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, url, nil)
+
+	h.List(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if len(capturedFilter.Brands) != 2 || capturedFilter.Brands[1] != "Samsung" {
+		t.Errorf("expected brands [Apple Samsung], got %v", capturedFilter.Brands)
+	}
+	if len(capturedFilter.ExcludeBrands) != 1 || capturedFilter.ExcludeBrands[0] != "Xiaomi" {
+		t.Errorf("expected excluded brands [Xiaomi], got %v", capturedFilter.ExcludeBrands)
+	}
+	if len(capturedFilter.CategoryIDs) != 2 || capturedFilter.CategoryIDs[0] != cat1 || capturedFilter.CategoryIDs[1] != cat2 {
+		t.Errorf("expected two category ids, got %v", capturedFilter.CategoryIDs)
+	}
+	if !capturedFilter.OnSale {
+		t.Error("expected on_sale filter")
+	}
+	if capturedFilter.MinDiscount == nil || *capturedFilter.MinDiscount != 10 || capturedFilter.MaxDiscount == nil || *capturedFilter.MaxDiscount != 50 {
+		t.Error("expected discount range 10-50")
+	}
+	if capturedFilter.UpdatedSince == nil || !capturedFilter.UpdatedSince.Equal(time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected updated_since 2026-01-15, got %v", capturedFilter.UpdatedSince)
+	}
+	if !capturedFilter.IncludeUnavailable {
+		t.Error("expected include_unavailable filter")
+	}
+}
+
+func TestProductHandler_List_AttributeFilters(t *testing.T) {
+	var capturedFilter domain.ProductFilter
+	svc := &mocks.ProductServiceMock{
+		GetByFilterFunc: func(_ context.Context, f domain.ProductFilter) (*domain.ProductList, error) {
+			capturedFilter = f
+			return &domain.ProductList{Products: []domain.Product{}, Page: 1, PageSize: 24}, nil
+		},
+	}
+
+	q := neturl.Values{}
+	q.Add("attr[Цвет]", "Черный")
+	q.Add("attr[Объем памяти]", "128 ГБ")
+	q.Add("attr[Объем памяти]", "256 ГБ")
+	q.Add("attr[Диагональ]", " ")
+	q.Add("brand", "Apple")
+
+	h := NewProductHandler(svc)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/products?"+q.Encode(), nil)
+
+	h.List(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	want := map[string][]string{
+		"Цвет":         {"Черный"},
+		"Объем памяти": {"128 ГБ", "256 ГБ"},
+	}
+	if !reflect.DeepEqual(capturedFilter.Attributes, want) {
+		t.Errorf("expected attributes %v, got %v", want, capturedFilter.Attributes)
+	}
+}
+
+func TestProductHandler_List_InvalidFilterValues(t *testing.T) {
+	tooMany := neturl.Values{}
+	for i := range maxAttributeFilters + 1 {
+		tooMany.Set(fmt.Sprintf("attr[a%d]", i), "x")
+	}
+
+	tests := []string{
+		"/products?" + tooMany.Encode(),
+		"/products?attr%5B%5D=x",
+		"/products?attr%5Bcolor=x",
+		"/products?min_discount=-5",
+		"/products?max_discount=150",
+		"/products?min_discount=60&max_discount=20",
+		"/products?updated_since=yesterday",
+		"/products?category_id=" + uuid.New().String() + "&category_id=bad",
+	}
+
+	for _, url := range tests {
+		svc := &mocks.ProductServiceMock{}
+		h := NewProductHandler(svc)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, url, nil)
+
+		h.List(c)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", url, w.Code)
+		}
+	}
+}
+
+func TestProductHandler_List_Cursor(t *testing.T) {
+	var capturedFilter domain.ProductFilter
+	svc := &mocks.ProductServiceMock{
+		GetByFilterFunc: func(_ context.Context, f domain.ProductFilter) (*domain.ProductList, error) {
+			capturedFilter = f
+			return &domain.ProductList{Products: []domain.Product{}, Total: domain.TotalSkipped, PageSize: 24}, nil
+		},
+	}
+
+	lastID := uuid.New()
+	token, err := pagination.EncodeCursor(domain.ProductCursor{
+		SortBy: []string{"price ASC", "created_at DESC"},
+		Values: []any{1990, time.Date(2026, 2, 1, 10, 0, 0, 0, time.UTC)},
+		ID:     lastID,
+	})
+	if err != nil {
+		t.Fatalf("failed to encode cursor: %v", err)
+	}
+
+	h := NewProductHandler(svc)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/products?page=3&skip_total=true&sort_fields=price:asc,created_at:desc&cursor="+token, nil)
+
+	h.List(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if capturedFilter.Cursor == nil || capturedFilter.Cursor.ID != lastID {
+		t.Fatal("expected decoded cursor to be passed")
+	}
+	if capturedFilter.Offset != 0 {
+		t.Errorf("expected offset to be ignored in cursor mode, got %d", capturedFilter.Offset)
+	}
+	if !capturedFilter.SkipTotal {
+		t.Error("expected skip_total to be passed")
+	}
+}
+
+func TestProductHandler_List_InvalidCursor(t *testing.T) {
+	mismatched, _ := pagination.EncodeCursor(domain.ProductCursor{
+		SortBy: []string{"name ASC"},
+		Values: []any{"iPhone"},
+		ID:     uuid.New(),
+	})
+	badValue, _ := pagination.EncodeCursor(domain.ProductCursor{
+		SortBy: []string{"price ASC"},
+		Values: []any{"cheap"},
+		ID:     uuid.New(),
+	})
+
+	tests := []string{
+		"/products?cursor=not-a-cursor",
+		"/products?sort_fields=price:asc&cursor=" + mismatched,
+		"/products?sort_fields=price:asc&cursor=" + badValue,
+	}
+
+	for _, url := range tests {
+		svc := &mocks.ProductServiceMock{}
+		h := NewProductHandler(svc)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, url, nil)
+
+		h.List(c)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", url, w.Code)
+		}
+	}
+}
+
+func TestProductHandler_List_SearchDefaultsToRelevance(t *testing.T) {
+	var capturedFilter domain.ProductFilter
+	svc := &mocks.ProductServiceMock{
+		GetByFilterFunc: func(_ context.Context, f domain.ProductFilter) (*domain.ProductList, error) {
+			capturedFilter = f
+			return &domain.ProductList{Products: []domain.Product{}, Page: 1, PageSize: 24}, nil
+		},
+	}
+
+	h := NewProductHandler(svc)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/products?search=%D1%87%D0%B5%D1%85%D0%BB%D1%8B", nil)
+
+	h.List(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if len(capturedFilter.SortBy) != 1 || capturedFilter.SortBy[0] != "relevance DESC" {
+		t.Errorf("expected relevance sort, got %v", capturedFilter.SortBy)
+	}
+}
+
+func TestProductHandler_List_RelevanceRequiresSearch(t *testing.T) {
+	svc := &mocks.ProductServiceMock{}
+	h := NewProductHandler(svc)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/products?sort_fields=relevance:desc", nil)
+
+	h.List(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestProductHandler_Facets_Success(t *testing.T) {
+	svc := &mocks.ProductServiceMock{
+		GetFacetsFunc: func(_ context.Context, _ domain.ProductFilter, _ int) (*domain.ProductFacets, error) {
+			return &domain.ProductFacets{
+				Total:  42,
+				Brands: []domain.BrandFacet{{Brand: "Apple", Count: 42}},
+				Price:  &domain.PriceRange{Min: 1000, Max: 5000},
+			}, nil
+		},
+	}
+
+	h := NewProductHandler(svc)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/products/facets?brand=Apple&min_price=1000&price_buckets=500", nil)
+
+	h.Facets(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+
+	var resp domain.ProductFacets
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+	if resp.Total != 42 || len(resp.Brands) != 1 || resp.Brands[0].Count != 42 {
+		t.Errorf("unexpected facets: %+v", resp)
+	}
+
+	calls := svc.GetFacetsCalls()
+	if len(calls) != 1 {
+		t.Fatalf("expected 1 call to GetFacets, got %d", len(calls))
+	}
+	if len(calls[0].Filter.Brands) != 1 || calls[0].Filter.Brands[0] != "Apple" {
+		t.Error("expected brand filter to be passed")
+	}
+	if calls[0].Filter.MinPrice == nil || *calls[0].Filter.MinPrice != 1000 {
+		t.Error("expected min_price filter to be passed")
+	}
+	if calls[0].PriceBuckets != domain.MaxPriceBuckets {
+		t.Errorf("expected price buckets capped at %d, got %d", domain.MaxPriceBuckets, calls[0].PriceBuckets)
+	}
+}
+
+func TestProductHandler_Facets_DefaultBuckets(t *testing.T) {
+	svc := &mocks.ProductServiceMock{
+		GetFacetsFunc: func(_ context.Context, _ domain.ProductFilter, _ int) (*domain.ProductFacets, error) {
+			return &domain.ProductFacets{}, nil
+		},
+	}
+
+	h := NewProductHandler(svc)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/products/facets", nil)
+
+	h.Facets(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if svc.GetFacetsCalls()[0].PriceBuckets != domain.DefaultPriceBuckets {
+		t.Errorf("expected default price buckets, got %d", svc.GetFacetsCalls()[0].PriceBuckets)
+	}
+}
+
+func TestProductHandler_Facets_InvalidCategoryID(t *testing.T) {
+	svc := &mocks.ProductServiceMock{}
+	h := NewProductHandler(svc)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/products/facets?category_id=bad", nil)
+
+	h.Facets(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", w.Code)
+	}
 }
 
 func TestProductHandler_GetByID_Success(t *testing.T) {
@@ -156,6 +473,214 @@ func TestProductHandler_GetByID_Success(t *testing.T) {
 	}
 }
 
+func TestProductGroupHandler_GetByID(t *testing.T) {
+	id := uuid.New()
+	svc := &mocks.ProductGroupServiceMock{
+		GetByIDFunc: func(_ context.Context, gotID uuid.UUID) (*domain.ProductGroup, error) {
+			if gotID != id {
+				return nil, sql.ErrNoRows
+			}
+			return &domain.ProductGroup{
+				ID:       id,
+				Name:     "Apple iPhone 15 Pro",
+				MinPrice: 99990,
+				MaxPrice: 149990,
+				Variants: []domain.Product{{Name: "Apple iPhone 15 Pro 128GB Blue", Price: 99990, GroupID: &id}},
+			}, nil
+		},
+	}
+	h := NewProductGroupHandler(svc)
+
+	tests := []struct {
+		id   string
+		code int
+	}{
+		{id.String(), http.StatusOK},
+		{uuid.New().String(), http.StatusNotFound},
+		{"bad-id", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/product-groups/"+tt.id, nil)
+		c.Params = gin.Params{{Key: "id", Value: tt.id}}
+
+		h.GetByID(c)
+
+		if w.Code != tt.code {
+			t.Errorf("%s: expected %d, got %d", tt.id, tt.code, w.Code)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/product-groups/"+id.String(), nil)
+	c.Params = gin.Params{{Key: "id", Value: id.String()}}
+	h.GetByID(c)
+
+	var resp domain.ProductGroup
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+	if len(resp.Variants) != 1 || resp.Variants[0].GroupID == nil || *resp.Variants[0].GroupID != id || resp.MinPrice != 99990 {
+		t.Errorf("unexpected group: %+v", resp)
+	}
+}
+
+func TestProductMatchHandler_Offers(t *testing.T) {
+	id := uuid.New()
+	svc := &mocks.ProductMatchServiceMock{
+		GetOffersFunc: func(_ context.Context, productID uuid.UUID) ([]domain.ProductOffer, error) {
+			if productID != id {
+				return nil, sql.ErrNoRows
+			}
+			return []domain.ProductOffer{
+				{ProductID: id, Source: "store77", Price: 99990},
+				{ProductID: uuid.New(), Source: "other", Price: 97990},
+			}, nil
+		},
+	}
+	h := NewProductMatchHandler(svc)
+
+	tests := []struct {
+		id   string
+		code int
+	}{
+		{id.String(), http.StatusOK},
+		{uuid.New().String(), http.StatusNotFound},
+		{"bad-id", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/products/"+tt.id+"/offers", nil)
+		c.Params = gin.Params{{Key: "id", Value: tt.id}}
+
+		h.Offers(c)
+
+		if w.Code != tt.code {
+			t.Errorf("%s: expected %d, got %d", tt.id, tt.code, w.Code)
+		}
+		if tt.code != http.StatusOK {
+			continue
+		}
+
+		var resp []domain.ProductOffer
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to unmarshal: %v", err)
+		}
+		if len(resp) != 2 || resp[0].ProductID != id || resp[1].Source != "other" {
+			t.Errorf("unexpected offers: %+v", resp)
+		}
+	}
+}
+
+func TestProductMatchHandler_List(t *testing.T) {
+	productID := uuid.New()
+	svc := &mocks.ProductMatchServiceMock{
+		GetListFunc: func(_ context.Context, f domain.ProductMatchFilter) (*domain.ProductMatchList, error) {
+			return &domain.ProductMatchList{Matches: []domain.ProductMatch{}, Page: 1, PageSize: f.Limit}, nil
+		},
+	}
+
+	h := NewProductMatchHandler(svc)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/admin/product-matches?status=suggested&product_id="+productID.String()+"&page=3&page_size=5", nil)
+
+	h.List(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+
+	calls := svc.GetListCalls()
+	if len(calls) != 1 {
+		t.Fatalf("expected 1 call to GetList, got %d", len(calls))
+	}
+	f := calls[0].Filter
+	if f.Status == nil || *f.Status != domain.ProductMatchSuggested {
+		t.Error("expected status filter 'suggested'")
+	}
+	if f.ProductID == nil || *f.ProductID != productID {
+		t.Error("expected product_id filter")
+	}
+	if f.Limit != 5 || f.Offset != 10 {
+		t.Errorf("expected limit 5 offset 10, got %d/%d", f.Limit, f.Offset)
+	}
+}
+
+func TestProductMatchHandler_List_InvalidQuery(t *testing.T) {
+	h := NewProductMatchHandler(&mocks.ProductMatchServiceMock{})
+
+	for _, query := range []string{"status=maybe", "product_id=bad-id", "page_size=500"} {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/admin/product-matches?"+query, nil)
+
+		h.List(c)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", query, w.Code)
+		}
+	}
+}
+
+func TestProductMatchHandler_ConfirmReject(t *testing.T) {
+	id, conflicting := uuid.New(), uuid.New()
+	svc := &mocks.ProductMatchServiceMock{
+		SetStatusFunc: func(_ context.Context, gotID uuid.UUID, status string) (*domain.ProductMatch, error) {
+			if gotID == conflicting {
+				return nil, domain.ErrProductMatchConflict
+			}
+			if gotID != id {
+				return nil, sql.ErrNoRows
+			}
+			return &domain.ProductMatch{ID: id, Status: status}, nil
+		},
+	}
+	h := NewProductMatchHandler(svc)
+
+	tests := []struct {
+		name   string
+		action func(*gin.Context)
+		id     string
+		code   int
+		status string
+	}{
+		{"confirm", h.Confirm, id.String(), http.StatusOK, domain.ProductMatchConfirmed},
+		{"reject", h.Reject, id.String(), http.StatusOK, domain.ProductMatchRejected},
+		{"unknown match", h.Confirm, uuid.New().String(), http.StatusNotFound, ""},
+		{"already confirmed elsewhere", h.Confirm, conflicting.String(), http.StatusConflict, ""},
+		{"bad id", h.Reject, "bad-id", http.StatusBadRequest, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/admin/product-matches/x", nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.id}}
+
+			tt.action(c)
+
+			if w.Code != tt.code {
+				t.Fatalf("expected %d, got %d", tt.code, w.Code)
+			}
+			if tt.status == "" {
+				return
+			}
+
+			var resp domain.ProductMatch
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to unmarshal: %v", err)
+			}
+			if resp.Status != tt.status {
+				t.Errorf("expected status %q, got %q", tt.status, resp.Status)
+			}
+		})
+	}
+}
+
 func TestProductHandler_GetByID_InvalidUUID(t *testing.T) {
 	svc := &mocks.ProductServiceMock{}
 	h := NewProductHandler(svc)
@@ -257,8 +782,108 @@ func TestProductHandler_GetBrands_Error(t *testing.T) {
 
 	h.GetBrands(c)
 
-	if w.Code != http.StatusInternalServerError {
-		t.Errorf("expected 500, got %d", w.Code)
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500, got %d", w.Code)
+	}
+}
+
+func TestProductHandler_GetPriceHistory_Success(t *testing.T) {
+	id := uuid.New()
+	var capturedFilter domain.PriceHistoryFilter
+	svc := &mocks.ProductServiceMock{
+		GetPriceHistoryFunc: func(_ context.Context, f domain.PriceHistoryFilter) (*domain.PriceHistory, error) {
+			capturedFilter = f
+			return &domain.PriceHistory{
+				ProductID: f.ProductID,
+				Interval:  f.Interval,
+				Points:    []domain.PricePoint{{Price: 49000}, {Price: 47000}},
+			}, nil
+		},
+	}
+
+	h := NewProductHandler(svc)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/products/"+id.String()+"/price-history?from=2026-01-01&to=2026-02-01T00:00:00Z&interval=day", nil)
+	c.Params = gin.Params{{Key: "id", Value: id.String()}}
+
+	h.GetPriceHistory(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if capturedFilter.ProductID != id {
+		t.Errorf("expected product id %s, got %s", id, capturedFilter.ProductID)
+	}
+	if capturedFilter.Interval != "day" {
+		t.Errorf("expected interval 'day', got %q", capturedFilter.Interval)
+	}
+	if capturedFilter.From == nil || !capturedFilter.From.Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected from: %v", capturedFilter.From)
+	}
+	if capturedFilter.To == nil || !capturedFilter.To.Equal(time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected to: %v", capturedFilter.To)
+	}
+
+	var result domain.PriceHistory
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatalf("unmarshal response: %v", err)
+	}
+	if len(result.Points) != 2 {
+		t.Errorf("expected 2 points, got %d", len(result.Points))
+	}
+}
+
+func TestProductHandler_GetPriceHistory_BadRequest(t *testing.T) {
+	id := uuid.New().String()
+	tests := []struct {
+		name  string
+		id    string
+		query string
+	}{
+		{"invalid uuid", "bad-id", ""},
+		{"invalid interval", id, "?interval=minute"},
+		{"invalid from", id, "?from=yesterday"},
+		{"invalid to", id, "?to=2026-13-01"},
+		{"from after to", id, "?from=2026-02-01&to=2026-01-01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &mocks.ProductServiceMock{}
+			h := NewProductHandler(svc)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/products/"+tt.id+"/price-history"+tt.query, nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.id}}
+
+			h.GetPriceHistory(c)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("expected 400, got %d", w.Code)
+			}
+		})
+	}
+}
+
+func TestProductHandler_GetPriceHistory_NotFound(t *testing.T) {
+	svc := &mocks.ProductServiceMock{
+		GetPriceHistoryFunc: func(_ context.Context, _ domain.PriceHistoryFilter) (*domain.PriceHistory, error) {
+			return nil, sql.ErrNoRows
+		},
+	}
+
+	h := NewProductHandler(svc)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	id := uuid.New()
+	c.Request = httptest.NewRequest(http.MethodGet, "/products/"+id.String()+"/price-history", nil)
+	c.Params = gin.Params{{Key: "id", Value: id.String()}}
+
+	h.GetPriceHistory(c)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", w.Code)
 	}
 }
 
@@ -315,6 +940,62 @@ func TestCategoryHandler_List_Error(t *testing.T) {
 	}
 }
 
+func TestCategoryHandler_Tree_Success(t *testing.T) {
+	svc := &mocks.CategoryServiceMock{
+		GetTreeFunc: func(_ context.Context) ([]domain.CategoryNode, error) {
+			return []domain.CategoryNode{
+				{
+					Category: domain.Category{Name: "Phones", Slug: "phones"},
+					Children: []domain.CategoryNode{
+						{Category: domain.Category{Name: "Apple", Slug: "phones/apple", Depth: 1}, Children: []domain.CategoryNode{}},
+					},
+				},
+			}, nil
+		},
+	}
+
+	h := NewCategoryHandler(svc)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/categories/tree", nil)
+
+	h.Tree(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+
+	var tree []domain.CategoryNode
+	if err := json.Unmarshal(w.Body.Bytes(), &tree); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if len(tree) != 1 || tree[0].Slug != "phones" {
+		t.Fatalf("unexpected tree: %+v", tree)
+	}
+	if len(tree[0].Children) != 1 || tree[0].Children[0].Depth != 1 {
+		t.Errorf("expected one child at depth 1, got %+v", tree[0].Children)
+	}
+}
+
+func TestCategoryHandler_Tree_Error(t *testing.T) {
+	svc := &mocks.CategoryServiceMock{
+		GetTreeFunc: func(_ context.Context) ([]domain.CategoryNode, error) {
+			return nil, fmt.Errorf("db error")
+		},
+	}
+
+	h := NewCategoryHandler(svc)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/categories/tree", nil)
+
+	h.Tree(c)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500, got %d", w.Code)
+	}
+}
+
 func TestCategoryHandler_GetByID_Success(t *testing.T) {
 	id := uuid.New()
 	svc := &mocks.CategoryServiceMock{
@@ -472,3 +1153,508 @@ func TestProductHandler_List_DefaultPagination(t *testing.T) {
 		t.Errorf("expected offset 0, got %d", capturedFilter.Offset)
 	}
 }
+
+func TestPricingRuleHandler_Create_Success(t *testing.T) {
+	svc := &mocks.PricingRuleServiceMock{
+		CreateFunc: func(_ context.Context, rule *domain.PricingRule) error {
+			rule.ID = uuid.New()
+			return nil
+		},
+	}
+
+	h := NewPricingRuleHandler(svc)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	body := `{"name":"Apple -5%","priority":10,"brand":"Apple","adjustment_type":"percent","adjustment_value":-5,"rounding_mode":"down","rounding_step":1000,"rounding_ending":990}`
+	c.Request = httptest.NewRequest(http.MethodPost, "/admin/pricing-rules", strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	h.Create(c)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	calls := svc.CreateCalls()
+	if len(calls) != 1 {
+		t.Fatalf("expected 1 call to Create, got %d", len(calls))
+	}
+	rule := calls[0].Rule
+	if !rule.Enabled {
+		t.Error("expected rule to be enabled by default")
+	}
+	if rule.Brand == nil || *rule.Brand != "Apple" {
+		t.Errorf("expected brand 'Apple', got %v", rule.Brand)
+	}
+	if rule.RoundingEnding != 990 {
+		t.Errorf("expected rounding ending 990, got %d", rule.RoundingEnding)
+	}
+}
+
+func TestPricingRuleHandler_Create_InvalidRule(t *testing.T) {
+	svc := &mocks.PricingRuleServiceMock{
+		CreateFunc: func(_ context.Context, rule *domain.PricingRule) error {
+			return rule.Validate()
+		},
+	}
+
+	h := NewPricingRuleHandler(svc)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	body := `{"name":"broken","adjustment_type":"multiply"}`
+	c.Request = httptest.NewRequest(http.MethodPost, "/admin/pricing-rules", strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	h.Create(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestPricingRuleHandler_Delete_NotFound(t *testing.T) {
+	svc := &mocks.PricingRuleServiceMock{
+		DeleteFunc: func(_ context.Context, _ uuid.UUID) error {
+			return sql.ErrNoRows
+		},
+	}
+
+	h := NewPricingRuleHandler(svc)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	id := uuid.New()
+	c.Request = httptest.NewRequest(http.MethodDelete, "/admin/pricing-rules/"+id.String(), nil)
+	c.Params = gin.Params{{Key: "id", Value: id.String()}}
+
+	h.Delete(c)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", w.Code)
+	}
+}
+
+func TestPricingRuleHandler_DryRun(t *testing.T) {
+	tests := []struct {
+		name          string
+		body          string
+		query         string
+		expectedRules int
+		storedRules   bool
+		expectedLimit int
+	}{
+		{"stored rules", "", "", 0, true, 100},
+		{"candidate rules", `{"rules":[{"name":"x","adjustment_type":"fixed","adjustment_value":-500}]}`, "?limit=5000", 1, false, 1000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &mocks.PricingRuleServiceMock{
+				DryRunFunc: func(_ context.Context, _ []domain.PricingRule, _ int) (*domain.PricingDryRun, error) {
+					return &domain.PricingDryRun{Items: []domain.PricingDryRunItem{}}, nil
+				},
+			}
+
+			h := NewPricingRuleHandler(svc)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/admin/pricing-rules/dry-run"+tt.query, strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			h.DryRun(c)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+			}
+
+			calls := svc.DryRunCalls()
+			if len(calls) != 1 {
+				t.Fatalf("expected 1 call to DryRun, got %d", len(calls))
+			}
+			if (calls[0].Rules == nil) != tt.storedRules {
+				t.Errorf("expected stored rules = %v, got rules %v", tt.storedRules, calls[0].Rules)
+			}
+			if len(calls[0].Rules) != tt.expectedRules {
+				t.Errorf("expected %d rules, got %d", tt.expectedRules, len(calls[0].Rules))
+			}
+			if calls[0].Limit != tt.expectedLimit {
+				t.Errorf("expected limit %d, got %d", tt.expectedLimit, calls[0].Limit)
+			}
+		})
+	}
+}
+
+func TestSearchHandler_Suggest(t *testing.T) {
+	svc := &mocks.SearchServiceMock{
+		SuggestFunc: func(_ context.Context, q string, _ int) ([]domain.Suggestion, error) {
+			return []domain.Suggestion{{Text: "Apple", Kind: domain.SuggestionBrand, Score: 1}}, nil
+		},
+	}
+
+	h := NewSearchHandler(svc)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/search/suggest?q=%D1%8D%D0%BF%D0%BB&limit=50", nil)
+
+	h.Suggest(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+
+	calls := svc.SuggestCalls()
+	if len(calls) != 1 {
+		t.Fatalf("expected 1 call to Suggest, got %d", len(calls))
+	}
+	if calls[0].Q != "эпл" {
+		t.Errorf("expected query 'эпл', got %q", calls[0].Q)
+	}
+	if calls[0].Limit != 20 {
+		t.Errorf("expected limit capped at 20, got %d", calls[0].Limit)
+	}
+}
+
+func TestSearchHandler_Suggest_MissingQuery(t *testing.T) {
+	svc := &mocks.SearchServiceMock{}
+	h := NewSearchHandler(svc)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/search/suggest", nil)
+
+	h.Suggest(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestScrapeRunHandler_List(t *testing.T) {
+	svc := &mocks.ScrapeRunServiceMock{
+		GetListFunc: func(_ context.Context, f domain.ScrapeRunFilter) (*domain.ScrapeRunList, error) {
+			return &domain.ScrapeRunList{Runs: []domain.ScrapeRun{{Status: domain.ScrapeStatusFailed}}, Total: 1, Page: 1, PageSize: f.Limit}, nil
+		},
+	}
+
+	h := NewScrapeRunHandler(svc)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/admin/scrape-runs?status=failed&markup_drift=true&page=2&page_size=10", nil)
+
+	h.List(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+
+	calls := svc.GetListCalls()
+	if len(calls) != 1 {
+		t.Fatalf("expected 1 call to GetList, got %d", len(calls))
+	}
+	if calls[0].Filter.Status == nil || *calls[0].Filter.Status != domain.ScrapeStatusFailed {
+		t.Error("expected status filter 'failed'")
+	}
+	if calls[0].Filter.MarkupDrift == nil || !*calls[0].Filter.MarkupDrift {
+		t.Error("expected markup_drift filter")
+	}
+	if calls[0].Filter.Limit != 10 || calls[0].Filter.Offset != 10 {
+		t.Errorf("expected limit 10 offset 10, got %d/%d", calls[0].Filter.Limit, calls[0].Filter.Offset)
+	}
+}
+
+func TestScrapeRunHandler_List_InvalidStatus(t *testing.T) {
+	svc := &mocks.ScrapeRunServiceMock{}
+	h := NewScrapeRunHandler(svc)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/admin/scrape-runs?status=exploded", nil)
+
+	h.List(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestScrapeRunHandler_GetByID_NotFound(t *testing.T) {
+	svc := &mocks.ScrapeRunServiceMock{
+		GetByIDFunc: func(_ context.Context, _ uuid.UUID) (*domain.ScrapeRunDetail, error) {
+			return nil, sql.ErrNoRows
+		},
+	}
+
+	h := NewScrapeRunHandler(svc)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = gin.Params{{Key: "id", Value: uuid.New().String()}}
+	c.Request = httptest.NewRequest(http.MethodGet, "/admin/scrape-runs/x", nil)
+
+	h.GetByID(c)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", w.Code)
+	}
+}
+
+func TestCategoryScheduleHandler_Set(t *testing.T) {
+	svc := &mocks.CategoryScheduleServiceMock{
+		SetFunc: func(_ context.Context, _ *domain.CategorySchedule) error {
+			return nil
+		},
+	}
+
+	h := NewCategoryScheduleHandler(svc)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	id := uuid.New()
+	c.Request = httptest.NewRequest(http.MethodPut, "/admin/category-schedules/"+id.String(), strings.NewReader(`{"cron":"*/15 * * * *"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "category_id", Value: id.String()}}
+
+	h.Set(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	calls := svc.SetCalls()
+	if len(calls) != 1 {
+		t.Fatalf("expected 1 call to Set, got %d", len(calls))
+	}
+	s := calls[0].Schedule
+	if s.CategoryID != id || s.Cron != "*/15 * * * *" || !s.Enabled {
+		t.Errorf("unexpected schedule %+v", s)
+	}
+}
+
+func TestCategoryScheduleHandler_Set_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		categoryID string
+		body       string
+		err        error
+		wantStatus int
+	}{
+		{"invalid category id", "abc", `{"cron":"@hourly"}`, nil, http.StatusBadRequest},
+		{"missing cron", uuid.New().String(), `{}`, nil, http.StatusBadRequest},
+		{"invalid cron", uuid.New().String(), `{"cron":"sometimes"}`, domain.ErrInvalidSchedule, http.StatusBadRequest},
+		{"unknown category", uuid.New().String(), `{"cron":"@hourly"}`, sql.ErrNoRows, http.StatusNotFound},
+		{"service error", uuid.New().String(), `{"cron":"@hourly"}`, fmt.Errorf("db down"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &mocks.CategoryScheduleServiceMock{
+				SetFunc: func(_ context.Context, _ *domain.CategorySchedule) error {
+					return tt.err
+				},
+			}
+
+			h := NewCategoryScheduleHandler(svc)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPut, "/admin/category-schedules/"+tt.categoryID, strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = gin.Params{{Key: "category_id", Value: tt.categoryID}}
+
+			h.Set(c)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected %d, got %d", tt.wantStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestCategoryScheduleHandler_Delete_NotFound(t *testing.T) {
+	svc := &mocks.CategoryScheduleServiceMock{
+		DeleteFunc: func(_ context.Context, _ uuid.UUID) error {
+			return sql.ErrNoRows
+		},
+	}
+
+	h := NewCategoryScheduleHandler(svc)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	id := uuid.New()
+	c.Request = httptest.NewRequest(http.MethodDelete, "/admin/category-schedules/"+id.String(), nil)
+	c.Params = gin.Params{{Key: "category_id", Value: id.String()}}
+
+	h.Delete(c)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", w.Code)
+	}
+}
+
+func TestAdminAuth(t *testing.T) {
+	tests := []struct {
+		name       string
+		token      string
+		header     string
+		wantStatus int
+	}{
+		{"valid token", "secret", "Bearer secret", http.StatusOK},
+		{"wrong token", "secret", "Bearer guess", http.StatusUnauthorized},
+		{"missing header", "secret", "", http.StatusUnauthorized},
+		{"not a bearer token", "secret", "secret", http.StatusUnauthorized},
+		{"admin API disabled", "", "Bearer ", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/admin", AdminAuth(tt.token), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected %d, got %d", tt.wantStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestScrapeRequestHandler_Create(t *testing.T) {
+	svc := &mocks.ScrapeRequestServiceMock{
+		CreateFunc: func(_ context.Context, req *domain.ScrapeRequest) error {
+			req.ID = uuid.New()
+			req.Status = domain.ScrapeRequestPending
+			return nil
+		},
+	}
+
+	h := NewScrapeRequestHandler(svc)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	id := uuid.New()
+	c.Request = httptest.NewRequest(http.MethodPost, "/admin/scrapes", strings.NewReader(`{"category_ids":["`+id.String()+`"],"full_refresh":true}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	h.Create(c)
+
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d: %s", w.Code, w.Body.String())
+	}
+
+	calls := svc.CreateCalls()
+	if len(calls) != 1 {
+		t.Fatalf("expected 1 call to Create, got %d", len(calls))
+	}
+	req := calls[0].Req
+	if len(req.CategoryIDs) != 1 || req.CategoryIDs[0] != id || !req.FullRefresh {
+		t.Errorf("unexpected request %+v", req)
+	}
+
+	var resp domain.ScrapeRequest
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp.Status != domain.ScrapeRequestPending {
+		t.Errorf("expected status pending, got %q", resp.Status)
+	}
+}
+
+func TestScrapeRequestHandler_Create_NoBody(t *testing.T) {
+	svc := &mocks.ScrapeRequestServiceMock{
+		CreateFunc: func(_ context.Context, _ *domain.ScrapeRequest) error {
+			return nil
+		},
+	}
+
+	h := NewScrapeRequestHandler(svc)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/admin/scrapes", nil)
+
+	h.Create(c)
+
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d: %s", w.Code, w.Body.String())
+	}
+
+	calls := svc.CreateCalls()
+	if len(calls) != 1 || len(calls[0].Req.CategoryIDs) != 0 || calls[0].Req.FullRefresh {
+		t.Errorf("expected a whole-catalog request, got %+v", calls)
+	}
+}
+
+func TestScrapeRequestHandler_Create_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		err        error
+		wantStatus int
+	}{
+		{"malformed body", `{"category_ids":["abc"]}`, nil, http.StatusBadRequest},
+		{"unknown category", `{"category_ids":["` + uuid.New().String() + `"]}`, domain.ErrInvalidScrapeRequest, http.StatusBadRequest},
+		{"service error", `{}`, fmt.Errorf("db down"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &mocks.ScrapeRequestServiceMock{
+				CreateFunc: func(_ context.Context, _ *domain.ScrapeRequest) error {
+					return tt.err
+				},
+			}
+
+			h := NewScrapeRequestHandler(svc)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/admin/scrapes", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			h.Create(c)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected %d, got %d", tt.wantStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestScrapeRequestHandler_Cancel(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{"cancelled", nil, http.StatusAccepted},
+		{"not found", sql.ErrNoRows, http.StatusNotFound},
+		{"already finished", domain.ErrScrapeRequestFinished, http.StatusConflict},
+		{"service error", fmt.Errorf("db down"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &mocks.ScrapeRequestServiceMock{
+				CancelFunc: func(_ context.Context, id uuid.UUID) (*domain.ScrapeRequest, error) {
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					return &domain.ScrapeRequest{ID: id, Status: domain.ScrapeRequestRunning, CancelRequested: true}, nil
+				},
+			}
+
+			h := NewScrapeRequestHandler(svc)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			id := uuid.New()
+			c.Request = httptest.NewRequest(http.MethodDelete, "/admin/scrapes/"+id.String(), nil)
+			c.Params = gin.Params{{Key: "id", Value: id.String()}}
+
+			h.Cancel(c)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected %d, got %d", tt.wantStatus, w.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,287 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/burbble/marketplace/internal/domain"
+	"github.com/burbble/marketplace/internal/service"
+)
+
+const (
+	defaultDryRunLimit = 100
+	maxDryRunLimit     = 1000
+)
+
+type pricingRuleRequest struct {
+	Name            string     `json:"name" binding:"required"`
+	Priority        int        `json:"priority"`
+	Enabled         *bool      `json:"enabled"`
+	CategoryID      *uuid.UUID `json:"category_id"`
+	Brand           *string    `json:"brand"`
+	MinPrice        *int       `json:"min_price"`
+	MaxPrice        *int       `json:"max_price"`
+	AdjustmentType  string     `json:"adjustment_type" binding:"required" enums:"fixed,percent"`
+	AdjustmentValue float64    `json:"adjustment_value"`
+	RoundingMode    string     `json:"rounding_mode" enums:"none,down,up,nearest"`
+	RoundingStep    int        `json:"rounding_step"`
+	RoundingEnding  int        `json:"rounding_ending"`
+	FloorPrice      *int       `json:"floor_price"`
+	CeilingPrice    *int       `json:"ceiling_price"`
+}
+
+func (r pricingRuleRequest) toDomain() domain.PricingRule {
+	rule := domain.PricingRule{
+		Name:            r.Name,
+		Priority:        r.Priority,
+		Enabled:         true,
+		CategoryID:      r.CategoryID,
+		Brand:           r.Brand,
+		MinPrice:        r.MinPrice,
+		MaxPrice:        r.MaxPrice,
+		AdjustmentType:  r.AdjustmentType,
+		AdjustmentValue: r.AdjustmentValue,
+		RoundingMode:    r.RoundingMode,
+		RoundingStep:    r.RoundingStep,
+		RoundingEnding:  r.RoundingEnding,
+		FloorPrice:      r.FloorPrice,
+		CeilingPrice:    r.CeilingPrice,
+	}
+
+	if r.Enabled != nil {
+		rule.Enabled = *r.Enabled
+	}
+	if rule.RoundingMode == "" {
+		rule.RoundingMode = domain.RoundingNone
+	}
+
+	return rule
+}
+
+type pricingDryRunRequest struct {
+	Rules []pricingRuleRequest `json:"rules"`
+}
+
+type pricingDryRunQuery struct {
+	Limit int `form:"limit" binding:"omitempty,min=0"`
+}
+
+type PricingRuleHandler struct {
+	svc service.PricingRuleService
+}
+
+func NewPricingRuleHandler(svc service.PricingRuleService) *PricingRuleHandler {
+	return &PricingRuleHandler{svc: svc}
+}
+
+// @Summary      List pricing rules
+// @Tags         pricing
+// @Security     AdminToken
+// @Produce      json
+// @Success      200  {array}   domain.PricingRule
+// @Failure      401  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /admin/pricing-rules [get]
+func (h *PricingRuleHandler) List(c *gin.Context) {
+	rules, err := h.svc.GetAll(c.Request.Context())
+	if err != nil {
+		errorResponse(c, http.StatusInternalServerError, "failed to get pricing rules")
+		return
+	}
+
+	c.JSON(http.StatusOK, rules)
+}
+
+// @Summary      Get pricing rule by ID
+// @Tags         pricing
+// @Security     AdminToken
+// @Produce      json
+// @Param        id   path      string  true  "Pricing rule UUID"
+// @Success      200  {object}  domain.PricingRule
+// @Failure      400  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /admin/pricing-rules/{id} [get]
+func (h *PricingRuleHandler) GetByID(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errorResponse(c, http.StatusBadRequest, "invalid pricing rule id")
+		return
+	}
+
+	rule, err := h.svc.GetByID(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			errorResponse(c, http.StatusNotFound, "pricing rule not found")
+			return
+		}
+		errorResponse(c, http.StatusInternalServerError, "failed to get pricing rule")
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// @Summary      Create pricing rule
+// @Tags         pricing
+// @Security     AdminToken
+// @Accept       json
+// @Produce      json
+// @Param        rule  body      pricingRuleRequest  true  "Pricing rule"
+// @Success      201   {object}  domain.PricingRule
+// @Failure      400   {object}  ErrorResponse
+// @Failure      401   {object}  ErrorResponse
+// @Failure      500   {object}  ErrorResponse
+// @Router       /admin/pricing-rules [post]
+func (h *PricingRuleHandler) Create(c *gin.Context) {
+	var req pricingRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rule := req.toDomain()
+	if err := h.svc.Create(c.Request.Context(), &rule); err != nil {
+		if errors.Is(err, domain.ErrInvalidPricingRule) {
+			errorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		errorResponse(c, http.StatusInternalServerError, "failed to create pricing rule")
+		return
+	}
+
+	c.JSON(http.StatusCreated, rule)
+}
+
+// @Summary      Update pricing rule
+// @Tags         pricing
+// @Security     AdminToken
+// @Accept       json
+// @Produce      json
+// @Param        id    path      string              true  "Pricing rule UUID"
+// @Param        rule  body      pricingRuleRequest  true  "Pricing rule"
+// @Success      200   {object}  domain.PricingRule
+// @Failure      400   {object}  ErrorResponse
+// @Failure      404   {object}  ErrorResponse
+// @Failure      401   {object}  ErrorResponse
+// @Failure      500   {object}  ErrorResponse
+// @Router       /admin/pricing-rules/{id} [put]
+func (h *PricingRuleHandler) Update(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errorResponse(c, http.StatusBadRequest, "invalid pricing rule id")
+		return
+	}
+
+	var req pricingRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rule := req.toDomain()
+	rule.ID = id
+	if err := h.svc.Update(c.Request.Context(), &rule); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidPricingRule):
+			errorResponse(c, http.StatusBadRequest, err.Error())
+		case errors.Is(err, sql.ErrNoRows):
+			errorResponse(c, http.StatusNotFound, "pricing rule not found")
+		default:
+			errorResponse(c, http.StatusInternalServerError, "failed to update pricing rule")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// @Summary      Delete pricing rule
+// @Tags         pricing
+// @Security     AdminToken
+// @Param        id   path  string  true  "Pricing rule UUID"
+// @Success      204
+// @Failure      400  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /admin/pricing-rules/{id} [delete]
+func (h *PricingRuleHandler) Delete(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errorResponse(c, http.StatusBadRequest, "invalid pricing rule id")
+		return
+	}
+
+	if err := h.svc.Delete(c.Request.Context(), id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			errorResponse(c, http.StatusNotFound, "pricing rule not found")
+			return
+		}
+		errorResponse(c, http.StatusInternalServerError, "failed to delete pricing rule")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary      Dry-run pricing rules against the current catalog
+// @Description  Prices every product with the rules from the body (or the stored rules when the body has none) without saving anything.
+// @Tags         pricing
+// @Security     AdminToken
+// @Accept       json
+// @Produce      json
+// @Param        limit  query     int                   false  "Max changed products to list"  default(100)
+// @Param        rules  body      pricingDryRunRequest  false  "Candidate rule set"
+// @Success      200    {object}  domain.PricingDryRun
+// @Failure      400    {object}  ErrorResponse
+// @Failure      401    {object}  ErrorResponse
+// @Failure      500    {object}  ErrorResponse
+// @Router       /admin/pricing-rules/dry-run [post]
+func (h *PricingRuleHandler) DryRun(c *gin.Context) {
+	var q pricingDryRunQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		errorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if q.Limit == 0 {
+		q.Limit = defaultDryRunLimit
+	}
+	if q.Limit > maxDryRunLimit {
+		q.Limit = maxDryRunLimit
+	}
+
+	var req pricingDryRunRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			errorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	var rules []domain.PricingRule
+	if req.Rules != nil {
+		rules = make([]domain.PricingRule, 0, len(req.Rules))
+		for _, r := range req.Rules {
+			rules = append(rules, r.toDomain())
+		}
+	}
+
+	result, err := h.svc.DryRun(c.Request.Context(), rules, q.Limit)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidPricingRule) {
+			errorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		errorResponse(c, http.StatusInternalServerError, "failed to dry-run pricing rules")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -3,8 +3,13 @@ package handler
 import (
 	"database/sql"
 	"errors"
+	"fmt"
+	"math"
 	"net/http"
+	"net/url"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -19,17 +24,43 @@ var allowedProductSortFields = map[string]bool{
 	"price":      true,
 	"created_at": true,
 	"brand":      true,
+
+	domain.ProductSortRelevance: true,
+}
+
+type productFilterQuery struct {
+	CategoryIDs   []string `form:"category_id"`
+	Brands        []string `form:"brand"`
+	ExcludeBrands []string `form:"exclude_brand"`
+	MinPrice      *int     `form:"min_price"`
+	MaxPrice      *int     `form:"max_price"`
+	OnSale        bool     `form:"on_sale"`
+	MinDiscount   *int     `form:"min_discount"`
+	MaxDiscount   *int     `form:"max_discount"`
+	UpdatedSince  string   `form:"updated_since"`
+	Unavailable   bool     `form:"include_unavailable"`
+	Search        string   `form:"search"`
 }
 
 type productListQuery struct {
+	productFilterQuery
 	Page       uint64 `form:"page"`
 	PageSize   uint64 `form:"page_size"`
+	Cursor     string `form:"cursor"`
+	SkipTotal  bool   `form:"skip_total"`
 	SortFields string `form:"sort_fields"`
-	CategoryID string `form:"category_id"`
-	Brand      string `form:"brand"`
-	MinPrice   *int   `form:"min_price"`
-	MaxPrice   *int   `form:"max_price"`
-	Search     string `form:"search"`
+	Collapse   bool   `form:"collapse_groups"`
+}
+
+type productFacetsQuery struct {
+	productFilterQuery
+	PriceBuckets int `form:"price_buckets"`
+}
+
+type priceHistoryQuery struct {
+	From     string `form:"from"`
+	To       string `form:"to"`
+	Interval string `form:"interval"`
 }
 
 type ProductHandler struct {
@@ -43,14 +74,24 @@ func NewProductHandler(svc service.ProductService) *ProductHandler {
 // @Summary      List products
 // @Tags         products
 // @Produce      json
-// @Param        page         query     int     false  "Page number"               default(1)
+// @Param        page         query     int     false  "Page number, ignored when cursor is set"  default(1)
 // @Param        page_size    query     int     false  "Page size"                 default(24)
-// @Param        sort_fields  query     string  false  "Sort (e.g. price:asc,name:desc)"
-// @Param        category_id  query     string  false  "Category UUID"
-// @Param        brand        query     string  false  "Brand filter"
-// @Param        min_price    query     int     false  "Min price (RUB)"
-// @Param        max_price    query     int     false  "Max price (RUB)"
-// @Param        search       query     string  false  "Search by name"
+// @Param        cursor       query     string  false  "Opaque keyset cursor from next_cursor of the previous page; sort_fields must stay the same"
+// @Param        skip_total   query     bool    false  "Skip the total count (total is -1)"
+// @Param        collapse_groups  query  bool   false  "One product per variant group (the cheapest matching one); total counts the collapsed rows"
+// @Param        sort_fields  query     string  false  "Sort (e.g. price:asc,name:desc; relevance:desc with search, default when searching)"
+// @Param        category_id    query     []string  false  "Category UUID, includes subcategories (repeatable)"  collectionFormat(multi)
+// @Param        brand          query     []string  false  "Brand filter (repeatable)"  collectionFormat(multi)
+// @Param        exclude_brand  query     []string  false  "Brands to exclude (repeatable)"  collectionFormat(multi)
+// @Param        min_price      query     int       false  "Min price (RUB)"
+// @Param        max_price      query     int       false  "Max price (RUB)"
+// @Param        on_sale        query     bool      false  "Only products priced below their original price"
+// @Param        min_discount   query     int       false  "Min discount, percent of original price (0-100)"
+// @Param        max_discount   query     int       false  "Max discount, percent of original price (0-100)"
+// @Param        updated_since  query     string    false  "Only products updated at or after (RFC3339 or YYYY-MM-DD)"
+// @Param        include_unavailable  query  bool  false  "Also return products no longer listed by the source"
+// @Param        search         query     string    false  "Full-text search over name, brand, SKU and description"
+// @Param        attr           query     string    false  "Specification filter as attr[<name>]=<value>, e.g. attr[объем памяти]=128 ГБ; repeat a name to match any of its values. Names and values are normalized (case, units, spelling)"
 // @Success      200  {object}  domain.ProductList
 // @Failure      400  {object}  ErrorResponse
 // @Failure      500  {object}  ErrorResponse
@@ -71,6 +112,10 @@ func (h *ProductHandler) List(c *gin.Context) {
 
 	pag := pagination.PagePagination{Page: q.Page, PageSize: q.PageSize}
 
+	if q.SortFields == "" && q.Search != "" {
+		q.SortFields = domain.ProductSortRelevance
+	}
+
 	sfr := pagination.SortFieldsRequest{SortFields: q.SortFields}
 	sortClauses, err := sfr.ParseSortFields()
 	if err != nil {
@@ -84,29 +129,31 @@ func (h *ProductHandler) List(c *gin.Context) {
 			errorResponse(c, http.StatusBadRequest, "invalid sort field: "+field)
 			return
 		}
+		if field == domain.ProductSortRelevance && q.Search == "" {
+			errorResponse(c, http.StatusBadRequest, "relevance sort requires search")
+			return
+		}
 	}
 
-	filter := domain.ProductFilter{
-		Limit:    pag.GetLimit(),
-		Offset:   pag.GetOffset(),
-		SortBy:   sortClauses,
-		MinPrice: q.MinPrice,
-		MaxPrice: q.MaxPrice,
+	filter, err := q.toFilter(c.Request.URL.Query())
+	if err != nil {
+		errorResponse(c, http.StatusBadRequest, err.Error())
+		return
 	}
+	filter.Limit = pag.GetLimit()
+	filter.Offset = pag.GetOffset()
+	filter.SortBy = sortClauses
+	filter.SkipTotal = q.SkipTotal
+	filter.CollapseGroups = q.Collapse
 
-	if q.CategoryID != "" {
-		id, err := uuid.Parse(q.CategoryID)
+	if q.Cursor != "" {
+		cursor, err := parseProductCursor(q.Cursor, sortClauses, q.Search != "")
 		if err != nil {
-			errorResponse(c, http.StatusBadRequest, "invalid category_id")
+			errorResponse(c, http.StatusBadRequest, err.Error())
 			return
 		}
-		filter.CategoryID = &id
-	}
-	if q.Brand != "" {
-		filter.Brand = &q.Brand
-	}
-	if q.Search != "" {
-		filter.Search = &q.Search
+		filter.Cursor = cursor
+		filter.Offset = 0
 	}
 
 	result, err := h.svc.GetByFilter(c.Request.Context(), filter)
@@ -118,7 +165,58 @@ func (h *ProductHandler) List(c *gin.Context) {
 	c.JSON(http.StatusOK, result)
 }
 
+// @Summary      Product facets
+// @Description  Brand and category counts, price range and price histogram for the products matching the filter. Each facet ignores its own filter so alternatives stay visible. With category_id set, attribute facets list the most frequent values of every specification attribute.
+// @Tags         products
+// @Produce      json
+// @Param        category_id    query     []string  false  "Category UUID, includes subcategories (repeatable)"  collectionFormat(multi)
+// @Param        brand          query     []string  false  "Brand filter (repeatable)"  collectionFormat(multi)
+// @Param        exclude_brand  query     []string  false  "Brands to exclude (repeatable)"  collectionFormat(multi)
+// @Param        min_price      query     int       false  "Min price (RUB)"
+// @Param        max_price      query     int       false  "Max price (RUB)"
+// @Param        on_sale        query     bool      false  "Only products priced below their original price"
+// @Param        min_discount   query     int       false  "Min discount, percent of original price (0-100)"
+// @Param        max_discount   query     int       false  "Max discount, percent of original price (0-100)"
+// @Param        updated_since  query     string    false  "Only products updated at or after (RFC3339 or YYYY-MM-DD)"
+// @Param        include_unavailable  query  bool  false  "Also return products no longer listed by the source"
+// @Param        search         query     string    false  "Full-text search over name, brand, SKU and description"
+// @Param        attr           query     string    false  "Specification filter as attr[<name>]=<value>, e.g. attr[объем памяти]=128 ГБ; repeat a name to match any of its values. Names and values are normalized (case, units, spelling)"
+// @Param        price_buckets  query     int       false  "Number of price histogram buckets (max 50)"  default(10)
+// @Success      200  {object}  domain.ProductFacets
+// @Failure      400  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /products/facets [get]
+func (h *ProductHandler) Facets(c *gin.Context) {
+	var q productFacetsQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		errorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if q.PriceBuckets <= 0 {
+		q.PriceBuckets = domain.DefaultPriceBuckets
+	}
+	if q.PriceBuckets > domain.MaxPriceBuckets {
+		q.PriceBuckets = domain.MaxPriceBuckets
+	}
+
+	filter, err := q.toFilter(c.Request.URL.Query())
+	if err != nil {
+		errorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	facets, err := h.svc.GetFacets(c.Request.Context(), filter, q.PriceBuckets)
+	if err != nil {
+		errorResponse(c, http.StatusInternalServerError, "failed to get product facets")
+		return
+	}
+
+	c.JSON(http.StatusOK, facets)
+}
+
 // @Summary      Get product by ID
+// @Description  Includes the specifications, gallery images and color/memory variants read from the product page.
 // @Tags         products
 // @Produce      json
 // @Param        id   path      string  true  "Product UUID"
@@ -147,6 +245,75 @@ func (h *ProductHandler) GetByID(c *gin.Context) {
 	c.JSON(http.StatusOK, product)
 }
 
+// @Summary      Get product price history
+// @Tags         products
+// @Produce      json
+// @Param        id        path      string  true   "Product UUID"
+// @Param        from      query     string  false  "Range start (RFC3339 or YYYY-MM-DD, inclusive)"
+// @Param        to        query     string  false  "Range end (RFC3339 or YYYY-MM-DD, exclusive)"
+// @Param        interval  query     string  false  "Downsampling bucket"  Enums(hour, day, week, month)
+// @Success      200  {object}  domain.PriceHistory
+// @Failure      400  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /products/{id}/price-history [get]
+func (h *ProductHandler) GetPriceHistory(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errorResponse(c, http.StatusBadRequest, "invalid product id")
+		return
+	}
+
+	var q priceHistoryQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		errorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if q.Interval != "" && !domain.IsValidPriceInterval(q.Interval) {
+		errorResponse(c, http.StatusBadRequest, "invalid interval: "+q.Interval)
+		return
+	}
+
+	filter := domain.PriceHistoryFilter{
+		ProductID: id,
+		Interval:  q.Interval,
+	}
+
+	if q.From != "" {
+		from, err := parseTimeParam(q.From)
+		if err != nil {
+			errorResponse(c, http.StatusBadRequest, "invalid from")
+			return
+		}
+		filter.From = &from
+	}
+	if q.To != "" {
+		to, err := parseTimeParam(q.To)
+		if err != nil {
+			errorResponse(c, http.StatusBadRequest, "invalid to")
+			return
+		}
+		filter.To = &to
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		errorResponse(c, http.StatusBadRequest, "from must be before to")
+		return
+	}
+
+	history, err := h.svc.GetPriceHistory(c.Request.Context(), filter)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			errorResponse(c, http.StatusNotFound, "product not found")
+			return
+		}
+		errorResponse(c, http.StatusInternalServerError, "failed to get price history")
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
 // @Summary      List all brands
 // @Tags         products
 // @Produce      json
@@ -162,3 +329,146 @@ func (h *ProductHandler) GetBrands(c *gin.Context) {
 
 	c.JSON(http.StatusOK, brands)
 }
+
+// maxAttributeFilters caps the attr[<name>] parameters of one request; each
+// filtered attribute adds a query to the facets.
+const maxAttributeFilters = 10
+
+func (q productFilterQuery) toFilter(query url.Values) (domain.ProductFilter, error) {
+	filter := domain.ProductFilter{
+		Brands:        nonEmpty(q.Brands),
+		ExcludeBrands: nonEmpty(q.ExcludeBrands),
+		MinPrice:      q.MinPrice,
+		MaxPrice:      q.MaxPrice,
+		OnSale:        q.OnSale,
+		MinDiscount:   q.MinDiscount,
+		MaxDiscount:   q.MaxDiscount,
+
+		IncludeUnavailable: q.Unavailable,
+	}
+
+	for _, raw := range nonEmpty(q.CategoryIDs) {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return filter, errors.New("invalid category_id")
+		}
+		filter.CategoryIDs = append(filter.CategoryIDs, id)
+	}
+
+	for _, d := range []*int{q.MinDiscount, q.MaxDiscount} {
+		if d != nil && (*d < 0 || *d > 100) {
+			return filter, errors.New("discount must be between 0 and 100")
+		}
+	}
+	if q.MinDiscount != nil && q.MaxDiscount != nil && *q.MinDiscount > *q.MaxDiscount {
+		return filter, errors.New("min_discount must not exceed max_discount")
+	}
+
+	if q.UpdatedSince != "" {
+		since, err := parseTimeParam(q.UpdatedSince)
+		if err != nil {
+			return filter, errors.New("invalid updated_since")
+		}
+		filter.UpdatedSince = &since
+	}
+	if q.Search != "" {
+		filter.Search = &q.Search
+	}
+
+	attrs, err := attributeFilters(query)
+	if err != nil {
+		return filter, err
+	}
+	filter.Attributes = attrs
+
+	return filter, nil
+}
+
+// attributeFilters collects attr[<name>]=<value> parameters. Repeating a
+// name matches any of its values.
+func attributeFilters(query url.Values) (map[string][]string, error) {
+	var attrs map[string][]string
+	for key, values := range query {
+		name, ok := strings.CutPrefix(key, "attr[")
+		if !ok {
+			continue
+		}
+		name, ok = strings.CutSuffix(name, "]")
+		if name = strings.TrimSpace(name); !ok || name == "" {
+			return nil, errors.New("invalid attribute filter: " + key)
+		}
+
+		values = nonEmpty(values)
+		if len(values) == 0 {
+			continue
+		}
+		if attrs == nil {
+			attrs = make(map[string][]string)
+		}
+		attrs[name] = append(attrs[name], values...)
+	}
+
+	if len(attrs) > maxAttributeFilters {
+		return nil, fmt.Errorf("at most %d attribute filters are allowed", maxAttributeFilters)
+	}
+
+	return attrs, nil
+}
+
+// parseProductCursor decodes a next_cursor token and checks that it was issued
+// for the same ordering and carries a well-typed value for every sort key.
+func parseProductCursor(token string, sortClauses []string, hasSearch bool) (*domain.ProductCursor, error) {
+	var cursor domain.ProductCursor
+	if err := pagination.DecodeCursor(token, &cursor); err != nil {
+		return nil, err
+	}
+
+	if !slices.Equal(cursor.SortBy, sortClauses) || len(cursor.Values) != len(sortClauses) {
+		return nil, errors.New("cursor does not match sort_fields")
+	}
+	if cursor.FuzzySearch != nil && !hasSearch {
+		return nil, pagination.ErrInvalidCursor
+	}
+
+	for i, clause := range sortClauses {
+		field, _, _ := strings.Cut(clause, " ")
+		switch v := cursor.Values[i].(type) {
+		case string:
+			if field == "created_at" {
+				if _, err := time.Parse(time.RFC3339Nano, v); err != nil {
+					return nil, pagination.ErrInvalidCursor
+				}
+				continue
+			}
+			if field == "name" || field == "brand" {
+				continue
+			}
+		case float64:
+			if field == domain.ProductSortRelevance || (field == "price" && v == math.Trunc(v)) {
+				continue
+			}
+		}
+		return nil, pagination.ErrInvalidCursor
+	}
+
+	return &cursor, nil
+}
+
+func nonEmpty(values []string) []string {
+	var out []string
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+
+	return out
+}
+
+func parseTimeParam(v string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+
+	return time.Parse(time.DateOnly, v)
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/burbble/marketplace/internal/service"
+)
+
+type ProductGroupHandler struct {
+	svc service.ProductGroupService
+}
+
+func NewProductGroupHandler(svc service.ProductGroupService) *ProductGroupHandler {
+	return &ProductGroupHandler{svc: svc}
+}
+
+// @Summary      Get product group by ID
+// @Description  Lists every color and storage variant of a model, cheapest first, including unavailable ones. The price range covers available variants only.
+// @Tags         products
+// @Produce      json
+// @Param        id   path      string  true  "Product group UUID (group_id of a product)"
+// @Success      200  {object}  domain.ProductGroup
+// @Failure      400  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /product-groups/{id} [get]
+func (h *ProductGroupHandler) GetByID(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errorResponse(c, http.StatusBadRequest, "invalid product group id")
+		return
+	}
+
+	group, err := h.svc.GetByID(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			errorResponse(c, http.StatusNotFound, "product group not found")
+			return
+		}
+		errorResponse(c, http.StatusInternalServerError, "failed to get product group")
+		return
+	}
+
+	c.JSON(http.StatusOK, group)
+}
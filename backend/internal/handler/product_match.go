@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/burbble/marketplace/internal/domain"
+	"github.com/burbble/marketplace/internal/service"
+	"github.com/burbble/marketplace/pkg/pagination"
+)
+
+type productMatchListQuery struct {
+	Page      uint64 `form:"page"`
+	PageSize  uint64 `form:"page_size" binding:"omitempty,max=100"`
+	Status    string `form:"status"`
+	ProductID string `form:"product_id"`
+}
+
+type ProductMatchHandler struct {
+	svc service.ProductMatchService
+}
+
+func NewProductMatchHandler(svc service.ProductMatchService) *ProductMatchHandler {
+	return &ProductMatchHandler{svc: svc}
+}
+
+// @Summary      Get product offers
+// @Description  Lists the offers of the same device across shops: the product itself first, then every product matched to it whose match was not rejected, available and cheapest first.
+// @Tags         products
+// @Produce      json
+// @Param        id   path      string  true  "Product UUID"
+// @Success      200  {array}   domain.ProductOffer
+// @Failure      400  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /products/{id}/offers [get]
+func (h *ProductMatchHandler) Offers(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errorResponse(c, http.StatusBadRequest, "invalid product id")
+		return
+	}
+
+	offers, err := h.svc.GetOffers(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			errorResponse(c, http.StatusNotFound, "product not found")
+			return
+		}
+		errorResponse(c, http.StatusInternalServerError, "failed to get product offers")
+		return
+	}
+
+	c.JSON(http.StatusOK, offers)
+}
+
+// @Summary      List product matches
+// @Description  Lists matches between products of different shops, the most confident first.
+// @Tags         product-matches
+// @Security     AdminToken
+// @Produce      json
+// @Param        page        query     int     false  "Page number"  default(1)
+// @Param        page_size   query     int     false  "Page size"    default(20)
+// @Param        status      query     string  false  "Match status"  Enums(suggested, confirmed, rejected)
+// @Param        product_id  query     string  false  "Only matches of this product"
+// @Success      200  {object}  domain.ProductMatchList
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /admin/product-matches [get]
+func (h *ProductMatchHandler) List(c *gin.Context) {
+	var q productMatchListQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		errorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if q.Page == 0 {
+		q.Page = 1
+	}
+	if q.PageSize == 0 {
+		q.PageSize = 20
+	}
+
+	pag := pagination.PagePagination{Page: q.Page, PageSize: q.PageSize}
+	filter := domain.ProductMatchFilter{
+		Limit:  pag.GetLimit(),
+		Offset: pag.GetOffset(),
+	}
+
+	if q.Status != "" {
+		if !domain.IsValidProductMatchStatus(q.Status) {
+			errorResponse(c, http.StatusBadRequest, "invalid status: "+q.Status)
+			return
+		}
+		filter.Status = &q.Status
+	}
+	if q.ProductID != "" {
+		id, err := uuid.Parse(q.ProductID)
+		if err != nil {
+			errorResponse(c, http.StatusBadRequest, "invalid product_id")
+			return
+		}
+		filter.ProductID = &id
+	}
+
+	matches, err := h.svc.GetList(c.Request.Context(), filter)
+	if err != nil {
+		errorResponse(c, http.StatusInternalServerError, "failed to get product matches")
+		return
+	}
+
+	c.JSON(http.StatusOK, matches)
+}
+
+// @Summary      Confirm product match
+// @Description  Confirms that both products are the same device. Matching never changes a confirmed match and suggests no other match for either product in the other's shop. A product has at most one confirmed match per shop: confirming another one is a conflict.
+// @Tags         product-matches
+// @Security     AdminToken
+// @Produce      json
+// @Param        id   path      string  true  "Product match UUID"
+// @Success      200  {object}  domain.ProductMatch
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      409  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /admin/product-matches/{id}/confirm [post]
+func (h *ProductMatchHandler) Confirm(c *gin.Context) {
+	h.setStatus(c, domain.ProductMatchConfirmed)
+}
+
+// @Summary      Reject product match
+// @Description  Marks the products as different devices. A rejected pair is no longer listed among the offers and is never suggested again.
+// @Tags         product-matches
+// @Security     AdminToken
+// @Produce      json
+// @Param        id   path      string  true  "Product match UUID"
+// @Success      200  {object}  domain.ProductMatch
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /admin/product-matches/{id}/reject [post]
+func (h *ProductMatchHandler) Reject(c *gin.Context) {
+	h.setStatus(c, domain.ProductMatchRejected)
+}
+
+func (h *ProductMatchHandler) setStatus(c *gin.Context, status string) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errorResponse(c, http.StatusBadRequest, "invalid product match id")
+		return
+	}
+
+	match, err := h.svc.SetStatus(c.Request.Context(), id, status)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			errorResponse(c, http.StatusNotFound, "product match not found")
+		case errors.Is(err, domain.ErrProductMatchConflict):
+			errorResponse(c, http.StatusConflict, err.Error())
+		default:
+			errorResponse(c, http.StatusInternalServerError, "failed to update product match")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, match)
+}
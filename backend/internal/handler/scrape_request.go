@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/burbble/marketplace/internal/domain"
+	"github.com/burbble/marketplace/internal/service"
+)
+
+type scrapeRequestBody struct {
+	CategoryIDs []uuid.UUID `json:"category_ids"`
+	FullRefresh bool        `json:"full_refresh"`
+}
+
+type ScrapeRequestHandler struct {
+	svc service.ScrapeRequestService
+}
+
+func NewScrapeRequestHandler(svc service.ScrapeRequestService) *ScrapeRequestHandler {
+	return &ScrapeRequestHandler{svc: svc}
+}
+
+// @Summary      Request a scrape
+// @Description  Asks the parser to scrape now, limited to the given categories and their subcategories when category_ids is set. The parser starts the run once the current one, if any, is finished.
+// @Tags         scrapes
+// @Accept       json
+// @Produce      json
+// @Security     AdminToken
+// @Param        request  body      scrapeRequestBody  false  "Categories to scrape (all when empty)"
+// @Success      202      {object}  domain.ScrapeRequest
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
+// @Router       /admin/scrapes [post]
+func (h *ScrapeRequestHandler) Create(c *gin.Context) {
+	var body scrapeRequestBody
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			errorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	req := domain.ScrapeRequest{CategoryIDs: body.CategoryIDs, FullRefresh: body.FullRefresh}
+	if req.CategoryIDs == nil {
+		req.CategoryIDs = []uuid.UUID{}
+	}
+
+	if err := h.svc.Create(c.Request.Context(), &req); err != nil {
+		if errors.Is(err, domain.ErrInvalidScrapeRequest) {
+			errorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		errorResponse(c, http.StatusInternalServerError, "failed to request scrape")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, req)
+}
+
+// @Summary      Get scrape request
+// @Tags         scrapes
+// @Produce      json
+// @Security     AdminToken
+// @Param        id   path      string  true  "Scrape request UUID"
+// @Success      200  {object}  domain.ScrapeRequest
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /admin/scrapes/{id} [get]
+func (h *ScrapeRequestHandler) GetByID(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errorResponse(c, http.StatusBadRequest, "invalid scrape request id")
+		return
+	}
+
+	req, err := h.svc.GetByID(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			errorResponse(c, http.StatusNotFound, "scrape request not found")
+			return
+		}
+		errorResponse(c, http.StatusInternalServerError, "failed to get scrape request")
+		return
+	}
+
+	c.JSON(http.StatusOK, req)
+}
+
+// @Summary      Cancel scrape request
+// @Description  A pending request, or a running one whose lease expired because its parser stopped, is cancelled at once; a running one has cancel_requested set and is stopped by the parser within seconds.
+// @Tags         scrapes
+// @Produce      json
+// @Security     AdminToken
+// @Param        id   path      string  true  "Scrape request UUID"
+// @Success      202  {object}  domain.ScrapeRequest
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      409  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /admin/scrapes/{id} [delete]
+func (h *ScrapeRequestHandler) Cancel(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errorResponse(c, http.StatusBadRequest, "invalid scrape request id")
+		return
+	}
+
+	req, err := h.svc.Cancel(c.Request.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			errorResponse(c, http.StatusNotFound, "scrape request not found")
+		case errors.Is(err, domain.ErrScrapeRequestFinished):
+			errorResponse(c, http.StatusConflict, err.Error())
+		default:
+			errorResponse(c, http.StatusInternalServerError, "failed to cancel scrape request")
+		}
+		return
+	}
+
+	c.JSON(http.StatusAccepted, req)
+}
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/burbble/marketplace/internal/domain"
+	"github.com/burbble/marketplace/internal/service"
+	"github.com/burbble/marketplace/pkg/pagination"
+)
+
+type scrapeRunListQuery struct {
+	Page        uint64 `form:"page"`
+	PageSize    uint64 `form:"page_size" binding:"omitempty,max=100"`
+	Status      string `form:"status"`
+	MarkupDrift *bool  `form:"markup_drift"`
+}
+
+type ScrapeRunHandler struct {
+	svc service.ScrapeRunService
+}
+
+func NewScrapeRunHandler(svc service.ScrapeRunService) *ScrapeRunHandler {
+	return &ScrapeRunHandler{svc: svc}
+}
+
+// @Summary      List scrape runs
+// @Tags         scrape-runs
+// @Security     AdminToken
+// @Produce      json
+// @Param        page       query     int     false  "Page number"  default(1)
+// @Param        page_size  query     int     false  "Page size"    default(20)
+// @Param        status     query     string  false  "Run status"   Enums(running, succeeded, partial, failed)
+// @Param        markup_drift  query  bool    false  "Only runs with (true) or without (false) markup drift"
+// @Success      200  {object}  domain.ScrapeRunList
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /admin/scrape-runs [get]
+func (h *ScrapeRunHandler) List(c *gin.Context) {
+	var q scrapeRunListQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		errorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if q.Page == 0 {
+		q.Page = 1
+	}
+	if q.PageSize == 0 {
+		q.PageSize = 20
+	}
+
+	pag := pagination.PagePagination{Page: q.Page, PageSize: q.PageSize}
+	filter := domain.ScrapeRunFilter{
+		MarkupDrift: q.MarkupDrift,
+		Limit:       pag.GetLimit(),
+		Offset:      pag.GetOffset(),
+	}
+
+	if q.Status != "" {
+		if !domain.IsValidScrapeStatus(q.Status) {
+			errorResponse(c, http.StatusBadRequest, "invalid status: "+q.Status)
+			return
+		}
+		filter.Status = &q.Status
+	}
+
+	runs, err := h.svc.GetList(c.Request.Context(), filter)
+	if err != nil {
+		errorResponse(c, http.StatusInternalServerError, "failed to get scrape runs")
+		return
+	}
+
+	c.JSON(http.StatusOK, runs)
+}
+
+// @Summary      Get scrape run by ID
+// @Description  Run totals with a per-category breakdown
+// @Tags         scrape-runs
+// @Security     AdminToken
+// @Produce      json
+// @Param        id   path      string  true  "Scrape run UUID"
+// @Success      200  {object}  domain.ScrapeRunDetail
+// @Failure      400  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /admin/scrape-runs/{id} [get]
+func (h *ScrapeRunHandler) GetByID(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errorResponse(c, http.StatusBadRequest, "invalid scrape run id")
+		return
+	}
+
+	run, err := h.svc.GetByID(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			errorResponse(c, http.StatusNotFound, "scrape run not found")
+			return
+		}
+		errorResponse(c, http.StatusInternalServerError, "failed to get scrape run")
+		return
+	}
+
+	c.JSON(http.StatusOK, run)
+}
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/burbble/marketplace/internal/service"
+)
+
+const (
+	defaultSuggestLimit = 10
+	maxSuggestLimit     = 20
+)
+
+type suggestQuery struct {
+	Q     string `form:"q" binding:"required"`
+	Limit int    `form:"limit" binding:"omitempty,min=1"`
+}
+
+type SearchHandler struct {
+	svc service.SearchService
+}
+
+func NewSearchHandler(svc service.SearchService) *SearchHandler {
+	return &SearchHandler{svc: svc}
+}
+
+// @Summary      Autocomplete suggestions
+// @Description  Typo-tolerant brand and product name suggestions; Cyrillic brand spellings are resolved through transliteration and aliases.
+// @Tags         search
+// @Produce      json
+// @Param        q      query     string  true   "Query (at least 2 characters)"
+// @Param        limit  query     int     false  "Max suggestions"  default(10)
+// @Success      200  {array}   domain.Suggestion
+// @Failure      400  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /search/suggest [get]
+func (h *SearchHandler) Suggest(c *gin.Context) {
+	var q suggestQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		errorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if q.Limit == 0 {
+		q.Limit = defaultSuggestLimit
+	}
+	if q.Limit > maxSuggestLimit {
+		q.Limit = maxSuggestLimit
+	}
+
+	suggestions, err := h.svc.Suggest(c.Request.Context(), q.Q, q.Limit)
+	if err != nil {
+		errorResponse(c, http.StatusInternalServerError, "failed to get suggestions")
+		return
+	}
+
+	c.JSON(http.StatusOK, suggestions)
+}
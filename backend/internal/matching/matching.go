@@ -0,0 +1,412 @@
+// Package matching links products of different sources that are the same
+// device, so that their offers can be compared. A pair is scored by SKU,
+// then by the model name once word order, punctuation and generic category
+// words are ignored, then by the trigram similarity of the names. Apart from
+// a SKU match, two names must consist of the same words: only a color left
+// out on one side and words spelled slightly differently are tolerated, so
+// "iPhone 15 Pro" never matches "iPhone 15 Pro Max" and "Blue Titanium"
+// never matches "Black Titanium". Products of different brands are never
+// matched.
+package matching
+
+import (
+	"bytes"
+	"cmp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+
+	"github.com/burbble/marketplace/internal/domain"
+)
+
+const (
+	// minSKULength keeps short, shop-internal codes from matching by
+	// accident.
+	minSKULength = 5
+
+	modelConfidence = 0.95
+	// nameWeight scales name similarity, so a fuzzy match never scores as
+	// high as an exact model match.
+	nameWeight = 0.9
+
+	// minSpellingLength and spellingSimilarity bound how two words may
+	// differ to count as one word spelled differently: "titan" and
+	// "titanium" do, "pro" and "max" or "blue" and "black" do not.
+	minSpellingLength  = 4
+	spellingSimilarity = 0.6
+)
+
+var capacityUnits = map[string]string{"gb": "gb", "tb": "tb", "гб": "gb", "тб": "tb"}
+
+// genericWords name the kind of product rather than the product and are
+// left out of names: one shop writes "Смартфон Apple iPhone 15", another
+// "Apple iPhone 15".
+var genericWords = map[string]bool{
+	"смартфон": true, "телефон": true, "мобильный": true, "планшет": true,
+	"ноутбук": true, "наушники": true, "беспроводные": true, "умные": true,
+	"часы": true, "смарт": true, "игровая": true, "консоль": true, "приставка": true,
+	"smartphone": true, "phone": true, "tablet": true, "laptop": true,
+	"headphones": true, "wireless": true, "smartwatch": true,
+}
+
+// colorWords are the words of color names, mapped to one spelling so that
+// "синий" and "Blue" compare equal. Only these words may be missing from
+// one of two matched names.
+var colorWords = map[string]string{
+	"black": "black", "черный": "black",
+	"white": "white", "белый": "white",
+	"blue": "blue", "синий": "blue",
+	"red": "red", "красный": "red",
+	"green": "green", "зеленый": "green",
+	"yellow": "yellow", "желтый": "yellow",
+	"pink": "pink", "розовый": "pink",
+	"purple": "purple", "фиолетовый": "purple",
+	"gold": "gold", "золотой": "gold", "золотистый": "gold",
+	"silver": "silver", "серебристый": "silver",
+	"gray": "gray", "grey": "gray", "серый": "gray",
+	"orange": "orange", "оранжевый": "orange",
+	"beige": "beige", "бежевый": "beige",
+	"brown": "brown", "коричневый": "brown",
+	"graphite": "graphite", "графитовый": "graphite",
+	"titanium": "titanium", "титановый": "titanium",
+	"titan": "titan", "титан": "titan",
+	"natural": "natural", "натуральный": "natural",
+	"midnight": "midnight", "starlight": "starlight",
+	"space": "space", "ultramarine": "ultramarine",
+	"teal": "teal", "lavender": "lavender", "mint": "mint", "cream": "cream",
+}
+
+// candidate is a product prepared for scoring.
+type candidate struct {
+	domain.MatchCandidate
+	sku      string
+	tokens   []string
+	model    string
+	core     []string
+	colors   []string
+	trigrams map[string]int
+}
+
+func prepare(c domain.MatchCandidate) candidate {
+	p := candidate{MatchCandidate: c, sku: normalizeSKU(c.SKU)}
+
+	brand := fold(strings.TrimSpace(c.Brand))
+	for _, t := range Tokens(c.Name) {
+		if t == brand || genericWords[t] {
+			continue
+		}
+		if color, ok := colorWords[t]; ok {
+			t = color
+		}
+		p.tokens = append(p.tokens, t)
+	}
+
+	slices.Sort(p.tokens)
+	p.tokens = slices.Compact(p.tokens)
+	p.model = strings.Join(p.tokens, " ")
+	p.trigrams = trigrams(p.model)
+
+	for _, t := range p.tokens {
+		if _, ok := colorWords[t]; ok {
+			p.colors = append(p.colors, t)
+		} else {
+			p.core = append(p.core, t)
+		}
+	}
+
+	return p
+}
+
+// Tokens splits a product name into lower-cased words, joining capacities
+// written apart ("256 ГБ" becomes "256gb").
+func Tokens(name string) []string {
+	words := strings.FieldsFunc(fold(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '/'
+	})
+
+	tokens := make([]string, 0, len(words))
+	for _, w := range words {
+		w = strings.Trim(w, "/")
+		if w == "" {
+			continue
+		}
+		if unit, ok := capacityUnits[w]; ok {
+			if n := len(tokens); n > 0 && isNumber(tokens[n-1]) {
+				tokens[n-1] += unit
+			}
+			continue
+		}
+		for suffix, unit := range capacityUnits {
+			if strings.HasSuffix(w, suffix) && isNumber(strings.TrimSuffix(w, suffix)) {
+				w = strings.TrimSuffix(w, suffix) + unit
+				break
+			}
+		}
+		tokens = append(tokens, w)
+	}
+
+	return tokens
+}
+
+// Score returns the confidence that two products are the same device and
+// the method it was established by. It is 0 for products that cannot be
+// the same device.
+func Score(a, b domain.MatchCandidate) (float64, string) {
+	return score(prepare(a), prepare(b))
+}
+
+func score(a, b candidate) (float64, string) {
+	if a.sku != "" && a.sku == b.sku {
+		return 1, domain.MatchMethodSKU
+	}
+
+	brandA, brandB := fold(strings.TrimSpace(a.Brand)), fold(strings.TrimSpace(b.Brand))
+	if brandA != "" && brandB != "" && brandA != brandB {
+		return 0, ""
+	}
+	if a.model == "" || b.model == "" || !sameWords(a.core, b.core) {
+		return 0, ""
+	}
+	if len(a.colors) > 0 && len(b.colors) > 0 && !sameWords(a.colors, b.colors) {
+		return 0, ""
+	}
+
+	if a.model == b.model {
+		return modelConfidence, domain.MatchMethodModel
+	}
+
+	return nameWeight * similarity(a.trigrams, b.trigrams), domain.MatchMethodName
+}
+
+// sameWords reports whether two sorted word lists hold the same words, up
+// to words spelled slightly differently. Words with digits, such as model
+// numbers and capacities, must be equal.
+func sameWords(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	var restA, restB []string
+	for _, w := range a {
+		if !slices.Contains(b, w) {
+			restA = append(restA, w)
+		}
+	}
+	for _, w := range b {
+		if !slices.Contains(a, w) {
+			restB = append(restB, w)
+		}
+	}
+	if len(restA) != len(restB) {
+		return false
+	}
+
+	used := make([]bool, len(restB))
+	for _, w := range restA {
+		best, bestSim := -1, spellingSimilarity
+		for i, v := range restB {
+			if used[i] || !spelledAlike(w, v) {
+				continue
+			}
+			if sim := similarity(trigrams(w), trigrams(v)); sim >= bestSim {
+				best, bestSim = i, sim
+			}
+		}
+		if best < 0 {
+			return false
+		}
+		used[best] = true
+	}
+
+	return true
+}
+
+// spelledAlike reports whether two words may be spellings of one word: both
+// long enough and free of digits.
+func spelledAlike(a, b string) bool {
+	for _, w := range []string{a, b} {
+		if len([]rune(w)) < minSpellingLength || strings.IndexFunc(w, unicode.IsDigit) >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Match suggests matches between products of different sources scoring at
+// least minConfidence. Products sharing a SKU are paired across the whole
+// catalog, whatever their brands; the rest are compared within their brand,
+// and products without a brand against every brand. A product is matched to
+// at most one product of each other source, the best scoring first. Decided
+// matches are respected: confirmed ones take their products' slots and are
+// not suggested again, rejected pairs are never suggested.
+func Match(products []domain.MatchCandidate, decided []domain.ProductMatch, minConfidence float64) []domain.ProductMatch {
+	sourceOf := make(map[uuid.UUID]string, len(products))
+	bySKU := make(map[string][]candidate)
+	blocks := make(map[string][]candidate)
+	var branded, unbranded []candidate
+	for _, p := range products {
+		sourceOf[p.ProductID] = p.Source
+		c := prepare(p)
+		if c.sku != "" {
+			bySKU[c.sku] = append(bySKU[c.sku], c)
+		}
+		if brand := fold(strings.TrimSpace(p.Brand)); brand != "" {
+			blocks[brand] = append(blocks[brand], c)
+			branded = append(branded, c)
+		} else {
+			unbranded = append(unbranded, c)
+		}
+	}
+
+	type slot struct {
+		product uuid.UUID
+		source  string
+	}
+	taken := make(map[slot]bool)
+	skip := make(map[[2]uuid.UUID]bool, len(decided))
+	for _, d := range decided {
+		skip[pair(d.ProductID, d.MatchedID)] = true
+		if d.Status == domain.ProductMatchConfirmed {
+			taken[slot{d.ProductID, sourceOf[d.MatchedID]}] = true
+			taken[slot{d.MatchedID, sourceOf[d.ProductID]}] = true
+		}
+	}
+
+	var scored []domain.ProductMatch
+	compare := func(a, b candidate) {
+		ids := pair(a.ProductID, b.ProductID)
+		if a.Source == b.Source || skip[ids] {
+			return
+		}
+
+		confidence, method := score(a, b)
+		if method == "" || confidence < minConfidence {
+			return
+		}
+
+		scored = append(scored, domain.ProductMatch{
+			ProductID:  ids[0],
+			MatchedID:  ids[1],
+			Confidence: confidence,
+			Method:     method,
+			Status:     domain.ProductMatchSuggested,
+		})
+	}
+	// Pairs sharing a SKU were compared in the first pass.
+	compareOther := func(a, b candidate) {
+		if a.sku == "" || a.sku != b.sku {
+			compare(a, b)
+		}
+	}
+
+	for _, group := range bySKU {
+		for i := range group {
+			for j := i + 1; j < len(group); j++ {
+				compare(group[i], group[j])
+			}
+		}
+	}
+	for _, block := range blocks {
+		for i := range block {
+			for j := i + 1; j < len(block); j++ {
+				compareOther(block[i], block[j])
+			}
+		}
+	}
+	for i, u := range unbranded {
+		for _, c := range branded {
+			compareOther(u, c)
+		}
+		for _, c := range unbranded[i+1:] {
+			compareOther(u, c)
+		}
+	}
+
+	slices.SortFunc(scored, func(x, y domain.ProductMatch) int {
+		return cmp.Or(
+			cmp.Compare(y.Confidence, x.Confidence),
+			bytes.Compare(x.ProductID[:], y.ProductID[:]),
+			bytes.Compare(x.MatchedID[:], y.MatchedID[:]),
+		)
+	})
+
+	matches := make([]domain.ProductMatch, 0, len(scored))
+	for _, m := range scored {
+		a := slot{m.ProductID, sourceOf[m.MatchedID]}
+		b := slot{m.MatchedID, sourceOf[m.ProductID]}
+		if taken[a] || taken[b] {
+			continue
+		}
+		taken[a], taken[b] = true, true
+		matches = append(matches, m)
+	}
+
+	return matches
+}
+
+// pair orders two ids the way product_matches stores them.
+func pair(a, b uuid.UUID) [2]uuid.UUID {
+	if bytes.Compare(a[:], b[:]) > 0 {
+		a, b = b, a
+	}
+	return [2]uuid.UUID{a, b}
+}
+
+func normalizeSKU(sku string) string {
+	s := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+		return -1
+	}, sku)
+
+	if len(s) < minSKULength {
+		return ""
+	}
+	return s
+}
+
+// trigrams counts the character trigrams of s, padded so that short words
+// still have some.
+func trigrams(s string) map[string]int {
+	grams := make(map[string]int)
+	runes := []rune("  " + s + " ")
+	for i := 0; i+3 <= len(runes); i++ {
+		grams[string(runes[i:i+3])]++
+	}
+	return grams
+}
+
+// similarity is the Dice coefficient of two trigram multisets.
+func similarity(a, b map[string]int) float64 {
+	var common, total int
+	for g, n := range a {
+		common += min(n, b[g])
+		total += n
+	}
+	for _, n := range b {
+		total += n
+	}
+	if total == 0 {
+		return 0
+	}
+	return 2 * float64(common) / float64(total)
+}
+
+func isNumber(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !unicode.IsDigit(r) && r != '/' {
+			return false
+		}
+	}
+	return true
+}
+
+func fold(s string) string {
+	return strings.ReplaceAll(strings.ToLower(s), "ё", "е")
+}
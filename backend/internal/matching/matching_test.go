@@ -0,0 +1,204 @@
+package matching
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/burbble/marketplace/internal/domain"
+)
+
+func TestTokens(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"Apple iPhone 15 Pro 256GB (Black Titanium)", []string{"apple", "iphone", "15", "pro", "256gb", "black", "titanium"}},
+		{"Смартфон Samsung Galaxy S24 12/256 ГБ, серый", []string{"смартфон", "samsung", "galaxy", "s24", "12/256gb", "серый"}},
+		{"Xiaomi Redmi Note 13 8/256Гб Чёрный", []string{"xiaomi", "redmi", "note", "13", "8/256gb", "черный"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := Tokens(tt.input); !slices.Equal(got, tt.expected) {
+				t.Errorf("Tokens(%q) = %v, expected %v", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		name   string
+		a, b   domain.MatchCandidate
+		method string
+		min    float64
+	}{
+		{
+			name:   "same sku",
+			a:      domain.MatchCandidate{SKU: "MTV03ZA/A", Brand: "Apple", Name: "iPhone 15 Pro"},
+			b:      domain.MatchCandidate{SKU: "mtv03za-a", Brand: "Apple", Name: "Смартфон Apple iPhone 15 Pro 256 ГБ"},
+			method: domain.MatchMethodSKU,
+			min:    1,
+		},
+		{
+			name:   "same model in other words",
+			a:      domain.MatchCandidate{Brand: "Apple", Name: "Apple iPhone 15 Pro 256GB Black Titanium"},
+			b:      domain.MatchCandidate{Brand: "apple", Name: "Смартфон iPhone 15 Pro, 256 ГБ, Titanium Black"},
+			method: domain.MatchMethodModel,
+			min:    0.95,
+		},
+		{
+			name:   "similar names",
+			a:      domain.MatchCandidate{Brand: "Apple", Name: "Apple iPhone 15 Pro 256GB Black Titanium"},
+			b:      domain.MatchCandidate{Brand: "Apple", Name: "Apple iPhone 15 Pro 256GB Black Titan"},
+			method: domain.MatchMethodName,
+			min:    0.8,
+		},
+		{
+			name: "other capacity",
+			a:    domain.MatchCandidate{Brand: "Apple", Name: "Apple iPhone 15 Pro 256GB Black"},
+			b:    domain.MatchCandidate{Brand: "Apple", Name: "Apple iPhone 15 Pro 512GB Black"},
+		},
+		{
+			name: "other model number",
+			a:    domain.MatchCandidate{Brand: "Apple", Name: "Apple iPhone 15 Pro 256GB"},
+			b:    domain.MatchCandidate{Brand: "Apple", Name: "Apple iPhone 14 Pro 256GB"},
+		},
+		{
+			name: "other brand",
+			a:    domain.MatchCandidate{Brand: "Apple", Name: "Watch Ultra 2"},
+			b:    domain.MatchCandidate{Brand: "Samsung", Name: "Watch Ultra 2"},
+		},
+		{
+			name:   "color named in russian",
+			a:      domain.MatchCandidate{Brand: "Apple", Name: "Apple iPhone 15 256GB Blue"},
+			b:      domain.MatchCandidate{Brand: "Apple", Name: "Смартфон Apple iPhone 15 256 ГБ синий"},
+			method: domain.MatchMethodModel,
+			min:    0.95,
+		},
+		{
+			name: "pro and pro max",
+			a:    domain.MatchCandidate{Brand: "Apple", Name: "Apple iPhone 15 Pro Max 256GB Blue"},
+			b:    domain.MatchCandidate{Brand: "Apple", Name: "Apple iPhone 15 Pro 256GB Blue"},
+		},
+		{
+			name: "base and plus",
+			a:    domain.MatchCandidate{Name: "iPhone 15 Plus 256GB Blue"},
+			b:    domain.MatchCandidate{Name: "iPhone 15 256GB Blue"},
+		},
+		{
+			name: "other color",
+			a:    domain.MatchCandidate{Brand: "Apple", Name: "Apple iPhone 15 Pro 256GB Blue Titanium"},
+			b:    domain.MatchCandidate{Brand: "Apple", Name: "Apple iPhone 15 Pro 256GB Black Titanium"},
+		},
+		{
+			name: "extra word",
+			a:    domain.MatchCandidate{Brand: "Apple", Name: "Apple iPhone 15 256GB Blue"},
+			b:    domain.MatchCandidate{Brand: "Apple", Name: "Apple iPhone 15 256GB Blue eSIM"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			confidence, method := Score(tt.a, tt.b)
+			if tt.method == "" {
+				if method != "" {
+					t.Errorf("expected no match, got %s with %v", method, confidence)
+				}
+				return
+			}
+			if method != tt.method || confidence < tt.min || confidence > 1 {
+				t.Errorf("expected %s with at least %v, got %s with %v", tt.method, tt.min, method, confidence)
+			}
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	a, b, c, e := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	products := []domain.MatchCandidate{
+		{ProductID: a, Source: "store77", Brand: "Apple", Name: "Apple iPhone 15 256GB Blue"},
+		{ProductID: b, Source: "other", Brand: "Apple", Name: "Смартфон Apple iPhone 15 256 ГБ Blue"},
+		// A weaker candidate of the same source as b.
+		{ProductID: c, Source: "other", Brand: "Apple", Name: "Apple iPhone 15 256GB Blue (eSIM)"},
+		{ProductID: e, Source: "third", Brand: "Apple", Name: "Apple iPhone 15 256GB Blue"},
+	}
+	rejected := pair(b, e)
+	decided := []domain.ProductMatch{{ProductID: rejected[0], MatchedID: rejected[1], Status: domain.ProductMatchRejected}}
+
+	matches := Match(products, decided, 0.8)
+
+	got := make(map[[2]uuid.UUID]domain.ProductMatch, len(matches))
+	for _, m := range matches {
+		if m.ProductID != pair(m.ProductID, m.MatchedID)[0] {
+			t.Errorf("expected ordered ids, got %v / %v", m.ProductID, m.MatchedID)
+		}
+		got[[2]uuid.UUID{m.ProductID, m.MatchedID}] = m
+	}
+
+	for _, want := range [][2]uuid.UUID{pair(a, b), pair(a, e)} {
+		if m, ok := got[want]; !ok || m.Method != domain.MatchMethodModel || m.Status != domain.ProductMatchSuggested {
+			t.Errorf("expected model match %v, got %+v", want, matches)
+		}
+	}
+	for _, unwanted := range [][2]uuid.UUID{pair(a, c), rejected} {
+		if _, ok := got[unwanted]; ok {
+			t.Errorf("unexpected match %v", unwanted)
+		}
+	}
+}
+
+func TestMatch_SameSource(t *testing.T) {
+	products := []domain.MatchCandidate{
+		{ProductID: uuid.New(), Source: "store77", Brand: "Apple", Name: "Apple iPhone 15 256GB Blue"},
+		{ProductID: uuid.New(), Source: "store77", Brand: "Apple", Name: "Apple iPhone 15 256GB Blue"},
+	}
+
+	if matches := Match(products, nil, 0.8); len(matches) != 0 {
+		t.Errorf("expected products of one source not to match, got %+v", matches)
+	}
+}
+
+func TestMatch_ConfirmedTakesSlot(t *testing.T) {
+	a, b, c := uuid.New(), uuid.New(), uuid.New()
+	products := []domain.MatchCandidate{
+		{ProductID: a, Source: "store77", Brand: "Apple", Name: "Apple iPhone 15 256GB Blue"},
+		{ProductID: b, Source: "other", Brand: "Apple", Name: "Apple iPhone 15 256GB Blue"},
+		{ProductID: c, Source: "other", Brand: "Apple", Name: "iPhone 15 Blue 256 GB"},
+	}
+	ids := pair(a, c)
+	decided := []domain.ProductMatch{{ProductID: ids[0], MatchedID: ids[1], Status: domain.ProductMatchConfirmed}}
+
+	if matches := Match(products, decided, 0.8); len(matches) != 0 {
+		t.Errorf("expected the confirmed match to keep b unmatched, got %+v", matches)
+	}
+}
+
+func TestMatch_SKUAcrossBrands(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+	products := []domain.MatchCandidate{
+		{ProductID: a, Source: "store77", SKU: "MTV03ZA/A", Brand: "Apple", Name: "Apple iPhone 15 Pro 256GB"},
+		{ProductID: b, Source: "other", SKU: "MTV03ZAA", Brand: "Apple Inc.", Name: "Смартфон iPhone 15 Pro"},
+	}
+
+	matches := Match(products, nil, 0.8)
+	if len(matches) != 1 || matches[0].Method != domain.MatchMethodSKU {
+		t.Errorf("expected a SKU match despite the brand spelling, got %+v", matches)
+	}
+}
+
+func TestMatch_WithoutBrand(t *testing.T) {
+	a, b, c := uuid.New(), uuid.New(), uuid.New()
+	products := []domain.MatchCandidate{
+		{ProductID: a, Source: "store77", Brand: "Apple", Name: "Apple iPhone 15 256GB Blue"},
+		{ProductID: b, Source: "other", Name: "iPhone 15 256GB Blue"},
+		{ProductID: c, Source: "third", Brand: "Samsung", Name: "Samsung Galaxy S24 256GB Blue"},
+	}
+
+	matches := Match(products, nil, 0.8)
+	if len(matches) != 1 || [2]uuid.UUID{matches[0].ProductID, matches[0].MatchedID} != pair(a, b) {
+		t.Errorf("expected the product without a brand to match its model, got %+v", matches)
+	}
+}
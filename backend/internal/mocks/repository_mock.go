@@ -9,6 +9,7 @@ import (
 	"github.com/burbble/marketplace/internal/repository/postgres"
 	"github.com/google/uuid"
 	"sync"
+	"time"
 )
 
 // Ensure, that ProductRepositoryMock does implement postgres.ProductRepository.
@@ -30,7 +31,31 @@ var _ postgres.ProductRepository = &ProductRepositoryMock{}
 //			GetByIDFunc: func(ctx context.Context, id uuid.UUID) (*domain.Product, error) {
 //				panic("mock out the GetByID method")
 //			},
-//			UpsertFunc: func(ctx context.Context, products []domain.Product) error {
+//			GetDetailsFunc: func(ctx context.Context, productID uuid.UUID) (*domain.ProductDetail, error) {
+//				panic("mock out the GetDetails method")
+//			},
+//			GetFacetsFunc: func(ctx context.Context, filter domain.ProductFilter, priceBuckets int) (*domain.ProductFacets, error) {
+//				panic("mock out the GetFacets method")
+//			},
+//			GetPriceHistoryFunc: func(ctx context.Context, filter domain.PriceHistoryFilter) ([]domain.PricePoint, error) {
+//				panic("mock out the GetPriceHistory method")
+//			},
+//			GetPricesFunc: func(ctx context.Context) ([]domain.ProductPrice, error) {
+//				panic("mock out the GetPrices method")
+//			},
+//			MarkSourcesUnavailableFunc: func(ctx context.Context, enabled []string, notSeenSince time.Time) (int64, error) {
+//				panic("mock out the MarkSourcesUnavailable method")
+//			},
+//			MarkUnavailableFunc: func(ctx context.Context, categoryID uuid.UUID, notSeenSince time.Time) (int64, error) {
+//				panic("mock out the MarkUnavailable method")
+//			},
+//			ReindexAttributesFunc: func(ctx context.Context, index func([]domain.ProductAttribute) map[string]string) (int, error) {
+//				panic("mock out the ReindexAttributes method")
+//			},
+//			SaveDetailsFunc: func(ctx context.Context, details []domain.ProductDetail) error {
+//				panic("mock out the SaveDetails method")
+//			},
+//			UpsertFunc: func(ctx context.Context, products []domain.Product) (int, error) {
 //				panic("mock out the Upsert method")
 //			},
 //		}
@@ -49,8 +74,32 @@ type ProductRepositoryMock struct {
 	// GetByIDFunc mocks the GetByID method.
 	GetByIDFunc func(ctx context.Context, id uuid.UUID) (*domain.Product, error)
 
+	// GetDetailsFunc mocks the GetDetails method.
+	GetDetailsFunc func(ctx context.Context, productID uuid.UUID) (*domain.ProductDetail, error)
+
+	// GetFacetsFunc mocks the GetFacets method.
+	GetFacetsFunc func(ctx context.Context, filter domain.ProductFilter, priceBuckets int) (*domain.ProductFacets, error)
+
+	// GetPriceHistoryFunc mocks the GetPriceHistory method.
+	GetPriceHistoryFunc func(ctx context.Context, filter domain.PriceHistoryFilter) ([]domain.PricePoint, error)
+
+	// GetPricesFunc mocks the GetPrices method.
+	GetPricesFunc func(ctx context.Context) ([]domain.ProductPrice, error)
+
+	// MarkSourcesUnavailableFunc mocks the MarkSourcesUnavailable method.
+	MarkSourcesUnavailableFunc func(ctx context.Context, enabled []string, notSeenSince time.Time) (int64, error)
+
+	// MarkUnavailableFunc mocks the MarkUnavailable method.
+	MarkUnavailableFunc func(ctx context.Context, categoryID uuid.UUID, notSeenSince time.Time) (int64, error)
+
+	// ReindexAttributesFunc mocks the ReindexAttributes method.
+	ReindexAttributesFunc func(ctx context.Context, index func([]domain.ProductAttribute) map[string]string) (int, error)
+
+	// SaveDetailsFunc mocks the SaveDetails method.
+	SaveDetailsFunc func(ctx context.Context, details []domain.ProductDetail) error
+
 	// UpsertFunc mocks the Upsert method.
-	UpsertFunc func(ctx context.Context, products []domain.Product) error
+	UpsertFunc func(ctx context.Context, products []domain.Product) (int, error)
 
 	// calls tracks calls to the methods.
 	calls struct {
@@ -73,6 +122,66 @@ type ProductRepositoryMock struct {
 			// ID is the id argument value.
 			ID uuid.UUID
 		}
+		// GetDetails holds details about calls to the GetDetails method.
+		GetDetails []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ProductID is the productID argument value.
+			ProductID uuid.UUID
+		}
+		// GetFacets holds details about calls to the GetFacets method.
+		GetFacets []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Filter is the filter argument value.
+			Filter domain.ProductFilter
+			// PriceBuckets is the priceBuckets argument value.
+			PriceBuckets int
+		}
+		// GetPriceHistory holds details about calls to the GetPriceHistory method.
+		GetPriceHistory []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Filter is the filter argument value.
+			Filter domain.PriceHistoryFilter
+		}
+		// GetPrices holds details about calls to the GetPrices method.
+		GetPrices []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// MarkSourcesUnavailable holds details about calls to the MarkSourcesUnavailable method.
+		MarkSourcesUnavailable []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Enabled is the enabled argument value.
+			Enabled []string
+			// NotSeenSince is the notSeenSince argument value.
+			NotSeenSince time.Time
+		}
+		// MarkUnavailable holds details about calls to the MarkUnavailable method.
+		MarkUnavailable []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// CategoryID is the categoryID argument value.
+			CategoryID uuid.UUID
+			// NotSeenSince is the notSeenSince argument value.
+			NotSeenSince time.Time
+		}
+		// ReindexAttributes holds details about calls to the ReindexAttributes method.
+		ReindexAttributes []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Index is the index argument value.
+			Index func([]domain.ProductAttribute) map[string]string
+		}
+		// SaveDetails holds details about calls to the SaveDetails method.
+		SaveDetails []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Details is the details argument value.
+			Details []domain.ProductDetail
+		}
 		// Upsert holds details about calls to the Upsert method.
 		Upsert []struct {
 			// Ctx is the ctx argument value.
@@ -81,10 +190,18 @@ type ProductRepositoryMock struct {
 			Products []domain.Product
 		}
 	}
-	lockGetBrands   sync.RWMutex
-	lockGetByFilter sync.RWMutex
-	lockGetByID     sync.RWMutex
-	lockUpsert      sync.RWMutex
+	lockGetBrands              sync.RWMutex
+	lockGetByFilter            sync.RWMutex
+	lockGetByID                sync.RWMutex
+	lockGetDetails             sync.RWMutex
+	lockGetFacets              sync.RWMutex
+	lockGetPriceHistory        sync.RWMutex
+	lockGetPrices              sync.RWMutex
+	lockMarkSourcesUnavailable sync.RWMutex
+	lockMarkUnavailable        sync.RWMutex
+	lockReindexAttributes      sync.RWMutex
+	lockSaveDetails            sync.RWMutex
+	lockUpsert                 sync.RWMutex
 }
 
 // GetBrands calls GetBrandsFunc.
@@ -191,8 +308,304 @@ func (mock *ProductRepositoryMock) GetByIDCalls() []struct {
 	return calls
 }
 
+// GetDetails calls GetDetailsFunc.
+func (mock *ProductRepositoryMock) GetDetails(ctx context.Context, productID uuid.UUID) (*domain.ProductDetail, error) {
+	if mock.GetDetailsFunc == nil {
+		panic("ProductRepositoryMock.GetDetailsFunc: method is nil but ProductRepository.GetDetails was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		ProductID uuid.UUID
+	}{
+		Ctx:       ctx,
+		ProductID: productID,
+	}
+	mock.lockGetDetails.Lock()
+	mock.calls.GetDetails = append(mock.calls.GetDetails, callInfo)
+	mock.lockGetDetails.Unlock()
+	return mock.GetDetailsFunc(ctx, productID)
+}
+
+// GetDetailsCalls gets all the calls that were made to GetDetails.
+// Check the length with:
+//
+//	len(mockedProductRepository.GetDetailsCalls())
+func (mock *ProductRepositoryMock) GetDetailsCalls() []struct {
+	Ctx       context.Context
+	ProductID uuid.UUID
+} {
+	var calls []struct {
+		Ctx       context.Context
+		ProductID uuid.UUID
+	}
+	mock.lockGetDetails.RLock()
+	calls = mock.calls.GetDetails
+	mock.lockGetDetails.RUnlock()
+	return calls
+}
+
+// GetFacets calls GetFacetsFunc.
+func (mock *ProductRepositoryMock) GetFacets(ctx context.Context, filter domain.ProductFilter, priceBuckets int) (*domain.ProductFacets, error) {
+	if mock.GetFacetsFunc == nil {
+		panic("ProductRepositoryMock.GetFacetsFunc: method is nil but ProductRepository.GetFacets was just called")
+	}
+	callInfo := struct {
+		Ctx          context.Context
+		Filter       domain.ProductFilter
+		PriceBuckets int
+	}{
+		Ctx:          ctx,
+		Filter:       filter,
+		PriceBuckets: priceBuckets,
+	}
+	mock.lockGetFacets.Lock()
+	mock.calls.GetFacets = append(mock.calls.GetFacets, callInfo)
+	mock.lockGetFacets.Unlock()
+	return mock.GetFacetsFunc(ctx, filter, priceBuckets)
+}
+
+// GetFacetsCalls gets all the calls that were made to GetFacets.
+// Check the length with:
+//
+//	len(mockedProductRepository.GetFacetsCalls())
+func (mock *ProductRepositoryMock) GetFacetsCalls() []struct {
+	Ctx          context.Context
+	Filter       domain.ProductFilter
+	PriceBuckets int
+} {
+	var calls []struct {
+		Ctx          context.Context
+		Filter       domain.ProductFilter
+		PriceBuckets int
+	}
+	mock.lockGetFacets.RLock()
+	calls = mock.calls.GetFacets
+	mock.lockGetFacets.RUnlock()
+	return calls
+}
+
+// GetPriceHistory calls GetPriceHistoryFunc.
+func (mock *ProductRepositoryMock) GetPriceHistory(ctx context.Context, filter domain.PriceHistoryFilter) ([]domain.PricePoint, error) {
+	if mock.GetPriceHistoryFunc == nil {
+		panic("ProductRepositoryMock.GetPriceHistoryFunc: method is nil but ProductRepository.GetPriceHistory was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Filter domain.PriceHistoryFilter
+	}{
+		Ctx:    ctx,
+		Filter: filter,
+	}
+	mock.lockGetPriceHistory.Lock()
+	mock.calls.GetPriceHistory = append(mock.calls.GetPriceHistory, callInfo)
+	mock.lockGetPriceHistory.Unlock()
+	return mock.GetPriceHistoryFunc(ctx, filter)
+}
+
+// GetPriceHistoryCalls gets all the calls that were made to GetPriceHistory.
+// Check the length with:
+//
+//	len(mockedProductRepository.GetPriceHistoryCalls())
+func (mock *ProductRepositoryMock) GetPriceHistoryCalls() []struct {
+	Ctx    context.Context
+	Filter domain.PriceHistoryFilter
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Filter domain.PriceHistoryFilter
+	}
+	mock.lockGetPriceHistory.RLock()
+	calls = mock.calls.GetPriceHistory
+	mock.lockGetPriceHistory.RUnlock()
+	return calls
+}
+
+// GetPrices calls GetPricesFunc.
+func (mock *ProductRepositoryMock) GetPrices(ctx context.Context) ([]domain.ProductPrice, error) {
+	if mock.GetPricesFunc == nil {
+		panic("ProductRepositoryMock.GetPricesFunc: method is nil but ProductRepository.GetPrices was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockGetPrices.Lock()
+	mock.calls.GetPrices = append(mock.calls.GetPrices, callInfo)
+	mock.lockGetPrices.Unlock()
+	return mock.GetPricesFunc(ctx)
+}
+
+// GetPricesCalls gets all the calls that were made to GetPrices.
+// Check the length with:
+//
+//	len(mockedProductRepository.GetPricesCalls())
+func (mock *ProductRepositoryMock) GetPricesCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockGetPrices.RLock()
+	calls = mock.calls.GetPrices
+	mock.lockGetPrices.RUnlock()
+	return calls
+}
+
+// MarkSourcesUnavailable calls MarkSourcesUnavailableFunc.
+func (mock *ProductRepositoryMock) MarkSourcesUnavailable(ctx context.Context, enabled []string, notSeenSince time.Time) (int64, error) {
+	if mock.MarkSourcesUnavailableFunc == nil {
+		panic("ProductRepositoryMock.MarkSourcesUnavailableFunc: method is nil but ProductRepository.MarkSourcesUnavailable was just called")
+	}
+	callInfo := struct {
+		Ctx          context.Context
+		Enabled      []string
+		NotSeenSince time.Time
+	}{
+		Ctx:          ctx,
+		Enabled:      enabled,
+		NotSeenSince: notSeenSince,
+	}
+	mock.lockMarkSourcesUnavailable.Lock()
+	mock.calls.MarkSourcesUnavailable = append(mock.calls.MarkSourcesUnavailable, callInfo)
+	mock.lockMarkSourcesUnavailable.Unlock()
+	return mock.MarkSourcesUnavailableFunc(ctx, enabled, notSeenSince)
+}
+
+// MarkSourcesUnavailableCalls gets all the calls that were made to MarkSourcesUnavailable.
+// Check the length with:
+//
+//	len(mockedProductRepository.MarkSourcesUnavailableCalls())
+func (mock *ProductRepositoryMock) MarkSourcesUnavailableCalls() []struct {
+	Ctx          context.Context
+	Enabled      []string
+	NotSeenSince time.Time
+} {
+	var calls []struct {
+		Ctx          context.Context
+		Enabled      []string
+		NotSeenSince time.Time
+	}
+	mock.lockMarkSourcesUnavailable.RLock()
+	calls = mock.calls.MarkSourcesUnavailable
+	mock.lockMarkSourcesUnavailable.RUnlock()
+	return calls
+}
+
+// MarkUnavailable calls MarkUnavailableFunc.
+func (mock *ProductRepositoryMock) MarkUnavailable(ctx context.Context, categoryID uuid.UUID, notSeenSince time.Time) (int64, error) {
+	if mock.MarkUnavailableFunc == nil {
+		panic("ProductRepositoryMock.MarkUnavailableFunc: method is nil but ProductRepository.MarkUnavailable was just called")
+	}
+	callInfo := struct {
+		Ctx          context.Context
+		CategoryID   uuid.UUID
+		NotSeenSince time.Time
+	}{
+		Ctx:          ctx,
+		CategoryID:   categoryID,
+		NotSeenSince: notSeenSince,
+	}
+	mock.lockMarkUnavailable.Lock()
+	mock.calls.MarkUnavailable = append(mock.calls.MarkUnavailable, callInfo)
+	mock.lockMarkUnavailable.Unlock()
+	return mock.MarkUnavailableFunc(ctx, categoryID, notSeenSince)
+}
+
+// MarkUnavailableCalls gets all the calls that were made to MarkUnavailable.
+// Check the length with:
+//
+//	len(mockedProductRepository.MarkUnavailableCalls())
+func (mock *ProductRepositoryMock) MarkUnavailableCalls() []struct {
+	Ctx          context.Context
+	CategoryID   uuid.UUID
+	NotSeenSince time.Time
+} {
+	var calls []struct {
+		Ctx          context.Context
+		CategoryID   uuid.UUID
+		NotSeenSince time.Time
+	}
+	mock.lockMarkUnavailable.RLock()
+	calls = mock.calls.MarkUnavailable
+	mock.lockMarkUnavailable.RUnlock()
+	return calls
+}
+
+// ReindexAttributes calls ReindexAttributesFunc.
+func (mock *ProductRepositoryMock) ReindexAttributes(ctx context.Context, index func([]domain.ProductAttribute) map[string]string) (int, error) {
+	if mock.ReindexAttributesFunc == nil {
+		panic("ProductRepositoryMock.ReindexAttributesFunc: method is nil but ProductRepository.ReindexAttributes was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Index func([]domain.ProductAttribute) map[string]string
+	}{
+		Ctx:   ctx,
+		Index: index,
+	}
+	mock.lockReindexAttributes.Lock()
+	mock.calls.ReindexAttributes = append(mock.calls.ReindexAttributes, callInfo)
+	mock.lockReindexAttributes.Unlock()
+	return mock.ReindexAttributesFunc(ctx, index)
+}
+
+// ReindexAttributesCalls gets all the calls that were made to ReindexAttributes.
+// Check the length with:
+//
+//	len(mockedProductRepository.ReindexAttributesCalls())
+func (mock *ProductRepositoryMock) ReindexAttributesCalls() []struct {
+	Ctx   context.Context
+	Index func([]domain.ProductAttribute) map[string]string
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Index func([]domain.ProductAttribute) map[string]string
+	}
+	mock.lockReindexAttributes.RLock()
+	calls = mock.calls.ReindexAttributes
+	mock.lockReindexAttributes.RUnlock()
+	return calls
+}
+
+// SaveDetails calls SaveDetailsFunc.
+func (mock *ProductRepositoryMock) SaveDetails(ctx context.Context, details []domain.ProductDetail) error {
+	if mock.SaveDetailsFunc == nil {
+		panic("ProductRepositoryMock.SaveDetailsFunc: method is nil but ProductRepository.SaveDetails was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		Details []domain.ProductDetail
+	}{
+		Ctx:     ctx,
+		Details: details,
+	}
+	mock.lockSaveDetails.Lock()
+	mock.calls.SaveDetails = append(mock.calls.SaveDetails, callInfo)
+	mock.lockSaveDetails.Unlock()
+	return mock.SaveDetailsFunc(ctx, details)
+}
+
+// SaveDetailsCalls gets all the calls that were made to SaveDetails.
+// Check the length with:
+//
+//	len(mockedProductRepository.SaveDetailsCalls())
+func (mock *ProductRepositoryMock) SaveDetailsCalls() []struct {
+	Ctx     context.Context
+	Details []domain.ProductDetail
+} {
+	var calls []struct {
+		Ctx     context.Context
+		Details []domain.ProductDetail
+	}
+	mock.lockSaveDetails.RLock()
+	calls = mock.calls.SaveDetails
+	mock.lockSaveDetails.RUnlock()
+	return calls
+}
+
 // Upsert calls UpsertFunc.
-func (mock *ProductRepositoryMock) Upsert(ctx context.Context, products []domain.Product) error {
+func (mock *ProductRepositoryMock) Upsert(ctx context.Context, products []domain.Product) (int, error) {
 	if mock.UpsertFunc == nil {
 		panic("ProductRepositoryMock.UpsertFunc: method is nil but ProductRepository.Upsert was just called")
 	}
@@ -243,7 +656,7 @@ var _ postgres.CategoryRepository = &CategoryRepositoryMock{}
 //			GetByIDFunc: func(ctx context.Context, id uuid.UUID) (*domain.Category, error) {
 //				panic("mock out the GetByID method")
 //			},
-//			GetBySlugFunc: func(ctx context.Context, slug string) (*domain.Category, error) {
+//			GetBySlugFunc: func(ctx context.Context, source string, slug string) (*domain.Category, error) {
 //				panic("mock out the GetBySlug method")
 //			},
 //			UpsertFunc: func(ctx context.Context, categories []domain.Category) error {
@@ -263,7 +676,7 @@ type CategoryRepositoryMock struct {
 	GetByIDFunc func(ctx context.Context, id uuid.UUID) (*domain.Category, error)
 
 	// GetBySlugFunc mocks the GetBySlug method.
-	GetBySlugFunc func(ctx context.Context, slug string) (*domain.Category, error)
+	GetBySlugFunc func(ctx context.Context, source string, slug string) (*domain.Category, error)
 
 	// UpsertFunc mocks the Upsert method.
 	UpsertFunc func(ctx context.Context, categories []domain.Category) error
@@ -286,6 +699,8 @@ type CategoryRepositoryMock struct {
 		GetBySlug []struct {
 			// Ctx is the ctx argument value.
 			Ctx context.Context
+			// Source is the source argument value.
+			Source string
 			// Slug is the slug argument value.
 			Slug string
 		}
@@ -372,21 +787,23 @@ func (mock *CategoryRepositoryMock) GetByIDCalls() []struct {
 }
 
 // GetBySlug calls GetBySlugFunc.
-func (mock *CategoryRepositoryMock) GetBySlug(ctx context.Context, slug string) (*domain.Category, error) {
+func (mock *CategoryRepositoryMock) GetBySlug(ctx context.Context, source string, slug string) (*domain.Category, error) {
 	if mock.GetBySlugFunc == nil {
 		panic("CategoryRepositoryMock.GetBySlugFunc: method is nil but CategoryRepository.GetBySlug was just called")
 	}
 	callInfo := struct {
-		Ctx  context.Context
-		Slug string
+		Ctx    context.Context
+		Source string
+		Slug   string
 	}{
-		Ctx:  ctx,
-		Slug: slug,
+		Ctx:    ctx,
+		Source: source,
+		Slug:   slug,
 	}
 	mock.lockGetBySlug.Lock()
 	mock.calls.GetBySlug = append(mock.calls.GetBySlug, callInfo)
 	mock.lockGetBySlug.Unlock()
-	return mock.GetBySlugFunc(ctx, slug)
+	return mock.GetBySlugFunc(ctx, source, slug)
 }
 
 // GetBySlugCalls gets all the calls that were made to GetBySlug.
@@ -394,12 +811,14 @@ func (mock *CategoryRepositoryMock) GetBySlug(ctx context.Context, slug string)
 //
 //	len(mockedCategoryRepository.GetBySlugCalls())
 func (mock *CategoryRepositoryMock) GetBySlugCalls() []struct {
-	Ctx  context.Context
-	Slug string
+	Ctx    context.Context
+	Source string
+	Slug   string
 } {
 	var calls []struct {
-		Ctx  context.Context
-		Slug string
+		Ctx    context.Context
+		Source string
+		Slug   string
 	}
 	mock.lockGetBySlug.RLock()
 	calls = mock.calls.GetBySlug
@@ -442,3 +861,2051 @@ func (mock *CategoryRepositoryMock) UpsertCalls() []struct {
 	mock.lockUpsert.RUnlock()
 	return calls
 }
+
+// Ensure, that PricingRuleRepositoryMock does implement postgres.PricingRuleRepository.
+// If this is not the case, regenerate this file with moq.
+var _ postgres.PricingRuleRepository = &PricingRuleRepositoryMock{}
+
+// PricingRuleRepositoryMock is a mock implementation of postgres.PricingRuleRepository.
+//
+//	func TestSomethingThatUsesPricingRuleRepository(t *testing.T) {
+//
+//		// make and configure a mocked postgres.PricingRuleRepository
+//		mockedPricingRuleRepository := &PricingRuleRepositoryMock{
+//			CreateFunc: func(ctx context.Context, rule *domain.PricingRule) error {
+//				panic("mock out the Create method")
+//			},
+//			DeleteFunc: func(ctx context.Context, id uuid.UUID) error {
+//				panic("mock out the Delete method")
+//			},
+//			GetAllFunc: func(ctx context.Context) ([]domain.PricingRule, error) {
+//				panic("mock out the GetAll method")
+//			},
+//			GetByIDFunc: func(ctx context.Context, id uuid.UUID) (*domain.PricingRule, error) {
+//				panic("mock out the GetByID method")
+//			},
+//			UpdateFunc: func(ctx context.Context, rule *domain.PricingRule) error {
+//				panic("mock out the Update method")
+//			},
+//		}
+//
+//		// use mockedPricingRuleRepository in code that requires postgres.PricingRuleRepository
+//		// and then make assertions.
+//
+//	}
+type PricingRuleRepositoryMock struct {
+	// CreateFunc mocks the Create method.
+	CreateFunc func(ctx context.Context, rule *domain.PricingRule) error
+
+	// DeleteFunc mocks the Delete method.
+	DeleteFunc func(ctx context.Context, id uuid.UUID) error
+
+	// GetAllFunc mocks the GetAll method.
+	GetAllFunc func(ctx context.Context) ([]domain.PricingRule, error)
+
+	// GetByIDFunc mocks the GetByID method.
+	GetByIDFunc func(ctx context.Context, id uuid.UUID) (*domain.PricingRule, error)
+
+	// UpdateFunc mocks the Update method.
+	UpdateFunc func(ctx context.Context, rule *domain.PricingRule) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Create holds details about calls to the Create method.
+		Create []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Rule is the rule argument value.
+			Rule *domain.PricingRule
+		}
+		// Delete holds details about calls to the Delete method.
+		Delete []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID uuid.UUID
+		}
+		// GetAll holds details about calls to the GetAll method.
+		GetAll []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// GetByID holds details about calls to the GetByID method.
+		GetByID []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID uuid.UUID
+		}
+		// Update holds details about calls to the Update method.
+		Update []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Rule is the rule argument value.
+			Rule *domain.PricingRule
+		}
+	}
+	lockCreate  sync.RWMutex
+	lockDelete  sync.RWMutex
+	lockGetAll  sync.RWMutex
+	lockGetByID sync.RWMutex
+	lockUpdate  sync.RWMutex
+}
+
+// Create calls CreateFunc.
+func (mock *PricingRuleRepositoryMock) Create(ctx context.Context, rule *domain.PricingRule) error {
+	if mock.CreateFunc == nil {
+		panic("PricingRuleRepositoryMock.CreateFunc: method is nil but PricingRuleRepository.Create was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		Rule *domain.PricingRule
+	}{
+		Ctx:  ctx,
+		Rule: rule,
+	}
+	mock.lockCreate.Lock()
+	mock.calls.Create = append(mock.calls.Create, callInfo)
+	mock.lockCreate.Unlock()
+	return mock.CreateFunc(ctx, rule)
+}
+
+// CreateCalls gets all the calls that were made to Create.
+// Check the length with:
+//
+//	len(mockedPricingRuleRepository.CreateCalls())
+func (mock *PricingRuleRepositoryMock) CreateCalls() []struct {
+	Ctx  context.Context
+	Rule *domain.PricingRule
+} {
+	var calls []struct {
+		Ctx  context.Context
+		Rule *domain.PricingRule
+	}
+	mock.lockCreate.RLock()
+	calls = mock.calls.Create
+	mock.lockCreate.RUnlock()
+	return calls
+}
+
+// Delete calls DeleteFunc.
+func (mock *PricingRuleRepositoryMock) Delete(ctx context.Context, id uuid.UUID) error {
+	if mock.DeleteFunc == nil {
+		panic("PricingRuleRepositoryMock.DeleteFunc: method is nil but PricingRuleRepository.Delete was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  uuid.UUID
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockDelete.Lock()
+	mock.calls.Delete = append(mock.calls.Delete, callInfo)
+	mock.lockDelete.Unlock()
+	return mock.DeleteFunc(ctx, id)
+}
+
+// DeleteCalls gets all the calls that were made to Delete.
+// Check the length with:
+//
+//	len(mockedPricingRuleRepository.DeleteCalls())
+func (mock *PricingRuleRepositoryMock) DeleteCalls() []struct {
+	Ctx context.Context
+	ID  uuid.UUID
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  uuid.UUID
+	}
+	mock.lockDelete.RLock()
+	calls = mock.calls.Delete
+	mock.lockDelete.RUnlock()
+	return calls
+}
+
+// GetAll calls GetAllFunc.
+func (mock *PricingRuleRepositoryMock) GetAll(ctx context.Context) ([]domain.PricingRule, error) {
+	if mock.GetAllFunc == nil {
+		panic("PricingRuleRepositoryMock.GetAllFunc: method is nil but PricingRuleRepository.GetAll was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockGetAll.Lock()
+	mock.calls.GetAll = append(mock.calls.GetAll, callInfo)
+	mock.lockGetAll.Unlock()
+	return mock.GetAllFunc(ctx)
+}
+
+// GetAllCalls gets all the calls that were made to GetAll.
+// Check the length with:
+//
+//	len(mockedPricingRuleRepository.GetAllCalls())
+func (mock *PricingRuleRepositoryMock) GetAllCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockGetAll.RLock()
+	calls = mock.calls.GetAll
+	mock.lockGetAll.RUnlock()
+	return calls
+}
+
+// GetByID calls GetByIDFunc.
+func (mock *PricingRuleRepositoryMock) GetByID(ctx context.Context, id uuid.UUID) (*domain.PricingRule, error) {
+	if mock.GetByIDFunc == nil {
+		panic("PricingRuleRepositoryMock.GetByIDFunc: method is nil but PricingRuleRepository.GetByID was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  uuid.UUID
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockGetByID.Lock()
+	mock.calls.GetByID = append(mock.calls.GetByID, callInfo)
+	mock.lockGetByID.Unlock()
+	return mock.GetByIDFunc(ctx, id)
+}
+
+// GetByIDCalls gets all the calls that were made to GetByID.
+// Check the length with:
+//
+//	len(mockedPricingRuleRepository.GetByIDCalls())
+func (mock *PricingRuleRepositoryMock) GetByIDCalls() []struct {
+	Ctx context.Context
+	ID  uuid.UUID
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  uuid.UUID
+	}
+	mock.lockGetByID.RLock()
+	calls = mock.calls.GetByID
+	mock.lockGetByID.RUnlock()
+	return calls
+}
+
+// Update calls UpdateFunc.
+func (mock *PricingRuleRepositoryMock) Update(ctx context.Context, rule *domain.PricingRule) error {
+	if mock.UpdateFunc == nil {
+		panic("PricingRuleRepositoryMock.UpdateFunc: method is nil but PricingRuleRepository.Update was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		Rule *domain.PricingRule
+	}{
+		Ctx:  ctx,
+		Rule: rule,
+	}
+	mock.lockUpdate.Lock()
+	mock.calls.Update = append(mock.calls.Update, callInfo)
+	mock.lockUpdate.Unlock()
+	return mock.UpdateFunc(ctx, rule)
+}
+
+// UpdateCalls gets all the calls that were made to Update.
+// Check the length with:
+//
+//	len(mockedPricingRuleRepository.UpdateCalls())
+func (mock *PricingRuleRepositoryMock) UpdateCalls() []struct {
+	Ctx  context.Context
+	Rule *domain.PricingRule
+} {
+	var calls []struct {
+		Ctx  context.Context
+		Rule *domain.PricingRule
+	}
+	mock.lockUpdate.RLock()
+	calls = mock.calls.Update
+	mock.lockUpdate.RUnlock()
+	return calls
+}
+
+// Ensure, that SearchRepositoryMock does implement postgres.SearchRepository.
+// If this is not the case, regenerate this file with moq.
+var _ postgres.SearchRepository = &SearchRepositoryMock{}
+
+// SearchRepositoryMock is a mock implementation of postgres.SearchRepository.
+//
+//	func TestSomethingThatUsesSearchRepository(t *testing.T) {
+//
+//		// make and configure a mocked postgres.SearchRepository
+//		mockedSearchRepository := &SearchRepositoryMock{
+//			GetBrandAliasesFunc: func(ctx context.Context) ([]domain.BrandAlias, error) {
+//				panic("mock out the GetBrandAliases method")
+//			},
+//			SuggestFunc: func(ctx context.Context, term string, limit uint64) ([]domain.Suggestion, error) {
+//				panic("mock out the Suggest method")
+//			},
+//		}
+//
+//		// use mockedSearchRepository in code that requires postgres.SearchRepository
+//		// and then make assertions.
+//
+//	}
+type SearchRepositoryMock struct {
+	// GetBrandAliasesFunc mocks the GetBrandAliases method.
+	GetBrandAliasesFunc func(ctx context.Context) ([]domain.BrandAlias, error)
+
+	// SuggestFunc mocks the Suggest method.
+	SuggestFunc func(ctx context.Context, term string, limit uint64) ([]domain.Suggestion, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// GetBrandAliases holds details about calls to the GetBrandAliases method.
+		GetBrandAliases []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// Suggest holds details about calls to the Suggest method.
+		Suggest []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Term is the term argument value.
+			Term string
+			// Limit is the limit argument value.
+			Limit uint64
+		}
+	}
+	lockGetBrandAliases sync.RWMutex
+	lockSuggest         sync.RWMutex
+}
+
+// GetBrandAliases calls GetBrandAliasesFunc.
+func (mock *SearchRepositoryMock) GetBrandAliases(ctx context.Context) ([]domain.BrandAlias, error) {
+	if mock.GetBrandAliasesFunc == nil {
+		panic("SearchRepositoryMock.GetBrandAliasesFunc: method is nil but SearchRepository.GetBrandAliases was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockGetBrandAliases.Lock()
+	mock.calls.GetBrandAliases = append(mock.calls.GetBrandAliases, callInfo)
+	mock.lockGetBrandAliases.Unlock()
+	return mock.GetBrandAliasesFunc(ctx)
+}
+
+// GetBrandAliasesCalls gets all the calls that were made to GetBrandAliases.
+// Check the length with:
+//
+//	len(mockedSearchRepository.GetBrandAliasesCalls())
+func (mock *SearchRepositoryMock) GetBrandAliasesCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockGetBrandAliases.RLock()
+	calls = mock.calls.GetBrandAliases
+	mock.lockGetBrandAliases.RUnlock()
+	return calls
+}
+
+// Suggest calls SuggestFunc.
+func (mock *SearchRepositoryMock) Suggest(ctx context.Context, term string, limit uint64) ([]domain.Suggestion, error) {
+	if mock.SuggestFunc == nil {
+		panic("SearchRepositoryMock.SuggestFunc: method is nil but SearchRepository.Suggest was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Term  string
+		Limit uint64
+	}{
+		Ctx:   ctx,
+		Term:  term,
+		Limit: limit,
+	}
+	mock.lockSuggest.Lock()
+	mock.calls.Suggest = append(mock.calls.Suggest, callInfo)
+	mock.lockSuggest.Unlock()
+	return mock.SuggestFunc(ctx, term, limit)
+}
+
+// SuggestCalls gets all the calls that were made to Suggest.
+// Check the length with:
+//
+//	len(mockedSearchRepository.SuggestCalls())
+func (mock *SearchRepositoryMock) SuggestCalls() []struct {
+	Ctx   context.Context
+	Term  string
+	Limit uint64
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Term  string
+		Limit uint64
+	}
+	mock.lockSuggest.RLock()
+	calls = mock.calls.Suggest
+	mock.lockSuggest.RUnlock()
+	return calls
+}
+
+// Ensure, that ScrapeRunRepositoryMock does implement postgres.ScrapeRunRepository.
+// If this is not the case, regenerate this file with moq.
+var _ postgres.ScrapeRunRepository = &ScrapeRunRepositoryMock{}
+
+// ScrapeRunRepositoryMock is a mock implementation of postgres.ScrapeRunRepository.
+//
+//	func TestSomethingThatUsesScrapeRunRepository(t *testing.T) {
+//
+//		// make and configure a mocked postgres.ScrapeRunRepository
+//		mockedScrapeRunRepository := &ScrapeRunRepositoryMock{
+//			AddCategoryFunc: func(ctx context.Context, category *domain.ScrapeRunCategory) error {
+//				panic("mock out the AddCategory method")
+//			},
+//			AddFailedPagesFunc: func(ctx context.Context, runID uuid.UUID, pages []domain.ScrapeRunFailedPage) error {
+//				panic("mock out the AddFailedPages method")
+//			},
+//			CreateFunc: func(ctx context.Context, run *domain.ScrapeRun) error {
+//				panic("mock out the Create method")
+//			},
+//			FailAbandonedFunc: func(ctx context.Context, reason string) (int64, error) {
+//				panic("mock out the FailAbandoned method")
+//			},
+//			FinishFunc: func(ctx context.Context, run *domain.ScrapeRun) error {
+//				panic("mock out the Finish method")
+//			},
+//			GetByIDFunc: func(ctx context.Context, id uuid.UUID) (*domain.ScrapeRun, error) {
+//				panic("mock out the GetByID method")
+//			},
+//			GetCategoriesFunc: func(ctx context.Context, runID uuid.UUID) ([]domain.ScrapeRunCategory, error) {
+//				panic("mock out the GetCategories method")
+//			},
+//			GetFailedPagesFunc: func(ctx context.Context, runID uuid.UUID) ([]domain.ScrapeRunFailedPage, error) {
+//				panic("mock out the GetFailedPages method")
+//			},
+//			GetListFunc: func(ctx context.Context, filter domain.ScrapeRunFilter) (*domain.ScrapeRunList, error) {
+//				panic("mock out the GetList method")
+//			},
+//			LastScrapedFunc: func(ctx context.Context) (map[uuid.UUID]time.Time, error) {
+//				panic("mock out the LastScraped method")
+//			},
+//			SetMarkedUnavailableFunc: func(ctx context.Context, categoryRunID int64, marked int) error {
+//				panic("mock out the SetMarkedUnavailable method")
+//			},
+//		}
+//
+//		// use mockedScrapeRunRepository in code that requires postgres.ScrapeRunRepository
+//		// and then make assertions.
+//
+//	}
+type ScrapeRunRepositoryMock struct {
+	// AddCategoryFunc mocks the AddCategory method.
+	AddCategoryFunc func(ctx context.Context, category *domain.ScrapeRunCategory) error
+
+	// AddFailedPagesFunc mocks the AddFailedPages method.
+	AddFailedPagesFunc func(ctx context.Context, runID uuid.UUID, pages []domain.ScrapeRunFailedPage) error
+
+	// CreateFunc mocks the Create method.
+	CreateFunc func(ctx context.Context, run *domain.ScrapeRun) error
+
+	// FailAbandonedFunc mocks the FailAbandoned method.
+	FailAbandonedFunc func(ctx context.Context, reason string) (int64, error)
+
+	// FinishFunc mocks the Finish method.
+	FinishFunc func(ctx context.Context, run *domain.ScrapeRun) error
+
+	// GetByIDFunc mocks the GetByID method.
+	GetByIDFunc func(ctx context.Context, id uuid.UUID) (*domain.ScrapeRun, error)
+
+	// GetCategoriesFunc mocks the GetCategories method.
+	GetCategoriesFunc func(ctx context.Context, runID uuid.UUID) ([]domain.ScrapeRunCategory, error)
+
+	// GetFailedPagesFunc mocks the GetFailedPages method.
+	GetFailedPagesFunc func(ctx context.Context, runID uuid.UUID) ([]domain.ScrapeRunFailedPage, error)
+
+	// GetListFunc mocks the GetList method.
+	GetListFunc func(ctx context.Context, filter domain.ScrapeRunFilter) (*domain.ScrapeRunList, error)
+
+	// LastScrapedFunc mocks the LastScraped method.
+	LastScrapedFunc func(ctx context.Context) (map[uuid.UUID]time.Time, error)
+
+	// SetMarkedUnavailableFunc mocks the SetMarkedUnavailable method.
+	SetMarkedUnavailableFunc func(ctx context.Context, categoryRunID int64, marked int) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// AddCategory holds details about calls to the AddCategory method.
+		AddCategory []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Category is the category argument value.
+			Category *domain.ScrapeRunCategory
+		}
+		// AddFailedPages holds details about calls to the AddFailedPages method.
+		AddFailedPages []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// RunID is the runID argument value.
+			RunID uuid.UUID
+			// Pages is the pages argument value.
+			Pages []domain.ScrapeRunFailedPage
+		}
+		// Create holds details about calls to the Create method.
+		Create []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Run is the run argument value.
+			Run *domain.ScrapeRun
+		}
+		// FailAbandoned holds details about calls to the FailAbandoned method.
+		FailAbandoned []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Reason is the reason argument value.
+			Reason string
+		}
+		// Finish holds details about calls to the Finish method.
+		Finish []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Run is the run argument value.
+			Run *domain.ScrapeRun
+		}
+		// GetByID holds details about calls to the GetByID method.
+		GetByID []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID uuid.UUID
+		}
+		// GetCategories holds details about calls to the GetCategories method.
+		GetCategories []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// RunID is the runID argument value.
+			RunID uuid.UUID
+		}
+		// GetFailedPages holds details about calls to the GetFailedPages method.
+		GetFailedPages []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// RunID is the runID argument value.
+			RunID uuid.UUID
+		}
+		// GetList holds details about calls to the GetList method.
+		GetList []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Filter is the filter argument value.
+			Filter domain.ScrapeRunFilter
+		}
+		// LastScraped holds details about calls to the LastScraped method.
+		LastScraped []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// SetMarkedUnavailable holds details about calls to the SetMarkedUnavailable method.
+		SetMarkedUnavailable []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// CategoryRunID is the categoryRunID argument value.
+			CategoryRunID int64
+			// Marked is the marked argument value.
+			Marked int
+		}
+	}
+	lockAddCategory          sync.RWMutex
+	lockAddFailedPages       sync.RWMutex
+	lockCreate               sync.RWMutex
+	lockFailAbandoned        sync.RWMutex
+	lockFinish               sync.RWMutex
+	lockGetByID              sync.RWMutex
+	lockGetCategories        sync.RWMutex
+	lockGetFailedPages       sync.RWMutex
+	lockGetList              sync.RWMutex
+	lockLastScraped          sync.RWMutex
+	lockSetMarkedUnavailable sync.RWMutex
+}
+
+// AddCategory calls AddCategoryFunc.
+func (mock *ScrapeRunRepositoryMock) AddCategory(ctx context.Context, category *domain.ScrapeRunCategory) error {
+	if mock.AddCategoryFunc == nil {
+		panic("ScrapeRunRepositoryMock.AddCategoryFunc: method is nil but ScrapeRunRepository.AddCategory was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Category *domain.ScrapeRunCategory
+	}{
+		Ctx:      ctx,
+		Category: category,
+	}
+	mock.lockAddCategory.Lock()
+	mock.calls.AddCategory = append(mock.calls.AddCategory, callInfo)
+	mock.lockAddCategory.Unlock()
+	return mock.AddCategoryFunc(ctx, category)
+}
+
+// AddCategoryCalls gets all the calls that were made to AddCategory.
+// Check the length with:
+//
+//	len(mockedScrapeRunRepository.AddCategoryCalls())
+func (mock *ScrapeRunRepositoryMock) AddCategoryCalls() []struct {
+	Ctx      context.Context
+	Category *domain.ScrapeRunCategory
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Category *domain.ScrapeRunCategory
+	}
+	mock.lockAddCategory.RLock()
+	calls = mock.calls.AddCategory
+	mock.lockAddCategory.RUnlock()
+	return calls
+}
+
+// AddFailedPages calls AddFailedPagesFunc.
+func (mock *ScrapeRunRepositoryMock) AddFailedPages(ctx context.Context, runID uuid.UUID, pages []domain.ScrapeRunFailedPage) error {
+	if mock.AddFailedPagesFunc == nil {
+		panic("ScrapeRunRepositoryMock.AddFailedPagesFunc: method is nil but ScrapeRunRepository.AddFailedPages was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		RunID uuid.UUID
+		Pages []domain.ScrapeRunFailedPage
+	}{
+		Ctx:   ctx,
+		RunID: runID,
+		Pages: pages,
+	}
+	mock.lockAddFailedPages.Lock()
+	mock.calls.AddFailedPages = append(mock.calls.AddFailedPages, callInfo)
+	mock.lockAddFailedPages.Unlock()
+	return mock.AddFailedPagesFunc(ctx, runID, pages)
+}
+
+// AddFailedPagesCalls gets all the calls that were made to AddFailedPages.
+// Check the length with:
+//
+//	len(mockedScrapeRunRepository.AddFailedPagesCalls())
+func (mock *ScrapeRunRepositoryMock) AddFailedPagesCalls() []struct {
+	Ctx   context.Context
+	RunID uuid.UUID
+	Pages []domain.ScrapeRunFailedPage
+} {
+	var calls []struct {
+		Ctx   context.Context
+		RunID uuid.UUID
+		Pages []domain.ScrapeRunFailedPage
+	}
+	mock.lockAddFailedPages.RLock()
+	calls = mock.calls.AddFailedPages
+	mock.lockAddFailedPages.RUnlock()
+	return calls
+}
+
+// Create calls CreateFunc.
+func (mock *ScrapeRunRepositoryMock) Create(ctx context.Context, run *domain.ScrapeRun) error {
+	if mock.CreateFunc == nil {
+		panic("ScrapeRunRepositoryMock.CreateFunc: method is nil but ScrapeRunRepository.Create was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Run *domain.ScrapeRun
+	}{
+		Ctx: ctx,
+		Run: run,
+	}
+	mock.lockCreate.Lock()
+	mock.calls.Create = append(mock.calls.Create, callInfo)
+	mock.lockCreate.Unlock()
+	return mock.CreateFunc(ctx, run)
+}
+
+// CreateCalls gets all the calls that were made to Create.
+// Check the length with:
+//
+//	len(mockedScrapeRunRepository.CreateCalls())
+func (mock *ScrapeRunRepositoryMock) CreateCalls() []struct {
+	Ctx context.Context
+	Run *domain.ScrapeRun
+} {
+	var calls []struct {
+		Ctx context.Context
+		Run *domain.ScrapeRun
+	}
+	mock.lockCreate.RLock()
+	calls = mock.calls.Create
+	mock.lockCreate.RUnlock()
+	return calls
+}
+
+// FailAbandoned calls FailAbandonedFunc.
+func (mock *ScrapeRunRepositoryMock) FailAbandoned(ctx context.Context, reason string) (int64, error) {
+	if mock.FailAbandonedFunc == nil {
+		panic("ScrapeRunRepositoryMock.FailAbandonedFunc: method is nil but ScrapeRunRepository.FailAbandoned was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Reason string
+	}{
+		Ctx:    ctx,
+		Reason: reason,
+	}
+	mock.lockFailAbandoned.Lock()
+	mock.calls.FailAbandoned = append(mock.calls.FailAbandoned, callInfo)
+	mock.lockFailAbandoned.Unlock()
+	return mock.FailAbandonedFunc(ctx, reason)
+}
+
+// FailAbandonedCalls gets all the calls that were made to FailAbandoned.
+// Check the length with:
+//
+//	len(mockedScrapeRunRepository.FailAbandonedCalls())
+func (mock *ScrapeRunRepositoryMock) FailAbandonedCalls() []struct {
+	Ctx    context.Context
+	Reason string
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Reason string
+	}
+	mock.lockFailAbandoned.RLock()
+	calls = mock.calls.FailAbandoned
+	mock.lockFailAbandoned.RUnlock()
+	return calls
+}
+
+// Finish calls FinishFunc.
+func (mock *ScrapeRunRepositoryMock) Finish(ctx context.Context, run *domain.ScrapeRun) error {
+	if mock.FinishFunc == nil {
+		panic("ScrapeRunRepositoryMock.FinishFunc: method is nil but ScrapeRunRepository.Finish was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Run *domain.ScrapeRun
+	}{
+		Ctx: ctx,
+		Run: run,
+	}
+	mock.lockFinish.Lock()
+	mock.calls.Finish = append(mock.calls.Finish, callInfo)
+	mock.lockFinish.Unlock()
+	return mock.FinishFunc(ctx, run)
+}
+
+// FinishCalls gets all the calls that were made to Finish.
+// Check the length with:
+//
+//	len(mockedScrapeRunRepository.FinishCalls())
+func (mock *ScrapeRunRepositoryMock) FinishCalls() []struct {
+	Ctx context.Context
+	Run *domain.ScrapeRun
+} {
+	var calls []struct {
+		Ctx context.Context
+		Run *domain.ScrapeRun
+	}
+	mock.lockFinish.RLock()
+	calls = mock.calls.Finish
+	mock.lockFinish.RUnlock()
+	return calls
+}
+
+// GetByID calls GetByIDFunc.
+func (mock *ScrapeRunRepositoryMock) GetByID(ctx context.Context, id uuid.UUID) (*domain.ScrapeRun, error) {
+	if mock.GetByIDFunc == nil {
+		panic("ScrapeRunRepositoryMock.GetByIDFunc: method is nil but ScrapeRunRepository.GetByID was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  uuid.UUID
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockGetByID.Lock()
+	mock.calls.GetByID = append(mock.calls.GetByID, callInfo)
+	mock.lockGetByID.Unlock()
+	return mock.GetByIDFunc(ctx, id)
+}
+
+// GetByIDCalls gets all the calls that were made to GetByID.
+// Check the length with:
+//
+//	len(mockedScrapeRunRepository.GetByIDCalls())
+func (mock *ScrapeRunRepositoryMock) GetByIDCalls() []struct {
+	Ctx context.Context
+	ID  uuid.UUID
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  uuid.UUID
+	}
+	mock.lockGetByID.RLock()
+	calls = mock.calls.GetByID
+	mock.lockGetByID.RUnlock()
+	return calls
+}
+
+// GetCategories calls GetCategoriesFunc.
+func (mock *ScrapeRunRepositoryMock) GetCategories(ctx context.Context, runID uuid.UUID) ([]domain.ScrapeRunCategory, error) {
+	if mock.GetCategoriesFunc == nil {
+		panic("ScrapeRunRepositoryMock.GetCategoriesFunc: method is nil but ScrapeRunRepository.GetCategories was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		RunID uuid.UUID
+	}{
+		Ctx:   ctx,
+		RunID: runID,
+	}
+	mock.lockGetCategories.Lock()
+	mock.calls.GetCategories = append(mock.calls.GetCategories, callInfo)
+	mock.lockGetCategories.Unlock()
+	return mock.GetCategoriesFunc(ctx, runID)
+}
+
+// GetCategoriesCalls gets all the calls that were made to GetCategories.
+// Check the length with:
+//
+//	len(mockedScrapeRunRepository.GetCategoriesCalls())
+func (mock *ScrapeRunRepositoryMock) GetCategoriesCalls() []struct {
+	Ctx   context.Context
+	RunID uuid.UUID
+} {
+	var calls []struct {
+		Ctx   context.Context
+		RunID uuid.UUID
+	}
+	mock.lockGetCategories.RLock()
+	calls = mock.calls.GetCategories
+	mock.lockGetCategories.RUnlock()
+	return calls
+}
+
+// GetFailedPages calls GetFailedPagesFunc.
+func (mock *ScrapeRunRepositoryMock) GetFailedPages(ctx context.Context, runID uuid.UUID) ([]domain.ScrapeRunFailedPage, error) {
+	if mock.GetFailedPagesFunc == nil {
+		panic("ScrapeRunRepositoryMock.GetFailedPagesFunc: method is nil but ScrapeRunRepository.GetFailedPages was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		RunID uuid.UUID
+	}{
+		Ctx:   ctx,
+		RunID: runID,
+	}
+	mock.lockGetFailedPages.Lock()
+	mock.calls.GetFailedPages = append(mock.calls.GetFailedPages, callInfo)
+	mock.lockGetFailedPages.Unlock()
+	return mock.GetFailedPagesFunc(ctx, runID)
+}
+
+// GetFailedPagesCalls gets all the calls that were made to GetFailedPages.
+// Check the length with:
+//
+//	len(mockedScrapeRunRepository.GetFailedPagesCalls())
+func (mock *ScrapeRunRepositoryMock) GetFailedPagesCalls() []struct {
+	Ctx   context.Context
+	RunID uuid.UUID
+} {
+	var calls []struct {
+		Ctx   context.Context
+		RunID uuid.UUID
+	}
+	mock.lockGetFailedPages.RLock()
+	calls = mock.calls.GetFailedPages
+	mock.lockGetFailedPages.RUnlock()
+	return calls
+}
+
+// GetList calls GetListFunc.
+func (mock *ScrapeRunRepositoryMock) GetList(ctx context.Context, filter domain.ScrapeRunFilter) (*domain.ScrapeRunList, error) {
+	if mock.GetListFunc == nil {
+		panic("ScrapeRunRepositoryMock.GetListFunc: method is nil but ScrapeRunRepository.GetList was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Filter domain.ScrapeRunFilter
+	}{
+		Ctx:    ctx,
+		Filter: filter,
+	}
+	mock.lockGetList.Lock()
+	mock.calls.GetList = append(mock.calls.GetList, callInfo)
+	mock.lockGetList.Unlock()
+	return mock.GetListFunc(ctx, filter)
+}
+
+// GetListCalls gets all the calls that were made to GetList.
+// Check the length with:
+//
+//	len(mockedScrapeRunRepository.GetListCalls())
+func (mock *ScrapeRunRepositoryMock) GetListCalls() []struct {
+	Ctx    context.Context
+	Filter domain.ScrapeRunFilter
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Filter domain.ScrapeRunFilter
+	}
+	mock.lockGetList.RLock()
+	calls = mock.calls.GetList
+	mock.lockGetList.RUnlock()
+	return calls
+}
+
+// LastScraped calls LastScrapedFunc.
+func (mock *ScrapeRunRepositoryMock) LastScraped(ctx context.Context) (map[uuid.UUID]time.Time, error) {
+	if mock.LastScrapedFunc == nil {
+		panic("ScrapeRunRepositoryMock.LastScrapedFunc: method is nil but ScrapeRunRepository.LastScraped was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockLastScraped.Lock()
+	mock.calls.LastScraped = append(mock.calls.LastScraped, callInfo)
+	mock.lockLastScraped.Unlock()
+	return mock.LastScrapedFunc(ctx)
+}
+
+// LastScrapedCalls gets all the calls that were made to LastScraped.
+// Check the length with:
+//
+//	len(mockedScrapeRunRepository.LastScrapedCalls())
+func (mock *ScrapeRunRepositoryMock) LastScrapedCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockLastScraped.RLock()
+	calls = mock.calls.LastScraped
+	mock.lockLastScraped.RUnlock()
+	return calls
+}
+
+// SetMarkedUnavailable calls SetMarkedUnavailableFunc.
+func (mock *ScrapeRunRepositoryMock) SetMarkedUnavailable(ctx context.Context, categoryRunID int64, marked int) error {
+	if mock.SetMarkedUnavailableFunc == nil {
+		panic("ScrapeRunRepositoryMock.SetMarkedUnavailableFunc: method is nil but ScrapeRunRepository.SetMarkedUnavailable was just called")
+	}
+	callInfo := struct {
+		Ctx           context.Context
+		CategoryRunID int64
+		Marked        int
+	}{
+		Ctx:           ctx,
+		CategoryRunID: categoryRunID,
+		Marked:        marked,
+	}
+	mock.lockSetMarkedUnavailable.Lock()
+	mock.calls.SetMarkedUnavailable = append(mock.calls.SetMarkedUnavailable, callInfo)
+	mock.lockSetMarkedUnavailable.Unlock()
+	return mock.SetMarkedUnavailableFunc(ctx, categoryRunID, marked)
+}
+
+// SetMarkedUnavailableCalls gets all the calls that were made to SetMarkedUnavailable.
+// Check the length with:
+//
+//	len(mockedScrapeRunRepository.SetMarkedUnavailableCalls())
+func (mock *ScrapeRunRepositoryMock) SetMarkedUnavailableCalls() []struct {
+	Ctx           context.Context
+	CategoryRunID int64
+	Marked        int
+} {
+	var calls []struct {
+		Ctx           context.Context
+		CategoryRunID int64
+		Marked        int
+	}
+	mock.lockSetMarkedUnavailable.RLock()
+	calls = mock.calls.SetMarkedUnavailable
+	mock.lockSetMarkedUnavailable.RUnlock()
+	return calls
+}
+
+// Ensure, that CategoryScheduleRepositoryMock does implement postgres.CategoryScheduleRepository.
+// If this is not the case, regenerate this file with moq.
+var _ postgres.CategoryScheduleRepository = &CategoryScheduleRepositoryMock{}
+
+// CategoryScheduleRepositoryMock is a mock implementation of postgres.CategoryScheduleRepository.
+//
+//	func TestSomethingThatUsesCategoryScheduleRepository(t *testing.T) {
+//
+//		// make and configure a mocked postgres.CategoryScheduleRepository
+//		mockedCategoryScheduleRepository := &CategoryScheduleRepositoryMock{
+//			DeleteFunc: func(ctx context.Context, categoryID uuid.UUID) error {
+//				panic("mock out the Delete method")
+//			},
+//			GetAllFunc: func(ctx context.Context) ([]domain.CategorySchedule, error) {
+//				panic("mock out the GetAll method")
+//			},
+//			UpsertFunc: func(ctx context.Context, schedule *domain.CategorySchedule) error {
+//				panic("mock out the Upsert method")
+//			},
+//		}
+//
+//		// use mockedCategoryScheduleRepository in code that requires postgres.CategoryScheduleRepository
+//		// and then make assertions.
+//
+//	}
+type CategoryScheduleRepositoryMock struct {
+	// DeleteFunc mocks the Delete method.
+	DeleteFunc func(ctx context.Context, categoryID uuid.UUID) error
+
+	// GetAllFunc mocks the GetAll method.
+	GetAllFunc func(ctx context.Context) ([]domain.CategorySchedule, error)
+
+	// UpsertFunc mocks the Upsert method.
+	UpsertFunc func(ctx context.Context, schedule *domain.CategorySchedule) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Delete holds details about calls to the Delete method.
+		Delete []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// CategoryID is the categoryID argument value.
+			CategoryID uuid.UUID
+		}
+		// GetAll holds details about calls to the GetAll method.
+		GetAll []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// Upsert holds details about calls to the Upsert method.
+		Upsert []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Schedule is the schedule argument value.
+			Schedule *domain.CategorySchedule
+		}
+	}
+	lockDelete sync.RWMutex
+	lockGetAll sync.RWMutex
+	lockUpsert sync.RWMutex
+}
+
+// Delete calls DeleteFunc.
+func (mock *CategoryScheduleRepositoryMock) Delete(ctx context.Context, categoryID uuid.UUID) error {
+	if mock.DeleteFunc == nil {
+		panic("CategoryScheduleRepositoryMock.DeleteFunc: method is nil but CategoryScheduleRepository.Delete was just called")
+	}
+	callInfo := struct {
+		Ctx        context.Context
+		CategoryID uuid.UUID
+	}{
+		Ctx:        ctx,
+		CategoryID: categoryID,
+	}
+	mock.lockDelete.Lock()
+	mock.calls.Delete = append(mock.calls.Delete, callInfo)
+	mock.lockDelete.Unlock()
+	return mock.DeleteFunc(ctx, categoryID)
+}
+
+// DeleteCalls gets all the calls that were made to Delete.
+// Check the length with:
+//
+//	len(mockedCategoryScheduleRepository.DeleteCalls())
+func (mock *CategoryScheduleRepositoryMock) DeleteCalls() []struct {
+	Ctx        context.Context
+	CategoryID uuid.UUID
+} {
+	var calls []struct {
+		Ctx        context.Context
+		CategoryID uuid.UUID
+	}
+	mock.lockDelete.RLock()
+	calls = mock.calls.Delete
+	mock.lockDelete.RUnlock()
+	return calls
+}
+
+// GetAll calls GetAllFunc.
+func (mock *CategoryScheduleRepositoryMock) GetAll(ctx context.Context) ([]domain.CategorySchedule, error) {
+	if mock.GetAllFunc == nil {
+		panic("CategoryScheduleRepositoryMock.GetAllFunc: method is nil but CategoryScheduleRepository.GetAll was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockGetAll.Lock()
+	mock.calls.GetAll = append(mock.calls.GetAll, callInfo)
+	mock.lockGetAll.Unlock()
+	return mock.GetAllFunc(ctx)
+}
+
+// GetAllCalls gets all the calls that were made to GetAll.
+// Check the length with:
+//
+//	len(mockedCategoryScheduleRepository.GetAllCalls())
+func (mock *CategoryScheduleRepositoryMock) GetAllCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockGetAll.RLock()
+	calls = mock.calls.GetAll
+	mock.lockGetAll.RUnlock()
+	return calls
+}
+
+// Upsert calls UpsertFunc.
+func (mock *CategoryScheduleRepositoryMock) Upsert(ctx context.Context, schedule *domain.CategorySchedule) error {
+	if mock.UpsertFunc == nil {
+		panic("CategoryScheduleRepositoryMock.UpsertFunc: method is nil but CategoryScheduleRepository.Upsert was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Schedule *domain.CategorySchedule
+	}{
+		Ctx:      ctx,
+		Schedule: schedule,
+	}
+	mock.lockUpsert.Lock()
+	mock.calls.Upsert = append(mock.calls.Upsert, callInfo)
+	mock.lockUpsert.Unlock()
+	return mock.UpsertFunc(ctx, schedule)
+}
+
+// UpsertCalls gets all the calls that were made to Upsert.
+// Check the length with:
+//
+//	len(mockedCategoryScheduleRepository.UpsertCalls())
+func (mock *CategoryScheduleRepositoryMock) UpsertCalls() []struct {
+	Ctx      context.Context
+	Schedule *domain.CategorySchedule
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Schedule *domain.CategorySchedule
+	}
+	mock.lockUpsert.RLock()
+	calls = mock.calls.Upsert
+	mock.lockUpsert.RUnlock()
+	return calls
+}
+
+// Ensure, that ScrapeRequestRepositoryMock does implement postgres.ScrapeRequestRepository.
+// If this is not the case, regenerate this file with moq.
+var _ postgres.ScrapeRequestRepository = &ScrapeRequestRepositoryMock{}
+
+// ScrapeRequestRepositoryMock is a mock implementation of postgres.ScrapeRequestRepository.
+//
+//	func TestSomethingThatUsesScrapeRequestRepository(t *testing.T) {
+//
+//		// make and configure a mocked postgres.ScrapeRequestRepository
+//		mockedScrapeRequestRepository := &ScrapeRequestRepositoryMock{
+//			CancelFunc: func(ctx context.Context, id uuid.UUID) (*domain.ScrapeRequest, error) {
+//				panic("mock out the Cancel method")
+//			},
+//			ClaimPendingFunc: func(ctx context.Context, lease time.Duration) (*domain.ScrapeRequest, error) {
+//				panic("mock out the ClaimPending method")
+//			},
+//			CreateFunc: func(ctx context.Context, req *domain.ScrapeRequest) error {
+//				panic("mock out the Create method")
+//			},
+//			FailExpiredFunc: func(ctx context.Context, reason string) (int64, error) {
+//				panic("mock out the FailExpired method")
+//			},
+//			FinishFunc: func(ctx context.Context, req *domain.ScrapeRequest) error {
+//				panic("mock out the Finish method")
+//			},
+//			GetByIDFunc: func(ctx context.Context, id uuid.UUID) (*domain.ScrapeRequest, error) {
+//				panic("mock out the GetByID method")
+//			},
+//			HeartbeatFunc: func(ctx context.Context, id uuid.UUID, lease time.Duration) (bool, error) {
+//				panic("mock out the Heartbeat method")
+//			},
+//			SetRunFunc: func(ctx context.Context, id uuid.UUID, runID uuid.UUID) error {
+//				panic("mock out the SetRun method")
+//			},
+//		}
+//
+//		// use mockedScrapeRequestRepository in code that requires postgres.ScrapeRequestRepository
+//		// and then make assertions.
+//
+//	}
+type ScrapeRequestRepositoryMock struct {
+	// CancelFunc mocks the Cancel method.
+	CancelFunc func(ctx context.Context, id uuid.UUID) (*domain.ScrapeRequest, error)
+
+	// ClaimPendingFunc mocks the ClaimPending method.
+	ClaimPendingFunc func(ctx context.Context, lease time.Duration) (*domain.ScrapeRequest, error)
+
+	// CreateFunc mocks the Create method.
+	CreateFunc func(ctx context.Context, req *domain.ScrapeRequest) error
+
+	// FailExpiredFunc mocks the FailExpired method.
+	FailExpiredFunc func(ctx context.Context, reason string) (int64, error)
+
+	// FinishFunc mocks the Finish method.
+	FinishFunc func(ctx context.Context, req *domain.ScrapeRequest) error
+
+	// GetByIDFunc mocks the GetByID method.
+	GetByIDFunc func(ctx context.Context, id uuid.UUID) (*domain.ScrapeRequest, error)
+
+	// HeartbeatFunc mocks the Heartbeat method.
+	HeartbeatFunc func(ctx context.Context, id uuid.UUID, lease time.Duration) (bool, error)
+
+	// SetRunFunc mocks the SetRun method.
+	SetRunFunc func(ctx context.Context, id uuid.UUID, runID uuid.UUID) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Cancel holds details about calls to the Cancel method.
+		Cancel []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID uuid.UUID
+		}
+		// ClaimPending holds details about calls to the ClaimPending method.
+		ClaimPending []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Lease is the lease argument value.
+			Lease time.Duration
+		}
+		// Create holds details about calls to the Create method.
+		Create []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Req is the req argument value.
+			Req *domain.ScrapeRequest
+		}
+		// FailExpired holds details about calls to the FailExpired method.
+		FailExpired []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Reason is the reason argument value.
+			Reason string
+		}
+		// Finish holds details about calls to the Finish method.
+		Finish []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Req is the req argument value.
+			Req *domain.ScrapeRequest
+		}
+		// GetByID holds details about calls to the GetByID method.
+		GetByID []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID uuid.UUID
+		}
+		// Heartbeat holds details about calls to the Heartbeat method.
+		Heartbeat []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID uuid.UUID
+			// Lease is the lease argument value.
+			Lease time.Duration
+		}
+		// SetRun holds details about calls to the SetRun method.
+		SetRun []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID uuid.UUID
+			// RunID is the runID argument value.
+			RunID uuid.UUID
+		}
+	}
+	lockCancel       sync.RWMutex
+	lockClaimPending sync.RWMutex
+	lockCreate       sync.RWMutex
+	lockFailExpired  sync.RWMutex
+	lockFinish       sync.RWMutex
+	lockGetByID      sync.RWMutex
+	lockHeartbeat    sync.RWMutex
+	lockSetRun       sync.RWMutex
+}
+
+// Cancel calls CancelFunc.
+func (mock *ScrapeRequestRepositoryMock) Cancel(ctx context.Context, id uuid.UUID) (*domain.ScrapeRequest, error) {
+	if mock.CancelFunc == nil {
+		panic("ScrapeRequestRepositoryMock.CancelFunc: method is nil but ScrapeRequestRepository.Cancel was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  uuid.UUID
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockCancel.Lock()
+	mock.calls.Cancel = append(mock.calls.Cancel, callInfo)
+	mock.lockCancel.Unlock()
+	return mock.CancelFunc(ctx, id)
+}
+
+// CancelCalls gets all the calls that were made to Cancel.
+// Check the length with:
+//
+//	len(mockedScrapeRequestRepository.CancelCalls())
+func (mock *ScrapeRequestRepositoryMock) CancelCalls() []struct {
+	Ctx context.Context
+	ID  uuid.UUID
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  uuid.UUID
+	}
+	mock.lockCancel.RLock()
+	calls = mock.calls.Cancel
+	mock.lockCancel.RUnlock()
+	return calls
+}
+
+// ClaimPending calls ClaimPendingFunc.
+func (mock *ScrapeRequestRepositoryMock) ClaimPending(ctx context.Context, lease time.Duration) (*domain.ScrapeRequest, error) {
+	if mock.ClaimPendingFunc == nil {
+		panic("ScrapeRequestRepositoryMock.ClaimPendingFunc: method is nil but ScrapeRequestRepository.ClaimPending was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Lease time.Duration
+	}{
+		Ctx:   ctx,
+		Lease: lease,
+	}
+	mock.lockClaimPending.Lock()
+	mock.calls.ClaimPending = append(mock.calls.ClaimPending, callInfo)
+	mock.lockClaimPending.Unlock()
+	return mock.ClaimPendingFunc(ctx, lease)
+}
+
+// ClaimPendingCalls gets all the calls that were made to ClaimPending.
+// Check the length with:
+//
+//	len(mockedScrapeRequestRepository.ClaimPendingCalls())
+func (mock *ScrapeRequestRepositoryMock) ClaimPendingCalls() []struct {
+	Ctx   context.Context
+	Lease time.Duration
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Lease time.Duration
+	}
+	mock.lockClaimPending.RLock()
+	calls = mock.calls.ClaimPending
+	mock.lockClaimPending.RUnlock()
+	return calls
+}
+
+// Create calls CreateFunc.
+func (mock *ScrapeRequestRepositoryMock) Create(ctx context.Context, req *domain.ScrapeRequest) error {
+	if mock.CreateFunc == nil {
+		panic("ScrapeRequestRepositoryMock.CreateFunc: method is nil but ScrapeRequestRepository.Create was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Req *domain.ScrapeRequest
+	}{
+		Ctx: ctx,
+		Req: req,
+	}
+	mock.lockCreate.Lock()
+	mock.calls.Create = append(mock.calls.Create, callInfo)
+	mock.lockCreate.Unlock()
+	return mock.CreateFunc(ctx, req)
+}
+
+// CreateCalls gets all the calls that were made to Create.
+// Check the length with:
+//
+//	len(mockedScrapeRequestRepository.CreateCalls())
+func (mock *ScrapeRequestRepositoryMock) CreateCalls() []struct {
+	Ctx context.Context
+	Req *domain.ScrapeRequest
+} {
+	var calls []struct {
+		Ctx context.Context
+		Req *domain.ScrapeRequest
+	}
+	mock.lockCreate.RLock()
+	calls = mock.calls.Create
+	mock.lockCreate.RUnlock()
+	return calls
+}
+
+// FailExpired calls FailExpiredFunc.
+func (mock *ScrapeRequestRepositoryMock) FailExpired(ctx context.Context, reason string) (int64, error) {
+	if mock.FailExpiredFunc == nil {
+		panic("ScrapeRequestRepositoryMock.FailExpiredFunc: method is nil but ScrapeRequestRepository.FailExpired was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Reason string
+	}{
+		Ctx:    ctx,
+		Reason: reason,
+	}
+	mock.lockFailExpired.Lock()
+	mock.calls.FailExpired = append(mock.calls.FailExpired, callInfo)
+	mock.lockFailExpired.Unlock()
+	return mock.FailExpiredFunc(ctx, reason)
+}
+
+// FailExpiredCalls gets all the calls that were made to FailExpired.
+// Check the length with:
+//
+//	len(mockedScrapeRequestRepository.FailExpiredCalls())
+func (mock *ScrapeRequestRepositoryMock) FailExpiredCalls() []struct {
+	Ctx    context.Context
+	Reason string
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Reason string
+	}
+	mock.lockFailExpired.RLock()
+	calls = mock.calls.FailExpired
+	mock.lockFailExpired.RUnlock()
+	return calls
+}
+
+// Finish calls FinishFunc.
+func (mock *ScrapeRequestRepositoryMock) Finish(ctx context.Context, req *domain.ScrapeRequest) error {
+	if mock.FinishFunc == nil {
+		panic("ScrapeRequestRepositoryMock.FinishFunc: method is nil but ScrapeRequestRepository.Finish was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Req *domain.ScrapeRequest
+	}{
+		Ctx: ctx,
+		Req: req,
+	}
+	mock.lockFinish.Lock()
+	mock.calls.Finish = append(mock.calls.Finish, callInfo)
+	mock.lockFinish.Unlock()
+	return mock.FinishFunc(ctx, req)
+}
+
+// FinishCalls gets all the calls that were made to Finish.
+// Check the length with:
+//
+//	len(mockedScrapeRequestRepository.FinishCalls())
+func (mock *ScrapeRequestRepositoryMock) FinishCalls() []struct {
+	Ctx context.Context
+	Req *domain.ScrapeRequest
+} {
+	var calls []struct {
+		Ctx context.Context
+		Req *domain.ScrapeRequest
+	}
+	mock.lockFinish.RLock()
+	calls = mock.calls.Finish
+	mock.lockFinish.RUnlock()
+	return calls
+}
+
+// GetByID calls GetByIDFunc.
+func (mock *ScrapeRequestRepositoryMock) GetByID(ctx context.Context, id uuid.UUID) (*domain.ScrapeRequest, error) {
+	if mock.GetByIDFunc == nil {
+		panic("ScrapeRequestRepositoryMock.GetByIDFunc: method is nil but ScrapeRequestRepository.GetByID was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  uuid.UUID
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockGetByID.Lock()
+	mock.calls.GetByID = append(mock.calls.GetByID, callInfo)
+	mock.lockGetByID.Unlock()
+	return mock.GetByIDFunc(ctx, id)
+}
+
+// GetByIDCalls gets all the calls that were made to GetByID.
+// Check the length with:
+//
+//	len(mockedScrapeRequestRepository.GetByIDCalls())
+func (mock *ScrapeRequestRepositoryMock) GetByIDCalls() []struct {
+	Ctx context.Context
+	ID  uuid.UUID
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  uuid.UUID
+	}
+	mock.lockGetByID.RLock()
+	calls = mock.calls.GetByID
+	mock.lockGetByID.RUnlock()
+	return calls
+}
+
+// Heartbeat calls HeartbeatFunc.
+func (mock *ScrapeRequestRepositoryMock) Heartbeat(ctx context.Context, id uuid.UUID, lease time.Duration) (bool, error) {
+	if mock.HeartbeatFunc == nil {
+		panic("ScrapeRequestRepositoryMock.HeartbeatFunc: method is nil but ScrapeRequestRepository.Heartbeat was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		ID    uuid.UUID
+		Lease time.Duration
+	}{
+		Ctx:   ctx,
+		ID:    id,
+		Lease: lease,
+	}
+	mock.lockHeartbeat.Lock()
+	mock.calls.Heartbeat = append(mock.calls.Heartbeat, callInfo)
+	mock.lockHeartbeat.Unlock()
+	return mock.HeartbeatFunc(ctx, id, lease)
+}
+
+// HeartbeatCalls gets all the calls that were made to Heartbeat.
+// Check the length with:
+//
+//	len(mockedScrapeRequestRepository.HeartbeatCalls())
+func (mock *ScrapeRequestRepositoryMock) HeartbeatCalls() []struct {
+	Ctx   context.Context
+	ID    uuid.UUID
+	Lease time.Duration
+} {
+	var calls []struct {
+		Ctx   context.Context
+		ID    uuid.UUID
+		Lease time.Duration
+	}
+	mock.lockHeartbeat.RLock()
+	calls = mock.calls.Heartbeat
+	mock.lockHeartbeat.RUnlock()
+	return calls
+}
+
+// SetRun calls SetRunFunc.
+func (mock *ScrapeRequestRepositoryMock) SetRun(ctx context.Context, id uuid.UUID, runID uuid.UUID) error {
+	if mock.SetRunFunc == nil {
+		panic("ScrapeRequestRepositoryMock.SetRunFunc: method is nil but ScrapeRequestRepository.SetRun was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		ID    uuid.UUID
+		RunID uuid.UUID
+	}{
+		Ctx:   ctx,
+		ID:    id,
+		RunID: runID,
+	}
+	mock.lockSetRun.Lock()
+	mock.calls.SetRun = append(mock.calls.SetRun, callInfo)
+	mock.lockSetRun.Unlock()
+	return mock.SetRunFunc(ctx, id, runID)
+}
+
+// SetRunCalls gets all the calls that were made to SetRun.
+// Check the length with:
+//
+//	len(mockedScrapeRequestRepository.SetRunCalls())
+func (mock *ScrapeRequestRepositoryMock) SetRunCalls() []struct {
+	Ctx   context.Context
+	ID    uuid.UUID
+	RunID uuid.UUID
+} {
+	var calls []struct {
+		Ctx   context.Context
+		ID    uuid.UUID
+		RunID uuid.UUID
+	}
+	mock.lockSetRun.RLock()
+	calls = mock.calls.SetRun
+	mock.lockSetRun.RUnlock()
+	return calls
+}
+
+// Ensure, that ProductGroupRepositoryMock does implement postgres.ProductGroupRepository.
+// If this is not the case, regenerate this file with moq.
+var _ postgres.ProductGroupRepository = &ProductGroupRepositoryMock{}
+
+// ProductGroupRepositoryMock is a mock implementation of postgres.ProductGroupRepository.
+//
+//	func TestSomethingThatUsesProductGroupRepository(t *testing.T) {
+//
+//		// make and configure a mocked postgres.ProductGroupRepository
+//		mockedProductGroupRepository := &ProductGroupRepositoryMock{
+//			AssignFunc: func(ctx context.Context, groups []domain.ProductGroupAssignment) error {
+//				panic("mock out the Assign method")
+//			},
+//			GetByIDFunc: func(ctx context.Context, id uuid.UUID) (*domain.ProductGroup, error) {
+//				panic("mock out the GetByID method")
+//			},
+//			GetMembersFunc: func(ctx context.Context) ([]domain.ProductGroupMember, error) {
+//				panic("mock out the GetMembers method")
+//			},
+//		}
+//
+//		// use mockedProductGroupRepository in code that requires postgres.ProductGroupRepository
+//		// and then make assertions.
+//
+//	}
+type ProductGroupRepositoryMock struct {
+	// AssignFunc mocks the Assign method.
+	AssignFunc func(ctx context.Context, groups []domain.ProductGroupAssignment) error
+
+	// GetByIDFunc mocks the GetByID method.
+	GetByIDFunc func(ctx context.Context, id uuid.UUID) (*domain.ProductGroup, error)
+
+	// GetMembersFunc mocks the GetMembers method.
+	GetMembersFunc func(ctx context.Context) ([]domain.ProductGroupMember, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Assign holds details about calls to the Assign method.
+		Assign []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Groups is the groups argument value.
+			Groups []domain.ProductGroupAssignment
+		}
+		// GetByID holds details about calls to the GetByID method.
+		GetByID []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID uuid.UUID
+		}
+		// GetMembers holds details about calls to the GetMembers method.
+		GetMembers []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+	}
+	lockAssign     sync.RWMutex
+	lockGetByID    sync.RWMutex
+	lockGetMembers sync.RWMutex
+}
+
+// Assign calls AssignFunc.
+func (mock *ProductGroupRepositoryMock) Assign(ctx context.Context, groups []domain.ProductGroupAssignment) error {
+	if mock.AssignFunc == nil {
+		panic("ProductGroupRepositoryMock.AssignFunc: method is nil but ProductGroupRepository.Assign was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Groups []domain.ProductGroupAssignment
+	}{
+		Ctx:    ctx,
+		Groups: groups,
+	}
+	mock.lockAssign.Lock()
+	mock.calls.Assign = append(mock.calls.Assign, callInfo)
+	mock.lockAssign.Unlock()
+	return mock.AssignFunc(ctx, groups)
+}
+
+// AssignCalls gets all the calls that were made to Assign.
+// Check the length with:
+//
+//	len(mockedProductGroupRepository.AssignCalls())
+func (mock *ProductGroupRepositoryMock) AssignCalls() []struct {
+	Ctx    context.Context
+	Groups []domain.ProductGroupAssignment
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Groups []domain.ProductGroupAssignment
+	}
+	mock.lockAssign.RLock()
+	calls = mock.calls.Assign
+	mock.lockAssign.RUnlock()
+	return calls
+}
+
+// GetByID calls GetByIDFunc.
+func (mock *ProductGroupRepositoryMock) GetByID(ctx context.Context, id uuid.UUID) (*domain.ProductGroup, error) {
+	if mock.GetByIDFunc == nil {
+		panic("ProductGroupRepositoryMock.GetByIDFunc: method is nil but ProductGroupRepository.GetByID was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  uuid.UUID
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockGetByID.Lock()
+	mock.calls.GetByID = append(mock.calls.GetByID, callInfo)
+	mock.lockGetByID.Unlock()
+	return mock.GetByIDFunc(ctx, id)
+}
+
+// GetByIDCalls gets all the calls that were made to GetByID.
+// Check the length with:
+//
+//	len(mockedProductGroupRepository.GetByIDCalls())
+func (mock *ProductGroupRepositoryMock) GetByIDCalls() []struct {
+	Ctx context.Context
+	ID  uuid.UUID
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  uuid.UUID
+	}
+	mock.lockGetByID.RLock()
+	calls = mock.calls.GetByID
+	mock.lockGetByID.RUnlock()
+	return calls
+}
+
+// GetMembers calls GetMembersFunc.
+func (mock *ProductGroupRepositoryMock) GetMembers(ctx context.Context) ([]domain.ProductGroupMember, error) {
+	if mock.GetMembersFunc == nil {
+		panic("ProductGroupRepositoryMock.GetMembersFunc: method is nil but ProductGroupRepository.GetMembers was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockGetMembers.Lock()
+	mock.calls.GetMembers = append(mock.calls.GetMembers, callInfo)
+	mock.lockGetMembers.Unlock()
+	return mock.GetMembersFunc(ctx)
+}
+
+// GetMembersCalls gets all the calls that were made to GetMembers.
+// Check the length with:
+//
+//	len(mockedProductGroupRepository.GetMembersCalls())
+func (mock *ProductGroupRepositoryMock) GetMembersCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockGetMembers.RLock()
+	calls = mock.calls.GetMembers
+	mock.lockGetMembers.RUnlock()
+	return calls
+}
+
+// Ensure, that ProductMatchRepositoryMock does implement postgres.ProductMatchRepository.
+// If this is not the case, regenerate this file with moq.
+var _ postgres.ProductMatchRepository = &ProductMatchRepositoryMock{}
+
+// ProductMatchRepositoryMock is a mock implementation of postgres.ProductMatchRepository.
+//
+//	func TestSomethingThatUsesProductMatchRepository(t *testing.T) {
+//
+//		// make and configure a mocked postgres.ProductMatchRepository
+//		mockedProductMatchRepository := &ProductMatchRepositoryMock{
+//			GetCandidatesFunc: func(ctx context.Context) ([]domain.MatchCandidate, error) {
+//				panic("mock out the GetCandidates method")
+//			},
+//			GetDecidedFunc: func(ctx context.Context) ([]domain.ProductMatch, error) {
+//				panic("mock out the GetDecided method")
+//			},
+//			GetListFunc: func(ctx context.Context, filter domain.ProductMatchFilter) (*domain.ProductMatchList, error) {
+//				panic("mock out the GetList method")
+//			},
+//			GetOffersFunc: func(ctx context.Context, productID uuid.UUID) ([]domain.ProductOffer, error) {
+//				panic("mock out the GetOffers method")
+//			},
+//			ReplaceSuggestionsFunc: func(ctx context.Context, matches []domain.ProductMatch) error {
+//				panic("mock out the ReplaceSuggestions method")
+//			},
+//			SetStatusFunc: func(ctx context.Context, id uuid.UUID, status string) (*domain.ProductMatch, error) {
+//				panic("mock out the SetStatus method")
+//			},
+//		}
+//
+//		// use mockedProductMatchRepository in code that requires postgres.ProductMatchRepository
+//		// and then make assertions.
+//
+//	}
+type ProductMatchRepositoryMock struct {
+	// GetCandidatesFunc mocks the GetCandidates method.
+	GetCandidatesFunc func(ctx context.Context) ([]domain.MatchCandidate, error)
+
+	// GetDecidedFunc mocks the GetDecided method.
+	GetDecidedFunc func(ctx context.Context) ([]domain.ProductMatch, error)
+
+	// GetListFunc mocks the GetList method.
+	GetListFunc func(ctx context.Context, filter domain.ProductMatchFilter) (*domain.ProductMatchList, error)
+
+	// GetOffersFunc mocks the GetOffers method.
+	GetOffersFunc func(ctx context.Context, productID uuid.UUID) ([]domain.ProductOffer, error)
+
+	// ReplaceSuggestionsFunc mocks the ReplaceSuggestions method.
+	ReplaceSuggestionsFunc func(ctx context.Context, matches []domain.ProductMatch) error
+
+	// SetStatusFunc mocks the SetStatus method.
+	SetStatusFunc func(ctx context.Context, id uuid.UUID, status string) (*domain.ProductMatch, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// GetCandidates holds details about calls to the GetCandidates method.
+		GetCandidates []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// GetDecided holds details about calls to the GetDecided method.
+		GetDecided []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// GetList holds details about calls to the GetList method.
+		GetList []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Filter is the filter argument value.
+			Filter domain.ProductMatchFilter
+		}
+		// GetOffers holds details about calls to the GetOffers method.
+		GetOffers []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ProductID is the productID argument value.
+			ProductID uuid.UUID
+		}
+		// ReplaceSuggestions holds details about calls to the ReplaceSuggestions method.
+		ReplaceSuggestions []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Matches is the matches argument value.
+			Matches []domain.ProductMatch
+		}
+		// SetStatus holds details about calls to the SetStatus method.
+		SetStatus []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID uuid.UUID
+			// Status is the status argument value.
+			Status string
+		}
+	}
+	lockGetCandidates      sync.RWMutex
+	lockGetDecided         sync.RWMutex
+	lockGetList            sync.RWMutex
+	lockGetOffers          sync.RWMutex
+	lockReplaceSuggestions sync.RWMutex
+	lockSetStatus          sync.RWMutex
+}
+
+// GetCandidates calls GetCandidatesFunc.
+func (mock *ProductMatchRepositoryMock) GetCandidates(ctx context.Context) ([]domain.MatchCandidate, error) {
+	if mock.GetCandidatesFunc == nil {
+		panic("ProductMatchRepositoryMock.GetCandidatesFunc: method is nil but ProductMatchRepository.GetCandidates was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockGetCandidates.Lock()
+	mock.calls.GetCandidates = append(mock.calls.GetCandidates, callInfo)
+	mock.lockGetCandidates.Unlock()
+	return mock.GetCandidatesFunc(ctx)
+}
+
+// GetCandidatesCalls gets all the calls that were made to GetCandidates.
+// Check the length with:
+//
+//	len(mockedProductMatchRepository.GetCandidatesCalls())
+func (mock *ProductMatchRepositoryMock) GetCandidatesCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockGetCandidates.RLock()
+	calls = mock.calls.GetCandidates
+	mock.lockGetCandidates.RUnlock()
+	return calls
+}
+
+// GetDecided calls GetDecidedFunc.
+func (mock *ProductMatchRepositoryMock) GetDecided(ctx context.Context) ([]domain.ProductMatch, error) {
+	if mock.GetDecidedFunc == nil {
+		panic("ProductMatchRepositoryMock.GetDecidedFunc: method is nil but ProductMatchRepository.GetDecided was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockGetDecided.Lock()
+	mock.calls.GetDecided = append(mock.calls.GetDecided, callInfo)
+	mock.lockGetDecided.Unlock()
+	return mock.GetDecidedFunc(ctx)
+}
+
+// GetDecidedCalls gets all the calls that were made to GetDecided.
+// Check the length with:
+//
+//	len(mockedProductMatchRepository.GetDecidedCalls())
+func (mock *ProductMatchRepositoryMock) GetDecidedCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockGetDecided.RLock()
+	calls = mock.calls.GetDecided
+	mock.lockGetDecided.RUnlock()
+	return calls
+}
+
+// GetList calls GetListFunc.
+func (mock *ProductMatchRepositoryMock) GetList(ctx context.Context, filter domain.ProductMatchFilter) (*domain.ProductMatchList, error) {
+	if mock.GetListFunc == nil {
+		panic("ProductMatchRepositoryMock.GetListFunc: method is nil but ProductMatchRepository.GetList was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Filter domain.ProductMatchFilter
+	}{
+		Ctx:    ctx,
+		Filter: filter,
+	}
+	mock.lockGetList.Lock()
+	mock.calls.GetList = append(mock.calls.GetList, callInfo)
+	mock.lockGetList.Unlock()
+	return mock.GetListFunc(ctx, filter)
+}
+
+// GetListCalls gets all the calls that were made to GetList.
+// Check the length with:
+//
+//	len(mockedProductMatchRepository.GetListCalls())
+func (mock *ProductMatchRepositoryMock) GetListCalls() []struct {
+	Ctx    context.Context
+	Filter domain.ProductMatchFilter
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Filter domain.ProductMatchFilter
+	}
+	mock.lockGetList.RLock()
+	calls = mock.calls.GetList
+	mock.lockGetList.RUnlock()
+	return calls
+}
+
+// GetOffers calls GetOffersFunc.
+func (mock *ProductMatchRepositoryMock) GetOffers(ctx context.Context, productID uuid.UUID) ([]domain.ProductOffer, error) {
+	if mock.GetOffersFunc == nil {
+		panic("ProductMatchRepositoryMock.GetOffersFunc: method is nil but ProductMatchRepository.GetOffers was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		ProductID uuid.UUID
+	}{
+		Ctx:       ctx,
+		ProductID: productID,
+	}
+	mock.lockGetOffers.Lock()
+	mock.calls.GetOffers = append(mock.calls.GetOffers, callInfo)
+	mock.lockGetOffers.Unlock()
+	return mock.GetOffersFunc(ctx, productID)
+}
+
+// GetOffersCalls gets all the calls that were made to GetOffers.
+// Check the length with:
+//
+//	len(mockedProductMatchRepository.GetOffersCalls())
+func (mock *ProductMatchRepositoryMock) GetOffersCalls() []struct {
+	Ctx       context.Context
+	ProductID uuid.UUID
+} {
+	var calls []struct {
+		Ctx       context.Context
+		ProductID uuid.UUID
+	}
+	mock.lockGetOffers.RLock()
+	calls = mock.calls.GetOffers
+	mock.lockGetOffers.RUnlock()
+	return calls
+}
+
+// ReplaceSuggestions calls ReplaceSuggestionsFunc.
+func (mock *ProductMatchRepositoryMock) ReplaceSuggestions(ctx context.Context, matches []domain.ProductMatch) error {
+	if mock.ReplaceSuggestionsFunc == nil {
+		panic("ProductMatchRepositoryMock.ReplaceSuggestionsFunc: method is nil but ProductMatchRepository.ReplaceSuggestions was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		Matches []domain.ProductMatch
+	}{
+		Ctx:     ctx,
+		Matches: matches,
+	}
+	mock.lockReplaceSuggestions.Lock()
+	mock.calls.ReplaceSuggestions = append(mock.calls.ReplaceSuggestions, callInfo)
+	mock.lockReplaceSuggestions.Unlock()
+	return mock.ReplaceSuggestionsFunc(ctx, matches)
+}
+
+// ReplaceSuggestionsCalls gets all the calls that were made to ReplaceSuggestions.
+// Check the length with:
+//
+//	len(mockedProductMatchRepository.ReplaceSuggestionsCalls())
+func (mock *ProductMatchRepositoryMock) ReplaceSuggestionsCalls() []struct {
+	Ctx     context.Context
+	Matches []domain.ProductMatch
+} {
+	var calls []struct {
+		Ctx     context.Context
+		Matches []domain.ProductMatch
+	}
+	mock.lockReplaceSuggestions.RLock()
+	calls = mock.calls.ReplaceSuggestions
+	mock.lockReplaceSuggestions.RUnlock()
+	return calls
+}
+
+// SetStatus calls SetStatusFunc.
+func (mock *ProductMatchRepositoryMock) SetStatus(ctx context.Context, id uuid.UUID, status string) (*domain.ProductMatch, error) {
+	if mock.SetStatusFunc == nil {
+		panic("ProductMatchRepositoryMock.SetStatusFunc: method is nil but ProductMatchRepository.SetStatus was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		ID     uuid.UUID
+		Status string
+	}{
+		Ctx:    ctx,
+		ID:     id,
+		Status: status,
+	}
+	mock.lockSetStatus.Lock()
+	mock.calls.SetStatus = append(mock.calls.SetStatus, callInfo)
+	mock.lockSetStatus.Unlock()
+	return mock.SetStatusFunc(ctx, id, status)
+}
+
+// SetStatusCalls gets all the calls that were made to SetStatus.
+// Check the length with:
+//
+//	len(mockedProductMatchRepository.SetStatusCalls())
+func (mock *ProductMatchRepositoryMock) SetStatusCalls() []struct {
+	Ctx    context.Context
+	ID     uuid.UUID
+	Status string
+} {
+	var calls []struct {
+		Ctx    context.Context
+		ID     uuid.UUID
+		Status string
+	}
+	mock.lockSetStatus.RLock()
+	calls = mock.calls.SetStatus
+	mock.lockSetStatus.RUnlock()
+	return calls
+}
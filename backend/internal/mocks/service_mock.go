@@ -30,6 +30,12 @@ var _ service.ProductService = &ProductServiceMock{}
 //			GetByIDFunc: func(ctx context.Context, id uuid.UUID) (*domain.Product, error) {
 //				panic("mock out the GetByID method")
 //			},
+//			GetFacetsFunc: func(ctx context.Context, filter domain.ProductFilter, priceBuckets int) (*domain.ProductFacets, error) {
+//				panic("mock out the GetFacets method")
+//			},
+//			GetPriceHistoryFunc: func(ctx context.Context, filter domain.PriceHistoryFilter) (*domain.PriceHistory, error) {
+//				panic("mock out the GetPriceHistory method")
+//			},
 //		}
 //
 //		// use mockedProductService in code that requires service.ProductService
@@ -46,6 +52,12 @@ type ProductServiceMock struct {
 	// GetByIDFunc mocks the GetByID method.
 	GetByIDFunc func(ctx context.Context, id uuid.UUID) (*domain.Product, error)
 
+	// GetFacetsFunc mocks the GetFacets method.
+	GetFacetsFunc func(ctx context.Context, filter domain.ProductFilter, priceBuckets int) (*domain.ProductFacets, error)
+
+	// GetPriceHistoryFunc mocks the GetPriceHistory method.
+	GetPriceHistoryFunc func(ctx context.Context, filter domain.PriceHistoryFilter) (*domain.PriceHistory, error)
+
 	// calls tracks calls to the methods.
 	calls struct {
 		// GetBrands holds details about calls to the GetBrands method.
@@ -67,10 +79,28 @@ type ProductServiceMock struct {
 			// ID is the id argument value.
 			ID uuid.UUID
 		}
+		// GetFacets holds details about calls to the GetFacets method.
+		GetFacets []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Filter is the filter argument value.
+			Filter domain.ProductFilter
+			// PriceBuckets is the priceBuckets argument value.
+			PriceBuckets int
+		}
+		// GetPriceHistory holds details about calls to the GetPriceHistory method.
+		GetPriceHistory []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Filter is the filter argument value.
+			Filter domain.PriceHistoryFilter
+		}
 	}
-	lockGetBrands   sync.RWMutex
-	lockGetByFilter sync.RWMutex
-	lockGetByID     sync.RWMutex
+	lockGetBrands       sync.RWMutex
+	lockGetByFilter     sync.RWMutex
+	lockGetByID         sync.RWMutex
+	lockGetFacets       sync.RWMutex
+	lockGetPriceHistory sync.RWMutex
 }
 
 // GetBrands calls GetBrandsFunc.
@@ -177,6 +207,82 @@ func (mock *ProductServiceMock) GetByIDCalls() []struct {
 	return calls
 }
 
+// GetFacets calls GetFacetsFunc.
+func (mock *ProductServiceMock) GetFacets(ctx context.Context, filter domain.ProductFilter, priceBuckets int) (*domain.ProductFacets, error) {
+	if mock.GetFacetsFunc == nil {
+		panic("ProductServiceMock.GetFacetsFunc: method is nil but ProductService.GetFacets was just called")
+	}
+	callInfo := struct {
+		Ctx          context.Context
+		Filter       domain.ProductFilter
+		PriceBuckets int
+	}{
+		Ctx:          ctx,
+		Filter:       filter,
+		PriceBuckets: priceBuckets,
+	}
+	mock.lockGetFacets.Lock()
+	mock.calls.GetFacets = append(mock.calls.GetFacets, callInfo)
+	mock.lockGetFacets.Unlock()
+	return mock.GetFacetsFunc(ctx, filter, priceBuckets)
+}
+
+// GetFacetsCalls gets all the calls that were made to GetFacets.
+// Check the length with:
+//
+//	len(mockedProductService.GetFacetsCalls())
+func (mock *ProductServiceMock) GetFacetsCalls() []struct {
+	Ctx          context.Context
+	Filter       domain.ProductFilter
+	PriceBuckets int
+} {
+	var calls []struct {
+		Ctx          context.Context
+		Filter       domain.ProductFilter
+		PriceBuckets int
+	}
+	mock.lockGetFacets.RLock()
+	calls = mock.calls.GetFacets
+	mock.lockGetFacets.RUnlock()
+	return calls
+}
+
+// GetPriceHistory calls GetPriceHistoryFunc.
+func (mock *ProductServiceMock) GetPriceHistory(ctx context.Context, filter domain.PriceHistoryFilter) (*domain.PriceHistory, error) {
+	if mock.GetPriceHistoryFunc == nil {
+		panic("ProductServiceMock.GetPriceHistoryFunc: method is nil but ProductService.GetPriceHistory was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Filter domain.PriceHistoryFilter
+	}{
+		Ctx:    ctx,
+		Filter: filter,
+	}
+	mock.lockGetPriceHistory.Lock()
+	mock.calls.GetPriceHistory = append(mock.calls.GetPriceHistory, callInfo)
+	mock.lockGetPriceHistory.Unlock()
+	return mock.GetPriceHistoryFunc(ctx, filter)
+}
+
+// GetPriceHistoryCalls gets all the calls that were made to GetPriceHistory.
+// Check the length with:
+//
+//	len(mockedProductService.GetPriceHistoryCalls())
+func (mock *ProductServiceMock) GetPriceHistoryCalls() []struct {
+	Ctx    context.Context
+	Filter domain.PriceHistoryFilter
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Filter domain.PriceHistoryFilter
+	}
+	mock.lockGetPriceHistory.RLock()
+	calls = mock.calls.GetPriceHistory
+	mock.lockGetPriceHistory.RUnlock()
+	return calls
+}
+
 // Ensure, that CategoryServiceMock does implement service.CategoryService.
 // If this is not the case, regenerate this file with moq.
 var _ service.CategoryService = &CategoryServiceMock{}
@@ -193,6 +299,9 @@ var _ service.CategoryService = &CategoryServiceMock{}
 //			GetByIDFunc: func(ctx context.Context, id uuid.UUID) (*domain.Category, error) {
 //				panic("mock out the GetByID method")
 //			},
+//			GetTreeFunc: func(ctx context.Context) ([]domain.CategoryNode, error) {
+//				panic("mock out the GetTree method")
+//			},
 //		}
 //
 //		// use mockedCategoryService in code that requires service.CategoryService
@@ -206,6 +315,9 @@ type CategoryServiceMock struct {
 	// GetByIDFunc mocks the GetByID method.
 	GetByIDFunc func(ctx context.Context, id uuid.UUID) (*domain.Category, error)
 
+	// GetTreeFunc mocks the GetTree method.
+	GetTreeFunc func(ctx context.Context) ([]domain.CategoryNode, error)
+
 	// calls tracks calls to the methods.
 	calls struct {
 		// GetAll holds details about calls to the GetAll method.
@@ -220,9 +332,15 @@ type CategoryServiceMock struct {
 			// ID is the id argument value.
 			ID uuid.UUID
 		}
+		// GetTree holds details about calls to the GetTree method.
+		GetTree []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
 	}
 	lockGetAll  sync.RWMutex
 	lockGetByID sync.RWMutex
+	lockGetTree sync.RWMutex
 }
 
 // GetAll calls GetAllFunc.
@@ -292,3 +410,1145 @@ func (mock *CategoryServiceMock) GetByIDCalls() []struct {
 	mock.lockGetByID.RUnlock()
 	return calls
 }
+
+// GetTree calls GetTreeFunc.
+func (mock *CategoryServiceMock) GetTree(ctx context.Context) ([]domain.CategoryNode, error) {
+	if mock.GetTreeFunc == nil {
+		panic("CategoryServiceMock.GetTreeFunc: method is nil but CategoryService.GetTree was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockGetTree.Lock()
+	mock.calls.GetTree = append(mock.calls.GetTree, callInfo)
+	mock.lockGetTree.Unlock()
+	return mock.GetTreeFunc(ctx)
+}
+
+// GetTreeCalls gets all the calls that were made to GetTree.
+// Check the length with:
+//
+//	len(mockedCategoryService.GetTreeCalls())
+func (mock *CategoryServiceMock) GetTreeCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockGetTree.RLock()
+	calls = mock.calls.GetTree
+	mock.lockGetTree.RUnlock()
+	return calls
+}
+
+// Ensure, that PricingRuleServiceMock does implement service.PricingRuleService.
+// If this is not the case, regenerate this file with moq.
+var _ service.PricingRuleService = &PricingRuleServiceMock{}
+
+// PricingRuleServiceMock is a mock implementation of service.PricingRuleService.
+//
+//	func TestSomethingThatUsesPricingRuleService(t *testing.T) {
+//
+//		// make and configure a mocked service.PricingRuleService
+//		mockedPricingRuleService := &PricingRuleServiceMock{
+//			CreateFunc: func(ctx context.Context, rule *domain.PricingRule) error {
+//				panic("mock out the Create method")
+//			},
+//			DeleteFunc: func(ctx context.Context, id uuid.UUID) error {
+//				panic("mock out the Delete method")
+//			},
+//			DryRunFunc: func(ctx context.Context, rules []domain.PricingRule, limit int) (*domain.PricingDryRun, error) {
+//				panic("mock out the DryRun method")
+//			},
+//			GetAllFunc: func(ctx context.Context) ([]domain.PricingRule, error) {
+//				panic("mock out the GetAll method")
+//			},
+//			GetByIDFunc: func(ctx context.Context, id uuid.UUID) (*domain.PricingRule, error) {
+//				panic("mock out the GetByID method")
+//			},
+//			UpdateFunc: func(ctx context.Context, rule *domain.PricingRule) error {
+//				panic("mock out the Update method")
+//			},
+//		}
+//
+//		// use mockedPricingRuleService in code that requires service.PricingRuleService
+//		// and then make assertions.
+//
+//	}
+type PricingRuleServiceMock struct {
+	// CreateFunc mocks the Create method.
+	CreateFunc func(ctx context.Context, rule *domain.PricingRule) error
+
+	// DeleteFunc mocks the Delete method.
+	DeleteFunc func(ctx context.Context, id uuid.UUID) error
+
+	// DryRunFunc mocks the DryRun method.
+	DryRunFunc func(ctx context.Context, rules []domain.PricingRule, limit int) (*domain.PricingDryRun, error)
+
+	// GetAllFunc mocks the GetAll method.
+	GetAllFunc func(ctx context.Context) ([]domain.PricingRule, error)
+
+	// GetByIDFunc mocks the GetByID method.
+	GetByIDFunc func(ctx context.Context, id uuid.UUID) (*domain.PricingRule, error)
+
+	// UpdateFunc mocks the Update method.
+	UpdateFunc func(ctx context.Context, rule *domain.PricingRule) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Create holds details about calls to the Create method.
+		Create []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Rule is the rule argument value.
+			Rule *domain.PricingRule
+		}
+		// Delete holds details about calls to the Delete method.
+		Delete []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID uuid.UUID
+		}
+		// DryRun holds details about calls to the DryRun method.
+		DryRun []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Rules is the rules argument value.
+			Rules []domain.PricingRule
+			// Limit is the limit argument value.
+			Limit int
+		}
+		// GetAll holds details about calls to the GetAll method.
+		GetAll []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// GetByID holds details about calls to the GetByID method.
+		GetByID []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID uuid.UUID
+		}
+		// Update holds details about calls to the Update method.
+		Update []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Rule is the rule argument value.
+			Rule *domain.PricingRule
+		}
+	}
+	lockCreate  sync.RWMutex
+	lockDelete  sync.RWMutex
+	lockDryRun  sync.RWMutex
+	lockGetAll  sync.RWMutex
+	lockGetByID sync.RWMutex
+	lockUpdate  sync.RWMutex
+}
+
+// Create calls CreateFunc.
+func (mock *PricingRuleServiceMock) Create(ctx context.Context, rule *domain.PricingRule) error {
+	if mock.CreateFunc == nil {
+		panic("PricingRuleServiceMock.CreateFunc: method is nil but PricingRuleService.Create was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		Rule *domain.PricingRule
+	}{
+		Ctx:  ctx,
+		Rule: rule,
+	}
+	mock.lockCreate.Lock()
+	mock.calls.Create = append(mock.calls.Create, callInfo)
+	mock.lockCreate.Unlock()
+	return mock.CreateFunc(ctx, rule)
+}
+
+// CreateCalls gets all the calls that were made to Create.
+// Check the length with:
+//
+//	len(mockedPricingRuleService.CreateCalls())
+func (mock *PricingRuleServiceMock) CreateCalls() []struct {
+	Ctx  context.Context
+	Rule *domain.PricingRule
+} {
+	var calls []struct {
+		Ctx  context.Context
+		Rule *domain.PricingRule
+	}
+	mock.lockCreate.RLock()
+	calls = mock.calls.Create
+	mock.lockCreate.RUnlock()
+	return calls
+}
+
+// Delete calls DeleteFunc.
+func (mock *PricingRuleServiceMock) Delete(ctx context.Context, id uuid.UUID) error {
+	if mock.DeleteFunc == nil {
+		panic("PricingRuleServiceMock.DeleteFunc: method is nil but PricingRuleService.Delete was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  uuid.UUID
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockDelete.Lock()
+	mock.calls.Delete = append(mock.calls.Delete, callInfo)
+	mock.lockDelete.Unlock()
+	return mock.DeleteFunc(ctx, id)
+}
+
+// DeleteCalls gets all the calls that were made to Delete.
+// Check the length with:
+//
+//	len(mockedPricingRuleService.DeleteCalls())
+func (mock *PricingRuleServiceMock) DeleteCalls() []struct {
+	Ctx context.Context
+	ID  uuid.UUID
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  uuid.UUID
+	}
+	mock.lockDelete.RLock()
+	calls = mock.calls.Delete
+	mock.lockDelete.RUnlock()
+	return calls
+}
+
+// DryRun calls DryRunFunc.
+func (mock *PricingRuleServiceMock) DryRun(ctx context.Context, rules []domain.PricingRule, limit int) (*domain.PricingDryRun, error) {
+	if mock.DryRunFunc == nil {
+		panic("PricingRuleServiceMock.DryRunFunc: method is nil but PricingRuleService.DryRun was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Rules []domain.PricingRule
+		Limit int
+	}{
+		Ctx:   ctx,
+		Rules: rules,
+		Limit: limit,
+	}
+	mock.lockDryRun.Lock()
+	mock.calls.DryRun = append(mock.calls.DryRun, callInfo)
+	mock.lockDryRun.Unlock()
+	return mock.DryRunFunc(ctx, rules, limit)
+}
+
+// DryRunCalls gets all the calls that were made to DryRun.
+// Check the length with:
+//
+//	len(mockedPricingRuleService.DryRunCalls())
+func (mock *PricingRuleServiceMock) DryRunCalls() []struct {
+	Ctx   context.Context
+	Rules []domain.PricingRule
+	Limit int
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Rules []domain.PricingRule
+		Limit int
+	}
+	mock.lockDryRun.RLock()
+	calls = mock.calls.DryRun
+	mock.lockDryRun.RUnlock()
+	return calls
+}
+
+// GetAll calls GetAllFunc.
+func (mock *PricingRuleServiceMock) GetAll(ctx context.Context) ([]domain.PricingRule, error) {
+	if mock.GetAllFunc == nil {
+		panic("PricingRuleServiceMock.GetAllFunc: method is nil but PricingRuleService.GetAll was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockGetAll.Lock()
+	mock.calls.GetAll = append(mock.calls.GetAll, callInfo)
+	mock.lockGetAll.Unlock()
+	return mock.GetAllFunc(ctx)
+}
+
+// GetAllCalls gets all the calls that were made to GetAll.
+// Check the length with:
+//
+//	len(mockedPricingRuleService.GetAllCalls())
+func (mock *PricingRuleServiceMock) GetAllCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockGetAll.RLock()
+	calls = mock.calls.GetAll
+	mock.lockGetAll.RUnlock()
+	return calls
+}
+
+// GetByID calls GetByIDFunc.
+func (mock *PricingRuleServiceMock) GetByID(ctx context.Context, id uuid.UUID) (*domain.PricingRule, error) {
+	if mock.GetByIDFunc == nil {
+		panic("PricingRuleServiceMock.GetByIDFunc: method is nil but PricingRuleService.GetByID was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  uuid.UUID
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockGetByID.Lock()
+	mock.calls.GetByID = append(mock.calls.GetByID, callInfo)
+	mock.lockGetByID.Unlock()
+	return mock.GetByIDFunc(ctx, id)
+}
+
+// GetByIDCalls gets all the calls that were made to GetByID.
+// Check the length with:
+//
+//	len(mockedPricingRuleService.GetByIDCalls())
+func (mock *PricingRuleServiceMock) GetByIDCalls() []struct {
+	Ctx context.Context
+	ID  uuid.UUID
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  uuid.UUID
+	}
+	mock.lockGetByID.RLock()
+	calls = mock.calls.GetByID
+	mock.lockGetByID.RUnlock()
+	return calls
+}
+
+// Update calls UpdateFunc.
+func (mock *PricingRuleServiceMock) Update(ctx context.Context, rule *domain.PricingRule) error {
+	if mock.UpdateFunc == nil {
+		panic("PricingRuleServiceMock.UpdateFunc: method is nil but PricingRuleService.Update was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		Rule *domain.PricingRule
+	}{
+		Ctx:  ctx,
+		Rule: rule,
+	}
+	mock.lockUpdate.Lock()
+	mock.calls.Update = append(mock.calls.Update, callInfo)
+	mock.lockUpdate.Unlock()
+	return mock.UpdateFunc(ctx, rule)
+}
+
+// UpdateCalls gets all the calls that were made to Update.
+// Check the length with:
+//
+//	len(mockedPricingRuleService.UpdateCalls())
+func (mock *PricingRuleServiceMock) UpdateCalls() []struct {
+	Ctx  context.Context
+	Rule *domain.PricingRule
+} {
+	var calls []struct {
+		Ctx  context.Context
+		Rule *domain.PricingRule
+	}
+	mock.lockUpdate.RLock()
+	calls = mock.calls.Update
+	mock.lockUpdate.RUnlock()
+	return calls
+}
+
+// Ensure, that SearchServiceMock does implement service.SearchService.
+// If this is not the case, regenerate this file with moq.
+var _ service.SearchService = &SearchServiceMock{}
+
+// SearchServiceMock is a mock implementation of service.SearchService.
+//
+//	func TestSomethingThatUsesSearchService(t *testing.T) {
+//
+//		// make and configure a mocked service.SearchService
+//		mockedSearchService := &SearchServiceMock{
+//			SuggestFunc: func(ctx context.Context, q string, limit int) ([]domain.Suggestion, error) {
+//				panic("mock out the Suggest method")
+//			},
+//		}
+//
+//		// use mockedSearchService in code that requires service.SearchService
+//		// and then make assertions.
+//
+//	}
+type SearchServiceMock struct {
+	// SuggestFunc mocks the Suggest method.
+	SuggestFunc func(ctx context.Context, q string, limit int) ([]domain.Suggestion, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Suggest holds details about calls to the Suggest method.
+		Suggest []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Q is the q argument value.
+			Q string
+			// Limit is the limit argument value.
+			Limit int
+		}
+	}
+	lockSuggest sync.RWMutex
+}
+
+// Suggest calls SuggestFunc.
+func (mock *SearchServiceMock) Suggest(ctx context.Context, q string, limit int) ([]domain.Suggestion, error) {
+	if mock.SuggestFunc == nil {
+		panic("SearchServiceMock.SuggestFunc: method is nil but SearchService.Suggest was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Q     string
+		Limit int
+	}{
+		Ctx:   ctx,
+		Q:     q,
+		Limit: limit,
+	}
+	mock.lockSuggest.Lock()
+	mock.calls.Suggest = append(mock.calls.Suggest, callInfo)
+	mock.lockSuggest.Unlock()
+	return mock.SuggestFunc(ctx, q, limit)
+}
+
+// SuggestCalls gets all the calls that were made to Suggest.
+// Check the length with:
+//
+//	len(mockedSearchService.SuggestCalls())
+func (mock *SearchServiceMock) SuggestCalls() []struct {
+	Ctx   context.Context
+	Q     string
+	Limit int
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Q     string
+		Limit int
+	}
+	mock.lockSuggest.RLock()
+	calls = mock.calls.Suggest
+	mock.lockSuggest.RUnlock()
+	return calls
+}
+
+// Ensure, that ScrapeRunServiceMock does implement service.ScrapeRunService.
+// If this is not the case, regenerate this file with moq.
+var _ service.ScrapeRunService = &ScrapeRunServiceMock{}
+
+// ScrapeRunServiceMock is a mock implementation of service.ScrapeRunService.
+//
+//	func TestSomethingThatUsesScrapeRunService(t *testing.T) {
+//
+//		// make and configure a mocked service.ScrapeRunService
+//		mockedScrapeRunService := &ScrapeRunServiceMock{
+//			GetByIDFunc: func(ctx context.Context, id uuid.UUID) (*domain.ScrapeRunDetail, error) {
+//				panic("mock out the GetByID method")
+//			},
+//			GetListFunc: func(ctx context.Context, filter domain.ScrapeRunFilter) (*domain.ScrapeRunList, error) {
+//				panic("mock out the GetList method")
+//			},
+//		}
+//
+//		// use mockedScrapeRunService in code that requires service.ScrapeRunService
+//		// and then make assertions.
+//
+//	}
+type ScrapeRunServiceMock struct {
+	// GetByIDFunc mocks the GetByID method.
+	GetByIDFunc func(ctx context.Context, id uuid.UUID) (*domain.ScrapeRunDetail, error)
+
+	// GetListFunc mocks the GetList method.
+	GetListFunc func(ctx context.Context, filter domain.ScrapeRunFilter) (*domain.ScrapeRunList, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// GetByID holds details about calls to the GetByID method.
+		GetByID []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID uuid.UUID
+		}
+		// GetList holds details about calls to the GetList method.
+		GetList []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Filter is the filter argument value.
+			Filter domain.ScrapeRunFilter
+		}
+	}
+	lockGetByID sync.RWMutex
+	lockGetList sync.RWMutex
+}
+
+// GetByID calls GetByIDFunc.
+func (mock *ScrapeRunServiceMock) GetByID(ctx context.Context, id uuid.UUID) (*domain.ScrapeRunDetail, error) {
+	if mock.GetByIDFunc == nil {
+		panic("ScrapeRunServiceMock.GetByIDFunc: method is nil but ScrapeRunService.GetByID was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  uuid.UUID
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockGetByID.Lock()
+	mock.calls.GetByID = append(mock.calls.GetByID, callInfo)
+	mock.lockGetByID.Unlock()
+	return mock.GetByIDFunc(ctx, id)
+}
+
+// GetByIDCalls gets all the calls that were made to GetByID.
+// Check the length with:
+//
+//	len(mockedScrapeRunService.GetByIDCalls())
+func (mock *ScrapeRunServiceMock) GetByIDCalls() []struct {
+	Ctx context.Context
+	ID  uuid.UUID
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  uuid.UUID
+	}
+	mock.lockGetByID.RLock()
+	calls = mock.calls.GetByID
+	mock.lockGetByID.RUnlock()
+	return calls
+}
+
+// GetList calls GetListFunc.
+func (mock *ScrapeRunServiceMock) GetList(ctx context.Context, filter domain.ScrapeRunFilter) (*domain.ScrapeRunList, error) {
+	if mock.GetListFunc == nil {
+		panic("ScrapeRunServiceMock.GetListFunc: method is nil but ScrapeRunService.GetList was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Filter domain.ScrapeRunFilter
+	}{
+		Ctx:    ctx,
+		Filter: filter,
+	}
+	mock.lockGetList.Lock()
+	mock.calls.GetList = append(mock.calls.GetList, callInfo)
+	mock.lockGetList.Unlock()
+	return mock.GetListFunc(ctx, filter)
+}
+
+// GetListCalls gets all the calls that were made to GetList.
+// Check the length with:
+//
+//	len(mockedScrapeRunService.GetListCalls())
+func (mock *ScrapeRunServiceMock) GetListCalls() []struct {
+	Ctx    context.Context
+	Filter domain.ScrapeRunFilter
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Filter domain.ScrapeRunFilter
+	}
+	mock.lockGetList.RLock()
+	calls = mock.calls.GetList
+	mock.lockGetList.RUnlock()
+	return calls
+}
+
+// Ensure, that CategoryScheduleServiceMock does implement service.CategoryScheduleService.
+// If this is not the case, regenerate this file with moq.
+var _ service.CategoryScheduleService = &CategoryScheduleServiceMock{}
+
+// CategoryScheduleServiceMock is a mock implementation of service.CategoryScheduleService.
+//
+//	func TestSomethingThatUsesCategoryScheduleService(t *testing.T) {
+//
+//		// make and configure a mocked service.CategoryScheduleService
+//		mockedCategoryScheduleService := &CategoryScheduleServiceMock{
+//			DeleteFunc: func(ctx context.Context, categoryID uuid.UUID) error {
+//				panic("mock out the Delete method")
+//			},
+//			GetAllFunc: func(ctx context.Context) ([]domain.CategorySchedule, error) {
+//				panic("mock out the GetAll method")
+//			},
+//			SetFunc: func(ctx context.Context, schedule *domain.CategorySchedule) error {
+//				panic("mock out the Set method")
+//			},
+//		}
+//
+//		// use mockedCategoryScheduleService in code that requires service.CategoryScheduleService
+//		// and then make assertions.
+//
+//	}
+type CategoryScheduleServiceMock struct {
+	// DeleteFunc mocks the Delete method.
+	DeleteFunc func(ctx context.Context, categoryID uuid.UUID) error
+
+	// GetAllFunc mocks the GetAll method.
+	GetAllFunc func(ctx context.Context) ([]domain.CategorySchedule, error)
+
+	// SetFunc mocks the Set method.
+	SetFunc func(ctx context.Context, schedule *domain.CategorySchedule) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Delete holds details about calls to the Delete method.
+		Delete []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// CategoryID is the categoryID argument value.
+			CategoryID uuid.UUID
+		}
+		// GetAll holds details about calls to the GetAll method.
+		GetAll []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// Set holds details about calls to the Set method.
+		Set []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Schedule is the schedule argument value.
+			Schedule *domain.CategorySchedule
+		}
+	}
+	lockDelete sync.RWMutex
+	lockGetAll sync.RWMutex
+	lockSet    sync.RWMutex
+}
+
+// Delete calls DeleteFunc.
+func (mock *CategoryScheduleServiceMock) Delete(ctx context.Context, categoryID uuid.UUID) error {
+	if mock.DeleteFunc == nil {
+		panic("CategoryScheduleServiceMock.DeleteFunc: method is nil but CategoryScheduleService.Delete was just called")
+	}
+	callInfo := struct {
+		Ctx        context.Context
+		CategoryID uuid.UUID
+	}{
+		Ctx:        ctx,
+		CategoryID: categoryID,
+	}
+	mock.lockDelete.Lock()
+	mock.calls.Delete = append(mock.calls.Delete, callInfo)
+	mock.lockDelete.Unlock()
+	return mock.DeleteFunc(ctx, categoryID)
+}
+
+// DeleteCalls gets all the calls that were made to Delete.
+// Check the length with:
+//
+//	len(mockedCategoryScheduleService.DeleteCalls())
+func (mock *CategoryScheduleServiceMock) DeleteCalls() []struct {
+	Ctx        context.Context
+	CategoryID uuid.UUID
+} {
+	var calls []struct {
+		Ctx        context.Context
+		CategoryID uuid.UUID
+	}
+	mock.lockDelete.RLock()
+	calls = mock.calls.Delete
+	mock.lockDelete.RUnlock()
+	return calls
+}
+
+// GetAll calls GetAllFunc.
+func (mock *CategoryScheduleServiceMock) GetAll(ctx context.Context) ([]domain.CategorySchedule, error) {
+	if mock.GetAllFunc == nil {
+		panic("CategoryScheduleServiceMock.GetAllFunc: method is nil but CategoryScheduleService.GetAll was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockGetAll.Lock()
+	mock.calls.GetAll = append(mock.calls.GetAll, callInfo)
+	mock.lockGetAll.Unlock()
+	return mock.GetAllFunc(ctx)
+}
+
+// GetAllCalls gets all the calls that were made to GetAll.
+// Check the length with:
+//
+//	len(mockedCategoryScheduleService.GetAllCalls())
+func (mock *CategoryScheduleServiceMock) GetAllCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockGetAll.RLock()
+	calls = mock.calls.GetAll
+	mock.lockGetAll.RUnlock()
+	return calls
+}
+
+// Set calls SetFunc.
+func (mock *CategoryScheduleServiceMock) Set(ctx context.Context, schedule *domain.CategorySchedule) error {
+	if mock.SetFunc == nil {
+		panic("CategoryScheduleServiceMock.SetFunc: method is nil but CategoryScheduleService.Set was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Schedule *domain.CategorySchedule
+	}{
+		Ctx:      ctx,
+		Schedule: schedule,
+	}
+	mock.lockSet.Lock()
+	mock.calls.Set = append(mock.calls.Set, callInfo)
+	mock.lockSet.Unlock()
+	return mock.SetFunc(ctx, schedule)
+}
+
+// SetCalls gets all the calls that were made to Set.
+// Check the length with:
+//
+//	len(mockedCategoryScheduleService.SetCalls())
+func (mock *CategoryScheduleServiceMock) SetCalls() []struct {
+	Ctx      context.Context
+	Schedule *domain.CategorySchedule
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Schedule *domain.CategorySchedule
+	}
+	mock.lockSet.RLock()
+	calls = mock.calls.Set
+	mock.lockSet.RUnlock()
+	return calls
+}
+
+// Ensure, that ScrapeRequestServiceMock does implement service.ScrapeRequestService.
+// If this is not the case, regenerate this file with moq.
+var _ service.ScrapeRequestService = &ScrapeRequestServiceMock{}
+
+// ScrapeRequestServiceMock is a mock implementation of service.ScrapeRequestService.
+//
+//	func TestSomethingThatUsesScrapeRequestService(t *testing.T) {
+//
+//		// make and configure a mocked service.ScrapeRequestService
+//		mockedScrapeRequestService := &ScrapeRequestServiceMock{
+//			CancelFunc: func(ctx context.Context, id uuid.UUID) (*domain.ScrapeRequest, error) {
+//				panic("mock out the Cancel method")
+//			},
+//			CreateFunc: func(ctx context.Context, req *domain.ScrapeRequest) error {
+//				panic("mock out the Create method")
+//			},
+//			GetByIDFunc: func(ctx context.Context, id uuid.UUID) (*domain.ScrapeRequest, error) {
+//				panic("mock out the GetByID method")
+//			},
+//		}
+//
+//		// use mockedScrapeRequestService in code that requires service.ScrapeRequestService
+//		// and then make assertions.
+//
+//	}
+type ScrapeRequestServiceMock struct {
+	// CancelFunc mocks the Cancel method.
+	CancelFunc func(ctx context.Context, id uuid.UUID) (*domain.ScrapeRequest, error)
+
+	// CreateFunc mocks the Create method.
+	CreateFunc func(ctx context.Context, req *domain.ScrapeRequest) error
+
+	// GetByIDFunc mocks the GetByID method.
+	GetByIDFunc func(ctx context.Context, id uuid.UUID) (*domain.ScrapeRequest, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Cancel holds details about calls to the Cancel method.
+		Cancel []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID uuid.UUID
+		}
+		// Create holds details about calls to the Create method.
+		Create []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Req is the req argument value.
+			Req *domain.ScrapeRequest
+		}
+		// GetByID holds details about calls to the GetByID method.
+		GetByID []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID uuid.UUID
+		}
+	}
+	lockCancel  sync.RWMutex
+	lockCreate  sync.RWMutex
+	lockGetByID sync.RWMutex
+}
+
+// Cancel calls CancelFunc.
+func (mock *ScrapeRequestServiceMock) Cancel(ctx context.Context, id uuid.UUID) (*domain.ScrapeRequest, error) {
+	if mock.CancelFunc == nil {
+		panic("ScrapeRequestServiceMock.CancelFunc: method is nil but ScrapeRequestService.Cancel was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  uuid.UUID
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockCancel.Lock()
+	mock.calls.Cancel = append(mock.calls.Cancel, callInfo)
+	mock.lockCancel.Unlock()
+	return mock.CancelFunc(ctx, id)
+}
+
+// CancelCalls gets all the calls that were made to Cancel.
+// Check the length with:
+//
+//	len(mockedScrapeRequestService.CancelCalls())
+func (mock *ScrapeRequestServiceMock) CancelCalls() []struct {
+	Ctx context.Context
+	ID  uuid.UUID
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  uuid.UUID
+	}
+	mock.lockCancel.RLock()
+	calls = mock.calls.Cancel
+	mock.lockCancel.RUnlock()
+	return calls
+}
+
+// Create calls CreateFunc.
+func (mock *ScrapeRequestServiceMock) Create(ctx context.Context, req *domain.ScrapeRequest) error {
+	if mock.CreateFunc == nil {
+		panic("ScrapeRequestServiceMock.CreateFunc: method is nil but ScrapeRequestService.Create was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Req *domain.ScrapeRequest
+	}{
+		Ctx: ctx,
+		Req: req,
+	}
+	mock.lockCreate.Lock()
+	mock.calls.Create = append(mock.calls.Create, callInfo)
+	mock.lockCreate.Unlock()
+	return mock.CreateFunc(ctx, req)
+}
+
+// CreateCalls gets all the calls that were made to Create.
+// Check the length with:
+//
+//	len(mockedScrapeRequestService.CreateCalls())
+func (mock *ScrapeRequestServiceMock) CreateCalls() []struct {
+	Ctx context.Context
+	Req *domain.ScrapeRequest
+} {
+	var calls []struct {
+		Ctx context.Context
+		Req *domain.ScrapeRequest
+	}
+	mock.lockCreate.RLock()
+	calls = mock.calls.Create
+	mock.lockCreate.RUnlock()
+	return calls
+}
+
+// GetByID calls GetByIDFunc.
+func (mock *ScrapeRequestServiceMock) GetByID(ctx context.Context, id uuid.UUID) (*domain.ScrapeRequest, error) {
+	if mock.GetByIDFunc == nil {
+		panic("ScrapeRequestServiceMock.GetByIDFunc: method is nil but ScrapeRequestService.GetByID was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  uuid.UUID
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockGetByID.Lock()
+	mock.calls.GetByID = append(mock.calls.GetByID, callInfo)
+	mock.lockGetByID.Unlock()
+	return mock.GetByIDFunc(ctx, id)
+}
+
+// GetByIDCalls gets all the calls that were made to GetByID.
+// Check the length with:
+//
+//	len(mockedScrapeRequestService.GetByIDCalls())
+func (mock *ScrapeRequestServiceMock) GetByIDCalls() []struct {
+	Ctx context.Context
+	ID  uuid.UUID
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  uuid.UUID
+	}
+	mock.lockGetByID.RLock()
+	calls = mock.calls.GetByID
+	mock.lockGetByID.RUnlock()
+	return calls
+}
+
+// Ensure, that ProductGroupServiceMock does implement service.ProductGroupService.
+// If this is not the case, regenerate this file with moq.
+var _ service.ProductGroupService = &ProductGroupServiceMock{}
+
+// ProductGroupServiceMock is a mock implementation of service.ProductGroupService.
+//
+//	func TestSomethingThatUsesProductGroupService(t *testing.T) {
+//
+//		// make and configure a mocked service.ProductGroupService
+//		mockedProductGroupService := &ProductGroupServiceMock{
+//			GetByIDFunc: func(ctx context.Context, id uuid.UUID) (*domain.ProductGroup, error) {
+//				panic("mock out the GetByID method")
+//			},
+//		}
+//
+//		// use mockedProductGroupService in code that requires service.ProductGroupService
+//		// and then make assertions.
+//
+//	}
+type ProductGroupServiceMock struct {
+	// GetByIDFunc mocks the GetByID method.
+	GetByIDFunc func(ctx context.Context, id uuid.UUID) (*domain.ProductGroup, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// GetByID holds details about calls to the GetByID method.
+		GetByID []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID uuid.UUID
+		}
+	}
+	lockGetByID sync.RWMutex
+}
+
+// GetByID calls GetByIDFunc.
+func (mock *ProductGroupServiceMock) GetByID(ctx context.Context, id uuid.UUID) (*domain.ProductGroup, error) {
+	if mock.GetByIDFunc == nil {
+		panic("ProductGroupServiceMock.GetByIDFunc: method is nil but ProductGroupService.GetByID was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  uuid.UUID
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockGetByID.Lock()
+	mock.calls.GetByID = append(mock.calls.GetByID, callInfo)
+	mock.lockGetByID.Unlock()
+	return mock.GetByIDFunc(ctx, id)
+}
+
+// GetByIDCalls gets all the calls that were made to GetByID.
+// Check the length with:
+//
+//	len(mockedProductGroupService.GetByIDCalls())
+func (mock *ProductGroupServiceMock) GetByIDCalls() []struct {
+	Ctx context.Context
+	ID  uuid.UUID
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  uuid.UUID
+	}
+	mock.lockGetByID.RLock()
+	calls = mock.calls.GetByID
+	mock.lockGetByID.RUnlock()
+	return calls
+}
+
+// Ensure, that ProductMatchServiceMock does implement service.ProductMatchService.
+// If this is not the case, regenerate this file with moq.
+var _ service.ProductMatchService = &ProductMatchServiceMock{}
+
+// ProductMatchServiceMock is a mock implementation of service.ProductMatchService.
+//
+//	func TestSomethingThatUsesProductMatchService(t *testing.T) {
+//
+//		// make and configure a mocked service.ProductMatchService
+//		mockedProductMatchService := &ProductMatchServiceMock{
+//			GetListFunc: func(ctx context.Context, filter domain.ProductMatchFilter) (*domain.ProductMatchList, error) {
+//				panic("mock out the GetList method")
+//			},
+//			GetOffersFunc: func(ctx context.Context, productID uuid.UUID) ([]domain.ProductOffer, error) {
+//				panic("mock out the GetOffers method")
+//			},
+//			SetStatusFunc: func(ctx context.Context, id uuid.UUID, status string) (*domain.ProductMatch, error) {
+//				panic("mock out the SetStatus method")
+//			},
+//		}
+//
+//		// use mockedProductMatchService in code that requires service.ProductMatchService
+//		// and then make assertions.
+//
+//	}
+type ProductMatchServiceMock struct {
+	// GetListFunc mocks the GetList method.
+	GetListFunc func(ctx context.Context, filter domain.ProductMatchFilter) (*domain.ProductMatchList, error)
+
+	// GetOffersFunc mocks the GetOffers method.
+	GetOffersFunc func(ctx context.Context, productID uuid.UUID) ([]domain.ProductOffer, error)
+
+	// SetStatusFunc mocks the SetStatus method.
+	SetStatusFunc func(ctx context.Context, id uuid.UUID, status string) (*domain.ProductMatch, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// GetList holds details about calls to the GetList method.
+		GetList []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Filter is the filter argument value.
+			Filter domain.ProductMatchFilter
+		}
+		// GetOffers holds details about calls to the GetOffers method.
+		GetOffers []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ProductID is the productID argument value.
+			ProductID uuid.UUID
+		}
+		// SetStatus holds details about calls to the SetStatus method.
+		SetStatus []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID uuid.UUID
+			// Status is the status argument value.
+			Status string
+		}
+	}
+	lockGetList   sync.RWMutex
+	lockGetOffers sync.RWMutex
+	lockSetStatus sync.RWMutex
+}
+
+// GetList calls GetListFunc.
+func (mock *ProductMatchServiceMock) GetList(ctx context.Context, filter domain.ProductMatchFilter) (*domain.ProductMatchList, error) {
+	if mock.GetListFunc == nil {
+		panic("ProductMatchServiceMock.GetListFunc: method is nil but ProductMatchService.GetList was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Filter domain.ProductMatchFilter
+	}{
+		Ctx:    ctx,
+		Filter: filter,
+	}
+	mock.lockGetList.Lock()
+	mock.calls.GetList = append(mock.calls.GetList, callInfo)
+	mock.lockGetList.Unlock()
+	return mock.GetListFunc(ctx, filter)
+}
+
+// GetListCalls gets all the calls that were made to GetList.
+// Check the length with:
+//
+//	len(mockedProductMatchService.GetListCalls())
+func (mock *ProductMatchServiceMock) GetListCalls() []struct {
+	Ctx    context.Context
+	Filter domain.ProductMatchFilter
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Filter domain.ProductMatchFilter
+	}
+	mock.lockGetList.RLock()
+	calls = mock.calls.GetList
+	mock.lockGetList.RUnlock()
+	return calls
+}
+
+// GetOffers calls GetOffersFunc.
+func (mock *ProductMatchServiceMock) GetOffers(ctx context.Context, productID uuid.UUID) ([]domain.ProductOffer, error) {
+	if mock.GetOffersFunc == nil {
+		panic("ProductMatchServiceMock.GetOffersFunc: method is nil but ProductMatchService.GetOffers was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		ProductID uuid.UUID
+	}{
+		Ctx:       ctx,
+		ProductID: productID,
+	}
+	mock.lockGetOffers.Lock()
+	mock.calls.GetOffers = append(mock.calls.GetOffers, callInfo)
+	mock.lockGetOffers.Unlock()
+	return mock.GetOffersFunc(ctx, productID)
+}
+
+// GetOffersCalls gets all the calls that were made to GetOffers.
+// Check the length with:
+//
+//	len(mockedProductMatchService.GetOffersCalls())
+func (mock *ProductMatchServiceMock) GetOffersCalls() []struct {
+	Ctx       context.Context
+	ProductID uuid.UUID
+} {
+	var calls []struct {
+		Ctx       context.Context
+		ProductID uuid.UUID
+	}
+	mock.lockGetOffers.RLock()
+	calls = mock.calls.GetOffers
+	mock.lockGetOffers.RUnlock()
+	return calls
+}
+
+// SetStatus calls SetStatusFunc.
+func (mock *ProductMatchServiceMock) SetStatus(ctx context.Context, id uuid.UUID, status string) (*domain.ProductMatch, error) {
+	if mock.SetStatusFunc == nil {
+		panic("ProductMatchServiceMock.SetStatusFunc: method is nil but ProductMatchService.SetStatus was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		ID     uuid.UUID
+		Status string
+	}{
+		Ctx:    ctx,
+		ID:     id,
+		Status: status,
+	}
+	mock.lockSetStatus.Lock()
+	mock.calls.SetStatus = append(mock.calls.SetStatus, callInfo)
+	mock.lockSetStatus.Unlock()
+	return mock.SetStatusFunc(ctx, id, status)
+}
+
+// SetStatusCalls gets all the calls that were made to SetStatus.
+// Check the length with:
+//
+//	len(mockedProductMatchService.SetStatusCalls())
+func (mock *ProductMatchServiceMock) SetStatusCalls() []struct {
+	Ctx    context.Context
+	ID     uuid.UUID
+	Status string
+} {
+	var calls []struct {
+		Ctx    context.Context
+		ID     uuid.UUID
+		Status string
+	}
+	mock.lockSetStatus.RLock()
+	calls = mock.calls.SetStatus
+	mock.lockSetStatus.RUnlock()
+	return calls
+}
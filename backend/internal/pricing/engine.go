@@ -0,0 +1,134 @@
+package pricing
+
+import (
+	"math"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/burbble/marketplace/internal/domain"
+)
+
+type Input struct {
+	CategoryID uuid.UUID
+	// Ancestors are the parent categories of CategoryID up to the root, so
+	// that a rule on a category also prices its subcategories.
+	Ancestors []uuid.UUID
+	Brand     string
+	Price     int
+}
+
+type Engine struct {
+	rules []domain.PricingRule
+}
+
+// NewEngine keeps the enabled rules ordered by descending priority. Rules with
+// equal priority keep their input order, so callers should pass them sorted by
+// creation time.
+func NewEngine(rules []domain.PricingRule) *Engine {
+	enabled := make([]domain.PricingRule, 0, len(rules))
+	for _, r := range rules {
+		if r.Enabled {
+			enabled = append(enabled, r)
+		}
+	}
+
+	sort.SliceStable(enabled, func(i, j int) bool {
+		return enabled[i].Priority > enabled[j].Priority
+	})
+
+	return &Engine{rules: enabled}
+}
+
+// Apply returns the selling price for the input and the rule that produced it.
+// When no rule matches the source price is returned unchanged with a nil rule.
+func (e *Engine) Apply(in Input) (int, *domain.PricingRule) {
+	for i := range e.rules {
+		r := &e.rules[i]
+		if matches(r, in) {
+			return applyRule(r, in.Price), r
+		}
+	}
+
+	return in.Price, nil
+}
+
+func matches(r *domain.PricingRule, in Input) bool {
+	if r.CategoryID != nil && *r.CategoryID != in.CategoryID && !slices.Contains(in.Ancestors, *r.CategoryID) {
+		return false
+	}
+	if r.Brand != nil && *r.Brand != "" && !strings.EqualFold(*r.Brand, in.Brand) {
+		return false
+	}
+	if r.MinPrice != nil && in.Price < *r.MinPrice {
+		return false
+	}
+	if r.MaxPrice != nil && in.Price > *r.MaxPrice {
+		return false
+	}
+
+	return true
+}
+
+func applyRule(r *domain.PricingRule, price int) int {
+	adjusted := float64(price)
+	switch r.AdjustmentType {
+	case domain.AdjustmentFixed:
+		adjusted += r.AdjustmentValue
+	case domain.AdjustmentPercent:
+		adjusted *= 1 + r.AdjustmentValue/100
+	}
+
+	result := roundPrice(int(math.Round(adjusted)), r.RoundingMode, r.RoundingStep, r.RoundingEnding)
+
+	if r.FloorPrice != nil && result < *r.FloorPrice {
+		result = *r.FloorPrice
+	}
+	if r.CeilingPrice != nil && result > *r.CeilingPrice {
+		result = *r.CeilingPrice
+	}
+	if result < 0 {
+		result = 0
+	}
+
+	return result
+}
+
+// roundPrice snaps price to the nearest value that ends with ending modulo
+// step, e.g. step 1000 and ending 990 turns 47350 into 46990 (down), 47990 (up)
+// or 47990 (nearest).
+func roundPrice(price int, mode string, step, ending int) int {
+	if step <= 0 {
+		return price
+	}
+
+	down := floorDiv(price-ending, step)*step + ending
+	if down == price {
+		return price
+	}
+	up := down + step
+
+	switch mode {
+	case domain.RoundingDown:
+		return down
+	case domain.RoundingUp:
+		return up
+	case domain.RoundingNearest:
+		if price-down < up-price {
+			return down
+		}
+		return up
+	}
+
+	return price
+}
+
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
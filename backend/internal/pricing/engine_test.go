@@ -0,0 +1,130 @@
+package pricing
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/burbble/marketplace/internal/domain"
+)
+
+func intPtr(v int) *int { return &v }
+
+func strPtr(v string) *string { return &v }
+
+func TestEngine_Apply_PriorityAndMatching(t *testing.T) {
+	phones := uuid.New()
+	smartphones := uuid.New()
+	laptops := uuid.New()
+
+	rules := []domain.PricingRule{
+		{Name: "default", Priority: 0, Enabled: true, AdjustmentType: domain.AdjustmentFixed, AdjustmentValue: -1000, RoundingMode: domain.RoundingNone},
+		{Name: "apple phones", Priority: 20, Enabled: true, CategoryID: &phones, Brand: strPtr("apple"), AdjustmentType: domain.AdjustmentPercent, AdjustmentValue: -5, RoundingMode: domain.RoundingNone},
+		{Name: "phones", Priority: 10, Enabled: true, CategoryID: &phones, AdjustmentType: domain.AdjustmentFixed, AdjustmentValue: -500, RoundingMode: domain.RoundingNone},
+		{Name: "cheap", Priority: 30, Enabled: true, MaxPrice: intPtr(5000), AdjustmentType: domain.AdjustmentFixed, AdjustmentValue: 0, RoundingMode: domain.RoundingNone},
+		{Name: "disabled", Priority: 100, Enabled: false, AdjustmentType: domain.AdjustmentFixed, AdjustmentValue: -99999, RoundingMode: domain.RoundingNone},
+	}
+
+	e := NewEngine(rules)
+
+	tests := []struct {
+		name     string
+		in       Input
+		expected int
+		rule     string
+	}{
+		{"brand and category", Input{CategoryID: phones, Brand: "Apple", Price: 100000}, 95000, "apple phones"},
+		{"category only", Input{CategoryID: phones, Brand: "Samsung", Price: 50000}, 49500, "phones"},
+		{"subcategory", Input{CategoryID: smartphones, Ancestors: []uuid.UUID{phones}, Brand: "Samsung", Price: 50000}, 49500, "phones"},
+		{"fallback", Input{CategoryID: laptops, Brand: "Apple", Price: 80000}, 79000, "default"},
+		{"price band wins by priority", Input{CategoryID: phones, Brand: "Apple", Price: 4000}, 4000, "cheap"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			price, rule := e.Apply(tt.in)
+			if price != tt.expected {
+				t.Errorf("expected price %d, got %d", tt.expected, price)
+			}
+			if rule == nil || rule.Name != tt.rule {
+				t.Errorf("expected rule %q, got %+v", tt.rule, rule)
+			}
+		})
+	}
+}
+
+func TestEngine_Apply_NoRules(t *testing.T) {
+	e := NewEngine(nil)
+
+	price, rule := e.Apply(Input{Price: 12345})
+	if price != 12345 {
+		t.Errorf("expected unchanged price 12345, got %d", price)
+	}
+	if rule != nil {
+		t.Errorf("expected nil rule, got %+v", rule)
+	}
+}
+
+func TestEngine_Apply_FloorAndCeiling(t *testing.T) {
+	e := NewEngine([]domain.PricingRule{
+		{
+			Name: "clamped", Enabled: true,
+			AdjustmentType: domain.AdjustmentFixed, AdjustmentValue: -1000,
+			RoundingMode: domain.RoundingNone,
+			FloorPrice:   intPtr(500), CeilingPrice: intPtr(90000),
+		},
+	})
+
+	tests := []struct {
+		price    int
+		expected int
+	}{
+		{1200, 500},
+		{50000, 49000},
+		{100000, 90000},
+	}
+
+	for _, tt := range tests {
+		if got, _ := e.Apply(Input{Price: tt.price}); got != tt.expected {
+			t.Errorf("Apply(%d) = %d, expected %d", tt.price, got, tt.expected)
+		}
+	}
+}
+
+func TestEngine_Apply_NeverNegative(t *testing.T) {
+	e := NewEngine([]domain.PricingRule{
+		{Name: "markdown", Enabled: true, AdjustmentType: domain.AdjustmentFixed, AdjustmentValue: -1000, RoundingMode: domain.RoundingNone},
+	})
+
+	if got, _ := e.Apply(Input{Price: 300}); got != 0 {
+		t.Errorf("expected 0, got %d", got)
+	}
+}
+
+func TestRoundPrice(t *testing.T) {
+	tests := []struct {
+		name     string
+		price    int
+		mode     string
+		step     int
+		ending   int
+		expected int
+	}{
+		{"down to 990", 47350, domain.RoundingDown, 1000, 990, 46990},
+		{"up to 990", 47350, domain.RoundingUp, 1000, 990, 47990},
+		{"nearest to 990 goes up", 47600, domain.RoundingNearest, 1000, 990, 47990},
+		{"nearest to 990 goes down", 47400, domain.RoundingNearest, 1000, 990, 46990},
+		{"already rounded", 46990, domain.RoundingUp, 1000, 990, 46990},
+		{"hundreds", 12345, domain.RoundingNearest, 100, 0, 12300},
+		{"below ending", 500, domain.RoundingDown, 1000, 990, -10},
+		{"none", 12345, domain.RoundingNone, 100, 0, 12345},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := roundPrice(tt.price, tt.mode, tt.step, tt.ending); got != tt.expected {
+				t.Errorf("roundPrice(%d) = %d, expected %d", tt.price, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	descriptionKeyPrefix = "product:description:"
+
+	// legacyDescriptionSource is the source whose entries predate
+	// multi-source scraping and are keyed by the external id alone.
+	legacyDescriptionSource = "store77"
+)
+
+// Description is a product description cached together with the
+// fingerprint of the listing it was fetched for and when the product page
+// was fetched. Entries cached before FetchedAt was recorded leave it zero.
+type Description struct {
+	Fingerprint string    `json:"f"`
+	Text        string    `json:"t"`
+	FetchedAt   time.Time `json:"at"`
+}
+
+// DescriptionCache keys descriptions by the product's source and external
+// id.
+type DescriptionCache interface {
+	GetMany(ctx context.Context, source string, externalIDs []string) (map[string]Description, error)
+	Set(ctx context.Context, source, externalID string, d Description) error
+}
+
+type descriptionCache struct {
+	rdb *redis.Client
+	ttl time.Duration
+}
+
+func NewDescriptionCache(rdb *redis.Client, ttl time.Duration) DescriptionCache {
+	return &descriptionCache{rdb: rdb, ttl: ttl}
+}
+
+// GetMany returns the cached descriptions of the given products; products
+// without a cache entry are absent from the map.
+func (c *descriptionCache) GetMany(ctx context.Context, source string, externalIDs []string) (map[string]Description, error) {
+	result := make(map[string]Description, len(externalIDs))
+	if len(externalIDs) == 0 {
+		return result, nil
+	}
+
+	keys := make([]string, len(externalIDs))
+	for i, id := range externalIDs {
+		keys[i] = descriptionKey(source, id)
+	}
+
+	values, err := c.rdb.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("get cached descriptions: %w", err)
+	}
+
+	for i, v := range values {
+		s, ok := v.(string)
+		if !ok {
+			continue
+		}
+
+		var d Description
+		if err := json.Unmarshal([]byte(s), &d); err != nil {
+			continue
+		}
+		result[externalIDs[i]] = d
+	}
+
+	return result, nil
+}
+
+func (c *descriptionCache) Set(ctx context.Context, source, externalID string, d Description) error {
+	b, err := json.Marshal(d)
+	if err != nil {
+		return fmt.Errorf("marshal description: %w", err)
+	}
+
+	if err := c.rdb.Set(ctx, descriptionKey(source, externalID), b, c.ttl).Err(); err != nil {
+		return fmt.Errorf("cache description: %w", err)
+	}
+
+	return nil
+}
+
+type nopDescriptionCache struct{}
+
+// NewNopDescriptionCache returns a cache that stores nothing, for runs that
+// must not read or overwrite the shared cache, such as snapshot replays.
+func NewNopDescriptionCache() DescriptionCache {
+	return nopDescriptionCache{}
+}
+
+func (nopDescriptionCache) GetMany(_ context.Context, _ string, _ []string) (map[string]Description, error) {
+	return map[string]Description{}, nil
+}
+
+func (nopDescriptionCache) Set(_ context.Context, _, _ string, _ Description) error {
+	return nil
+}
+
+func descriptionKey(source, externalID string) string {
+	if source == legacyDescriptionSource {
+		return descriptionKeyPrefix + externalID
+	}
+	return descriptionKeyPrefix + source + ":" + externalID
+}
@@ -0,0 +1,116 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestCache(t *testing.T, ttl time.Duration) (DescriptionCache, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+
+	return NewDescriptionCache(rdb, ttl), mr
+}
+
+func TestDescriptionCache_SetAndGetMany(t *testing.T) {
+	c, _ := newTestCache(t, time.Hour)
+	ctx := context.Background()
+
+	if err := c.Set(ctx, "store77", "100", Description{Fingerprint: "abc", Text: "Смартфон"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Set(ctx, "store77", "200", Description{Fingerprint: "def", Text: "Ноутбук"}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := c.GetMany(ctx, "store77", []string{"100", "200", "300"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 cached descriptions, got %d", len(got))
+	}
+	if got["100"].Fingerprint != "abc" || got["100"].Text != "Смартфон" {
+		t.Errorf("unexpected entry %+v", got["100"])
+	}
+	if _, ok := got["300"]; ok {
+		t.Error("expected no entry for an uncached product")
+	}
+}
+
+func TestDescriptionCache_Expires(t *testing.T) {
+	c, mr := newTestCache(t, time.Hour)
+	ctx := context.Background()
+
+	if err := c.Set(ctx, "store77", "100", Description{Fingerprint: "abc", Text: "Смартфон"}); err != nil {
+		t.Fatal(err)
+	}
+
+	mr.FastForward(time.Hour + time.Second)
+
+	got, err := c.GetMany(ctx, "store77", []string{"100"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Errorf("expected entry to expire, got %+v", got)
+	}
+}
+
+func TestDescriptionCache_GetManyEmpty(t *testing.T) {
+	c, _ := newTestCache(t, time.Hour)
+
+	got, err := c.GetMany(context.Background(), "store77", nil)
+	if err != nil || len(got) != 0 {
+		t.Errorf("expected empty result, got %v (%v)", got, err)
+	}
+}
+
+func TestDescriptionCache_KeyedBySource(t *testing.T) {
+	c, mr := newTestCache(t, time.Hour)
+	ctx := context.Background()
+
+	if err := c.Set(ctx, "store77", "100", Description{Fingerprint: "abc", Text: "Смартфон"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Set(ctx, "other", "100", Description{Fingerprint: "def", Text: "Телефон"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// store77 entries keep the key used before sources were introduced.
+	if !mr.Exists("product:description:100") || !mr.Exists("product:description:other:100") {
+		t.Errorf("unexpected keys %v", mr.Keys())
+	}
+
+	got, err := c.GetMany(ctx, "other", []string{"100"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got["100"].Text != "Телефон" {
+		t.Errorf("expected the other source's entry, got %+v", got["100"])
+	}
+}
+
+func TestNopDescriptionCache(t *testing.T) {
+	c := NewNopDescriptionCache()
+	ctx := context.Background()
+
+	if err := c.Set(ctx, "store77", "100", Description{Fingerprint: "abc", Text: "Смартфон"}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := c.GetMany(ctx, "store77", []string{"100"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("expected nothing cached, got %v", got)
+	}
+}
@@ -11,10 +11,12 @@ import (
 	"github.com/burbble/marketplace/pkg/db"
 )
 
+var categoryColumns = []string{"id", "source", "parent_id", "depth", "name", "slug", "url", "created_at", "updated_at"}
+
 type CategoryRepository interface {
 	Upsert(ctx context.Context, categories []domain.Category) error
 	GetAll(ctx context.Context) ([]domain.Category, error)
-	GetBySlug(ctx context.Context, slug string) (*domain.Category, error)
+	GetBySlug(ctx context.Context, source, slug string) (*domain.Category, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.Category, error)
 }
 
@@ -33,14 +35,19 @@ func (r *categoryRepo) Upsert(ctx context.Context, categories []domain.Category)
 
 	q := r.conn.Builder.
 		Insert("categories").
-		Columns("name", "slug", "url", "updated_at")
+		Columns("source", "name", "slug", "url", "parent_id", "depth", "updated_at")
 
 	now := time.Now()
 	for _, c := range categories {
-		q = q.Values(c.Name, c.Slug, c.URL, now)
+		q = q.Values(c.Source, c.Name, c.Slug, c.URL, c.ParentID, c.Depth, now)
 	}
 
-	q = q.Suffix("ON CONFLICT (slug) DO UPDATE SET name = EXCLUDED.name, url = EXCLUDED.url, updated_at = EXCLUDED.updated_at")
+	q = q.Suffix(`ON CONFLICT (source, slug) DO UPDATE SET
+		name = EXCLUDED.name,
+		url = EXCLUDED.url,
+		parent_id = EXCLUDED.parent_id,
+		depth = EXCLUDED.depth,
+		updated_at = EXCLUDED.updated_at`)
 
 	query, args, err := q.ToSql()
 	if err != nil {
@@ -57,9 +64,9 @@ func (r *categoryRepo) Upsert(ctx context.Context, categories []domain.Category)
 
 func (r *categoryRepo) GetAll(ctx context.Context) ([]domain.Category, error) {
 	query, args, err := r.conn.Builder.
-		Select("id", "name", "slug", "url", "created_at", "updated_at").
+		Select(categoryColumns...).
 		From("categories").
-		OrderBy("name ASC").
+		OrderBy("depth ASC", "name ASC").
 		ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("build select categories: %w", err)
@@ -73,11 +80,11 @@ func (r *categoryRepo) GetAll(ctx context.Context) ([]domain.Category, error) {
 	return categories, nil
 }
 
-func (r *categoryRepo) GetBySlug(ctx context.Context, slug string) (*domain.Category, error) {
+func (r *categoryRepo) GetBySlug(ctx context.Context, source, slug string) (*domain.Category, error) {
 	query, args, err := r.conn.Builder.
-		Select("id", "name", "slug", "url", "created_at", "updated_at").
+		Select(categoryColumns...).
 		From("categories").
-		Where("slug = ?", slug).
+		Where("source = ? AND slug = ?", source, slug).
 		ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("build select category by slug: %w", err)
@@ -93,7 +100,7 @@ func (r *categoryRepo) GetBySlug(ctx context.Context, slug string) (*domain.Cate
 
 func (r *categoryRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Category, error) {
 	query, args, err := r.conn.Builder.
-		Select("id", "name", "slug", "url", "created_at", "updated_at").
+		Select(categoryColumns...).
 		From("categories").
 		Where("id = ?", id).
 		ToSql()
@@ -0,0 +1,92 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/burbble/marketplace/internal/domain"
+	"github.com/burbble/marketplace/pkg/db"
+)
+
+type CategoryScheduleRepository interface {
+	GetAll(ctx context.Context) ([]domain.CategorySchedule, error)
+	Upsert(ctx context.Context, schedule *domain.CategorySchedule) error
+	Delete(ctx context.Context, categoryID uuid.UUID) error
+}
+
+type categoryScheduleRepo struct {
+	conn *db.Connection
+}
+
+func NewCategoryScheduleRepo(conn *db.Connection) CategoryScheduleRepository {
+	return &categoryScheduleRepo{conn: conn}
+}
+
+func (r *categoryScheduleRepo) GetAll(ctx context.Context) ([]domain.CategorySchedule, error) {
+	query, args, err := r.conn.Builder.
+		Select("category_id", "cron", "enabled", "created_at", "updated_at").
+		From("category_schedules").
+		OrderBy("created_at ASC").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build select category schedules: %w", err)
+	}
+
+	schedules := make([]domain.CategorySchedule, 0)
+	if err := r.conn.DB.SelectContext(ctx, &schedules, query, args...); err != nil {
+		return nil, fmt.Errorf("select category schedules: %w", err)
+	}
+
+	return schedules, nil
+}
+
+func (r *categoryScheduleRepo) Upsert(ctx context.Context, schedule *domain.CategorySchedule) error {
+	query, args, err := r.conn.Builder.
+		Insert("category_schedules").
+		Columns("category_id", "cron", "enabled", "updated_at").
+		Values(schedule.CategoryID, schedule.Cron, schedule.Enabled, time.Now()).
+		Suffix(`ON CONFLICT (category_id) DO UPDATE SET
+			cron = EXCLUDED.cron,
+			enabled = EXCLUDED.enabled,
+			updated_at = EXCLUDED.updated_at
+		RETURNING created_at, updated_at`).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build upsert category schedule: %w", err)
+	}
+
+	if err := r.conn.DB.QueryRowxContext(ctx, query, args...).Scan(&schedule.CreatedAt, &schedule.UpdatedAt); err != nil {
+		return fmt.Errorf("upsert category schedule: %w", err)
+	}
+
+	return nil
+}
+
+func (r *categoryScheduleRepo) Delete(ctx context.Context, categoryID uuid.UUID) error {
+	query, args, err := r.conn.Builder.
+		Delete("category_schedules").
+		Where("category_id = ?", categoryID).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build delete category schedule: %w", err)
+	}
+
+	res, err := r.conn.DB.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("delete category schedule: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("delete category schedule rows affected: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("delete category schedule: %w", sql.ErrNoRows)
+	}
+
+	return nil
+}
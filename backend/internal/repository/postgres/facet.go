@@ -0,0 +1,311 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/burbble/marketplace/internal/domain"
+)
+
+type priceStats struct {
+	Min   int `db:"min"`
+	Max   int `db:"max"`
+	Count int `db:"count"`
+}
+
+type attributeValueCount struct {
+	Name  string `db:"name"`
+	Value string `db:"value"`
+	Count int    `db:"count"`
+}
+
+type priceBucketCount struct {
+	Bucket int `db:"bucket"`
+	Count  int `db:"count"`
+}
+
+func (r *productRepo) GetFacets(ctx context.Context, filter domain.ProductFilter, priceBuckets int) (*domain.ProductFacets, error) {
+	withoutBrand := filter
+	withoutBrand.Brands = nil
+
+	withoutCategory := filter
+	withoutCategory.CategoryIDs = nil
+
+	withoutPrice := filter
+	withoutPrice.MinPrice = nil
+	withoutPrice.MaxPrice = nil
+
+	facets := &domain.ProductFacets{
+		Brands:     make([]domain.BrandFacet, 0),
+		Categories: make([]domain.CategoryFacet, 0),
+		Attributes: make([]domain.AttributeFacet, 0),
+		Histogram:  make([]domain.PriceBucket, 0),
+	}
+
+	run := func(db sqlx.QueryerContext) error {
+		if err := r.countFacet(ctx, db, filter, &facets.Total); err != nil {
+			return err
+		}
+		if err := r.brandFacets(ctx, db, withoutBrand, &facets.Brands); err != nil {
+			return err
+		}
+		if err := r.categoryFacets(ctx, db, withoutCategory, &facets.Categories); err != nil {
+			return err
+		}
+		if len(filter.CategoryIDs) > 0 {
+			if err := r.attributeFacets(ctx, db, filter, &facets.Attributes); err != nil {
+				return err
+			}
+		}
+
+		stats, err := r.priceStats(ctx, db, withoutPrice)
+		if err != nil {
+			return err
+		}
+		if stats.Count == 0 {
+			return nil
+		}
+
+		facets.Price = &domain.PriceRange{Min: stats.Min, Max: stats.Max}
+		facets.Histogram, err = r.priceHistogram(ctx, db, withoutPrice, stats, priceBuckets)
+		return err
+	}
+
+	var err error
+	if filter.FuzzySearch {
+		err = withTrigramThreshold(ctx, r.conn, func(tx *sqlx.Tx) error { return run(tx) })
+	} else {
+		err = run(r.conn.DB)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return facets, nil
+}
+
+func (r *productRepo) countFacet(ctx context.Context, db sqlx.QueryerContext, filter domain.ProductFilter, total *int) error {
+	query, args, err := r.conn.Builder.
+		Select("COUNT(*)").
+		From("products").
+		Where(buildProductWhere(filter)).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build count facet: %w", err)
+	}
+
+	if err := sqlx.GetContext(ctx, db, total, query, args...); err != nil {
+		return fmt.Errorf("count facet: %w", err)
+	}
+
+	return nil
+}
+
+func (r *productRepo) brandFacets(ctx context.Context, db sqlx.QueryerContext, filter domain.ProductFilter, brands *[]domain.BrandFacet) error {
+	query, args, err := r.conn.Builder.
+		Select("brand", "COUNT(*) AS count").
+		From("products").
+		Where(buildProductWhere(filter)).
+		Where("brand != ''").
+		GroupBy("brand").
+		OrderBy("count DESC", "brand ASC").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build brand facets: %w", err)
+	}
+
+	if err := sqlx.SelectContext(ctx, db, brands, query, args...); err != nil {
+		return fmt.Errorf("select brand facets: %w", err)
+	}
+
+	return nil
+}
+
+func (r *productRepo) categoryFacets(ctx context.Context, db sqlx.QueryerContext, filter domain.ProductFilter, categories *[]domain.CategoryFacet) error {
+	counts := r.conn.Builder.
+		Select("category_id", "COUNT(*) AS count").
+		From("products").
+		Where(buildProductWhere(filter)).
+		GroupBy("category_id")
+
+	query, args, err := r.conn.Builder.
+		Select("f.category_id", "c.name", "f.count").
+		FromSelect(counts, "f").
+		Join("categories c ON c.id = f.category_id").
+		OrderBy("f.count DESC", "c.name ASC").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build category facets: %w", err)
+	}
+
+	if err := sqlx.SelectContext(ctx, db, categories, query, args...); err != nil {
+		return fmt.Errorf("select category facets: %w", err)
+	}
+
+	return nil
+}
+
+// attributeFacets counts attribute values across the filtered products.
+// Attributes that are not filtered on are counted against the whole filter in
+// one query; each filtered attribute is counted with its own constraint
+// dropped.
+func (r *productRepo) attributeFacets(ctx context.Context, db sqlx.QueryerContext, filter domain.ProductFilter, attrs *[]domain.AttributeFacet) error {
+	filtered := make([]string, 0, len(filter.Attributes))
+	for name := range filter.Attributes {
+		filtered = append(filtered, name)
+	}
+	slices.Sort(filtered)
+
+	var counts []attributeValueCount
+
+	others, err := r.attributeValueCounts(ctx, db, filter, sq.NotEq{"a.key": filtered})
+	if err != nil {
+		return err
+	}
+	counts = append(counts, others...)
+
+	for _, name := range filtered {
+		without := filter
+		without.Attributes = maps.Clone(filter.Attributes)
+		delete(without.Attributes, name)
+
+		own, err := r.attributeValueCounts(ctx, db, without, sq.Eq{"a.key": name})
+		if err != nil {
+			return err
+		}
+		counts = append(counts, own...)
+	}
+
+	*attrs = buildAttributeFacets(counts)
+	return nil
+}
+
+// attributeValueCounts returns up to MaxAttributeFacetValues of the most
+// frequent values of every attribute matching keys.
+func (r *productRepo) attributeValueCounts(ctx context.Context, db sqlx.QueryerContext, filter domain.ProductFilter, keys sq.Sqlizer) ([]attributeValueCount, error) {
+	counts := r.conn.Builder.
+		Select(
+			"a.key AS name",
+			"a.value AS value",
+			"COUNT(*) AS count",
+			"ROW_NUMBER() OVER (PARTITION BY a.key ORDER BY COUNT(*) DESC, a.value ASC) AS rank",
+		).
+		From("products").
+		CrossJoin("LATERAL jsonb_each_text(products.attributes) AS a(key, value)").
+		Where(buildProductWhere(filter)).
+		Where(keys).
+		GroupBy("a.key", "a.value")
+
+	query, args, err := r.conn.Builder.
+		Select("name", "value", "count").
+		FromSelect(counts, "f").
+		Where(sq.LtOrEq{"rank": domain.MaxAttributeFacetValues}).
+		OrderBy("name ASC", "count DESC", "value ASC").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build attribute facets: %w", err)
+	}
+
+	var rows []attributeValueCount
+	if err := sqlx.SelectContext(ctx, db, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("select attribute facets: %w", err)
+	}
+
+	return rows, nil
+}
+
+// buildAttributeFacets groups value counts by attribute, ordering attributes
+// by name and values by count.
+func buildAttributeFacets(counts []attributeValueCount) []domain.AttributeFacet {
+	byName := make(map[string][]domain.AttributeValueCount)
+	for _, c := range counts {
+		byName[c.Name] = append(byName[c.Name], domain.AttributeValueCount{Value: c.Value, Count: c.Count})
+	}
+
+	facets := make([]domain.AttributeFacet, 0, len(byName))
+	for _, name := range slices.Sorted(maps.Keys(byName)) {
+		values := byName[name]
+		slices.SortStableFunc(values, func(a, b domain.AttributeValueCount) int {
+			return b.Count - a.Count
+		})
+		facets = append(facets, domain.AttributeFacet{Name: name, Values: values})
+	}
+
+	return facets
+}
+
+func (r *productRepo) priceStats(ctx context.Context, db sqlx.QueryerContext, filter domain.ProductFilter) (priceStats, error) {
+	var stats priceStats
+
+	query, args, err := r.conn.Builder.
+		Select("COALESCE(MIN(price), 0) AS min", "COALESCE(MAX(price), 0) AS max", "COUNT(*) AS count").
+		From("products").
+		Where(buildProductWhere(filter)).
+		ToSql()
+	if err != nil {
+		return stats, fmt.Errorf("build price stats: %w", err)
+	}
+
+	if err := sqlx.GetContext(ctx, db, &stats, query, args...); err != nil {
+		return stats, fmt.Errorf("get price stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+// priceHistogram splits [min, max] into equal-width buckets. Bucket edges are
+// integers, so the number of buckets never exceeds the width of the range.
+func (r *productRepo) priceHistogram(ctx context.Context, db sqlx.QueryerContext, filter domain.ProductFilter, stats priceStats, buckets int) ([]domain.PriceBucket, error) {
+	width := stats.Max - stats.Min
+	if width == 0 || buckets <= 1 {
+		return []domain.PriceBucket{{From: stats.Min, To: stats.Max, Count: stats.Count}}, nil
+	}
+	buckets = min(buckets, width)
+
+	query, args, err := r.conn.Builder.
+		Select().
+		Column(sq.Expr("LEAST((price::bigint - ?) * ? / ?, ?) AS bucket", stats.Min, buckets, width, buckets-1)).
+		Column("COUNT(*) AS count").
+		From("products").
+		Where(buildProductWhere(filter)).
+		GroupBy("bucket").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build price histogram: %w", err)
+	}
+
+	var counts []priceBucketCount
+	if err := sqlx.SelectContext(ctx, db, &counts, query, args...); err != nil {
+		return nil, fmt.Errorf("select price histogram: %w", err)
+	}
+
+	return buildPriceHistogram(stats.Min, stats.Max, buckets, counts), nil
+}
+
+// buildPriceHistogram lays out every bucket, including empty ones. A price p
+// falls into bucket (p-min)*n/width, so bucket i starts at the smallest price
+// mapping to it: min + ceil(i*width/n).
+func buildPriceHistogram(lo, hi, n int, counts []priceBucketCount) []domain.PriceBucket {
+	width := hi - lo
+	edge := func(i int) int {
+		return lo + (i*width+n-1)/n
+	}
+
+	histogram := make([]domain.PriceBucket, n)
+	for i := range histogram {
+		histogram[i] = domain.PriceBucket{From: edge(i), To: edge(i + 1)}
+	}
+
+	for _, c := range counts {
+		if c.Bucket >= 0 && c.Bucket < n {
+			histogram[c.Bucket].Count = c.Count
+		}
+	}
+
+	return histogram
+}
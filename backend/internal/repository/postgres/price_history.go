@@ -0,0 +1,128 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/burbble/marketplace/internal/domain"
+)
+
+type storedPrice struct {
+	Source        string `db:"source"`
+	ExternalID    string `db:"external_id"`
+	Price         int    `db:"price"`
+	OriginalPrice int    `db:"original_price"`
+}
+
+type priceChange struct {
+	ProductID     uuid.UUID
+	Price         int
+	OriginalPrice int
+}
+
+func (r *productRepo) currentPrices(ctx context.Context, tx *sqlx.Tx, products []domain.Product) (map[sourceKey]storedPrice, error) {
+	keys := make([]sourceKey, 0, len(products))
+	for _, p := range products {
+		keys = append(keys, sourceKey{p.Source, p.ExternalID})
+	}
+
+	query, args, err := r.conn.Builder.
+		Select("source", "external_id", "price", "original_price").
+		From("products").
+		Where(inSource("external_id", keys)).
+		Suffix("FOR UPDATE").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build select current prices: %w", err)
+	}
+
+	var rows []storedPrice
+	if err := tx.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("select current prices: %w", err)
+	}
+
+	prices := make(map[sourceKey]storedPrice, len(rows))
+	for _, row := range rows {
+		prices[sourceKey{row.Source, row.ExternalID}] = row
+	}
+
+	return prices, nil
+}
+
+func (r *productRepo) recordPriceChanges(ctx context.Context, tx *sqlx.Tx, changes []priceChange, at time.Time) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	q := r.conn.Builder.
+		Insert("product_price_history").
+		Columns("product_id", "price", "original_price", "recorded_at")
+
+	for _, c := range changes {
+		q = q.Values(c.ProductID, c.Price, c.OriginalPrice, at)
+	}
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return fmt.Errorf("build insert price history: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("exec insert price history: %w", err)
+	}
+
+	return nil
+}
+
+func (r *productRepo) GetPriceHistory(ctx context.Context, filter domain.PriceHistoryFilter) ([]domain.PricePoint, error) {
+	conds := sq.And{sq.Eq{"product_id": filter.ProductID}}
+	if filter.From != nil {
+		conds = append(conds, sq.GtOrEq{"recorded_at": *filter.From})
+	}
+	if filter.To != nil {
+		conds = append(conds, sq.Lt{"recorded_at": *filter.To})
+	}
+
+	var q sq.SelectBuilder
+	if filter.Interval == "" {
+		q = r.conn.Builder.
+			Select(
+				"recorded_at", "price", "original_price",
+				"price AS min_price", "price AS max_price",
+			).
+			From("product_price_history").
+			Where(conds).
+			OrderBy("recorded_at ASC", "id ASC")
+	} else {
+		q = r.conn.Builder.
+			Select().
+			Column(sq.Expr("date_trunc(?, recorded_at) AS recorded_at", filter.Interval)).
+			Columns(
+				"(array_agg(price ORDER BY recorded_at DESC, id DESC))[1] AS price",
+				"(array_agg(original_price ORDER BY recorded_at DESC, id DESC))[1] AS original_price",
+				"MIN(price) AS min_price",
+				"MAX(price) AS max_price",
+			).
+			From("product_price_history").
+			Where(conds).
+			GroupBy("1").
+			OrderBy("1 ASC")
+	}
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build select price history: %w", err)
+	}
+
+	points := make([]domain.PricePoint, 0)
+	if err := r.conn.DB.SelectContext(ctx, &points, query, args...); err != nil {
+		return nil, fmt.Errorf("select price history: %w", err)
+	}
+
+	return points, nil
+}
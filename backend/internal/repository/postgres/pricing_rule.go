@@ -0,0 +1,156 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/burbble/marketplace/internal/domain"
+	"github.com/burbble/marketplace/pkg/db"
+)
+
+var pricingRuleColumns = []string{
+	"id", "name", "priority", "enabled", "category_id", "brand", "min_price", "max_price",
+	"adjustment_type", "adjustment_value", "rounding_mode", "rounding_step", "rounding_ending",
+	"floor_price", "ceiling_price", "created_at", "updated_at",
+}
+
+type PricingRuleRepository interface {
+	GetAll(ctx context.Context) ([]domain.PricingRule, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.PricingRule, error)
+	Create(ctx context.Context, rule *domain.PricingRule) error
+	Update(ctx context.Context, rule *domain.PricingRule) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type pricingRuleRepo struct {
+	conn *db.Connection
+}
+
+func NewPricingRuleRepo(conn *db.Connection) PricingRuleRepository {
+	return &pricingRuleRepo{conn: conn}
+}
+
+func (r *pricingRuleRepo) GetAll(ctx context.Context) ([]domain.PricingRule, error) {
+	query, args, err := r.conn.Builder.
+		Select(pricingRuleColumns...).
+		From("pricing_rules").
+		OrderBy("priority DESC", "created_at ASC").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build select pricing rules: %w", err)
+	}
+
+	rules := make([]domain.PricingRule, 0)
+	if err := r.conn.DB.SelectContext(ctx, &rules, query, args...); err != nil {
+		return nil, fmt.Errorf("select pricing rules: %w", err)
+	}
+
+	return rules, nil
+}
+
+func (r *pricingRuleRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.PricingRule, error) {
+	query, args, err := r.conn.Builder.
+		Select(pricingRuleColumns...).
+		From("pricing_rules").
+		Where("id = ?", id).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build select pricing rule by id: %w", err)
+	}
+
+	var rule domain.PricingRule
+	if err := r.conn.DB.GetContext(ctx, &rule, query, args...); err != nil {
+		return nil, fmt.Errorf("get pricing rule by id: %w", err)
+	}
+
+	return &rule, nil
+}
+
+func (r *pricingRuleRepo) Create(ctx context.Context, rule *domain.PricingRule) error {
+	query, args, err := r.conn.Builder.
+		Insert("pricing_rules").
+		Columns(
+			"name", "priority", "enabled", "category_id", "brand", "min_price", "max_price",
+			"adjustment_type", "adjustment_value", "rounding_mode", "rounding_step", "rounding_ending",
+			"floor_price", "ceiling_price",
+		).
+		Values(
+			rule.Name, rule.Priority, rule.Enabled, rule.CategoryID, rule.Brand, rule.MinPrice, rule.MaxPrice,
+			rule.AdjustmentType, rule.AdjustmentValue, rule.RoundingMode, rule.RoundingStep, rule.RoundingEnding,
+			rule.FloorPrice, rule.CeilingPrice,
+		).
+		Suffix("RETURNING id, created_at, updated_at").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build insert pricing rule: %w", err)
+	}
+
+	if err := r.conn.DB.QueryRowxContext(ctx, query, args...).Scan(&rule.ID, &rule.CreatedAt, &rule.UpdatedAt); err != nil {
+		return fmt.Errorf("insert pricing rule: %w", err)
+	}
+
+	return nil
+}
+
+func (r *pricingRuleRepo) Update(ctx context.Context, rule *domain.PricingRule) error {
+	query, args, err := r.conn.Builder.
+		Update("pricing_rules").
+		SetMap(map[string]any{
+			"name":             rule.Name,
+			"priority":         rule.Priority,
+			"enabled":          rule.Enabled,
+			"category_id":      rule.CategoryID,
+			"brand":            rule.Brand,
+			"min_price":        rule.MinPrice,
+			"max_price":        rule.MaxPrice,
+			"adjustment_type":  rule.AdjustmentType,
+			"adjustment_value": rule.AdjustmentValue,
+			"rounding_mode":    rule.RoundingMode,
+			"rounding_step":    rule.RoundingStep,
+			"rounding_ending":  rule.RoundingEnding,
+			"floor_price":      rule.FloorPrice,
+			"ceiling_price":    rule.CeilingPrice,
+			"updated_at":       time.Now(),
+		}).
+		Where("id = ?", rule.ID).
+		Suffix("RETURNING created_at, updated_at").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build update pricing rule: %w", err)
+	}
+
+	if err := r.conn.DB.QueryRowxContext(ctx, query, args...).Scan(&rule.CreatedAt, &rule.UpdatedAt); err != nil {
+		return fmt.Errorf("update pricing rule: %w", err)
+	}
+
+	return nil
+}
+
+func (r *pricingRuleRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	query, args, err := r.conn.Builder.
+		Delete("pricing_rules").
+		Where("id = ?", id).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build delete pricing rule: %w", err)
+	}
+
+	res, err := r.conn.DB.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("delete pricing rule: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("delete pricing rule rows affected: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("delete pricing rule: %w", sql.ErrNoRows)
+	}
+
+	return nil
+}
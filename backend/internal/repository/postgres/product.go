@@ -2,21 +2,92 @@ package postgres
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 
 	"github.com/burbble/marketplace/internal/domain"
 	"github.com/burbble/marketplace/pkg/db"
+	"github.com/burbble/marketplace/pkg/pagination"
 )
 
+const (
+	searchQuery = "websearch_to_tsquery('russian', ?)"
+
+	// categorySubtreeQuery expands the given categories to themselves and all
+	// of their descendants.
+	categorySubtreeQuery = `WITH RECURSIVE subtree AS (
+		SELECT id FROM categories WHERE id = ANY(?)
+		UNION
+		SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+	) SELECT id FROM subtree`
+
+	// discountPercent is 0 for products without an original price.
+	discountPercent = "COALESCE((original_price - price) * 100.0 / NULLIF(original_price, 0), 0)"
+
+	// Highlights are HTML: the text is escaped before ts_headline wraps the
+	// matched words in <mark>, so scraped markup is never passed through.
+	headlineNameOptions    = "HighlightAll=true, StartSel=<mark>, StopSel=</mark>"
+	headlineSnippetOptions = "MaxFragments=2, MaxWords=20, MinWords=5, FragmentDelimiter= … , StartSel=<mark>, StopSel=</mark>"
+)
+
+var productColumns = []string{
+	"id", "source", "external_id", "sku", "name", "original_price", "price",
+	"image_url", "product_url", "brand", "description", "category_id", "group_id",
+	"available", "stock_status", "stock_label", "last_seen_at", "created_at", "updated_at",
+}
+
+// htmlEscaped returns an expression that escapes the column's text for HTML.
+// The search parser reads the entities as non-words, so escaping does not
+// change which words match.
+func htmlEscaped(column string) string {
+	expr := column
+	for _, r := range [][2]string{{"&", "&amp;"}, {"<", "&lt;"}, {">", "&gt;"}, {`"`, "&quot;"}, {"'", "&#39;"}} {
+		expr = fmt.Sprintf("replace(%s, '%s', '%s')", expr, strings.ReplaceAll(r[0], "'", "''"), r[1])
+	}
+	return expr
+}
+
+// sourceKey identifies a row by its source and the external id, slug or
+// key it has within that source.
+type sourceKey struct {
+	source string
+	key    string
+}
+
+// inSource matches rows whose source and the given column equal one of the
+// keys.
+func inSource(column string, keys []sourceKey) sq.Sqlizer {
+	sources := make([]string, 0, len(keys))
+	values := make([]string, 0, len(keys))
+	for _, k := range keys {
+		sources = append(sources, k.source)
+		values = append(values, k.key)
+	}
+
+	return sq.Expr("(source, "+column+") IN (SELECT * FROM unnest(?::text[], ?::text[]))", pq.Array(sources), pq.Array(values))
+}
+
 type ProductRepository interface {
-	Upsert(ctx context.Context, products []domain.Product) error
+	Upsert(ctx context.Context, products []domain.Product) (int, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.Product, error)
 	GetByFilter(ctx context.Context, filter domain.ProductFilter) (*domain.ProductList, error)
 	GetBrands(ctx context.Context) ([]string, error)
+	GetPriceHistory(ctx context.Context, filter domain.PriceHistoryFilter) ([]domain.PricePoint, error)
+	GetPrices(ctx context.Context) ([]domain.ProductPrice, error)
+	GetFacets(ctx context.Context, filter domain.ProductFilter, priceBuckets int) (*domain.ProductFacets, error)
+	MarkUnavailable(ctx context.Context, categoryID uuid.UUID, notSeenSince time.Time) (int64, error)
+	MarkSourcesUnavailable(ctx context.Context, enabled []string, notSeenSince time.Time) (int64, error)
+	SaveDetails(ctx context.Context, details []domain.ProductDetail) error
+	GetDetails(ctx context.Context, productID uuid.UUID) (*domain.ProductDetail, error)
+	ReindexAttributes(ctx context.Context, index func([]domain.ProductAttribute) map[string]string) (int, error)
 }
 
 type productRepo struct {
@@ -27,27 +98,32 @@ func NewProductRepo(conn *db.Connection) ProductRepository {
 	return &productRepo{conn: conn}
 }
 
-func (r *productRepo) Upsert(ctx context.Context, products []domain.Product) error {
+// Upsert stores the products and returns how many of them are new or changed
+// price. An empty description, left by a product page that failed or was
+// skipped, keeps the stored one.
+func (r *productRepo) Upsert(ctx context.Context, products []domain.Product) (int, error) {
 	if len(products) == 0 {
-		return nil
+		return 0, nil
 	}
 
 	q := r.conn.Builder.
 		Insert("products").
 		Columns(
-			"external_id", "sku", "name", "original_price", "price",
-			"image_url", "product_url", "brand", "description", "category_id", "updated_at",
+			"source", "external_id", "sku", "name", "original_price", "price",
+			"image_url", "product_url", "brand", "description", "category_id",
+			"available", "last_seen_at", "updated_at",
 		)
 
 	now := time.Now()
 	for _, p := range products {
 		q = q.Values(
-			p.ExternalID, p.SKU, p.Name, p.OriginalPrice, p.Price,
-			p.ImageURL, p.ProductURL, p.Brand, p.Description, p.CategoryID, now,
+			p.Source, p.ExternalID, p.SKU, p.Name, p.OriginalPrice, p.Price,
+			p.ImageURL, p.ProductURL, p.Brand, p.Description, p.CategoryID,
+			true, now, now,
 		)
 	}
 
-	q = q.Suffix(`ON CONFLICT (external_id) DO UPDATE SET
+	q = q.Suffix(`ON CONFLICT (source, external_id) DO UPDATE SET
 		sku = EXCLUDED.sku,
 		name = EXCLUDED.name,
 		original_price = EXCLUDED.original_price,
@@ -55,29 +131,72 @@ func (r *productRepo) Upsert(ctx context.Context, products []domain.Product) err
 		image_url = EXCLUDED.image_url,
 		product_url = EXCLUDED.product_url,
 		brand = EXCLUDED.brand,
-		description = EXCLUDED.description,
+		description = COALESCE(NULLIF(EXCLUDED.description, ''), products.description),
 		category_id = EXCLUDED.category_id,
+		available = EXCLUDED.available,
+		last_seen_at = EXCLUDED.last_seen_at,
 		updated_at = EXCLUDED.updated_at`)
 
+	q = q.Suffix("RETURNING id, source, external_id")
+
 	query, args, err := q.ToSql()
 	if err != nil {
-		return fmt.Errorf("build upsert products: %w", err)
+		return 0, fmt.Errorf("build upsert products: %w", err)
+	}
+
+	tx, err := r.conn.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin upsert products: %w", err)
 	}
+	defer func() { _ = tx.Rollback() }()
 
-	_, err = r.conn.DB.ExecContext(ctx, query, args...)
+	previous, err := r.currentPrices(ctx, tx, products)
 	if err != nil {
-		return fmt.Errorf("exec upsert products: %w", err)
+		return 0, err
+	}
+
+	var upserted []struct {
+		ID         uuid.UUID `db:"id"`
+		Source     string    `db:"source"`
+		ExternalID string    `db:"external_id"`
+	}
+	if err := tx.SelectContext(ctx, &upserted, query, args...); err != nil {
+		return 0, fmt.Errorf("exec upsert products: %w", err)
+	}
+
+	byExternalID := make(map[sourceKey]domain.Product, len(products))
+	for _, p := range products {
+		byExternalID[sourceKey{p.Source, p.ExternalID}] = p
+	}
+
+	changes := make([]priceChange, 0, len(upserted))
+	for _, u := range upserted {
+		key := sourceKey{u.Source, u.ExternalID}
+		p := byExternalID[key]
+		if prev, ok := previous[key]; ok && prev.Price == p.Price && prev.OriginalPrice == p.OriginalPrice {
+			continue
+		}
+		changes = append(changes, priceChange{
+			ProductID:     u.ID,
+			Price:         p.Price,
+			OriginalPrice: p.OriginalPrice,
+		})
+	}
+
+	if err := r.recordPriceChanges(ctx, tx, changes, now); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit upsert products: %w", err)
 	}
 
-	return nil
+	return len(changes), nil
 }
 
 func (r *productRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Product, error) {
 	query, args, err := r.conn.Builder.
-		Select(
-			"id", "external_id", "sku", "name", "original_price", "price",
-			"image_url", "product_url", "brand", "description", "category_id", "created_at", "updated_at",
-		).
+		Select(productColumns...).
 		From("products").
 		Where("id = ?", id).
 		ToSql()
@@ -95,6 +214,10 @@ func (r *productRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Produc
 
 func (r *productRepo) GetByFilter(ctx context.Context, filter domain.ProductFilter) (*domain.ProductList, error) {
 	where := buildProductWhere(filter)
+	if filter.CollapseGroups {
+		where = append(where, groupRepresentative(filter))
+	}
+	sortClauses := productSortClauses(filter)
 
 	countQ, countArgs, err := r.conn.Builder.
 		Select("COUNT(*)").
@@ -105,48 +228,133 @@ func (r *productRepo) GetByFilter(ctx context.Context, filter domain.ProductFilt
 		return nil, fmt.Errorf("build count products: %w", err)
 	}
 
-	var total int
-	if err := r.conn.DB.GetContext(ctx, &total, countQ, countArgs...); err != nil {
-		return nil, fmt.Errorf("count products: %w", err)
-	}
-
 	q := r.conn.Builder.
-		Select(
-			"id", "external_id", "sku", "name", "original_price", "price",
-			"image_url", "product_url", "brand", "description", "category_id", "created_at", "updated_at",
-		).
+		Select(productColumns...).
 		From("products").
 		Where(where).
-		Limit(filter.Limit).
-		Offset(filter.Offset)
+		Limit(filter.Limit)
 
-	if len(filter.SortBy) > 0 {
-		q = q.OrderBy(filter.SortBy...)
+	if filter.Cursor != nil {
+		after, err := buildCursorWhere(filter, sortClauses)
+		if err != nil {
+			return nil, err
+		}
+		q = q.Where(after)
 	} else {
-		q = q.OrderBy("created_at DESC")
+		q = q.Offset(filter.Offset)
+	}
+
+	if filter.Search != nil && *filter.Search != "" && !filter.FuzzySearch {
+		q = q.
+			Column(sq.Expr("ts_headline('russian', "+htmlEscaped("name")+", "+searchQuery+", ?) AS highlight_name", *filter.Search, headlineNameOptions)).
+			Column(sq.Expr("ts_headline('russian', "+htmlEscaped("description")+", "+searchQuery+", ?) AS highlight_snippet", *filter.Search, headlineSnippetOptions))
+	}
+	for _, clause := range sortClauses {
+		if field, _, _ := strings.Cut(clause, " "); field == domain.ProductSortRelevance {
+			expr, args := sortKeyExpr(field, filter)
+			q = q.Column(sq.Expr(expr+" AS rank", args...))
+		}
 	}
 
+	q = applyProductSort(q, filter, sortClauses)
+
 	dataQ, dataArgs, err := q.ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("build select products: %w", err)
 	}
 
+	total := domain.TotalSkipped
 	products := make([]domain.Product, 0)
-	if err := r.conn.DB.SelectContext(ctx, &products, dataQ, dataArgs...); err != nil {
-		return nil, fmt.Errorf("select products: %w", err)
+	run := func(db sqlx.QueryerContext) error {
+		if !filter.SkipTotal {
+			if err := sqlx.GetContext(ctx, db, &total, countQ, countArgs...); err != nil {
+				return fmt.Errorf("count products: %w", err)
+			}
+		}
+		if err := sqlx.SelectContext(ctx, db, &products, dataQ, dataArgs...); err != nil {
+			return fmt.Errorf("select products: %w", err)
+		}
+		return nil
 	}
 
-	page := uint64(1)
-	if filter.Limit > 0 {
-		page = filter.Offset/filter.Limit + 1
+	if filter.FuzzySearch {
+		err = withTrigramThreshold(ctx, r.conn, func(tx *sqlx.Tx) error { return run(tx) })
+	} else {
+		err = run(r.conn.DB)
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	return &domain.ProductList{
+	list := &domain.ProductList{
 		Products: products,
 		Total:    total,
-		Page:     page,
 		PageSize: filter.Limit,
-	}, nil
+	}
+
+	if filter.Cursor == nil {
+		list.Page = 1
+		if filter.Limit > 0 {
+			list.Page = filter.Offset/filter.Limit + 1
+		}
+	}
+
+	if filter.Limit > 0 && uint64(len(products)) == filter.Limit {
+		next, err := pagination.EncodeCursor(productCursor(products[len(products)-1], filter, sortClauses))
+		if err != nil {
+			return nil, fmt.Errorf("encode product cursor: %w", err)
+		}
+		list.NextCursor = next
+	}
+
+	return list, nil
+}
+
+// MarkUnavailable flags the category's products that no scrape has seen
+// since notSeenSince. Callers must only pass categories that were scraped
+// completely, otherwise products on skipped pages would be hidden.
+func (r *productRepo) MarkUnavailable(ctx context.Context, categoryID uuid.UUID, notSeenSince time.Time) (int64, error) {
+	query, args, err := r.conn.Builder.
+		Update("products").
+		Set("available", false).
+		Set("updated_at", time.Now()).
+		Where(sq.Eq{"category_id": categoryID, "available": true}).
+		Where(sq.Lt{"last_seen_at": notSeenSince}).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("build mark products unavailable: %w", err)
+	}
+
+	res, err := r.conn.DB.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("mark products unavailable: %w", err)
+	}
+
+	return res.RowsAffected()
+}
+
+// MarkSourcesUnavailable flags the products of every source not in enabled
+// that no scrape has seen since notSeenSince. A parser running elsewhere
+// with those sources keeps their products seen.
+func (r *productRepo) MarkSourcesUnavailable(ctx context.Context, enabled []string, notSeenSince time.Time) (int64, error) {
+	query, args, err := r.conn.Builder.
+		Update("products").
+		Set("available", false).
+		Set("updated_at", time.Now()).
+		Where(sq.Eq{"available": true}).
+		Where(sq.NotEq{"source": enabled}).
+		Where(sq.Lt{"last_seen_at": notSeenSince}).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("build mark source products unavailable: %w", err)
+	}
+
+	res, err := r.conn.DB.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("mark source products unavailable: %w", err)
+	}
+
+	return res.RowsAffected()
 }
 
 func (r *productRepo) GetBrands(ctx context.Context) ([]string, error) {
@@ -168,14 +376,39 @@ func (r *productRepo) GetBrands(ctx context.Context) ([]string, error) {
 	return brands, nil
 }
 
+func (r *productRepo) GetPrices(ctx context.Context) ([]domain.ProductPrice, error) {
+	query, args, err := r.conn.Builder.
+		Select("id", "name", "brand", "category_id", "original_price", "price").
+		From("products").
+		OrderBy("name ASC").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build select product prices: %w", err)
+	}
+
+	prices := make([]domain.ProductPrice, 0)
+	if err := r.conn.DB.SelectContext(ctx, &prices, query, args...); err != nil {
+		return nil, fmt.Errorf("select product prices: %w", err)
+	}
+
+	return prices, nil
+}
+
 func buildProductWhere(f domain.ProductFilter) sq.And {
 	var conds sq.And
 
-	if f.CategoryID != nil {
-		conds = append(conds, sq.Eq{"category_id": *f.CategoryID})
+	if !f.IncludeUnavailable {
+		conds = append(conds, sq.Eq{"available": true})
+	}
+
+	if len(f.CategoryIDs) > 0 {
+		conds = append(conds, sq.Expr("category_id IN ("+categorySubtreeQuery+")", pq.Array(f.CategoryIDs)))
 	}
-	if f.Brand != nil && *f.Brand != "" {
-		conds = append(conds, sq.Eq{"brand": *f.Brand})
+	if len(f.Brands) > 0 {
+		conds = append(conds, sq.Eq{"brand": f.Brands})
+	}
+	if len(f.ExcludeBrands) > 0 {
+		conds = append(conds, sq.NotEq{"brand": f.ExcludeBrands})
 	}
 	if f.MinPrice != nil {
 		conds = append(conds, sq.GtOrEq{"price": *f.MinPrice})
@@ -183,9 +416,170 @@ func buildProductWhere(f domain.ProductFilter) sq.And {
 	if f.MaxPrice != nil {
 		conds = append(conds, sq.LtOrEq{"price": *f.MaxPrice})
 	}
+	if f.OnSale {
+		conds = append(conds, sq.Expr("price < original_price"))
+	}
+	if f.MinDiscount != nil {
+		conds = append(conds, sq.Expr(discountPercent+" >= ?", *f.MinDiscount))
+	}
+	if f.MaxDiscount != nil {
+		conds = append(conds, sq.Expr(discountPercent+" <= ?", *f.MaxDiscount))
+	}
+	if f.UpdatedSince != nil {
+		conds = append(conds, sq.GtOrEq{"updated_at": *f.UpdatedSince})
+	}
+	conds = append(conds, attributeConds(f.Attributes)...)
 	if f.Search != nil && *f.Search != "" {
-		conds = append(conds, sq.ILike{"name": "%" + *f.Search + "%"})
+		if f.FuzzySearch {
+			conds = append(conds, sq.Expr("(? <% name OR ? <% brand)", *f.Search, *f.Search))
+		} else {
+			conds = append(conds, sq.Expr("search_vector @@ "+searchQuery, *f.Search))
+		}
+	}
+
+	return conds
+}
+
+// groupRepresentative keeps ungrouped products and, of every group, the
+// cheapest product matching the filter. The subquery repeats the filter
+// against its own products alias; unqualified columns resolve to it.
+func groupRepresentative(f domain.ProductFilter) sq.Sqlizer {
+	cheapest := sq.Select("g.id").
+		From("products g").
+		Where("g.group_id = products.group_id").
+		Where(buildProductWhere(f)).
+		OrderBy("g.price ASC", "g.id ASC").
+		Limit(1)
+
+	return sq.Or{
+		sq.Eq{"products.group_id": nil},
+		sq.Expr("products.id = (?)", cheapest),
+	}
+}
+
+// attributeConds matches products having, for every filtered attribute, one
+// of its values. Containment keeps the GIN index on attributes usable.
+func attributeConds(attrs map[string][]string) []sq.Sqlizer {
+	names := make([]string, 0, len(attrs))
+	for name := range attrs {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	conds := make([]sq.Sqlizer, 0, len(names))
+	for _, name := range names {
+		var anyOf sq.Or
+		for _, value := range attrs[name] {
+			doc, _ := json.Marshal(map[string]string{name: value})
+			anyOf = append(anyOf, sq.Expr("attributes @> ?::jsonb", string(doc)))
+		}
+		if len(anyOf) > 0 {
+			conds = append(conds, anyOf)
+		}
 	}
 
 	return conds
 }
+
+// productSortClauses returns the effective ORDER BY of a product list,
+// without the id tie-breaker that always follows it.
+func productSortClauses(f domain.ProductFilter) []string {
+	hasSearch := f.Search != nil && *f.Search != ""
+
+	clauses := make([]string, 0, len(f.SortBy))
+	for _, clause := range f.SortBy {
+		if field, _, _ := strings.Cut(clause, " "); field == domain.ProductSortRelevance && !hasSearch {
+			continue
+		}
+		clauses = append(clauses, clause)
+	}
+
+	if len(clauses) == 0 {
+		return []string{"created_at DESC"}
+	}
+
+	return clauses
+}
+
+func sortKeyExpr(field string, f domain.ProductFilter) (string, []any) {
+	if field != domain.ProductSortRelevance {
+		return field, nil
+	}
+	if f.FuzzySearch {
+		return "word_similarity(?, name)::float8", []any{*f.Search}
+	}
+
+	return "ts_rank(search_vector, " + searchQuery + ")::float8", []any{*f.Search}
+}
+
+func applyProductSort(q sq.SelectBuilder, f domain.ProductFilter, clauses []string) sq.SelectBuilder {
+	for _, clause := range clauses {
+		field, order, _ := strings.Cut(clause, " ")
+		expr, args := sortKeyExpr(field, f)
+		q = q.OrderByClause(expr+" "+order, args...)
+	}
+
+	return q.OrderBy("id ASC")
+}
+
+// buildCursorWhere selects the rows that sort strictly after the cursor:
+// (k1 > v1) OR (k1 = v1 AND k2 > v2) OR ... OR (k1 = v1 AND ... AND id > id0),
+// with < in place of > for descending keys.
+func buildCursorWhere(f domain.ProductFilter, clauses []string) (sq.Sqlizer, error) {
+	c := f.Cursor
+	if len(c.Values) != len(clauses) {
+		return nil, fmt.Errorf("cursor has %d sort values, want %d", len(c.Values), len(clauses))
+	}
+
+	var after sq.Or
+	var equal sq.And
+	for i, clause := range clauses {
+		field, order, _ := strings.Cut(clause, " ")
+		expr, args := sortKeyExpr(field, f)
+
+		op := " > ?"
+		if order == pagination.DESC {
+			op = " < ?"
+		}
+
+		after = append(after, append(slices.Clone(equal), sq.Expr(expr+op, append(slices.Clone(args), c.Values[i])...)))
+		equal = append(equal, sq.Expr(expr+" = ?", append(slices.Clone(args), c.Values[i])...))
+	}
+	after = append(after, append(slices.Clone(equal), sq.Gt{"id": c.ID}))
+
+	return after, nil
+}
+
+func productCursor(last domain.Product, f domain.ProductFilter, clauses []string) domain.ProductCursor {
+	cursor := domain.ProductCursor{
+		SortBy: clauses,
+		Values: make([]any, 0, len(clauses)),
+		ID:     last.ID,
+	}
+
+	for _, clause := range clauses {
+		field, _, _ := strings.Cut(clause, " ")
+		switch field {
+		case "name":
+			cursor.Values = append(cursor.Values, last.Name)
+		case "brand":
+			cursor.Values = append(cursor.Values, last.Brand)
+		case "price":
+			cursor.Values = append(cursor.Values, last.Price)
+		case "created_at":
+			cursor.Values = append(cursor.Values, last.CreatedAt)
+		case domain.ProductSortRelevance:
+			var rank float64
+			if last.Rank != nil {
+				rank = *last.Rank
+			}
+			cursor.Values = append(cursor.Values, rank)
+		}
+	}
+
+	if f.FuzzySearch {
+		cursor.FuzzySearch = f.Search
+	}
+
+	return cursor
+}
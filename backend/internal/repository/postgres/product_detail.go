@@ -0,0 +1,304 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/burbble/marketplace/internal/domain"
+)
+
+var productDetailTables = []string{"product_attributes", "product_images", "product_variants"}
+
+// reindexBatch is how many products ReindexAttributes updates per
+// transaction.
+const reindexBatch = 500
+
+// SaveDetails replaces the stock status, attributes, images and variants of
+// the products with the given sources and external ids, along with the attribute index
+// used for filtering. Details of unknown products are skipped.
+func (r *productRepo) SaveDetails(ctx context.Context, details []domain.ProductDetail) error {
+	if len(details) == 0 {
+		return nil
+	}
+
+	tx, err := r.conn.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin save product details: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	ids, err := r.productIDs(ctx, tx, details)
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	productIDs := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		productIDs = append(productIDs, id)
+	}
+
+	for _, table := range productDetailTables {
+		query, args, err := r.conn.Builder.
+			Delete(table).
+			Where(sq.Eq{"product_id": productIDs}).
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("build delete %s: %w", table, err)
+		}
+
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("delete %s: %w", table, err)
+		}
+	}
+
+	attrs := r.conn.Builder.Insert("product_attributes").Columns("product_id", "position", "attr_group", "name", "value")
+	images := r.conn.Builder.Insert("product_images").Columns("product_id", "position", "url")
+	variants := r.conn.Builder.Insert("product_variants").Columns("product_id", "position", "kind", "label", "url", "current")
+	var nAttrs, nImages, nVariants int
+
+	for _, d := range details {
+		id, ok := ids[sourceKey{d.Source, d.ExternalID}]
+		if !ok {
+			continue
+		}
+
+		index, err := json.Marshal(d.AttributeIndex)
+		if err != nil {
+			return fmt.Errorf("marshal product attribute index: %w", err)
+		}
+		if d.AttributeIndex == nil {
+			index = []byte("{}")
+		}
+
+		query, args, err := r.conn.Builder.
+			Update("products").
+			Set("stock_status", d.StockStatus).
+			Set("stock_label", d.StockLabel).
+			Set("attributes", string(index)).
+			Where("id = ?", id).
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("build update product stock: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("update product stock: %w", err)
+		}
+
+		for i, a := range d.Attributes {
+			attrs = attrs.Values(id, i, a.Group, a.Name, a.Value)
+			nAttrs++
+		}
+		for i, img := range d.Images {
+			images = images.Values(id, i, img.URL)
+			nImages++
+		}
+		for i, v := range d.Variants {
+			variants = variants.Values(id, i, v.Kind, v.Label, v.URL, v.Current)
+			nVariants++
+		}
+	}
+
+	inserts := []struct {
+		table string
+		rows  int
+		q     sq.InsertBuilder
+	}{
+		{"product_attributes", nAttrs, attrs},
+		{"product_images", nImages, images},
+		{"product_variants", nVariants, variants},
+	}
+	for _, ins := range inserts {
+		if ins.rows == 0 {
+			continue
+		}
+
+		query, args, err := ins.q.ToSql()
+		if err != nil {
+			return fmt.Errorf("build insert %s: %w", ins.table, err)
+		}
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("insert %s: %w", ins.table, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit save product details: %w", err)
+	}
+
+	return nil
+}
+
+func (r *productRepo) productIDs(ctx context.Context, tx *sqlx.Tx, details []domain.ProductDetail) (map[sourceKey]uuid.UUID, error) {
+	keys := make([]sourceKey, 0, len(details))
+	for _, d := range details {
+		keys = append(keys, sourceKey{d.Source, d.ExternalID})
+	}
+
+	query, args, err := r.conn.Builder.
+		Select("id", "source", "external_id").
+		From("products").
+		Where(inSource("external_id", keys)).
+		Suffix("FOR UPDATE").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build select product ids: %w", err)
+	}
+
+	var rows []struct {
+		ID         uuid.UUID `db:"id"`
+		Source     string    `db:"source"`
+		ExternalID string    `db:"external_id"`
+	}
+	if err := tx.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("select product ids: %w", err)
+	}
+
+	ids := make(map[sourceKey]uuid.UUID, len(rows))
+	for _, row := range rows {
+		ids[sourceKey{row.Source, row.ExternalID}] = row.ID
+	}
+
+	return ids, nil
+}
+
+// GetDetails returns the attributes, images and variants of a product.
+func (r *productRepo) GetDetails(ctx context.Context, productID uuid.UUID) (*domain.ProductDetail, error) {
+	d := &domain.ProductDetail{
+		Attributes: make([]domain.ProductAttribute, 0),
+		Images:     make([]domain.ProductImage, 0),
+		Variants:   make([]domain.ProductVariant, 0),
+	}
+
+	selects := []struct {
+		table   string
+		columns []string
+		dest    any
+	}{
+		{"product_attributes", []string{"attr_group", "name", "value"}, &d.Attributes},
+		{"product_images", []string{"url", "position"}, &d.Images},
+		{"product_variants", []string{"kind", "label", "url", "current"}, &d.Variants},
+	}
+	for _, s := range selects {
+		query, args, err := r.conn.Builder.
+			Select(s.columns...).
+			From(s.table).
+			Where("product_id = ?", productID).
+			OrderBy("position").
+			ToSql()
+		if err != nil {
+			return nil, fmt.Errorf("build select %s: %w", s.table, err)
+		}
+
+		if err := r.conn.DB.SelectContext(ctx, s.dest, query, args...); err != nil {
+			return nil, fmt.Errorf("select %s: %w", s.table, err)
+		}
+	}
+
+	return d, nil
+}
+
+// ReindexAttributes rebuilds the attribute index of every product from its
+// stored attributes with index, in batches of products. It returns the
+// number of products updated.
+func (r *productRepo) ReindexAttributes(ctx context.Context, index func([]domain.ProductAttribute) map[string]string) (int, error) {
+	var after uuid.UUID
+	total := 0
+	for {
+		n, last, err := r.reindexBatch(ctx, after, index)
+		if err != nil {
+			return total, err
+		}
+		if n == 0 {
+			return total, nil
+		}
+
+		total += n
+		after = last
+	}
+}
+
+// reindexBatch reindexes the next products with attributes after the given
+// product id and returns how many it updated and the last id.
+func (r *productRepo) reindexBatch(ctx context.Context, after uuid.UUID, index func([]domain.ProductAttribute) map[string]string) (int, uuid.UUID, error) {
+	query, args, err := r.conn.Builder.
+		Select("product_id").
+		From("product_attributes").
+		Where("product_id > ?", after).
+		GroupBy("product_id").
+		OrderBy("product_id").
+		Limit(reindexBatch).
+		ToSql()
+	if err != nil {
+		return 0, uuid.Nil, fmt.Errorf("build select products with attributes: %w", err)
+	}
+
+	var ids []uuid.UUID
+	if err := r.conn.DB.SelectContext(ctx, &ids, query, args...); err != nil {
+		return 0, uuid.Nil, fmt.Errorf("select products with attributes: %w", err)
+	}
+	if len(ids) == 0 {
+		return 0, uuid.Nil, nil
+	}
+
+	query, args, err = r.conn.Builder.
+		Select("product_id", "attr_group", "name", "value").
+		From("product_attributes").
+		Where(sq.Eq{"product_id": ids}).
+		OrderBy("product_id", "position").
+		ToSql()
+	if err != nil {
+		return 0, uuid.Nil, fmt.Errorf("build select product attributes: %w", err)
+	}
+
+	var rows []struct {
+		ProductID uuid.UUID `db:"product_id"`
+		domain.ProductAttribute
+	}
+	if err := r.conn.DB.SelectContext(ctx, &rows, query, args...); err != nil {
+		return 0, uuid.Nil, fmt.Errorf("select product attributes: %w", err)
+	}
+
+	attrs := make(map[uuid.UUID][]domain.ProductAttribute, len(ids))
+	for _, row := range rows {
+		attrs[row.ProductID] = append(attrs[row.ProductID], row.ProductAttribute)
+	}
+
+	tx, err := r.conn.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, uuid.Nil, fmt.Errorf("begin reindex product attributes: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, id := range ids {
+		doc, err := json.Marshal(index(attrs[id]))
+		if err != nil {
+			return 0, uuid.Nil, fmt.Errorf("marshal product attribute index: %w", err)
+		}
+
+		query, args, err := r.conn.Builder.
+			Update("products").
+			Set("attributes", string(doc)).
+			Where("id = ?", id).
+			ToSql()
+		if err != nil {
+			return 0, uuid.Nil, fmt.Errorf("build update product attributes: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return 0, uuid.Nil, fmt.Errorf("update product attributes: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, uuid.Nil, fmt.Errorf("commit reindex product attributes: %w", err)
+	}
+
+	return len(ids), ids[len(ids)-1], nil
+}
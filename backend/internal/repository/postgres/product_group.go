@@ -0,0 +1,191 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/burbble/marketplace/internal/domain"
+	"github.com/burbble/marketplace/pkg/db"
+)
+
+type ProductGroupRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.ProductGroup, error)
+	GetMembers(ctx context.Context) ([]domain.ProductGroupMember, error)
+	Assign(ctx context.Context, groups []domain.ProductGroupAssignment) error
+}
+
+type productGroupRepo struct {
+	conn *db.Connection
+}
+
+func NewProductGroupRepo(conn *db.Connection) ProductGroupRepository {
+	return &productGroupRepo{conn: conn}
+}
+
+// GetByID returns the group with all of its variants, cheapest first.
+func (r *productGroupRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.ProductGroup, error) {
+	query, args, err := r.conn.Builder.
+		Select("id", "name", "brand").
+		From("product_groups").
+		Where("id = ?", id).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build select product group by id: %w", err)
+	}
+
+	var g domain.ProductGroup
+	if err := r.conn.DB.GetContext(ctx, &g, query, args...); err != nil {
+		return nil, fmt.Errorf("get product group by id: %w", err)
+	}
+
+	query, args, err = r.conn.Builder.
+		Select(productColumns...).
+		From("products").
+		Where("group_id = ?", id).
+		OrderBy("price ASC", "id ASC").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build select product group variants: %w", err)
+	}
+
+	g.Variants = make([]domain.Product, 0)
+	if err := r.conn.DB.SelectContext(ctx, &g.Variants, query, args...); err != nil {
+		return nil, fmt.Errorf("select product group variants: %w", err)
+	}
+
+	return &g, nil
+}
+
+// GetMembers returns every product with the product pages its variant links
+// point to.
+func (r *productGroupRepo) GetMembers(ctx context.Context) ([]domain.ProductGroupMember, error) {
+	query, args, err := r.conn.Builder.
+		Select(
+			"p.id", "p.source", "p.brand", "p.name", "p.product_url",
+			"COALESCE(array_agg(v.url) FILTER (WHERE v.url IS NOT NULL), '{}') AS variant_urls",
+		).
+		From("products p").
+		LeftJoin("product_variants v ON v.product_id = p.id").
+		GroupBy("p.id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build select product group members: %w", err)
+	}
+
+	var rows []struct {
+		ID          uuid.UUID      `db:"id"`
+		Source      string         `db:"source"`
+		Brand       string         `db:"brand"`
+		Name        string         `db:"name"`
+		ProductURL  string         `db:"product_url"`
+		VariantURLs pq.StringArray `db:"variant_urls"`
+	}
+	if err := r.conn.DB.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("select product group members: %w", err)
+	}
+
+	members := make([]domain.ProductGroupMember, 0, len(rows))
+	for _, row := range rows {
+		members = append(members, domain.ProductGroupMember{
+			ProductID:   row.ID,
+			Source:      row.Source,
+			Brand:       row.Brand,
+			Name:        row.Name,
+			ProductURL:  row.ProductURL,
+			VariantURLs: row.VariantURLs,
+		})
+	}
+
+	return members, nil
+}
+
+// Assign replaces the grouping of the whole catalog. Groups are matched to
+// existing ones by source and key, so group ids survive regrouping; products left out
+// lose their group and groups left without products are deleted.
+func (r *productGroupRepo) Assign(ctx context.Context, groups []domain.ProductGroupAssignment) error {
+	sources := make([]string, 0, len(groups))
+	keys := make([]string, 0, len(groups))
+	names := make([]string, 0, len(groups))
+	brands := make([]string, 0, len(groups))
+	for _, g := range groups {
+		sources = append(sources, g.Source)
+		keys = append(keys, g.Key)
+		names = append(names, g.Name)
+		brands = append(brands, g.Brand)
+	}
+
+	tx, err := r.conn.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin assign product groups: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// Existing groups take the name of the latest grouping.
+	query := `INSERT INTO product_groups (source, key, name, brand)
+		SELECT * FROM unnest($1::text[], $2::text[], $3::text[], $4::text[])
+		ON CONFLICT (source, key) DO UPDATE SET
+			name = EXCLUDED.name,
+			brand = EXCLUDED.brand,
+			updated_at = now()
+		RETURNING id, source, key`
+
+	var upserted []struct {
+		ID     uuid.UUID `db:"id"`
+		Source string    `db:"source"`
+		Key    string    `db:"key"`
+	}
+	if err := tx.SelectContext(ctx, &upserted, query, pq.Array(sources), pq.Array(keys), pq.Array(names), pq.Array(brands)); err != nil {
+		return fmt.Errorf("upsert product groups: %w", err)
+	}
+
+	ids := make(map[sourceKey]uuid.UUID, len(upserted))
+	for _, u := range upserted {
+		ids[sourceKey{u.Source, u.Key}] = u.ID
+	}
+
+	var productIDs, groupIDs []uuid.UUID
+	for _, g := range groups {
+		for _, pid := range g.ProductIDs {
+			productIDs = append(productIDs, pid)
+			groupIDs = append(groupIDs, ids[sourceKey{g.Source, g.Key}])
+		}
+	}
+
+	statements := []struct {
+		what  string
+		query string
+		args  []any
+	}{
+		{
+			"set product groups",
+			`UPDATE products p SET group_id = a.group_id
+			FROM unnest($1::uuid[], $2::uuid[]) AS a(product_id, group_id)
+			WHERE p.id = a.product_id AND p.group_id IS DISTINCT FROM a.group_id`,
+			[]any{pq.Array(productIDs), pq.Array(groupIDs)},
+		},
+		{
+			"clear product groups",
+			`UPDATE products SET group_id = NULL WHERE group_id IS NOT NULL AND NOT (id = ANY($1::uuid[]))`,
+			[]any{pq.Array(productIDs)},
+		},
+		{
+			"delete empty product groups",
+			`DELETE FROM product_groups g WHERE NOT EXISTS (SELECT 1 FROM products p WHERE p.group_id = g.id)`,
+			nil,
+		},
+	}
+	for _, st := range statements {
+		if _, err := tx.ExecContext(ctx, st.query, st.args...); err != nil {
+			return fmt.Errorf("%s: %w", st.what, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit assign product groups: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,327 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/burbble/marketplace/internal/domain"
+	"github.com/burbble/marketplace/pkg/db"
+)
+
+var productMatchColumns = []string{
+	"m.id", "m.product_id", "m.matched_id", "m.confidence", "m.method", "m.status", "m.created_at", "m.updated_at",
+	"p.name AS product_name", "p.source AS product_source", "o.name AS matched_name", "o.source AS matched_source",
+}
+
+type ProductMatchRepository interface {
+	GetCandidates(ctx context.Context) ([]domain.MatchCandidate, error)
+	GetDecided(ctx context.Context) ([]domain.ProductMatch, error)
+	ReplaceSuggestions(ctx context.Context, matches []domain.ProductMatch) error
+	GetList(ctx context.Context, filter domain.ProductMatchFilter) (*domain.ProductMatchList, error)
+	SetStatus(ctx context.Context, id uuid.UUID, status string) (*domain.ProductMatch, error)
+	GetOffers(ctx context.Context, productID uuid.UUID) ([]domain.ProductOffer, error)
+}
+
+type productMatchRepo struct {
+	conn *db.Connection
+}
+
+func NewProductMatchRepo(conn *db.Connection) ProductMatchRepository {
+	return &productMatchRepo{conn: conn}
+}
+
+// GetCandidates returns every product in the form matching compares them.
+func (r *productMatchRepo) GetCandidates(ctx context.Context) ([]domain.MatchCandidate, error) {
+	query, args, err := r.conn.Builder.
+		Select("id", "source", "sku", "brand", "name").
+		From("products").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build select match candidates: %w", err)
+	}
+
+	var candidates []domain.MatchCandidate
+	if err := r.conn.DB.SelectContext(ctx, &candidates, query, args...); err != nil {
+		return nil, fmt.Errorf("select match candidates: %w", err)
+	}
+
+	return candidates, nil
+}
+
+// GetDecided returns the confirmed and rejected matches.
+func (r *productMatchRepo) GetDecided(ctx context.Context) ([]domain.ProductMatch, error) {
+	query, args, err := r.conn.Builder.
+		Select("id", "product_id", "matched_id", "confidence", "method", "status", "created_at", "updated_at").
+		From("product_matches").
+		Where(sq.NotEq{"status": domain.ProductMatchSuggested}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build select decided product matches: %w", err)
+	}
+
+	var matches []domain.ProductMatch
+	if err := r.conn.DB.SelectContext(ctx, &matches, query, args...); err != nil {
+		return nil, fmt.Errorf("select decided product matches: %w", err)
+	}
+
+	return matches, nil
+}
+
+// ReplaceSuggestions makes matches the suggested matches: pairs already
+// suggested keep their id and take the new confidence, suggestions left out
+// are deleted. Confirmed and rejected matches are not touched.
+func (r *productMatchRepo) ReplaceSuggestions(ctx context.Context, matches []domain.ProductMatch) error {
+	productIDs := make([]uuid.UUID, 0, len(matches))
+	matchedIDs := make([]uuid.UUID, 0, len(matches))
+	confidences := make([]float64, 0, len(matches))
+	methods := make([]string, 0, len(matches))
+	for _, m := range matches {
+		productIDs = append(productIDs, m.ProductID)
+		matchedIDs = append(matchedIDs, m.MatchedID)
+		confidences = append(confidences, m.Confidence)
+		methods = append(methods, m.Method)
+	}
+
+	tx, err := r.conn.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin replace product matches: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	statements := []struct {
+		what  string
+		query string
+		args  []any
+	}{
+		{
+			"upsert product matches",
+			`INSERT INTO product_matches (product_id, matched_id, confidence, method)
+			SELECT * FROM unnest($1::uuid[], $2::uuid[], $3::real[], $4::text[])
+			ON CONFLICT (product_id, matched_id) DO UPDATE SET
+				confidence = EXCLUDED.confidence,
+				method = EXCLUDED.method,
+				updated_at = now()
+			WHERE product_matches.status = 'suggested'`,
+			[]any{pq.Array(productIDs), pq.Array(matchedIDs), pq.Array(confidences), pq.Array(methods)},
+		},
+		{
+			"delete stale product matches",
+			`DELETE FROM product_matches
+			WHERE status = 'suggested'
+				AND (product_id, matched_id) NOT IN (SELECT * FROM unnest($1::uuid[], $2::uuid[]))`,
+			[]any{pq.Array(productIDs), pq.Array(matchedIDs)},
+		},
+	}
+	for _, st := range statements {
+		if _, err := tx.ExecContext(ctx, st.query, st.args...); err != nil {
+			return fmt.Errorf("%s: %w", st.what, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit replace product matches: %w", err)
+	}
+
+	return nil
+}
+
+// GetList returns matches with the names and sources of both products, the
+// most confident first.
+func (r *productMatchRepo) GetList(ctx context.Context, filter domain.ProductMatchFilter) (*domain.ProductMatchList, error) {
+	var where sq.And
+	if filter.Status != nil {
+		where = append(where, sq.Eq{"m.status": *filter.Status})
+	}
+	if filter.ProductID != nil {
+		where = append(where, sq.Or{sq.Eq{"m.product_id": *filter.ProductID}, sq.Eq{"m.matched_id": *filter.ProductID}})
+	}
+
+	countQ, countArgs, err := r.conn.Builder.
+		Select("COUNT(*)").
+		From("product_matches m").
+		Where(where).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build count product matches: %w", err)
+	}
+
+	var total int
+	if err := r.conn.DB.GetContext(ctx, &total, countQ, countArgs...); err != nil {
+		return nil, fmt.Errorf("count product matches: %w", err)
+	}
+
+	query, args, err := r.selectMatches().
+		Where(where).
+		OrderBy("m.confidence DESC", "m.id").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build select product matches: %w", err)
+	}
+
+	matches := make([]domain.ProductMatch, 0)
+	if err := r.conn.DB.SelectContext(ctx, &matches, query, args...); err != nil {
+		return nil, fmt.Errorf("select product matches: %w", err)
+	}
+
+	page := uint64(1)
+	if filter.Limit > 0 {
+		page = filter.Offset/filter.Limit + 1
+	}
+
+	return &domain.ProductMatchList{
+		Matches:  matches,
+		Total:    total,
+		Page:     page,
+		PageSize: filter.Limit,
+	}, nil
+}
+
+// SetStatus records an admin's decision on a match and returns the match. A
+// product has at most one confirmed match per shop, so confirming a match
+// whose products are already confirmed with others of those shops fails with
+// domain.ErrProductMatchConflict.
+func (r *productMatchRepo) SetStatus(ctx context.Context, id uuid.UUID, status string) (*domain.ProductMatch, error) {
+	tx, err := r.conn.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin set product match status: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if status == domain.ProductMatchConfirmed {
+		if err := r.checkConfirmable(ctx, tx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	query, args, err := r.conn.Builder.
+		Update("product_matches").
+		Set("status", status).
+		Set("updated_at", sq.Expr("now()")).
+		Where("id = ?", id).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build update product match status: %w", err)
+	}
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("update product match status: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	query, args, err = r.selectMatches().Where("m.id = ?", id).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build select product match: %w", err)
+	}
+
+	var m domain.ProductMatch
+	if err := tx.GetContext(ctx, &m, query, args...); err != nil {
+		return nil, fmt.Errorf("get product match: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit set product match status: %w", err)
+	}
+
+	return &m, nil
+}
+
+// checkConfirmable fails when either product of the match already has
+// another confirmed match with a product of the other one's shop. Both
+// products are locked first, so confirmations sharing a product take turns.
+func (r *productMatchRepo) checkConfirmable(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) error {
+	query, args, err := r.selectMatches().Where("m.id = ?", id).ToSql()
+	if err != nil {
+		return fmt.Errorf("build select product match: %w", err)
+	}
+
+	var m domain.ProductMatch
+	if err := tx.GetContext(ctx, &m, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		return fmt.Errorf("get product match: %w", err)
+	}
+
+	query, args, err = r.conn.Builder.
+		Select("id").
+		From("products").
+		Where(sq.Eq{"id": []uuid.UUID{m.ProductID, m.MatchedID}}).
+		OrderBy("id").
+		Suffix("FOR UPDATE").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build lock matched products: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("lock matched products: %w", err)
+	}
+
+	var taken bool
+	err = tx.GetContext(ctx, &taken,
+		`SELECT EXISTS (
+			SELECT 1 FROM product_matches m
+			JOIN products p ON p.id = m.product_id
+			JOIN products o ON o.id = m.matched_id
+			WHERE m.status = 'confirmed' AND m.id <> $1 AND (
+				(m.product_id = $2 AND o.source = $5) OR (m.matched_id = $2 AND p.source = $5) OR
+				(m.product_id = $3 AND o.source = $4) OR (m.matched_id = $3 AND p.source = $4)
+			)
+		)`,
+		id, m.ProductID, m.MatchedID, m.ProductSource, m.MatchedSource,
+	)
+	if err != nil {
+		return fmt.Errorf("check confirmed product matches: %w", err)
+	}
+	if taken {
+		return domain.ErrProductMatchConflict
+	}
+
+	return nil
+}
+
+func (r *productMatchRepo) selectMatches() sq.SelectBuilder {
+	return r.conn.Builder.
+		Select(productMatchColumns...).
+		From("product_matches m").
+		Join("products p ON p.id = m.product_id").
+		Join("products o ON o.id = m.matched_id")
+}
+
+// GetOffers returns the products matched to the product, except rejected
+// matches, available and cheapest first.
+func (r *productMatchRepo) GetOffers(ctx context.Context, productID uuid.UUID) ([]domain.ProductOffer, error) {
+	query, args, err := r.conn.Builder.
+		Select(
+			"p.id", "p.source", "p.name", "p.price", "p.original_price", "p.product_url",
+			"p.available", "p.stock_status", "p.last_seen_at",
+			"m.id AS match_id", "m.confidence", "m.status AS match_status",
+		).
+		From("product_matches m").
+		Join("products p ON p.id = CASE WHEN m.product_id = ? THEN m.matched_id ELSE m.product_id END", productID).
+		Where(sq.Or{sq.Eq{"m.product_id": productID}, sq.Eq{"m.matched_id": productID}}).
+		Where(sq.NotEq{"m.status": domain.ProductMatchRejected}).
+		OrderBy("p.available DESC", "p.price ASC", "p.id ASC").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build select product offers: %w", err)
+	}
+
+	offers := make([]domain.ProductOffer, 0)
+	if err := r.conn.DB.SelectContext(ctx, &offers, query, args...); err != nil {
+		return nil, fmt.Errorf("select product offers: %w", err)
+	}
+
+	return offers, nil
+}
@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/burbble/marketplace/internal/domain"
+)
+
+func TestProductMatchRepo_SetStatus_ConfirmsOneMatchPerShop(t *testing.T) {
+	id := uuid.New()
+	match := []driver.Value{
+		id.String(), uuid.New().String(), uuid.New().String(), 0.9, domain.MatchMethodSKU, domain.ProductMatchSuggested,
+		time.Now(), time.Now(), "iPhone 15", "store77", "iPhone 15", "other",
+	}
+
+	for _, taken := range []bool{true, false} {
+		rec := &recordingConnector{
+			answer: func(query string) ([]string, [][]driver.Value) {
+				switch {
+				case strings.HasPrefix(query, "SELECT EXISTS"):
+					return []string{"exists"}, [][]driver.Value{{taken}}
+				case strings.Contains(query, "FROM product_matches m JOIN"):
+					return []string{
+						"id", "product_id", "matched_id", "confidence", "method", "status", "created_at", "updated_at",
+						"product_name", "product_source", "matched_name", "matched_source",
+					}, [][]driver.Value{match}
+				}
+				return nil, nil
+			},
+		}
+
+		repo := NewProductMatchRepo(newRecordingConnection(rec))
+		_, err := repo.SetStatus(context.Background(), id, domain.ProductMatchConfirmed)
+
+		if locks := rec.sent("SELECT id FROM products"); len(locks) != 1 || !strings.HasSuffix(locks[0], "FOR UPDATE") {
+			t.Errorf("expected the matched products to be locked, got %v", rec.queries)
+		}
+		updates := rec.sent("UPDATE product_matches")
+		if taken {
+			if !errors.Is(err, domain.ErrProductMatchConflict) {
+				t.Errorf("expected a conflict, got %v", err)
+			}
+			if len(updates) != 0 {
+				t.Errorf("expected no update, got %v", updates)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if len(updates) != 1 {
+			t.Errorf("expected the match to be updated, got %v", rec.queries)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/burbble/marketplace/internal/domain"
+	"github.com/burbble/marketplace/pkg/db"
+)
+
+// recordingConnector is a database/sql driver that remembers the statements
+// it gets, for checking the SQL a repository sends without a database.
+// Queries are answered by answer when it returns columns, and with no rows
+// otherwise; statements affect one row.
+type recordingConnector struct {
+	mu      sync.Mutex
+	queries []string
+	answer  func(query string) (columns []string, rows [][]driver.Value)
+}
+
+func (c *recordingConnector) Connect(_ context.Context) (driver.Conn, error) {
+	return recordingConn{c}, nil
+}
+
+func (c *recordingConnector) Driver() driver.Driver { return nil }
+
+func (c *recordingConnector) record(query string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.queries = append(c.queries, query)
+}
+
+type recordingConn struct{ c *recordingConnector }
+
+func (recordingConn) Prepare(_ string) (driver.Stmt, error) { return nil, driver.ErrSkip }
+func (recordingConn) Close() error                          { return nil }
+func (recordingConn) Begin() (driver.Tx, error)             { return recordingTx{}, nil }
+
+func (conn recordingConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	conn.c.record(query)
+	if conn.c.answer != nil {
+		if columns, rows := conn.c.answer(query); columns != nil {
+			return &cannedRows{columns: columns, rows: rows}, nil
+		}
+	}
+	return emptyRows{}, nil
+}
+
+func (conn recordingConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	conn.c.record(query)
+	return driver.RowsAffected(1), nil
+}
+
+type recordingTx struct{}
+
+func (recordingTx) Commit() error   { return nil }
+func (recordingTx) Rollback() error { return nil }
+
+type emptyRows struct{}
+
+func (emptyRows) Columns() []string           { return nil }
+func (emptyRows) Close() error                { return nil }
+func (emptyRows) Next(_ []driver.Value) error { return io.EOF }
+
+type cannedRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *cannedRows) Columns() []string { return r.columns }
+func (r *cannedRows) Close() error      { return nil }
+
+func (r *cannedRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+func newRecordingConnection(rec *recordingConnector) *db.Connection {
+	return &db.Connection{
+		DB:      sqlx.NewDb(sql.OpenDB(rec), "postgres"),
+		Builder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+// sent returns the recorded statements starting with prefix.
+func (c *recordingConnector) sent(prefix string) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var found []string
+	for _, q := range c.queries {
+		if strings.HasPrefix(q, prefix) {
+			found = append(found, q)
+		}
+	}
+	return found
+}
+
+func TestProductRepo_Upsert_KeepsStoredDescription(t *testing.T) {
+	rec := &recordingConnector{}
+
+	repo := NewProductRepo(newRecordingConnection(rec))
+	if _, err := repo.Upsert(context.Background(), []domain.Product{{Source: "store77", ExternalID: "1", Name: "iPhone 15"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, q := range rec.queries {
+		if strings.HasPrefix(q, "INSERT INTO products") {
+			if !strings.Contains(q, "description = COALESCE(NULLIF(EXCLUDED.description, ''), products.description)") {
+				t.Errorf("expected an empty description to keep the stored one, got %s", q)
+			}
+			return
+		}
+	}
+	t.Fatalf("expected a products upsert, got %v", rec.queries)
+}
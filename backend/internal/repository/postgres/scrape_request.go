@@ -0,0 +1,252 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/burbble/marketplace/internal/domain"
+	"github.com/burbble/marketplace/pkg/db"
+)
+
+var scrapeRequestColumns = []string{
+	"id", "status", "category_ids", "full_refresh", "run_id", "cancel_requested", "lease_expires_at",
+	"error", "created_at", "started_at", "finished_at",
+}
+
+type ScrapeRequestRepository interface {
+	Create(ctx context.Context, req *domain.ScrapeRequest) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.ScrapeRequest, error)
+	Cancel(ctx context.Context, id uuid.UUID) (*domain.ScrapeRequest, error)
+	ClaimPending(ctx context.Context, lease time.Duration) (*domain.ScrapeRequest, error)
+	SetRun(ctx context.Context, id, runID uuid.UUID) error
+	Heartbeat(ctx context.Context, id uuid.UUID, lease time.Duration) (bool, error)
+	Finish(ctx context.Context, req *domain.ScrapeRequest) error
+	FailExpired(ctx context.Context, reason string) (int64, error)
+}
+
+type scrapeRequestRepo struct {
+	conn *db.Connection
+}
+
+func NewScrapeRequestRepo(conn *db.Connection) ScrapeRequestRepository {
+	return &scrapeRequestRepo{conn: conn}
+}
+
+// scrapeRequestRow is a scrape_requests row; category_ids needs pq to scan.
+type scrapeRequestRow struct {
+	ID              uuid.UUID      `db:"id"`
+	Status          string         `db:"status"`
+	CategoryIDs     pq.StringArray `db:"category_ids"`
+	FullRefresh     bool           `db:"full_refresh"`
+	RunID           *uuid.UUID     `db:"run_id"`
+	CancelRequested bool           `db:"cancel_requested"`
+	LeaseExpiresAt  *time.Time     `db:"lease_expires_at"`
+	Error           *string        `db:"error"`
+	CreatedAt       time.Time      `db:"created_at"`
+	StartedAt       *time.Time     `db:"started_at"`
+	FinishedAt      *time.Time     `db:"finished_at"`
+}
+
+func (row scrapeRequestRow) toDomain() (*domain.ScrapeRequest, error) {
+	ids := make([]uuid.UUID, 0, len(row.CategoryIDs))
+	for _, s := range row.CategoryIDs {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return nil, fmt.Errorf("parse scrape request category id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	return &domain.ScrapeRequest{
+		ID:              row.ID,
+		Status:          row.Status,
+		CategoryIDs:     ids,
+		FullRefresh:     row.FullRefresh,
+		RunID:           row.RunID,
+		CancelRequested: row.CancelRequested,
+		LeaseExpiresAt:  row.LeaseExpiresAt,
+		Error:           row.Error,
+		CreatedAt:       row.CreatedAt,
+		StartedAt:       row.StartedAt,
+		FinishedAt:      row.FinishedAt,
+	}, nil
+}
+
+func (r *scrapeRequestRepo) get(ctx context.Context, what, query string, args []any) (*domain.ScrapeRequest, error) {
+	var row scrapeRequestRow
+	if err := r.conn.DB.GetContext(ctx, &row, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: %w", what, err)
+	}
+
+	return row.toDomain()
+}
+
+func (r *scrapeRequestRepo) Create(ctx context.Context, req *domain.ScrapeRequest) error {
+	ids := make([]string, 0, len(req.CategoryIDs))
+	for _, id := range req.CategoryIDs {
+		ids = append(ids, id.String())
+	}
+
+	query, args, err := r.conn.Builder.
+		Insert("scrape_requests").
+		Columns("status", "category_ids", "full_refresh").
+		Values(domain.ScrapeRequestPending, pq.StringArray(ids), req.FullRefresh).
+		Suffix("RETURNING id, status, created_at").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build insert scrape request: %w", err)
+	}
+
+	if err := r.conn.DB.QueryRowxContext(ctx, query, args...).Scan(&req.ID, &req.Status, &req.CreatedAt); err != nil {
+		return fmt.Errorf("insert scrape request: %w", err)
+	}
+
+	return nil
+}
+
+func (r *scrapeRequestRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.ScrapeRequest, error) {
+	query, args, err := r.conn.Builder.
+		Select(scrapeRequestColumns...).
+		From("scrape_requests").
+		Where("id = ?", id).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build select scrape request by id: %w", err)
+	}
+
+	return r.get(ctx, "get scrape request by id", query, args)
+}
+
+// Cancel cancels a pending request, or a running one whose lease expired,
+// outright and flags a running one for the parser to stop. It returns
+// sql.ErrNoRows if the request is not pending or running.
+func (r *scrapeRequestRepo) Cancel(ctx context.Context, id uuid.UUID) (*domain.ScrapeRequest, error) {
+	query, args, err := r.conn.Builder.
+		Update("scrape_requests").
+		Set("cancel_requested", true).
+		Set("status", sq.Expr("CASE WHEN status = ? OR lease_expires_at < now() THEN ? ELSE status END", domain.ScrapeRequestPending, domain.ScrapeRequestCancelled)).
+		Set("finished_at", sq.Expr("CASE WHEN status = ? OR lease_expires_at < now() THEN now() ELSE finished_at END", domain.ScrapeRequestPending)).
+		Where("id = ?", id).
+		Where("status IN (?, ?)", domain.ScrapeRequestPending, domain.ScrapeRequestRunning).
+		Suffix("RETURNING " + strings.Join(scrapeRequestColumns, ", ")).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build cancel scrape request: %w", err)
+	}
+
+	return r.get(ctx, "cancel scrape request", query, args)
+}
+
+// ClaimPending marks the oldest pending request as running, leased for
+// lease, and returns it. It returns sql.ErrNoRows when there is none.
+func (r *scrapeRequestRepo) ClaimPending(ctx context.Context, lease time.Duration) (*domain.ScrapeRequest, error) {
+	now := time.Now()
+	query, args, err := r.conn.Builder.
+		Update("scrape_requests").
+		Set("status", domain.ScrapeRequestRunning).
+		Set("started_at", now).
+		Set("lease_expires_at", now.Add(lease)).
+		Where(`id = (
+			SELECT id FROM scrape_requests
+			WHERE status = ?
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)`, domain.ScrapeRequestPending).
+		Suffix("RETURNING " + strings.Join(scrapeRequestColumns, ", ")).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build claim scrape request: %w", err)
+	}
+
+	return r.get(ctx, "claim scrape request", query, args)
+}
+
+func (r *scrapeRequestRepo) SetRun(ctx context.Context, id, runID uuid.UUID) error {
+	query, args, err := r.conn.Builder.
+		Update("scrape_requests").
+		Set("run_id", runID).
+		Where("id = ?", id).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build set scrape request run: %w", err)
+	}
+
+	if _, err := r.conn.DB.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("set scrape request run: %w", err)
+	}
+
+	return nil
+}
+
+// Heartbeat extends the lease of a running request by lease and reports
+// whether its cancellation was requested.
+func (r *scrapeRequestRepo) Heartbeat(ctx context.Context, id uuid.UUID, lease time.Duration) (bool, error) {
+	query, args, err := r.conn.Builder.
+		Update("scrape_requests").
+		Set("lease_expires_at", time.Now().Add(lease)).
+		Where("id = ?", id).
+		Suffix("RETURNING cancel_requested").
+		ToSql()
+	if err != nil {
+		return false, fmt.Errorf("build scrape request heartbeat: %w", err)
+	}
+
+	var cancelled bool
+	if err := r.conn.DB.GetContext(ctx, &cancelled, query, args...); err != nil {
+		return false, fmt.Errorf("scrape request heartbeat: %w", err)
+	}
+
+	return cancelled, nil
+}
+
+func (r *scrapeRequestRepo) Finish(ctx context.Context, req *domain.ScrapeRequest) error {
+	query, args, err := r.conn.Builder.
+		Update("scrape_requests").
+		SetMap(map[string]any{
+			"status":      req.Status,
+			"error":       req.Error,
+			"finished_at": req.FinishedAt,
+		}).
+		Where("id = ?", req.ID).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build finish scrape request: %w", err)
+	}
+
+	if _, err := r.conn.DB.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("finish scrape request: %w", err)
+	}
+
+	return nil
+}
+
+// FailExpired finishes the running requests whose lease expired: they are
+// cancelled if cancellation was requested and fail with reason otherwise.
+// It returns the number of requests finished.
+func (r *scrapeRequestRepo) FailExpired(ctx context.Context, reason string) (int64, error) {
+	query, args, err := r.conn.Builder.
+		Update("scrape_requests").
+		Set("status", sq.Expr("CASE WHEN cancel_requested THEN ? ELSE ? END", domain.ScrapeRequestCancelled, domain.ScrapeRequestFailed)).
+		Set("error", sq.Expr("CASE WHEN cancel_requested THEN error ELSE ? END", reason)).
+		Set("finished_at", sq.Expr("now()")).
+		Where("status = ?", domain.ScrapeRequestRunning).
+		Where("lease_expires_at < now()").
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("build fail expired scrape requests: %w", err)
+	}
+
+	res, err := r.conn.DB.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("fail expired scrape requests: %w", err)
+	}
+
+	return res.RowsAffected()
+}
@@ -0,0 +1,328 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+
+	"github.com/burbble/marketplace/internal/domain"
+	"github.com/burbble/marketplace/pkg/db"
+)
+
+var scrapeRunColumns = []string{
+	"id", "status", "started_at", "finished_at", "categories_total", "categories_failed",
+	"pages_fetched", "pages_failed", "products_upserted", "products_changed",
+	"markup_drift", "drift_categories", "error",
+}
+
+var scrapeRunCategoryColumns = []string{
+	"id", "run_id", "category_id", "category_name", "status", "started_at", "finished_at",
+	"pages_total", "pages_fetched", "pages_failed", "products_upserted", "products_changed",
+	"marked_unavailable", "products_expected", "cards_found", "cards_parsed",
+	"missing_price", "missing_brand", "missing_sku", "missing_ecom",
+	"descriptions_fetched", "descriptions_missing", "descriptions_cached", "markup_drift", "drift_reason", "error",
+}
+
+type ScrapeRunRepository interface {
+	Create(ctx context.Context, run *domain.ScrapeRun) error
+	Finish(ctx context.Context, run *domain.ScrapeRun) error
+	FailAbandoned(ctx context.Context, reason string) (int64, error)
+	AddCategory(ctx context.Context, category *domain.ScrapeRunCategory) error
+	SetMarkedUnavailable(ctx context.Context, categoryRunID int64, marked int) error
+	GetList(ctx context.Context, filter domain.ScrapeRunFilter) (*domain.ScrapeRunList, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.ScrapeRun, error)
+	GetCategories(ctx context.Context, runID uuid.UUID) ([]domain.ScrapeRunCategory, error)
+	AddFailedPages(ctx context.Context, runID uuid.UUID, pages []domain.ScrapeRunFailedPage) error
+	GetFailedPages(ctx context.Context, runID uuid.UUID) ([]domain.ScrapeRunFailedPage, error)
+	LastScraped(ctx context.Context) (map[uuid.UUID]time.Time, error)
+}
+
+type scrapeRunRepo struct {
+	conn *db.Connection
+}
+
+func NewScrapeRunRepo(conn *db.Connection) ScrapeRunRepository {
+	return &scrapeRunRepo{conn: conn}
+}
+
+func (r *scrapeRunRepo) Create(ctx context.Context, run *domain.ScrapeRun) error {
+	query, args, err := r.conn.Builder.
+		Insert("scrape_runs").
+		Columns("status", "started_at").
+		Values(run.Status, run.StartedAt).
+		Suffix("RETURNING id").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build insert scrape run: %w", err)
+	}
+
+	if err := r.conn.DB.GetContext(ctx, &run.ID, query, args...); err != nil {
+		return fmt.Errorf("insert scrape run: %w", err)
+	}
+
+	return nil
+}
+
+func (r *scrapeRunRepo) Finish(ctx context.Context, run *domain.ScrapeRun) error {
+	query, args, err := r.conn.Builder.
+		Update("scrape_runs").
+		SetMap(map[string]any{
+			"status":            run.Status,
+			"finished_at":       run.FinishedAt,
+			"categories_total":  run.CategoriesTotal,
+			"categories_failed": run.CategoriesFailed,
+			"pages_fetched":     run.PagesFetched,
+			"pages_failed":      run.PagesFailed,
+			"products_upserted": run.ProductsUpserted,
+			"products_changed":  run.ProductsChanged,
+			"markup_drift":      run.MarkupDrift,
+			"drift_categories":  run.DriftCategories,
+			"error":             run.Error,
+		}).
+		Where("id = ?", run.ID).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build finish scrape run: %w", err)
+	}
+
+	if _, err := r.conn.DB.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("finish scrape run: %w", err)
+	}
+
+	return nil
+}
+
+// FailAbandoned marks every run still recorded as running as failed with the
+// given reason. Runs never overlap, so it is called when a new run starts: a
+// run left running then belongs to a parser that crashed or was killed. The
+// run is finished when its last category was, or when it started if it
+// recorded none.
+func (r *scrapeRunRepo) FailAbandoned(ctx context.Context, reason string) (int64, error) {
+	query, args, err := r.conn.Builder.
+		Update("scrape_runs").
+		Set("status", domain.ScrapeStatusFailed).
+		Set("finished_at", sq.Expr("COALESCE((SELECT MAX(c.finished_at) FROM scrape_run_categories c WHERE c.run_id = scrape_runs.id), started_at)")).
+		Set("error", reason).
+		Where(sq.Eq{"status": domain.ScrapeStatusRunning}).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("build fail abandoned scrape runs: %w", err)
+	}
+
+	res, err := r.conn.DB.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("fail abandoned scrape runs: %w", err)
+	}
+
+	return res.RowsAffected()
+}
+
+func (r *scrapeRunRepo) AddCategory(ctx context.Context, c *domain.ScrapeRunCategory) error {
+	query, args, err := r.conn.Builder.
+		Insert("scrape_run_categories").
+		Columns(scrapeRunCategoryColumns[1:]...).
+		Values(
+			c.RunID, c.CategoryID, c.CategoryName, c.Status, c.StartedAt, c.FinishedAt,
+			c.PagesTotal, c.PagesFetched, c.PagesFailed, c.ProductsUpserted, c.ProductsChanged,
+			c.MarkedUnavailable, c.ProductsExpected, c.CardsFound, c.CardsParsed,
+			c.MissingPrice, c.MissingBrand, c.MissingSKU, c.MissingEcom,
+			c.DescriptionsFetched, c.DescriptionsMissing, c.DescriptionsCached, c.MarkupDrift, c.DriftReason, c.Error,
+		).
+		Suffix("RETURNING id").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build insert scrape run category: %w", err)
+	}
+
+	if err := r.conn.DB.GetContext(ctx, &c.ID, query, args...); err != nil {
+		return fmt.Errorf("insert scrape run category: %w", err)
+	}
+
+	return nil
+}
+
+// SetMarkedUnavailable records how many products of a recorded category were
+// marked unavailable once its run finished.
+func (r *scrapeRunRepo) SetMarkedUnavailable(ctx context.Context, categoryRunID int64, marked int) error {
+	query, args, err := r.conn.Builder.
+		Update("scrape_run_categories").
+		Set("marked_unavailable", marked).
+		Where("id = ?", categoryRunID).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build update marked unavailable: %w", err)
+	}
+
+	if _, err := r.conn.DB.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("update marked unavailable: %w", err)
+	}
+
+	return nil
+}
+
+func (r *scrapeRunRepo) GetList(ctx context.Context, filter domain.ScrapeRunFilter) (*domain.ScrapeRunList, error) {
+	var where sq.And
+	if filter.Status != nil {
+		where = append(where, sq.Eq{"status": *filter.Status})
+	}
+	if filter.MarkupDrift != nil {
+		where = append(where, sq.Eq{"markup_drift": *filter.MarkupDrift})
+	}
+
+	countQ, countArgs, err := r.conn.Builder.
+		Select("COUNT(*)").
+		From("scrape_runs").
+		Where(where).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build count scrape runs: %w", err)
+	}
+
+	var total int
+	if err := r.conn.DB.GetContext(ctx, &total, countQ, countArgs...); err != nil {
+		return nil, fmt.Errorf("count scrape runs: %w", err)
+	}
+
+	query, args, err := r.conn.Builder.
+		Select(scrapeRunColumns...).
+		From("scrape_runs").
+		Where(where).
+		OrderBy("started_at DESC").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build select scrape runs: %w", err)
+	}
+
+	runs := make([]domain.ScrapeRun, 0)
+	if err := r.conn.DB.SelectContext(ctx, &runs, query, args...); err != nil {
+		return nil, fmt.Errorf("select scrape runs: %w", err)
+	}
+
+	page := uint64(1)
+	if filter.Limit > 0 {
+		page = filter.Offset/filter.Limit + 1
+	}
+
+	return &domain.ScrapeRunList{
+		Runs:     runs,
+		Total:    total,
+		Page:     page,
+		PageSize: filter.Limit,
+	}, nil
+}
+
+func (r *scrapeRunRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.ScrapeRun, error) {
+	query, args, err := r.conn.Builder.
+		Select(scrapeRunColumns...).
+		From("scrape_runs").
+		Where("id = ?", id).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build select scrape run by id: %w", err)
+	}
+
+	var run domain.ScrapeRun
+	if err := r.conn.DB.GetContext(ctx, &run, query, args...); err != nil {
+		return nil, fmt.Errorf("get scrape run by id: %w", err)
+	}
+
+	return &run, nil
+}
+
+func (r *scrapeRunRepo) GetCategories(ctx context.Context, runID uuid.UUID) ([]domain.ScrapeRunCategory, error) {
+	query, args, err := r.conn.Builder.
+		Select(scrapeRunCategoryColumns...).
+		From("scrape_run_categories").
+		Where("run_id = ?", runID).
+		OrderBy("started_at ASC", "id ASC").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build select scrape run categories: %w", err)
+	}
+
+	categories := make([]domain.ScrapeRunCategory, 0)
+	if err := r.conn.DB.SelectContext(ctx, &categories, query, args...); err != nil {
+		return nil, fmt.Errorf("select scrape run categories: %w", err)
+	}
+
+	return categories, nil
+}
+
+func (r *scrapeRunRepo) AddFailedPages(ctx context.Context, runID uuid.UUID, pages []domain.ScrapeRunFailedPage) error {
+	if len(pages) == 0 {
+		return nil
+	}
+
+	q := r.conn.Builder.
+		Insert("scrape_run_failed_pages").
+		Columns("run_id", "url", "kind", "attempts", "permanent", "error")
+
+	for _, p := range pages {
+		q = q.Values(runID, p.URL, p.Kind, p.Attempts, p.Permanent, p.Error)
+	}
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return fmt.Errorf("build insert scrape run failed pages: %w", err)
+	}
+
+	if _, err := r.conn.DB.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("insert scrape run failed pages: %w", err)
+	}
+
+	return nil
+}
+
+func (r *scrapeRunRepo) GetFailedPages(ctx context.Context, runID uuid.UUID) ([]domain.ScrapeRunFailedPage, error) {
+	query, args, err := r.conn.Builder.
+		Select("id", "run_id", "url", "kind", "attempts", "permanent", "error", "created_at").
+		From("scrape_run_failed_pages").
+		Where("run_id = ?", runID).
+		OrderBy("id ASC").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build select scrape run failed pages: %w", err)
+	}
+
+	pages := make([]domain.ScrapeRunFailedPage, 0)
+	if err := r.conn.DB.SelectContext(ctx, &pages, query, args...); err != nil {
+		return nil, fmt.Errorf("select scrape run failed pages: %w", err)
+	}
+
+	return pages, nil
+}
+
+// LastScraped returns when each category was last scraped successfully, by
+// category id. A category whose scrapes failed stays due.
+func (r *scrapeRunRepo) LastScraped(ctx context.Context) (map[uuid.UUID]time.Time, error) {
+	query, args, err := r.conn.Builder.
+		Select("category_id", "MAX(started_at) AS started_at").
+		From("scrape_run_categories").
+		Where("category_id IS NOT NULL").
+		Where(sq.Eq{"status": domain.ScrapeStatusSucceeded}).
+		GroupBy("category_id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build select last scraped: %w", err)
+	}
+
+	var rows []struct {
+		CategoryID uuid.UUID `db:"category_id"`
+		StartedAt  time.Time `db:"started_at"`
+	}
+	if err := r.conn.DB.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("select last scraped: %w", err)
+	}
+
+	last := make(map[uuid.UUID]time.Time, len(rows))
+	for _, row := range rows {
+		last[row.CategoryID] = row.StartedAt
+	}
+
+	return last, nil
+}
@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/burbble/marketplace/internal/domain"
+	"github.com/burbble/marketplace/pkg/db"
+)
+
+// trigramThreshold is the word similarity needed for the <% operator. The
+// pg_trgm default of 0.6 rejects common typos such as "iphnoe" for "iphone".
+const trigramThreshold = "0.3"
+
+type SearchRepository interface {
+	Suggest(ctx context.Context, term string, limit uint64) ([]domain.Suggestion, error)
+	GetBrandAliases(ctx context.Context) ([]domain.BrandAlias, error)
+}
+
+type searchRepo struct {
+	conn *db.Connection
+}
+
+func NewSearchRepo(conn *db.Connection) SearchRepository {
+	return &searchRepo{conn: conn}
+}
+
+// Suggest offers brands and names of available products only, like the
+// default product list.
+func (r *searchRepo) Suggest(ctx context.Context, term string, limit uint64) ([]domain.Suggestion, error) {
+	brandsQ, brandsArgs, err := r.conn.Builder.
+		Select("brand AS text", "'brand' AS kind", "NULL::uuid AS product_id").
+		Column(sq.Expr("word_similarity(?, brand) AS score", term)).
+		From("products").
+		Where("? <% brand", term).
+		Where("brand <> ''").
+		Where(sq.Eq{"available": true}).
+		GroupBy("brand").
+		OrderBy("score DESC", "brand ASC").
+		Limit(limit).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build suggest brands: %w", err)
+	}
+
+	productsQ, productsArgs, err := r.conn.Builder.
+		Select("name AS text", "'product' AS kind", "id AS product_id").
+		Column(sq.Expr("word_similarity(?, name) AS score", term)).
+		From("products").
+		Where("? <% name", term).
+		Where(sq.Eq{"available": true}).
+		OrderBy("score DESC", "name ASC").
+		Limit(limit).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build suggest products: %w", err)
+	}
+
+	suggestions := make([]domain.Suggestion, 0)
+	err = withTrigramThreshold(ctx, r.conn, func(tx *sqlx.Tx) error {
+		var brands, products []domain.Suggestion
+		if err := tx.SelectContext(ctx, &brands, brandsQ, brandsArgs...); err != nil {
+			return fmt.Errorf("select brand suggestions: %w", err)
+		}
+		if err := tx.SelectContext(ctx, &products, productsQ, productsArgs...); err != nil {
+			return fmt.Errorf("select product suggestions: %w", err)
+		}
+		suggestions = append(suggestions, brands...)
+		suggestions = append(suggestions, products...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return suggestions, nil
+}
+
+func (r *searchRepo) GetBrandAliases(ctx context.Context) ([]domain.BrandAlias, error) {
+	query, args, err := r.conn.Builder.
+		Select("alias", "brand").
+		From("brand_aliases").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build select brand aliases: %w", err)
+	}
+
+	var aliases []domain.BrandAlias
+	if err := r.conn.DB.SelectContext(ctx, &aliases, query, args...); err != nil {
+		return nil, fmt.Errorf("select brand aliases: %w", err)
+	}
+
+	return aliases, nil
+}
+
+// withTrigramThreshold runs fn in a read-only transaction with the pg_trgm
+// word similarity threshold lowered to trigramThreshold, so that the <%
+// operator keeps using the trigram indexes.
+func withTrigramThreshold(ctx context.Context, conn *db.Connection, fn func(tx *sqlx.Tx) error) error {
+	tx, err := conn.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin trigram search: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, "SELECT set_config('pg_trgm.word_similarity_threshold', $1, true)", trigramThreshold); err != nil {
+		return fmt.Errorf("set trigram threshold: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
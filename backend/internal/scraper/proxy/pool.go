@@ -0,0 +1,252 @@
+package proxy
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	StrategyRoundRobin = "round_robin"
+	StrategySticky     = "sticky"
+)
+
+var ErrNoHealthyProxy = errors.New("no healthy proxy available")
+
+var allowedSchemes = map[string]bool{
+	"http":    true,
+	"https":   true,
+	"socks5":  true,
+	"socks5h": true,
+}
+
+type Config struct {
+	Strategy      string
+	MaxFailures   int
+	BenchDuration time.Duration
+}
+
+// Proxy is a pool member. Its counters are guarded by the pool's mutex.
+type Proxy struct {
+	URL *url.URL
+
+	successes           int
+	failures            int
+	consecutiveFailures int
+	benchedUntil        time.Time
+}
+
+// String returns the proxy URL with the password masked.
+func (p *Proxy) String() string {
+	return p.URL.Redacted()
+}
+
+// Stat is a snapshot of a proxy's health.
+type Stat struct {
+	URL          string
+	Successes    int
+	Failures     int
+	BenchedUntil *time.Time
+}
+
+// Pool hands out proxies for scraper requests. A proxy that fails
+// MaxFailures times in a row is benched for BenchDuration and skipped by Pick.
+type Pool struct {
+	mu      sync.Mutex
+	cfg     Config
+	proxies []*Proxy
+	next    int
+	sticky  map[string]*Proxy
+	now     func() time.Time
+}
+
+func NewPool(urls []*url.URL, cfg Config) *Pool {
+	if cfg.MaxFailures <= 0 {
+		cfg.MaxFailures = 3
+	}
+	if cfg.BenchDuration <= 0 {
+		cfg.BenchDuration = 5 * time.Minute
+	}
+
+	proxies := make([]*Proxy, 0, len(urls))
+	for _, u := range urls {
+		proxies = append(proxies, &Proxy{URL: u})
+	}
+
+	return &Pool{
+		cfg:     cfg,
+		proxies: proxies,
+		sticky:  make(map[string]*Proxy),
+		now:     time.Now,
+	}
+}
+
+func IsValidStrategy(s string) bool {
+	return s == StrategyRoundRobin || s == StrategySticky
+}
+
+func (p *Pool) Len() int {
+	return len(p.proxies)
+}
+
+// Pick returns the proxy to use for a request. With the sticky strategy all
+// requests sharing a key go through the same proxy until it is benched.
+func (p *Pool) Pick(key string) (*Proxy, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	sticky := p.cfg.Strategy == StrategySticky && key != ""
+
+	if sticky {
+		if px, ok := p.sticky[key]; ok && px.healthy(now) {
+			return px, nil
+		}
+	}
+
+	for range p.proxies {
+		px := p.proxies[p.next]
+		p.next = (p.next + 1) % len(p.proxies)
+		if !px.healthy(now) {
+			continue
+		}
+		if sticky {
+			p.sticky[key] = px
+		}
+		return px, nil
+	}
+
+	return nil, ErrNoHealthyProxy
+}
+
+// Report records the outcome of a request made through px. It returns true
+// when the failure got the proxy benched.
+func (p *Pool) Report(px *Proxy, ok bool) (benched bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if ok {
+		px.successes++
+		px.consecutiveFailures = 0
+		return false
+	}
+
+	px.failures++
+	px.consecutiveFailures++
+	if px.consecutiveFailures < p.cfg.MaxFailures {
+		return false
+	}
+
+	px.consecutiveFailures = 0
+	px.benchedUntil = p.now().Add(p.cfg.BenchDuration)
+
+	return true
+}
+
+func (p *Pool) Stats() []Stat {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	stats := make([]Stat, 0, len(p.proxies))
+	for _, px := range p.proxies {
+		s := Stat{URL: px.String(), Successes: px.successes, Failures: px.failures}
+		if !px.healthy(now) {
+			until := px.benchedUntil
+			s.BenchedUntil = &until
+		}
+		stats = append(stats, s)
+	}
+
+	return stats
+}
+
+func (px *Proxy) healthy(now time.Time) bool {
+	return !now.Before(px.benchedUntil)
+}
+
+// Parse parses a proxy address. Addresses without a scheme are treated as
+// HTTP proxies.
+func Parse(raw string) (*url.URL, error) {
+	raw = strings.TrimSpace(raw)
+	if !strings.Contains(raw, "://") {
+		raw = "http://" + raw
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("parse proxy %q: %w", raw, err)
+	}
+	if !allowedSchemes[u.Scheme] {
+		return nil, fmt.Errorf("proxy %s: unsupported scheme %q", u.Redacted(), u.Scheme)
+	}
+	if u.Hostname() == "" || u.Port() == "" {
+		return nil, fmt.Errorf("proxy %s: host and port are required", u.Redacted())
+	}
+
+	return u, nil
+}
+
+// ParseList parses proxies separated by commas or whitespace.
+func ParseList(list string) ([]*url.URL, error) {
+	fields := strings.FieldsFunc(list, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n' || r == '\r'
+	})
+
+	urls := make([]*url.URL, 0, len(fields))
+	for _, f := range fields {
+		u, err := Parse(f)
+		if err != nil {
+			return nil, err
+		}
+		urls = append(urls, u)
+	}
+
+	return urls, nil
+}
+
+// LoadFile reads one proxy per line, skipping blank lines and # comments.
+func LoadFile(path string) ([]*url.URL, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open proxy file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var urls []*url.URL
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		u, err := Parse(line)
+		if err != nil {
+			return nil, err
+		}
+		urls = append(urls, u)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("read proxy file: %w", err)
+	}
+
+	return urls, nil
+}
+
+type keyCtxKey struct{}
+
+// WithKey tags requests made with ctx for sticky proxy assignment.
+func WithKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, keyCtxKey{}, key)
+}
+
+func KeyFrom(ctx context.Context) string {
+	key, _ := ctx.Value(keyCtxKey{}).(string)
+	return key
+}
@@ -0,0 +1,76 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// Lock is a Redis lease held by at most one process at a time. It is used
+// to elect the parser instance that schedules a scrape cycle; holding it
+// past its TTL requires Refresh.
+type Lock struct {
+	rdb   *redis.Client
+	key   string
+	token string
+}
+
+func NewLock(rdb *redis.Client, key string) *Lock {
+	return &Lock{rdb: rdb, key: key, token: uuid.NewString()}
+}
+
+var acquireScript = redis.NewScript(`
+if redis.call('SET', KEYS[1], ARGV[1], 'NX', 'PX', ARGV[2]) then
+	return 1
+end
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+	return 1
+end
+return 0
+`)
+
+// Acquire takes the lock for ttl and reports whether it is now held by this
+// Lock. Acquiring a lock already held by this Lock extends it.
+func (l *Lock) Acquire(ctx context.Context, ttl time.Duration) (bool, error) {
+	ok, err := acquireScript.Run(ctx, l.rdb, []string{l.key}, l.token, ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, fmt.Errorf("acquire lock %s: %w", l.key, err)
+	}
+	return ok == 1, nil
+}
+
+var refreshScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) ~= ARGV[1] then
+	return 0
+end
+redis.call('PEXPIRE', KEYS[1], ARGV[2])
+return 1
+`)
+
+// Refresh sets the lock's TTL and reports whether the lock was still held.
+func (l *Lock) Refresh(ctx context.Context, ttl time.Duration) (bool, error) {
+	ok, err := refreshScript.Run(ctx, l.rdb, []string{l.key}, l.token, ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, fmt.Errorf("refresh lock %s: %w", l.key, err)
+	}
+	return ok == 1, nil
+}
+
+var releaseScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) ~= ARGV[1] then
+	return 0
+end
+return redis.call('DEL', KEYS[1])
+`)
+
+// Release drops the lock if it is still held by this Lock.
+func (l *Lock) Release(ctx context.Context) error {
+	if err := releaseScript.Run(ctx, l.rdb, []string{l.key}, l.token).Err(); err != nil {
+		return fmt.Errorf("release lock %s: %w", l.key, err)
+	}
+	return nil
+}
@@ -0,0 +1,321 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// deadLimit caps how many dead jobs are kept for inspection.
+const deadLimit = 1000
+
+var (
+	// ErrEmpty is returned by Lease when no job is pending.
+	ErrEmpty = errors.New("queue is empty")
+	// ErrLeaseLost means the job's lease expired and the job was handed back
+	// to the queue, possibly to another worker.
+	ErrLeaseLost = errors.New("job lease lost")
+)
+
+type Config struct {
+	// Name prefixes every Redis key of the queue.
+	Name string
+	// Visibility is how long a leased job stays invisible to other workers
+	// unless the lease is extended.
+	Visibility time.Duration
+	// MaxAttempts is how many times a job is leased before it is moved to
+	// the dead list.
+	MaxAttempts int
+	// Now is the clock used for lease deadlines; time.Now when nil.
+	Now func() time.Time
+}
+
+// Job is a leased unit of work. Attempts counts this lease.
+type Job struct {
+	ID       string
+	Payload  json.RawMessage
+	Attempts int
+}
+
+// DeadJob is a job that ran out of attempts.
+type DeadJob struct {
+	ID       string          `json:"id"`
+	Payload  json.RawMessage `json:"payload"`
+	Attempts int             `json:"attempts"`
+	Reason   string          `json:"reason"`
+}
+
+type Stats struct {
+	Pending int64
+	Leased  int64
+	Dead    int64
+}
+
+// Queue is a Redis-backed at-least-once job queue shared by several
+// processes. Leased jobs are kept in a sorted set scored by their lease
+// deadline; a job whose lease expires is handed back to the queue by Reap,
+// or moved to the dead list once it has been leased MaxAttempts times.
+type Queue struct {
+	rdb *redis.Client
+	cfg Config
+
+	pending  string
+	leased   string
+	payloads string
+	attempts string
+	dead     string
+}
+
+func New(rdb *redis.Client, cfg Config) *Queue {
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 1
+	}
+	if cfg.Visibility <= 0 {
+		cfg.Visibility = time.Minute
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+
+	return &Queue{
+		rdb:      rdb,
+		cfg:      cfg,
+		pending:  cfg.Name + ":pending",
+		leased:   cfg.Name + ":leased",
+		payloads: cfg.Name + ":payloads",
+		attempts: cfg.Name + ":attempts",
+		dead:     cfg.Name + ":dead",
+	}
+}
+
+func (q *Queue) MaxAttempts() int {
+	return q.cfg.MaxAttempts
+}
+
+func (q *Queue) Visibility() time.Duration {
+	return q.cfg.Visibility
+}
+
+// Enqueue publishes one job per payload in order.
+func (q *Queue) Enqueue(ctx context.Context, payloads ...any) error {
+	if len(payloads) == 0 {
+		return nil
+	}
+
+	pipe := q.rdb.TxPipeline()
+	if err := q.EnqueueTx(ctx, pipe, payloads...); err != nil {
+		return err
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("enqueue jobs: %w", err)
+	}
+
+	return nil
+}
+
+// EnqueueTx queues the commands publishing one job per payload on pipe, so
+// that the jobs are published together with the caller's own commands once
+// the transaction is executed.
+func (q *Queue) EnqueueTx(ctx context.Context, pipe redis.Pipeliner, payloads ...any) error {
+	for _, p := range payloads {
+		b, err := json.Marshal(p)
+		if err != nil {
+			return fmt.Errorf("marshal job: %w", err)
+		}
+
+		id := uuid.NewString()
+		pipe.HSet(ctx, q.payloads, id, b)
+		pipe.LPush(ctx, q.pending, id)
+	}
+
+	return nil
+}
+
+var leaseScript = redis.NewScript(`
+local id = redis.call('RPOP', KEYS[1])
+if not id then
+	return false
+end
+redis.call('ZADD', KEYS[2], ARGV[1], id)
+local attempts = redis.call('HINCRBY', KEYS[4], id, 1)
+local payload = redis.call('HGET', KEYS[3], id) or 'null'
+return {id, payload, attempts}
+`)
+
+// Lease takes the oldest pending job. It returns ErrEmpty when there is
+// none.
+func (q *Queue) Lease(ctx context.Context) (*Job, error) {
+	res, err := leaseScript.Run(ctx, q.rdb,
+		[]string{q.pending, q.leased, q.payloads, q.attempts},
+		q.deadline(),
+	).Slice()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrEmpty
+	}
+	if err != nil {
+		return nil, fmt.Errorf("lease job: %w", err)
+	}
+
+	id, _ := res[0].(string)
+	payload, _ := res[1].(string)
+	attempts, _ := res[2].(int64)
+
+	return &Job{ID: id, Payload: json.RawMessage(payload), Attempts: int(attempts)}, nil
+}
+
+var extendScript = redis.NewScript(`
+if not redis.call('ZSCORE', KEYS[1], ARGV[1]) then
+	return 0
+end
+redis.call('ZADD', KEYS[1], ARGV[2], ARGV[1])
+return 1
+`)
+
+// Extend pushes the lease deadline of a job one visibility timeout ahead.
+func (q *Queue) Extend(ctx context.Context, id string) error {
+	ok, err := extendScript.Run(ctx, q.rdb, []string{q.leased}, id, q.deadline()).Int()
+	if err != nil {
+		return fmt.Errorf("extend lease: %w", err)
+	}
+	if ok == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+var ackScript = redis.NewScript(`
+if redis.call('ZREM', KEYS[1], ARGV[1]) == 0 then
+	return 0
+end
+redis.call('HDEL', KEYS[2], ARGV[1])
+redis.call('HDEL', KEYS[3], ARGV[1])
+return 1
+`)
+
+// Ack removes a finished job. It returns ErrLeaseLost if the lease expired
+// before the job was acknowledged.
+func (q *Queue) Ack(ctx context.Context, id string) error {
+	ok, err := ackScript.Run(ctx, q.rdb, []string{q.leased, q.payloads, q.attempts}, id).Int()
+	if err != nil {
+		return fmt.Errorf("ack job: %w", err)
+	}
+	if ok == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+// buryScript defines bury, which moves a job to the dead list capped at
+// ARGV[1] entries and returns the dead job as JSON.
+const buryScript = `
+local function bury(id, reason)
+	local attempts = tonumber(redis.call('HGET', KEYS[4], id) or '0')
+	local payload = redis.call('HGET', KEYS[3], id) or 'null'
+	local dead = '{"id":' .. cjson.encode(id) ..
+		',"attempts":' .. attempts ..
+		',"reason":' .. cjson.encode(reason) ..
+		',"payload":' .. payload .. '}'
+	redis.call('LPUSH', KEYS[5], dead)
+	redis.call('LTRIM', KEYS[5], 0, tonumber(ARGV[1]) - 1)
+	redis.call('HDEL', KEYS[3], id)
+	redis.call('HDEL', KEYS[4], id)
+	return dead
+end
+`
+
+var nackScript = redis.NewScript(buryScript + `
+local id = ARGV[3]
+if redis.call('ZREM', KEYS[2], id) == 0 then
+	return -1
+end
+local attempts = tonumber(redis.call('HGET', KEYS[4], id) or '0')
+if attempts >= tonumber(ARGV[2]) then
+	bury(id, ARGV[4])
+	return 1
+end
+redis.call('LPUSH', KEYS[1], id)
+return 0
+`)
+
+// Nack hands a failed job back to the queue, or moves it to the dead list
+// when it has used up its attempts, in which case dead is true.
+func (q *Queue) Nack(ctx context.Context, id, reason string) (dead bool, err error) {
+	res, err := nackScript.Run(ctx, q.rdb,
+		q.keys(),
+		deadLimit, q.cfg.MaxAttempts, id, reason,
+	).Int()
+	if err != nil {
+		return false, fmt.Errorf("nack job: %w", err)
+	}
+	if res < 0 {
+		return false, ErrLeaseLost
+	}
+	return res == 1, nil
+}
+
+var reapScript = redis.NewScript(buryScript + `
+local ids = redis.call('ZRANGEBYSCORE', KEYS[2], '-inf', ARGV[3])
+local dead = {}
+for _, id in ipairs(ids) do
+	redis.call('ZREM', KEYS[2], id)
+	local attempts = tonumber(redis.call('HGET', KEYS[4], id) or '0')
+	if attempts >= tonumber(ARGV[2]) then
+		table.insert(dead, bury(id, 'lease expired'))
+	else
+		redis.call('LPUSH', KEYS[1], id)
+	end
+end
+return dead
+`)
+
+// Reap hands jobs whose lease has expired back to the queue and returns the
+// ones that have used up their attempts. Any process may call it; the
+// script runs atomically, so each expired job is reaped once.
+func (q *Queue) Reap(ctx context.Context) ([]DeadJob, error) {
+	res, err := reapScript.Run(ctx, q.rdb,
+		q.keys(),
+		deadLimit, q.cfg.MaxAttempts, strconv.FormatInt(q.cfg.Now().UnixMilli(), 10),
+	).StringSlice()
+	if err != nil {
+		return nil, fmt.Errorf("reap jobs: %w", err)
+	}
+
+	dead := make([]DeadJob, 0, len(res))
+	for _, raw := range res {
+		var d DeadJob
+		if err := json.Unmarshal([]byte(raw), &d); err != nil {
+			return nil, fmt.Errorf("decode dead job: %w", err)
+		}
+		dead = append(dead, d)
+	}
+
+	return dead, nil
+}
+
+func (q *Queue) Stats(ctx context.Context) (Stats, error) {
+	pipe := q.rdb.Pipeline()
+	pending := pipe.LLen(ctx, q.pending)
+	leased := pipe.ZCard(ctx, q.leased)
+	dead := pipe.LLen(ctx, q.dead)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return Stats{}, fmt.Errorf("queue stats: %w", err)
+	}
+
+	return Stats{Pending: pending.Val(), Leased: leased.Val(), Dead: dead.Val()}, nil
+}
+
+func (q *Queue) keys() []string {
+	return []string{q.pending, q.leased, q.payloads, q.attempts, q.dead}
+}
+
+func (q *Queue) deadline() int64 {
+	return q.cfg.Now().Add(q.cfg.Visibility).UnixMilli()
+}
@@ -0,0 +1,234 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+type clock struct{ now time.Time }
+
+func (c *clock) Now() time.Time { return c.now }
+
+func newTestQueue(t *testing.T, maxAttempts int) (*Queue, *clock, *redis.Client) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+
+	c := &clock{now: time.Date(2026, 2, 3, 12, 0, 0, 0, time.UTC)}
+	q := New(rdb, Config{
+		Name:        "test:jobs",
+		Visibility:  time.Minute,
+		MaxAttempts: maxAttempts,
+		Now:         c.Now,
+	})
+
+	return q, c, rdb
+}
+
+type payload struct {
+	Slug string `json:"slug"`
+}
+
+func leaseSlug(t *testing.T, q *Queue) (*Job, string) {
+	t.Helper()
+
+	job, err := q.Lease(context.Background())
+	if err != nil {
+		t.Fatalf("lease: %v", err)
+	}
+
+	var p payload
+	if err := json.Unmarshal(job.Payload, &p); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+
+	return job, p.Slug
+}
+
+func TestQueue_LeaseInOrderAndAck(t *testing.T) {
+	q, _, _ := newTestQueue(t, 3)
+	ctx := context.Background()
+
+	if err := q.Enqueue(ctx, payload{"phones"}, payload{"laptops"}); err != nil {
+		t.Fatal(err)
+	}
+
+	first, slug := leaseSlug(t, q)
+	if slug != "phones" || first.Attempts != 1 {
+		t.Errorf("expected phones on attempt 1, got %s on attempt %d", slug, first.Attempts)
+	}
+	if _, slug := leaseSlug(t, q); slug != "laptops" {
+		t.Errorf("expected laptops, got %s", slug)
+	}
+
+	if _, err := q.Lease(ctx); !errors.Is(err, ErrEmpty) {
+		t.Errorf("expected ErrEmpty, got %v", err)
+	}
+
+	if err := q.Ack(ctx, first.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Ack(ctx, first.ID); !errors.Is(err, ErrLeaseLost) {
+		t.Errorf("expected second ack to report a lost lease, got %v", err)
+	}
+
+	stats, err := q.Stats(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats != (Stats{Pending: 0, Leased: 1, Dead: 0}) {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func TestQueue_EnqueueTx(t *testing.T) {
+	q, _, rdb := newTestQueue(t, 3)
+	ctx := context.Background()
+
+	_, err := rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, "test:marker", 1, 0)
+		return q.EnqueueTx(ctx, pipe, payload{"phones"})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, slug := leaseSlug(t, q); slug != "phones" {
+		t.Errorf("expected phones, got %s", slug)
+	}
+	if n, _ := rdb.Exists(ctx, "test:marker").Result(); n != 1 {
+		t.Error("expected the caller's command to run in the same transaction")
+	}
+}
+
+func TestQueue_NackRetriesThenBuries(t *testing.T) {
+	q, _, _ := newTestQueue(t, 2)
+	ctx := context.Background()
+
+	if err := q.Enqueue(ctx, payload{"phones"}); err != nil {
+		t.Fatal(err)
+	}
+
+	job, _ := leaseSlug(t, q)
+	dead, err := q.Nack(ctx, job.ID, "timeout")
+	if err != nil || dead {
+		t.Fatalf("expected job to be retried, dead=%v err=%v", dead, err)
+	}
+
+	job, _ = leaseSlug(t, q)
+	if job.Attempts != 2 {
+		t.Errorf("expected attempt 2, got %d", job.Attempts)
+	}
+
+	dead, err = q.Nack(ctx, job.ID, "timeout")
+	if err != nil || !dead {
+		t.Fatalf("expected job to be buried, dead=%v err=%v", dead, err)
+	}
+
+	stats, _ := q.Stats(ctx)
+	if stats != (Stats{Dead: 1}) {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func TestQueue_ReapExpiredLeases(t *testing.T) {
+	q, c, _ := newTestQueue(t, 2)
+	ctx := context.Background()
+
+	if err := q.Enqueue(ctx, payload{"phones"}, payload{"laptops"}); err != nil {
+		t.Fatal(err)
+	}
+
+	phones, _ := leaseSlug(t, q)
+	laptops, _ := leaseSlug(t, q)
+
+	c.now = c.now.Add(45 * time.Second)
+	if err := q.Extend(ctx, laptops.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	c.now = c.now.Add(30 * time.Second)
+	dead, err := q.Reap(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dead) != 0 {
+		t.Fatalf("expected no dead jobs, got %+v", dead)
+	}
+
+	if err := q.Extend(ctx, phones.ID); !errors.Is(err, ErrLeaseLost) {
+		t.Errorf("expected expired lease to be lost, got %v", err)
+	}
+
+	retried, slug := leaseSlug(t, q)
+	if slug != "phones" || retried.Attempts != 2 {
+		t.Errorf("expected phones on attempt 2, got %s on attempt %d", slug, retried.Attempts)
+	}
+
+	c.now = c.now.Add(2 * time.Minute)
+	dead, err = q.Reap(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dead) != 1 || dead[0].ID != phones.ID || dead[0].Attempts != 2 || dead[0].Reason != "lease expired" {
+		t.Fatalf("expected phones to be buried, got %+v", dead)
+	}
+
+	var p payload
+	if err := json.Unmarshal(dead[0].Payload, &p); err != nil || p.Slug != "phones" {
+		t.Errorf("expected dead job to keep its payload, got %s (%v)", dead[0].Payload, err)
+	}
+
+	if _, slug := leaseSlug(t, q); slug != "laptops" {
+		t.Errorf("expected laptops to be requeued, got %s", slug)
+	}
+}
+
+func TestLock_SingleHolder(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+	ctx := context.Background()
+
+	a := NewLock(rdb, "test:leader")
+	b := NewLock(rdb, "test:leader")
+
+	if ok, err := a.Acquire(ctx, time.Minute); err != nil || !ok {
+		t.Fatalf("expected a to acquire, ok=%v err=%v", ok, err)
+	}
+	if ok, _ := b.Acquire(ctx, time.Minute); ok {
+		t.Fatal("expected b to be refused while a holds the lock")
+	}
+	if ok, _ := a.Acquire(ctx, time.Minute); !ok {
+		t.Error("expected a to re-acquire its own lock")
+	}
+	if ok, _ := b.Refresh(ctx, time.Minute); ok {
+		t.Error("expected b not to refresh a lock it does not hold")
+	}
+	if err := b.Release(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := a.Refresh(ctx, time.Minute); !ok {
+		t.Error("expected release by b to leave a's lock in place")
+	}
+
+	mr.FastForward(time.Minute + time.Second)
+
+	if ok, _ := b.Acquire(ctx, time.Minute); !ok {
+		t.Error("expected b to acquire an expired lock")
+	}
+	if err := b.Release(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := a.Acquire(ctx, time.Minute); !ok {
+		t.Error("expected a to acquire a released lock")
+	}
+}
@@ -0,0 +1,145 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+
+	"github.com/burbble/marketplace/internal/domain"
+)
+
+// QuietHours is a daily window during which no scrape is started. End
+// before Start means the window wraps past midnight; the zero value is an
+// empty window.
+type QuietHours struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+// ParseQuietHours parses "HH:MM-HH:MM", e.g. "23:00-07:00". An empty string
+// yields no quiet hours.
+func ParseQuietHours(s string) (QuietHours, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return QuietHours{}, nil
+	}
+
+	from, to, ok := strings.Cut(s, "-")
+	if !ok {
+		return QuietHours{}, fmt.Errorf("invalid quiet hours %q: want HH:MM-HH:MM", s)
+	}
+
+	start, err := parseClock(from)
+	if err != nil {
+		return QuietHours{}, fmt.Errorf("invalid quiet hours %q: %w", s, err)
+	}
+	end, err := parseClock(to)
+	if err != nil {
+		return QuietHours{}, fmt.Errorf("invalid quiet hours %q: %w", s, err)
+	}
+
+	return QuietHours{Start: start, End: end}, nil
+}
+
+func parseClock(s string) (time.Duration, error) {
+	hh, mm, ok := strings.Cut(strings.TrimSpace(s), ":")
+	if !ok {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+
+	h, err := strconv.Atoi(hh)
+	if err != nil || h < 0 || h > 23 {
+		return 0, fmt.Errorf("invalid hour in %q", s)
+	}
+	m, err := strconv.Atoi(mm)
+	if err != nil || m < 0 || m > 59 {
+		return 0, fmt.Errorf("invalid minute in %q", s)
+	}
+
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute, nil
+}
+
+// Contains reports whether the wall-clock time of t falls into the window.
+func (q QuietHours) Contains(t time.Time) bool {
+	if q.Start == q.End {
+		return false
+	}
+
+	clock := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+	if q.Start < q.End {
+		return clock >= q.Start && clock < q.End
+	}
+	return clock >= q.Start || clock < q.End
+}
+
+// Planner decides which categories are due for a scrape. Each category
+// follows its own cron schedule, or the default schedule when it has none,
+// counted from when it was last scraped.
+type Planner struct {
+	loc       *time.Location
+	quiet     QuietHours
+	fallback  cron.Schedule
+	schedules map[uuid.UUID]cron.Schedule
+	disabled  map[uuid.UUID]struct{}
+}
+
+// NewPlanner builds a planner whose schedules are evaluated in loc.
+// Schedules with an invalid cron expression fall back to the default and
+// are returned in invalid.
+func NewPlanner(interval time.Duration, schedules []domain.CategorySchedule, quiet QuietHours, loc *time.Location) (p *Planner, invalid []domain.CategorySchedule) {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	p = &Planner{
+		loc:       loc,
+		quiet:     quiet,
+		fallback:  cron.Every(interval),
+		schedules: make(map[uuid.UUID]cron.Schedule, len(schedules)),
+		disabled:  make(map[uuid.UUID]struct{}),
+	}
+
+	for _, s := range schedules {
+		if !s.Enabled {
+			p.disabled[s.CategoryID] = struct{}{}
+			continue
+		}
+
+		sched, err := cron.ParseStandard(s.Cron)
+		if err != nil {
+			invalid = append(invalid, s)
+			continue
+		}
+		p.schedules[s.CategoryID] = sched
+	}
+
+	return p, invalid
+}
+
+// Quiet reports whether now falls into the quiet hours.
+func (p *Planner) Quiet(now time.Time) bool {
+	return p.quiet.Contains(now.In(p.loc))
+}
+
+// Due reports whether a category last scraped at last should be scraped
+// now. A category that was never scraped is due at once; a disabled one is
+// never due.
+func (p *Planner) Due(categoryID uuid.UUID, last, now time.Time) bool {
+	if _, ok := p.disabled[categoryID]; ok {
+		return false
+	}
+	if last.IsZero() {
+		return true
+	}
+
+	sched, ok := p.schedules[categoryID]
+	if !ok {
+		sched = p.fallback
+	}
+
+	return !sched.Next(last.In(p.loc)).After(now)
+}
@@ -0,0 +1,120 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/burbble/marketplace/internal/domain"
+)
+
+func TestParseQuietHours(t *testing.T) {
+	q, err := ParseQuietHours("23:30-07:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.Start != 23*time.Hour+30*time.Minute || q.End != 7*time.Hour {
+		t.Errorf("unexpected window %+v", q)
+	}
+
+	if q, err := ParseQuietHours(""); err != nil || q != (QuietHours{}) {
+		t.Errorf("expected empty window, got %+v (%v)", q, err)
+	}
+
+	for _, s := range []string{"23:00", "25:00-07:00", "23:00-07:60", "ab:00-07:00"} {
+		if _, err := ParseQuietHours(s); err == nil {
+			t.Errorf("expected error for %q", s)
+		}
+	}
+}
+
+func TestQuietHours_Contains(t *testing.T) {
+	at := func(h, m int) time.Time { return time.Date(2026, 2, 3, h, m, 0, 0, time.UTC) }
+
+	overnight := QuietHours{Start: 23 * time.Hour, End: 7 * time.Hour}
+	daytime := QuietHours{Start: 12 * time.Hour, End: 14 * time.Hour}
+
+	tests := []struct {
+		name  string
+		quiet QuietHours
+		t     time.Time
+		want  bool
+	}{
+		{"overnight before start", overnight, at(22, 59), false},
+		{"overnight at start", overnight, at(23, 0), true},
+		{"overnight after midnight", overnight, at(3, 0), true},
+		{"overnight at end", overnight, at(7, 0), false},
+		{"daytime inside", daytime, at(13, 0), true},
+		{"daytime outside", daytime, at(14, 30), false},
+		{"empty window", QuietHours{}, at(3, 0), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.quiet.Contains(tt.t); got != tt.want {
+				t.Errorf("Contains(%s) = %v, want %v", tt.t.Format("15:04"), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlanner_Due(t *testing.T) {
+	phones := uuid.New()
+	accessories := uuid.New()
+	archive := uuid.New()
+	other := uuid.New()
+	broken := uuid.New()
+
+	p, invalid := NewPlanner(time.Hour, []domain.CategorySchedule{
+		{CategoryID: phones, Cron: "*/15 * * * *", Enabled: true},
+		{CategoryID: accessories, Cron: "0 */6 * * *", Enabled: true},
+		{CategoryID: archive, Cron: "@hourly", Enabled: false},
+		{CategoryID: broken, Cron: "every now and then", Enabled: true},
+	}, QuietHours{}, time.UTC)
+
+	if len(invalid) != 1 || invalid[0].CategoryID != broken {
+		t.Fatalf("expected the broken schedule to be reported, got %+v", invalid)
+	}
+
+	at := func(h, m int) time.Time { return time.Date(2026, 2, 3, h, m, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name     string
+		category uuid.UUID
+		last     time.Time
+		now      time.Time
+		want     bool
+	}{
+		{"never scraped", phones, time.Time{}, at(10, 0), true},
+		{"cron slot not reached", phones, at(10, 1), at(10, 14), false},
+		{"cron slot reached", phones, at(10, 1), at(10, 15), true},
+		{"missed slots coalesce", phones, at(8, 1), at(10, 7), true},
+		{"six-hourly not due", accessories, at(6, 5), at(11, 59), false},
+		{"six-hourly due", accessories, at(6, 5), at(12, 0), true},
+		{"disabled", archive, time.Time{}, at(12, 0), false},
+		{"default interval not due", other, at(10, 0), at(10, 59), false},
+		{"default interval due", other, at(10, 0), at(11, 0), true},
+		{"invalid schedule uses default", broken, at(10, 0), at(10, 30), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p.Due(tt.category, tt.last, tt.now); got != tt.want {
+				t.Errorf("Due() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlanner_QuietUsesLocation(t *testing.T) {
+	msk := time.FixedZone("MSK", 3*60*60)
+	p, _ := NewPlanner(time.Hour, nil, QuietHours{Start: 1 * time.Hour, End: 7 * time.Hour}, msk)
+
+	if !p.Quiet(time.Date(2026, 2, 3, 0, 30, 0, 0, time.UTC)) {
+		t.Error("expected 03:30 MSK to be quiet")
+	}
+	if p.Quiet(time.Date(2026, 2, 3, 5, 0, 0, 0, time.UTC)) {
+		t.Error("expected 08:00 MSK not to be quiet")
+	}
+}
@@ -0,0 +1,228 @@
+package snapshot
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/burbble/marketplace/internal/scraper/store77"
+)
+
+const (
+	indexFile = "index.jsonl"
+	// nameLayout makes snapshot directories sort chronologically.
+	nameLayout = "20060102T150405Z"
+)
+
+var ErrNoSnapshots = errors.New("no snapshots found")
+
+// Entry describes one archived page in a snapshot's index.
+type Entry struct {
+	URL       string    `json:"url"`
+	File      string    `json:"file"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// Recorder archives fetched pages into a snapshot directory: every page is
+// stored gzipped in its own file and listed in index.jsonl in fetch order.
+type Recorder struct {
+	dir string
+
+	mu    sync.Mutex
+	seq   int
+	index *os.File
+}
+
+// NewRecorder creates a snapshot directory under root named after startedAt
+// and label.
+func NewRecorder(root, label string, startedAt time.Time) (*Recorder, error) {
+	name := startedAt.UTC().Format(nameLayout)
+	if label != "" {
+		name += "_" + label
+	}
+
+	dir := filepath.Join(root, name)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create snapshot dir: %w", err)
+	}
+
+	index, err := os.OpenFile(filepath.Join(dir, indexFile), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open snapshot index: %w", err)
+	}
+
+	return &Recorder{dir: dir, index: index}, nil
+}
+
+func (r *Recorder) Dir() string {
+	return r.dir
+}
+
+func (r *Recorder) Save(url, html string, fetchedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.seq++
+	name := fmt.Sprintf("%06d_%s.html.gz", r.seq, urlHash(url))
+
+	if err := writeGzip(filepath.Join(r.dir, name), html); err != nil {
+		return err
+	}
+
+	line, err := json.Marshal(Entry{URL: url, File: name, FetchedAt: fetchedAt.UTC()})
+	if err != nil {
+		return fmt.Errorf("marshal snapshot entry: %w", err)
+	}
+	if _, err := r.index.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("write snapshot index: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.index.Close()
+}
+
+func urlHash(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return hex.EncodeToString(sum[:6])
+}
+
+func writeGzip(path, content string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create snapshot page: %w", err)
+	}
+
+	zw := gzip.NewWriter(f)
+	if _, err := io.WriteString(zw, content); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("write snapshot page: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("write snapshot page: %w", err)
+	}
+
+	return f.Close()
+}
+
+// Replayer serves a recorded snapshot back as a store77.Fetcher. A URL
+// fetched several times during recording is replayed in the same order, the
+// last recording being repeated once the sequence is exhausted. URLs missing
+// from the snapshot answer 404.
+type Replayer struct {
+	dir string
+
+	mu      sync.Mutex
+	entries map[string][]Entry
+	served  map[string]int
+}
+
+func OpenReplay(dir string) (*Replayer, error) {
+	f, err := os.Open(filepath.Join(dir, indexFile))
+	if err != nil {
+		return nil, fmt.Errorf("open snapshot index: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	entries := make(map[string][]Entry)
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var e Entry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("parse snapshot index: %w", err)
+		}
+		entries[e.URL] = append(entries[e.URL], e)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("read snapshot index: %w", err)
+	}
+
+	return &Replayer{dir: dir, entries: entries, served: make(map[string]int)}, nil
+}
+
+// Len returns the number of distinct URLs in the snapshot.
+func (p *Replayer) Len() int {
+	return len(p.entries)
+}
+
+func (p *Replayer) Fetch(ctx context.Context, url string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	p.mu.Lock()
+	recorded := p.entries[url]
+	if len(recorded) == 0 {
+		p.mu.Unlock()
+		return "", &store77.StatusError{URL: url, StatusCode: http.StatusNotFound}
+	}
+	n := p.served[url]
+	p.served[url] = n + 1
+	p.mu.Unlock()
+
+	return readGzip(filepath.Join(p.dir, recorded[min(n, len(recorded)-1)].File))
+}
+
+func readGzip(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("open snapshot page: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return "", fmt.Errorf("read snapshot page %s: %w", path, err)
+	}
+	defer func() { _ = zr.Close() }()
+
+	b, err := io.ReadAll(zr)
+	if err != nil {
+		return "", fmt.Errorf("read snapshot page %s: %w", path, err)
+	}
+
+	return string(b), nil
+}
+
+// Latest returns the most recent snapshot directory under root.
+func Latest(root string) (string, error) {
+	dirs, err := os.ReadDir(root)
+	if err != nil {
+		return "", fmt.Errorf("list snapshots: %w", err)
+	}
+
+	var names []string
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(root, d.Name(), indexFile)); err == nil {
+			names = append(names, d.Name())
+		}
+	}
+	if len(names) == 0 {
+		return "", ErrNoSnapshots
+	}
+
+	sort.Strings(names)
+
+	return filepath.Join(root, names[len(names)-1]), nil
+}
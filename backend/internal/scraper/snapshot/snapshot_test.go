@@ -0,0 +1,159 @@
+package snapshot
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/burbble/marketplace/internal/scraper/store77"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	root := t.TempDir()
+	startedAt := time.Date(2026, 2, 3, 12, 0, 0, 0, time.UTC)
+
+	rec, err := NewRecorder(root, "run1", startedAt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(rec.Dir()) != "20260203T120000Z_run1" {
+		t.Errorf("unexpected snapshot dir %s", rec.Dir())
+	}
+
+	pages := []struct{ url, html string }{
+		{"https://store77.net/phones/", "<html>shell</html>"},
+		{"https://store77.net/phones/", "<html>rendered</html>"},
+		{"https://store77.net/p/1/", "<html>product</html>"},
+	}
+	for _, p := range pages {
+		if err := rec.Save(p.url, p.html, startedAt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := rec.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(filepath.Join(rec.Dir(), "000003_"+urlHash("https://store77.net/p/1/")+".html.gz"))
+	if err != nil {
+		t.Fatalf("expected gzipped page on disk: %v", err)
+	}
+	if _, err := gzip.NewReader(f); err != nil {
+		t.Errorf("page is not gzipped: %v", err)
+	}
+	_ = f.Close()
+
+	replay, err := OpenReplay(rec.Dir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replay.Len() != 2 {
+		t.Errorf("expected 2 urls, got %d", replay.Len())
+	}
+
+	ctx := context.Background()
+	for _, want := range []string{"<html>shell</html>", "<html>rendered</html>", "<html>rendered</html>"} {
+		got, err := replay.Fetch(ctx, "https://store77.net/phones/")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("expected %q, got %q", want, got)
+		}
+	}
+
+	_, err = replay.Fetch(ctx, "https://store77.net/missing/")
+	var statusErr *store77.StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404 for unrecorded url, got %v", err)
+	}
+}
+
+func TestLatest(t *testing.T) {
+	root := t.TempDir()
+
+	if _, err := Latest(root); !errors.Is(err, ErrNoSnapshots) {
+		t.Errorf("expected ErrNoSnapshots, got %v", err)
+	}
+
+	for _, at := range []time.Time{
+		time.Date(2026, 2, 3, 12, 0, 0, 0, time.UTC),
+		time.Date(2026, 2, 4, 9, 0, 0, 0, time.UTC),
+		time.Date(2026, 2, 1, 23, 0, 0, 0, time.UTC),
+	} {
+		rec, err := NewRecorder(root, "", at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = rec.Close()
+	}
+
+	latest, err := Latest(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(latest) != "20260204T090000Z" {
+		t.Errorf("expected newest snapshot, got %s", latest)
+	}
+}
+
+type pageFetcher map[string]string
+
+func (f pageFetcher) Fetch(_ context.Context, url string) (string, error) {
+	if html, ok := f[url]; ok {
+		return html, nil
+	}
+	return "", &store77.StatusError{URL: url, StatusCode: http.StatusNotFound}
+}
+
+// TestScraperReplaysRecordedRun records a scraper session and replays it
+// through a second scraper with no access to the original source.
+func TestScraperReplaysRecordedRun(t *testing.T) {
+	const (
+		categoryHTML = `<html><body><div class="wrap_list_prod"></div></body></html>`
+		productHTML  = `<html><body><div class="product_description">Описание</div></body></html>`
+	)
+	live := pageFetcher{
+		"https://store77.net/phones/": categoryHTML,
+		"https://store77.net/p/1/":    productHTML,
+	}
+
+	rec, err := NewRecorder(t.TempDir(), "", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	recording := store77.NewScraper(zap.NewNop(), store77.Config{HTTPFetcher: live, BrowserFetcher: live})
+	recording.SetRecorder(rec)
+	if _, err := recording.FetchCategoryPage(ctx, "/phones/", 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := recording.FetchProductPage(ctx, "/p/1/"); err != nil {
+		t.Fatal(err)
+	}
+	_ = rec.Close()
+
+	replay, err := OpenReplay(rec.Dir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	replaying := store77.NewScraper(zap.NewNop(), store77.Config{HTTPFetcher: replay, BrowserFetcher: replay})
+
+	if html, err := replaying.FetchCategoryPage(ctx, "/phones/", 1); err != nil || html != categoryHTML {
+		t.Errorf("expected recorded category page, got %q (%v)", html, err)
+	}
+	if html, err := replaying.FetchProductPage(ctx, "/p/1/"); err != nil || html != productHTML {
+		t.Errorf("expected recorded product page, got %q (%v)", html, err)
+	}
+	if _, err := replaying.FetchCategoryPage(ctx, "/tablets/", 1); err == nil {
+		t.Error("expected error for a page missing from the snapshot")
+	}
+}
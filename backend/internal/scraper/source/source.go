@@ -0,0 +1,173 @@
+// Package source defines what the parser needs from a shop it scrapes. Each
+// shop is a Source: it lists its catalog categories, the products on a page
+// of a category and the detail of a product page. The parser drives every
+// source enabled in SCRAPE_SOURCES the same way; products and categories are
+// stored per source, so external ids and slugs only have to be unique within
+// their shop.
+package source
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Source is a shop the parser can scrape.
+type Source interface {
+	// Name identifies the source in configuration and in the database.
+	Name() string
+	// ListCategories returns the catalog categories, parents before their
+	// children.
+	ListCategories(ctx context.Context) ([]Category, error)
+	// ListProducts returns the products listed on a page of a leaf
+	// category, counting from 1.
+	ListProducts(ctx context.Context, cat Category, page int) (*Page, error)
+	// FetchDetail loads and parses a product page.
+	FetchDetail(ctx context.Context, productURL string) (*ProductDetail, error)
+	// DrainGivenUp returns the pages given up on since the previous call,
+	// except those fetched with a GivenUpLog in their context.
+	DrainGivenUp() []GivenUpPage
+	// Stop releases resources held between runs, such as a browser.
+	Stop()
+}
+
+// Recorder archives fetched pages, e.g. into a snapshot for later replay.
+type Recorder interface {
+	Save(url, html string, fetchedAt time.Time) error
+}
+
+// Recording is implemented by sources that can archive the pages they fetch.
+type Recording interface {
+	SetRecorder(r Recorder)
+}
+
+// Category is a catalog menu entry. ParentSlug is empty for top-level
+// entries; Leaf marks categories without subcategories, which are the ones
+// that list products.
+type Category struct {
+	Name       string
+	URL        string
+	Slug       string
+	ParentSlug string
+	Depth      int
+	Leaf       bool
+}
+
+type Product struct {
+	ExternalID  string
+	SKU         string
+	Name        string
+	Price       int
+	ImageURL    string
+	ProductURL  string
+	Brand       string
+	Category    string
+	Description string
+}
+
+// Fingerprint identifies the listing state of a product: it changes when the
+// name, price or image shown on the category page changes.
+func (p Product) Fingerprint() string {
+	sum := sha256.Sum256([]byte(p.Name + "\x00" + strconv.Itoa(p.Price) + "\x00" + p.ImageURL))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Page is a page of a category listing. TotalPages and TotalProducts describe
+// the whole category; TotalProducts is 0 when the page does not show it.
+type Page struct {
+	Products      []Product
+	Health        PageHealth
+	TotalPages    int
+	TotalProducts int
+}
+
+// PageHealth describes how well a category page matched the expected
+// markup: Cards is the number of product cards found, Parsed how many of
+// them yielded a product, and Missing* count parsed products without the
+// field.
+type PageHealth struct {
+	Cards        int
+	Parsed       int
+	MissingPrice int
+	MissingBrand int
+	MissingSKU   int
+	MissingEcom  int
+}
+
+// Attribute is a row of the specification table. Group is the section
+// heading the row is listed under, if any.
+type Attribute struct {
+	Group string
+	Name  string
+	Value string
+}
+
+// Variant links to a sibling product that differs in color or memory.
+// Current marks the variant the page itself shows.
+type Variant struct {
+	Kind    string
+	Label   string
+	URL     string
+	Current bool
+}
+
+// ProductDetail is what a product page adds to the listing card.
+type ProductDetail struct {
+	Description string
+	Attributes  []Attribute
+	Images      []string
+	// StockLabel is the availability text as shown; StockStatus is one of
+	// the domain.Stock* constants.
+	StockLabel  string
+	StockStatus string
+	Variants    []Variant
+}
+
+// GivenUpPage is a page the scraper stopped trying to fetch, either because
+// the error was permanent or because every attempt failed.
+type GivenUpPage struct {
+	URL       string
+	Kind      string
+	Attempts  int
+	Permanent bool
+	Err       error
+}
+
+// GivenUpLog collects given-up pages. Sources keep one for DrainGivenUp; a
+// caller that needs the pages of its own fetches apart, such as a queue job
+// running next to others, passes one in the context with WithGivenUpLog.
+type GivenUpLog struct {
+	mu    sync.Mutex
+	pages []GivenUpPage
+}
+
+func (l *GivenUpLog) Add(p GivenUpPage) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.pages = append(l.pages, p)
+}
+
+// Drain returns the collected pages and clears the log.
+func (l *GivenUpLog) Drain() []GivenUpPage {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	pages := l.pages
+	l.pages = nil
+	return pages
+}
+
+type givenUpLogCtxKey struct{}
+
+// WithGivenUpLog makes sources report the pages they give up on during
+// fetches made with ctx to log instead of DrainGivenUp.
+func WithGivenUpLog(ctx context.Context, log *GivenUpLog) context.Context {
+	return context.WithValue(ctx, givenUpLogCtxKey{}, log)
+}
+
+func GivenUpLogFrom(ctx context.Context) *GivenUpLog {
+	log, _ := ctx.Value(givenUpLogCtxKey{}).(*GivenUpLog)
+	return log
+}
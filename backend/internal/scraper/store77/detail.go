@@ -0,0 +1,216 @@
+package store77
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"github.com/burbble/marketplace/internal/scraper/source"
+)
+
+// Stock statuses derived from the availability label of a product page.
+const (
+	StockInStock    = "in_stock"
+	StockOutOfStock = "out_of_stock"
+	StockPreorder   = "preorder"
+	StockUnknown    = "unknown"
+)
+
+// Variant kinds offered on a product page.
+const (
+	VariantColor  = "color"
+	VariantMemory = "memory"
+)
+
+type (
+	Attribute     = source.Attribute
+	Variant       = source.Variant
+	ProductDetail = source.ProductDetail
+)
+
+var (
+	specRowSelectors = []string{
+		"div.wrap_tabs_prod table tr",
+		"table.tabs_table tr",
+		"div#props table tr",
+	}
+	galleryImageSelectors = []string{
+		"div.prod_gallery a[data-fancybox]",
+		"div.prod_gallery img",
+		"div.slider_prod img",
+		"[itemprop='image']",
+	}
+	stockSelectors = []string{
+		"div.wrap_price_prod .prod_status",
+		"p.prod_status",
+		"div.availability",
+	}
+	variantSelectors = map[string]string{
+		VariantColor:  "div.prod_colors a, ul.color_list a",
+		VariantMemory: "div.prod_memory a, ul.memory_list a",
+	}
+)
+
+// ParseProductDetail parses a product page: the description, the
+// specification table, gallery images, the availability label and links to
+// color and memory variants. Missing sections are left empty.
+func ParseProductDetail(html string) (*ProductDetail, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return nil, fmt.Errorf("parse html: %w", err)
+	}
+
+	d := &ProductDetail{
+		Description: productDescription(doc),
+		Attributes:  productAttributes(doc),
+		Images:      productImages(doc),
+		StockLabel:  stockLabel(doc),
+		Variants:    productVariants(doc),
+	}
+	d.StockStatus = StockStatus(d.StockLabel)
+
+	return d, nil
+}
+
+// productAttributes reads the first specification table found. A row with a
+// single cell is a section heading for the rows below it. Schema.org
+// additionalProperty markup is used when there is no table.
+func productAttributes(doc *goquery.Document) []Attribute {
+	var attrs []Attribute
+
+	for _, sel := range specRowSelectors {
+		group := ""
+		doc.Find(sel).Each(func(_ int, row *goquery.Selection) {
+			cells := row.Find("th, td")
+			switch cells.Length() {
+			case 1:
+				group = normalizeSpace(cells.Text())
+			case 2:
+				name := strings.TrimSuffix(normalizeSpace(cells.Eq(0).Text()), ":")
+				value := normalizeSpace(cells.Eq(1).Text())
+				if name != "" && value != "" {
+					attrs = append(attrs, Attribute{Group: group, Name: name, Value: value})
+				}
+			}
+		})
+		if len(attrs) > 0 {
+			return attrs
+		}
+	}
+
+	doc.Find("[itemprop='additionalProperty']").Each(func(_ int, prop *goquery.Selection) {
+		name := normalizeSpace(prop.Find("[itemprop='name']").First().Text())
+		valueEl := prop.Find("[itemprop='value']").First()
+		value := valueEl.AttrOr("content", "")
+		if value == "" {
+			value = normalizeSpace(valueEl.Text())
+		}
+		if name != "" && value != "" {
+			attrs = append(attrs, Attribute{Name: name, Value: value})
+		}
+	})
+
+	return attrs
+}
+
+// productImages returns the gallery image URLs in page order without
+// duplicates. Links to full-size images are preferred over thumbnails.
+func productImages(doc *goquery.Document) []string {
+	var images []string
+	seen := make(map[string]struct{})
+
+	for _, sel := range galleryImageSelectors {
+		doc.Find(sel).Each(func(_ int, el *goquery.Selection) {
+			u := imageURL(el)
+			if u == "" {
+				return
+			}
+			if _, ok := seen[u]; ok {
+				return
+			}
+			seen[u] = struct{}{}
+			images = append(images, u)
+		})
+		if len(images) > 0 {
+			return images
+		}
+	}
+
+	return images
+}
+
+func imageURL(el *goquery.Selection) string {
+	for _, attr := range []string{"href", "data-src", "src", "content"} {
+		if v := strings.TrimSpace(el.AttrOr(attr, "")); v != "" && !strings.HasPrefix(v, "data:") {
+			return v
+		}
+	}
+	return ""
+}
+
+func stockLabel(doc *goquery.Document) string {
+	for _, sel := range stockSelectors {
+		if label := normalizeSpace(doc.Find(sel).First().Text()); label != "" {
+			return label
+		}
+	}
+
+	// Schema.org availability links to e.g. https://schema.org/InStock.
+	if href, ok := doc.Find("[itemprop='availability']").First().Attr("href"); ok {
+		switch {
+		case strings.HasSuffix(href, "/InStock"):
+			return "В наличии"
+		case strings.HasSuffix(href, "/OutOfStock"):
+			return "Нет в наличии"
+		case strings.HasSuffix(href, "/PreOrder"):
+			return "Предзаказ"
+		}
+	}
+
+	return ""
+}
+
+// StockStatus maps an availability label to one of the Stock* constants.
+func StockStatus(label string) string {
+	l := strings.ToLower(label)
+	switch {
+	case l == "":
+		return StockUnknown
+	case strings.Contains(l, "нет в наличии"), strings.Contains(l, "отсутству"), strings.Contains(l, "распродан"):
+		return StockOutOfStock
+	case strings.Contains(l, "под заказ"), strings.Contains(l, "предзаказ"), strings.Contains(l, "ожидается"):
+		return StockPreorder
+	case strings.Contains(l, "в наличии"):
+		return StockInStock
+	default:
+		return StockUnknown
+	}
+}
+
+func productVariants(doc *goquery.Document) []Variant {
+	var variants []Variant
+
+	for _, kind := range []string{VariantColor, VariantMemory} {
+		doc.Find(variantSelectors[kind]).Each(func(_ int, a *goquery.Selection) {
+			href := strings.TrimSpace(a.AttrOr("href", ""))
+			if href == "" || href == "#" {
+				return
+			}
+
+			label := strings.TrimSpace(a.AttrOr("title", ""))
+			if label == "" {
+				label = normalizeSpace(a.Text())
+			}
+
+			variants = append(variants, Variant{
+				Kind:    kind,
+				Label:   label,
+				URL:     href,
+				Current: a.HasClass("active") || a.HasClass("selected") || a.Parent().HasClass("active"),
+			})
+		})
+	}
+
+	return variants
+}
@@ -0,0 +1,135 @@
+package store77
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseProductDetail(t *testing.T) {
+	html := `<html><body>
+		<div class="prod_gallery">
+			<a data-fancybox="gallery" href="/upload/iphone-1.jpg"><img src="/upload/thumb/iphone-1.jpg"></a>
+			<a data-fancybox="gallery" href="/upload/iphone-2.jpg"><img src="/upload/thumb/iphone-2.jpg"></a>
+			<a data-fancybox="gallery" href="/upload/iphone-1.jpg"><img src="/upload/thumb/iphone-1.jpg"></a>
+		</div>
+		<div class="wrap_price_prod"><p class="prod_status"> В наличии </p></div>
+		<div class="prod_colors">
+			<a href="/phones/iphone-15-black/" title="Черный" class="active"></a>
+			<a href="/phones/iphone-15-blue/" title="Синий"></a>
+		</div>
+		<div class="prod_memory">
+			<a href="/phones/iphone-15-black/">128 ГБ</a>
+			<a href="/phones/iphone-15-black-256/">256 ГБ</a>
+			<a href="#">512 ГБ</a>
+		</div>
+		<div class="detail_text">Смартфон   Apple</div>
+		<div class="wrap_tabs_prod">
+			<table>
+				<tr><td colspan="2">Экран</td></tr>
+				<tr><td>Диагональ:</td><td>6.1 "</td></tr>
+				<tr><td>Разрешение</td><td> 2556 x 1179 </td></tr>
+				<tr><td colspan="2">Память</td></tr>
+				<tr><td>Встроенная память</td><td>128 ГБ</td></tr>
+				<tr><td>Пусто</td><td></td></tr>
+			</table>
+		</div>
+	</body></html>`
+
+	d, err := ParseProductDetail(html)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if d.Description != "Смартфон Apple" {
+		t.Errorf("unexpected description %q", d.Description)
+	}
+
+	wantAttrs := []Attribute{
+		{Group: "Экран", Name: "Диагональ", Value: `6.1 "`},
+		{Group: "Экран", Name: "Разрешение", Value: "2556 x 1179"},
+		{Group: "Память", Name: "Встроенная память", Value: "128 ГБ"},
+	}
+	if !reflect.DeepEqual(d.Attributes, wantAttrs) {
+		t.Errorf("attributes = %+v, want %+v", d.Attributes, wantAttrs)
+	}
+
+	wantImages := []string{"/upload/iphone-1.jpg", "/upload/iphone-2.jpg"}
+	if !reflect.DeepEqual(d.Images, wantImages) {
+		t.Errorf("images = %v, want %v", d.Images, wantImages)
+	}
+
+	if d.StockLabel != "В наличии" || d.StockStatus != StockInStock {
+		t.Errorf("unexpected stock %q / %q", d.StockLabel, d.StockStatus)
+	}
+
+	wantVariants := []Variant{
+		{Kind: VariantColor, Label: "Черный", URL: "/phones/iphone-15-black/", Current: true},
+		{Kind: VariantColor, Label: "Синий", URL: "/phones/iphone-15-blue/"},
+		{Kind: VariantMemory, Label: "128 ГБ", URL: "/phones/iphone-15-black/"},
+		{Kind: VariantMemory, Label: "256 ГБ", URL: "/phones/iphone-15-black-256/"},
+	}
+	if !reflect.DeepEqual(d.Variants, wantVariants) {
+		t.Errorf("variants = %+v, want %+v", d.Variants, wantVariants)
+	}
+}
+
+func TestParseProductDetailSchemaOrg(t *testing.T) {
+	html := `<html><body>
+		<div itemscope itemtype="https://schema.org/Product">
+			<meta itemprop="image" content="/upload/watch.jpg">
+			<div itemprop="offers" itemscope><link itemprop="availability" href="https://schema.org/OutOfStock"></div>
+			<div itemprop="additionalProperty" itemscope>
+				<span itemprop="name">Материал корпуса</span>
+				<meta itemprop="value" content="Алюминий">
+			</div>
+		</div>
+	</body></html>`
+
+	d, err := ParseProductDetail(html)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(d.Attributes) != 1 || d.Attributes[0] != (Attribute{Name: "Материал корпуса", Value: "Алюминий"}) {
+		t.Errorf("unexpected attributes %+v", d.Attributes)
+	}
+	if !reflect.DeepEqual(d.Images, []string{"/upload/watch.jpg"}) {
+		t.Errorf("unexpected images %v", d.Images)
+	}
+	if d.StockStatus != StockOutOfStock {
+		t.Errorf("expected out of stock, got %q (%q)", d.StockStatus, d.StockLabel)
+	}
+}
+
+func TestParseProductDetailEmpty(t *testing.T) {
+	d, err := ParseProductDetail(`<html><body><div>Нет данных</div></body></html>`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if d.Description != "" || len(d.Attributes) != 0 || len(d.Images) != 0 || len(d.Variants) != 0 {
+		t.Errorf("expected an empty detail, got %+v", d)
+	}
+	if d.StockStatus != StockUnknown {
+		t.Errorf("expected unknown stock, got %q", d.StockStatus)
+	}
+}
+
+func TestStockStatus(t *testing.T) {
+	tests := map[string]string{
+		"В наличии":          StockInStock,
+		"В наличии 5 шт.":    StockInStock,
+		"Нет в наличии":      StockOutOfStock,
+		"Товар отсутствует":  StockOutOfStock,
+		"Под заказ 3-5 дней": StockPreorder,
+		"Предзаказ":          StockPreorder,
+		"Уточняйте":          StockUnknown,
+		"":                   StockUnknown,
+	}
+
+	for label, want := range tests {
+		if got := StockStatus(label); got != want {
+			t.Errorf("StockStatus(%q) = %q, want %q", label, got, want)
+		}
+	}
+}
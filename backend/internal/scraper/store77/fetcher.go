@@ -0,0 +1,178 @@
+package store77
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"go.uber.org/zap"
+
+	"github.com/burbble/marketplace/internal/scraper/source"
+)
+
+const (
+	FetchModeHTTP    = "http"
+	FetchModeBrowser = "browser"
+
+	// browserModeTTL is how long a page stays on the browser before plain
+	// HTTP is tried again.
+	browserModeTTL = 6 * time.Hour
+)
+
+// Fetcher loads the HTML of a page.
+type Fetcher interface {
+	Fetch(ctx context.Context, url string) (string, error)
+}
+
+// FetcherFunc adapts a function to Fetcher.
+type FetcherFunc func(ctx context.Context, url string) (string, error)
+
+func (f FetcherFunc) Fetch(ctx context.Context, url string) (string, error) {
+	return f(ctx, url)
+}
+
+type Recorder = source.Recorder
+
+// SetRecorder makes the scraper archive every page it successfully fetches.
+// Passing nil stops recording.
+func (s *Scraper) SetRecorder(r Recorder) {
+	s.recorderMu.Lock()
+	defer s.recorderMu.Unlock()
+	s.recorder = r
+}
+
+// fetch loads url with f and hands the page to the recorder, if any.
+func (s *Scraper) fetch(ctx context.Context, f Fetcher, url string) (string, error) {
+	html, err := f.Fetch(ctx, url)
+	if err != nil {
+		return "", err
+	}
+
+	s.recorderMu.Lock()
+	rec := s.recorder
+	s.recorderMu.Unlock()
+
+	if rec != nil {
+		if err := rec.Save(url, html, time.Now()); err != nil {
+			s.logger.Warn("failed to record page", zap.String("url", url), zap.Error(err))
+		}
+	}
+
+	return html, nil
+}
+
+type fetchMode struct {
+	mode  string
+	until time.Time
+}
+
+// fetchModes remembers, per listing, whether plain HTTP returns usable
+// markup or the page needs the browser.
+type fetchModes struct {
+	mu    sync.Mutex
+	modes map[string]fetchMode
+}
+
+func (m *fetchModes) get(key string, now time.Time) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	fm, ok := m.modes[key]
+	if !ok || (!fm.until.IsZero() && now.After(fm.until)) {
+		return ""
+	}
+	return fm.mode
+}
+
+func (m *fetchModes) set(key, mode string, now time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	fm := fetchMode{mode: mode}
+	if mode == FetchModeBrowser {
+		fm.until = now.Add(browserModeTTL)
+	}
+	m.modes[key] = fm
+}
+
+// FetchMode returns the mode remembered for a category path, or an empty
+// string if none is known yet.
+func (s *Scraper) FetchMode(path string) string {
+	return s.modes.get(path, time.Now())
+}
+
+// fetchListing loads a listing page over plain HTTP and falls back to the
+// browser when the response lacks the markup valid looks for, is a captcha,
+// or plain HTTP is refused. A page that empty accepts, such as the listing
+// of an empty category, is used as it is. The outcome is remembered per key,
+// so listings that need the browser skip the HTTP attempt until
+// browserModeTTL passes. Transient HTTP errors fall back for the current
+// page only; a missing page is given up on.
+func (s *Scraper) fetchListing(ctx context.Context, kind, key, url string, valid, empty func(string) bool) (string, error) {
+	if s.modes.get(key, time.Now()) != FetchModeBrowser {
+		html, err := s.fetch(ctx, s.httpFetcher, url)
+		switch {
+		case err == nil && (valid(html) || empty != nil && empty(html)):
+			s.modes.set(key, FetchModeHTTP, time.Now())
+			return html, nil
+		case ctx.Err() != nil:
+			return "", ctx.Err()
+		case isNotFound(err):
+			s.giveUp(ctx, GivenUpPage{URL: url, Kind: kind, Attempts: 1, Permanent: true, Err: err})
+			return "", err
+		case err == nil:
+			s.logger.Info("expected markup missing over http, using browser", zap.String("url", url))
+			s.modes.set(key, FetchModeBrowser, time.Now())
+		case errors.Is(err, ErrCaptcha):
+			s.logger.Info("captcha served over http, using browser", zap.String("url", url))
+			s.modes.set(key, FetchModeBrowser, time.Now())
+		case !s.retryable(err):
+			s.logger.Info("http fetch refused, using browser", zap.String("url", url), zap.Error(err))
+			s.modes.set(key, FetchModeBrowser, time.Now())
+		default:
+			s.logger.Debug("http fetch failed, using browser for this page", zap.String("url", url), zap.Error(err))
+		}
+	}
+
+	return s.withRetry(ctx, kind, url, func() (string, error) {
+		return s.fetch(ctx, s.browserFetcher, url)
+	})
+}
+
+func isNotFound(err error) bool {
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) &&
+		(statusErr.StatusCode == http.StatusNotFound || statusErr.StatusCode == http.StatusGone)
+}
+
+func hasProductList(html string) bool {
+	return hasElement(html, "div.wrap_list_prod")
+}
+
+func hasCategoryMenu(html string) bool {
+	return hasElement(html, "ul.catalog_menu")
+}
+
+// isEmptyListing reports whether a category page without product cards is
+// the rendered listing of an empty category rather than a shell its scripts
+// have yet to fill: it carries the site's catalog menu and counts no
+// products.
+func isEmptyListing(html string) bool {
+	if !hasCategoryMenu(html) {
+		return false
+	}
+	total, err := ParseTotalProducts(html)
+	return err == nil && total == 0
+}
+
+func hasElement(html, selector string) bool {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return false
+	}
+	return doc.Find(selector).Length() > 0
+}
@@ -0,0 +1,180 @@
+package store77
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	listingHTML = `<html><body><div class="wrap_list_prod"><div class="blocks_product"></div></div></body></html>`
+	shellHTML   = `<html><body><div id="app"></div><script src="/bundle.js"></script></body></html>`
+	emptyHTML   = `<html><body><ul class="catalog_menu"><li><a href="/telefony/">Телефоны</a></li></ul><div class="search_all_produkt">Товаров: <span>0</span></div></body></html>`
+)
+
+type fakeFetcher struct {
+	calls   int
+	results []fakeResult
+}
+
+type fakeResult struct {
+	html string
+	err  error
+}
+
+func (f *fakeFetcher) Fetch(_ context.Context, _ string) (string, error) {
+	r := f.results[min(f.calls, len(f.results)-1)]
+	f.calls++
+	return r.html, r.err
+}
+
+func newFetcherScraper(httpF, browserF *fakeFetcher) *Scraper {
+	return NewScraper(zap.NewNop(), Config{
+		RetryAttempts:  1,
+		HTTPFetcher:    httpF,
+		BrowserFetcher: browserF,
+	})
+}
+
+func TestFetchCategoryPage_HTTPFirst(t *testing.T) {
+	httpF := &fakeFetcher{results: []fakeResult{{html: listingHTML}}}
+	browserF := &fakeFetcher{results: []fakeResult{{err: errors.New("browser should not be used")}}}
+	s := newFetcherScraper(httpF, browserF)
+
+	for page := 1; page <= 2; page++ {
+		html, err := s.FetchCategoryPage(context.Background(), "/telefony_apple/", page)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if html != listingHTML {
+			t.Errorf("unexpected html %q", html)
+		}
+	}
+
+	if httpF.calls != 2 || browserF.calls != 0 {
+		t.Errorf("expected 2 http and 0 browser fetches, got %d/%d", httpF.calls, browserF.calls)
+	}
+	if mode := s.FetchMode("/telefony_apple/"); mode != FetchModeHTTP {
+		t.Errorf("expected mode %q, got %q", FetchModeHTTP, mode)
+	}
+}
+
+func TestFetchCategoryPage_FallsBackWhenMarkupMissing(t *testing.T) {
+	httpF := &fakeFetcher{results: []fakeResult{{html: shellHTML}}}
+	browserF := &fakeFetcher{results: []fakeResult{{html: listingHTML}}}
+	s := newFetcherScraper(httpF, browserF)
+
+	for page := 1; page <= 3; page++ {
+		html, err := s.FetchCategoryPage(context.Background(), "/noutbuki/", page)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if html != listingHTML {
+			t.Errorf("expected browser html, got %q", html)
+		}
+	}
+
+	if httpF.calls != 1 {
+		t.Errorf("expected http to be tried once and then remembered as unusable, got %d calls", httpF.calls)
+	}
+	if browserF.calls != 3 {
+		t.Errorf("expected 3 browser fetches, got %d", browserF.calls)
+	}
+	if mode := s.FetchMode("/noutbuki/"); mode != FetchModeBrowser {
+		t.Errorf("expected mode %q, got %q", FetchModeBrowser, mode)
+	}
+	if mode := s.FetchMode("/telefony_apple/"); mode != "" {
+		t.Errorf("expected other categories to be unaffected, got %q", mode)
+	}
+}
+
+func TestFetchCategoryPage_TransientHTTPErrorFallsBackOnce(t *testing.T) {
+	httpF := &fakeFetcher{results: []fakeResult{
+		{err: &StatusError{StatusCode: http.StatusBadGateway}},
+		{html: listingHTML},
+	}}
+	browserF := &fakeFetcher{results: []fakeResult{{html: listingHTML}}}
+	s := newFetcherScraper(httpF, browserF)
+
+	for page := 1; page <= 2; page++ {
+		if _, err := s.FetchCategoryPage(context.Background(), "/planshety/", page); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if httpF.calls != 2 || browserF.calls != 1 {
+		t.Errorf("expected 2 http and 1 browser fetches, got %d/%d", httpF.calls, browserF.calls)
+	}
+	if mode := s.FetchMode("/planshety/"); mode != FetchModeHTTP {
+		t.Errorf("expected mode %q, got %q", FetchModeHTTP, mode)
+	}
+}
+
+func TestFetchCategoryPage_NotFoundSkipsBrowser(t *testing.T) {
+	httpF := &fakeFetcher{results: []fakeResult{{err: &StatusError{StatusCode: http.StatusNotFound}}}}
+	browserF := &fakeFetcher{results: []fakeResult{{html: listingHTML}}}
+	s := newFetcherScraper(httpF, browserF)
+
+	if _, err := s.FetchCategoryPage(context.Background(), "/removed/", 1); err == nil {
+		t.Fatal("expected error")
+	}
+	if browserF.calls != 0 {
+		t.Errorf("expected no browser fetch for a missing page, got %d", browserF.calls)
+	}
+	if given := s.DrainGivenUp(); len(given) != 1 || !given[0].Permanent || given[0].Kind != PageKindCategory {
+		t.Errorf("expected the missing page to be given up on permanently, got %+v", given)
+	}
+}
+
+func TestFetchCategoryPage_EmptyCategoryStaysOnHTTP(t *testing.T) {
+	httpF := &fakeFetcher{results: []fakeResult{{html: emptyHTML}}}
+	browserF := &fakeFetcher{results: []fakeResult{{err: errors.New("browser should not be used")}}}
+	s := newFetcherScraper(httpF, browserF)
+
+	html, err := s.FetchCategoryPage(context.Background(), "/empty/", 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if html != emptyHTML || browserF.calls != 0 {
+		t.Errorf("expected the empty listing over http, got %d browser fetches", browserF.calls)
+	}
+	if mode := s.FetchMode("/empty/"); mode != FetchModeHTTP {
+		t.Errorf("expected mode %q, got %q", FetchModeHTTP, mode)
+	}
+}
+
+func TestFetchCategoryPage_CaptchaSwitchesToBrowser(t *testing.T) {
+	httpF := &fakeFetcher{results: []fakeResult{{err: fmt.Errorf("https://store77.net/noutbuki/: %w", ErrCaptcha)}}}
+	browserF := &fakeFetcher{results: []fakeResult{{html: listingHTML}}}
+	s := newFetcherScraper(httpF, browserF)
+
+	if _, err := s.FetchCategoryPage(context.Background(), "/noutbuki/", 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mode := s.FetchMode("/noutbuki/"); mode != FetchModeBrowser {
+		t.Errorf("expected mode %q, got %q", FetchModeBrowser, mode)
+	}
+}
+
+func TestFetchModes_BrowserModeExpires(t *testing.T) {
+	m := fetchModes{modes: make(map[string]fetchMode)}
+	now := time.Date(2026, 2, 3, 12, 0, 0, 0, time.UTC)
+
+	m.set("/noutbuki/", FetchModeBrowser, now)
+	if got := m.get("/noutbuki/", now.Add(time.Hour)); got != FetchModeBrowser {
+		t.Errorf("expected browser mode, got %q", got)
+	}
+	if got := m.get("/noutbuki/", now.Add(browserModeTTL+time.Second)); got != "" {
+		t.Errorf("expected browser mode to expire, got %q", got)
+	}
+
+	m.set("/telefony_apple/", FetchModeHTTP, now)
+	if got := m.get("/telefony_apple/", now.Add(30*24*time.Hour)); got != FetchModeHTTP {
+		t.Errorf("expected http mode to persist, got %q", got)
+	}
+}
@@ -8,25 +8,14 @@ import (
 	"strings"
 
 	"github.com/PuerkitoBio/goquery"
-)
 
-type Category struct {
-	Name string
-	URL  string
-	Slug string
-}
+	"github.com/burbble/marketplace/internal/scraper/source"
+)
 
-type Product struct {
-	ExternalID  string
-	SKU         string
-	Name        string
-	Price       int
-	ImageURL    string
-	ProductURL  string
-	Brand       string
-	Category    string
-	Description string
-}
+type (
+	Category = source.Category
+	Product  = source.Product
+)
 
 type PaginationInfo struct {
 	CurrentPage int
@@ -50,72 +39,128 @@ func normalizeSpace(s string) string {
 	return strings.TrimSpace(spacesRe.ReplaceAllString(s, " "))
 }
 
+// ParseCategories walks the three-level catalog menu and returns every
+// category with its parent, parents before their children. Menu levels
+// without a link of their own are skipped and their children attach to the
+// nearest linked ancestor.
 func ParseCategories(html string) ([]Category, error) {
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
 	if err != nil {
 		return nil, fmt.Errorf("parse html: %w", err)
 	}
 
-	seen := make(map[string]struct{})
-	var categories []Category
+	tree := &categoryTree{seen: make(map[string]int)}
 
 	doc.Find("ul.catalog_menu > li").Each(func(_ int, topLi *goquery.Selection) {
+		top := tree.add(menuLink(topLi, "div.bli_pos_first > a"), nil)
+
 		topLi.Find("ul.catalog_menu_sub_second > li").Each(func(_ int, secLi *goquery.Selection) {
-			thirdUl := secLi.Find("ul.catalog_menu_sub_third")
-
-			if thirdUl.Length() > 0 {
-				thirdUl.Find("li > a").Each(func(_ int, a *goquery.Selection) {
-					href, _ := a.Attr("href")
-					name := strings.TrimSpace(a.Text())
-					addCategory(&categories, seen, name, href)
-				})
-			} else {
-				a := secLi.Find("div.bli_pos_second > a")
-				href, _ := a.Attr("href")
-				name := strings.TrimSpace(a.Text())
-				addCategory(&categories, seen, name, href)
+			second := tree.add(secLi.Find("div.bli_pos_second > a").First(), top)
+			if second == nil {
+				second = top
 			}
+
+			secLi.Find("ul.catalog_menu_sub_third li > a").Each(func(_ int, a *goquery.Selection) {
+				tree.add(a, second)
+			})
 		})
 	})
 
-	return categories, nil
+	return tree.categories(), nil
 }
 
-func addCategory(categories *[]Category, seen map[string]struct{}, name, href string) {
-	if href == "" || name == "" || href == "#" {
-		return
+type categoryTree struct {
+	list []Category
+	seen map[string]int
+}
+
+// add records the category behind link under parent and returns it, or nil
+// when the link is missing or unusable. A URL seen before resolves to the
+// first occurrence.
+func (t *categoryTree) add(link *goquery.Selection, parent *Category) *Category {
+	if link.Length() == 0 {
+		return nil
 	}
 
+	href, _ := link.Attr("href")
 	href = strings.TrimSpace(href)
-	name = normalizeSpace(name)
+	name := normalizeSpace(link.Text())
+	if href == "" || name == "" || href == "#" {
+		return nil
+	}
 
-	if _, ok := seen[href]; ok {
-		return
+	if i, ok := t.seen[href]; ok {
+		c := t.list[i]
+		return &c
 	}
-	seen[href] = struct{}{}
 
 	slug := strings.Trim(href, "/")
 	if idx := strings.Index(slug, "?"); idx != -1 {
 		slug = slug[:idx]
 	}
 
-	*categories = append(*categories, Category{
-		Name: name,
-		URL:  href,
-		Slug: slug,
-	})
+	c := Category{Name: name, URL: href, Slug: slug}
+	if parent != nil {
+		c.ParentSlug = parent.Slug
+		c.Depth = parent.Depth + 1
+	}
+
+	t.seen[href] = len(t.list)
+	t.list = append(t.list, c)
+
+	return &c
+}
+
+func (t *categoryTree) categories() []Category {
+	hasChildren := make(map[string]bool, len(t.list))
+	for _, c := range t.list {
+		if c.ParentSlug != "" {
+			hasChildren[c.ParentSlug] = true
+		}
+	}
+
+	categories := make([]Category, len(t.list))
+	for i, c := range t.list {
+		c.Leaf = !hasChildren[c.Slug]
+		categories[i] = c
+	}
+
+	return categories
 }
 
+// menuLink returns the link of a menu item: the one inside the given
+// wrapper, or else a direct child anchor.
+func menuLink(li *goquery.Selection, selector string) *goquery.Selection {
+	if a := li.Find(selector).First(); a.Length() > 0 {
+		return a
+	}
+
+	return li.ChildrenFiltered("a").First()
+}
+
+type PageHealth = source.PageHealth
+
 func ParseProducts(html string) ([]Product, error) {
+	products, _, err := ParseProductsWithHealth(html)
+	return products, err
+}
+
+// ParseProductsWithHealth is ParseProducts that also reports PageHealth, so
+// callers can notice when store77 markup stops matching.
+func ParseProductsWithHealth(html string) ([]Product, PageHealth, error) {
+	var health PageHealth
+
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
 	if err != nil {
-		return nil, fmt.Errorf("parse html: %w", err)
+		return nil, health, fmt.Errorf("parse html: %w", err)
 	}
 
 	var products []Product
 
 	doc.Find("div.wrap_list_prod div.blocks_product").Each(func(_ int, card *goquery.Selection) {
+		health.Cards++
 		p := Product{}
+		hasEcom := false
 
 		if btn := card.Find("button.favorite_product"); btn.Length() > 0 {
 			p.ExternalID, _ = btn.Attr("data-elid")
@@ -147,6 +192,7 @@ func ParseProducts(html string) ([]Product, error) {
 		onclick, exists := link.Attr("onclick")
 		if exists {
 			if ecom := parseEcomData(onclick); ecom != nil {
+				hasEcom = true
 				p.SKU = ecom.ID
 				p.Brand = ecom.Brand
 				p.Category = ecom.Category
@@ -158,10 +204,27 @@ func ParseProducts(html string) ([]Product, error) {
 
 		if p.Name != "" && p.ProductURL != "" {
 			products = append(products, p)
+			addHealth(&health, p, hasEcom)
 		}
 	})
 
-	return products, nil
+	return products, health, nil
+}
+
+func addHealth(h *PageHealth, p Product, hasEcom bool) {
+	h.Parsed++
+	if p.Price == 0 {
+		h.MissingPrice++
+	}
+	if p.Brand == "" {
+		h.MissingBrand++
+	}
+	if p.SKU == "" {
+		h.MissingSKU++
+	}
+	if !hasEcom {
+		h.MissingEcom++
+	}
 }
 
 func ParsePagination(html string) (*PaginationInfo, error) {
@@ -225,6 +288,10 @@ func ParseProductDescription(html string) string {
 		return ""
 	}
 
+	return productDescription(doc)
+}
+
+func productDescription(doc *goquery.Document) string {
 	selectors := []string{
 		"div.detail_text",
 		"div#detail_text",
@@ -138,6 +138,42 @@ func TestParseProducts(t *testing.T) {
 	}
 }
 
+func TestParseProductsWithHealth(t *testing.T) {
+	html := `<html><body>
+		<div class="wrap_list_prod">
+			<div class="blocks_product">
+				<div class="blocks_product_fix_w">
+					<a href="/product/full/" onclick="YandexEcommerce.getInstance().click([{&quot;name&quot;:&quot;Full&quot;,&quot;id&quot;:&quot;SKU1&quot;,&quot;price&quot;:1000,&quot;brand&quot;:&quot;Apple&quot;}]);"></a>
+					<p class="bp_text_price">1 000 —</p>
+					<h2 class="bp_text_info"><a href="/product/full/">Full</a></h2>
+				</div>
+			</div>
+			<div class="blocks_product">
+				<div class="blocks_product_fix_w">
+					<a href="/product/bare/"></a>
+					<h2 class="bp_text_info"><a href="/product/bare/">Bare</a></h2>
+				</div>
+			</div>
+			<div class="blocks_product">
+				<div class="some_new_wrapper"><a href="/product/moved/">Moved</a></div>
+			</div>
+		</div>
+	</body></html>`
+
+	products, health, err := ParseProductsWithHealth(html)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(products) != 2 {
+		t.Fatalf("expected 2 products, got %d", len(products))
+	}
+
+	expected := PageHealth{Cards: 3, Parsed: 2, MissingPrice: 1, MissingBrand: 1, MissingSKU: 1, MissingEcom: 1}
+	if health != expected {
+		t.Errorf("expected %+v, got %+v", expected, health)
+	}
+}
+
 func TestParseProductsEmpty(t *testing.T) {
 	html := `<html><body><div class="wrap_list_prod"></div></body></html>`
 
@@ -343,3 +379,74 @@ func TestNormalizeSpace(t *testing.T) {
 		})
 	}
 }
+
+func TestParseCategoriesTree(t *testing.T) {
+	html := `<html><body>
+		<ul class="catalog_menu">
+			<li>
+				<div class="bli_pos_first"><a href="/electronics/">Электроника</a></div>
+				<ul class="catalog_menu_sub_second">
+					<li>
+						<div class="bli_pos_second"><a href="/phones/">Телефоны</a></div>
+						<ul class="catalog_menu_sub_third">
+							<li><a href="/phones/apple/">Apple</a></li>
+							<li><a href="/phones/samsung/">Samsung</a></li>
+						</ul>
+					</li>
+					<li>
+						<div class="bli_pos_second"><a href="/tablets/">Планшеты</a></div>
+					</li>
+				</ul>
+			</li>
+			<li>
+				<a href="/watches/">Часы</a>
+			</li>
+		</ul>
+	</body></html>`
+
+	cats, err := ParseCategories(html)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []Category{
+		{Name: "Электроника", Slug: "electronics", Depth: 0},
+		{Name: "Телефоны", Slug: "phones", ParentSlug: "electronics", Depth: 1},
+		{Name: "Apple", Slug: "phones/apple", ParentSlug: "phones", Depth: 2, Leaf: true},
+		{Name: "Samsung", Slug: "phones/samsung", ParentSlug: "phones", Depth: 2, Leaf: true},
+		{Name: "Планшеты", Slug: "tablets", ParentSlug: "electronics", Depth: 1, Leaf: true},
+		{Name: "Часы", Slug: "watches", Depth: 0, Leaf: true},
+	}
+
+	if len(cats) != len(expected) {
+		t.Fatalf("expected %d categories, got %d", len(expected), len(cats))
+	}
+
+	for i, want := range expected {
+		got := cats[i]
+		if got.Name != want.Name || got.Slug != want.Slug || got.ParentSlug != want.ParentSlug || got.Depth != want.Depth || got.Leaf != want.Leaf {
+			t.Errorf("category %d: expected %+v, got %+v", i, want, got)
+		}
+	}
+}
+
+func TestProductFingerprint(t *testing.T) {
+	base := Product{ExternalID: "1", Name: "iPhone 16", Price: 99990, ImageURL: "/img/1.jpg", Description: "old"}
+
+	same := base
+	same.Description = "new"
+	same.SKU = "SKU1"
+	if base.Fingerprint() != same.Fingerprint() {
+		t.Error("expected fingerprint to ignore fields outside the listing")
+	}
+
+	for _, changed := range []Product{
+		{Name: "iPhone 16 Pro", Price: 99990, ImageURL: "/img/1.jpg"},
+		{Name: "iPhone 16", Price: 89990, ImageURL: "/img/1.jpg"},
+		{Name: "iPhone 16", Price: 99990, ImageURL: "/img/2.jpg"},
+	} {
+		if changed.Fingerprint() == base.Fingerprint() {
+			t.Errorf("expected fingerprint to change for %+v", changed)
+		}
+	}
+}
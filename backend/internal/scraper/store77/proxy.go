@@ -0,0 +1,118 @@
+package store77
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"go.uber.org/zap"
+
+	"github.com/burbble/marketplace/internal/scraper/proxy"
+)
+
+type proxyCtxKey struct{}
+
+// requestProxy routes HTTP requests through the proxy picked for them by
+// fetchHTTP and falls back to the environment otherwise.
+func requestProxy(r *http.Request) (*url.URL, error) {
+	if px, ok := r.Context().Value(proxyCtxKey{}).(*proxy.Proxy); ok {
+		return px.URL, nil
+	}
+	return http.ProxyFromEnvironment(r)
+}
+
+// pickProxy returns nil when no pool is configured. Requests made with a
+// context tagged by proxy.WithKey stick to one proxy under the sticky
+// strategy.
+func (s *Scraper) pickProxy(ctx context.Context) (*proxy.Proxy, error) {
+	if s.cfg.Proxies == nil {
+		return nil, nil
+	}
+	return s.cfg.Proxies.Pick(proxy.KeyFrom(ctx))
+}
+
+func (s *Scraper) reportProxy(px *proxy.Proxy, err error) {
+	if px == nil || errors.Is(err, context.Canceled) {
+		return
+	}
+	if s.cfg.Proxies.Report(px, !proxyFault(err)) {
+		s.logger.Warn("proxy benched after repeated failures", zap.String("proxy", px.String()), zap.Error(err))
+	}
+}
+
+// ProxyStats returns the health of every configured proxy.
+func (s *Scraper) ProxyStats() []proxy.Stat {
+	if s.cfg.Proxies == nil {
+		return nil
+	}
+	return s.cfg.Proxies.Stats()
+}
+
+// proxyFault reports whether a failed fetch counts against the proxy:
+// network errors and statuses store77 uses to turn away an address do, other
+// HTTP statuses are the site's own answer and do not.
+func proxyFault(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		switch statusErr.StatusCode {
+		case http.StatusForbidden, http.StatusProxyAuthRequired, http.StatusTooManyRequests:
+			return true
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// proxyBrowser opens a browser context that sends its traffic through px.
+// The returned function disposes of the context.
+func proxyBrowser(browser *rod.Browser, px *proxy.Proxy) (*rod.Browser, func(), error) {
+	server := url.URL{Scheme: px.URL.Scheme, Host: px.URL.Host}
+	if server.Scheme == "socks5h" {
+		server.Scheme = "socks5"
+	}
+
+	res, err := proto.TargetCreateBrowserContext{ProxyServer: server.String()}.Call(browser)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	b := *browser
+	b.BrowserContextID = res.BrowserContextID
+
+	return &b, func() {
+		_ = proto.TargetDisposeBrowserContext{BrowserContextID: res.BrowserContextID}.Call(browser)
+	}, nil
+}
+
+// handleProxyAuth answers proxy authentication challenges on the page,
+// since Chrome ignores credentials embedded in the proxy address.
+func handleProxyAuth(page *rod.Page, user *url.Userinfo) {
+	password, _ := user.Password()
+
+	page.EnableDomain(&proto.FetchEnable{HandleAuthRequests: true})
+
+	go page.EachEvent(
+		func(e *proto.FetchRequestPaused) {
+			_ = proto.FetchContinueRequest{RequestID: e.RequestID}.Call(page)
+		},
+		func(e *proto.FetchAuthRequired) {
+			_ = proto.FetchContinueWithAuth{
+				RequestID: e.RequestID,
+				AuthChallengeResponse: &proto.FetchAuthChallengeResponse{
+					Response: proto.FetchAuthChallengeResponseResponseProvideCredentials,
+					Username: user.Username(),
+					Password: password,
+				},
+			}.Call(page)
+		},
+	)()
+}
@@ -0,0 +1,86 @@
+package store77
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/burbble/marketplace/internal/scraper/proxy"
+)
+
+func TestProxyFault(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"success", nil, false},
+		{"network", errors.New("proxyconnect tcp: connection refused"), true},
+		{"forbidden", &StatusError{StatusCode: http.StatusForbidden}, true},
+		{"too many requests", &StatusError{StatusCode: http.StatusTooManyRequests}, true},
+		{"not found", &StatusError{StatusCode: http.StatusNotFound}, false},
+		{"server error", &StatusError{StatusCode: http.StatusInternalServerError}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := proxyFault(tt.err); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestFetchHTTP_UsesProxyPool(t *testing.T) {
+	var proxied atomic.Int32
+	good := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied.Add(1)
+		if r.URL.Host != "store77.test" {
+			t.Errorf("expected absolute request URL via proxy, got %s", r.URL)
+		}
+		_, _ = w.Write([]byte("<html>via proxy</html>"))
+	}))
+	defer good.Close()
+
+	blocked := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer blocked.Close()
+
+	urls, err := proxy.ParseList(blocked.URL + "," + good.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := proxy.NewPool(urls, proxy.Config{Strategy: proxy.StrategyRoundRobin, MaxFailures: 1, BenchDuration: time.Hour})
+
+	s := NewScraper(zap.NewNop(), Config{
+		RetryAttempts:  2,
+		RetryBaseDelay: time.Millisecond,
+		RetryMaxDelay:  time.Millisecond,
+		Proxies:        pool,
+	})
+
+	html, err := s.withRetry(context.Background(), PageKindProduct, "http://store77.test/p/1", func() (string, error) {
+		return s.fetchHTTP(context.Background(), "http://store77.test/p/1")
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if html != "<html>via proxy</html>" || proxied.Load() != 1 {
+		t.Errorf("expected the page from the healthy proxy, got %q (%d requests)", html, proxied.Load())
+	}
+
+	stats := s.ProxyStats()
+	if stats[0].BenchedUntil == nil || stats[0].Failures != 1 {
+		t.Errorf("expected blocked proxy to be benched, got %+v", stats[0])
+	}
+	if stats[1].Successes != 1 {
+		t.Errorf("expected one success through the healthy proxy, got %+v", stats[1])
+	}
+}
@@ -0,0 +1,161 @@
+package store77
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/burbble/marketplace/internal/scraper/source"
+)
+
+const maxRetryAfter = 5 * time.Minute
+
+// Page kinds reported in GivenUpPage.
+const (
+	PageKindMain     = "main"
+	PageKindCategory = "category"
+	PageKindProduct  = "product"
+)
+
+// StatusError is returned when store77 answers with a non-200 status.
+type StatusError struct {
+	URL        string
+	StatusCode int
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s returned status %d", e.URL, e.StatusCode)
+}
+
+type GivenUpPage = source.GivenUpPage
+
+// IsRetryable reports whether a fetch error is worth another attempt:
+// timeouts, network failures, 408, 429 and 5xx are; other 4xx statuses and
+// cancellation are not. Unknown errors are treated as transient.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		code := statusErr.StatusCode
+		return code == http.StatusRequestTimeout || code == http.StatusTooManyRequests || code >= 500
+	}
+
+	return true
+}
+
+// retryAfter returns the delay requested by the server, if any.
+func retryAfter(err error) time.Duration {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return min(statusErr.RetryAfter, maxRetryAfter)
+	}
+	return 0
+}
+
+// parseRetryAfter accepts both forms of the Retry-After header: a number of
+// seconds or an HTTP date.
+func parseRetryAfter(v string, now time.Time) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
+
+// backoff returns the delay before retry number attempt (1-based): the base
+// delay doubled per attempt, capped at maxDelay, with the upper half
+// randomized so parallel workers do not retry in lockstep.
+func backoff(attempt int, base, maxDelay time.Duration) time.Duration {
+	d := base
+	for i := 1; i < attempt && d < maxDelay; i++ {
+		d *= 2
+	}
+	d = min(d, maxDelay)
+	if d <= 1 {
+		return d
+	}
+
+	half := d / 2
+	return half + rand.N(d-half+1)
+}
+
+// giveUp remembers a page given up on, in the log of the caller's context
+// when there is one.
+func (s *Scraper) giveUp(ctx context.Context, p GivenUpPage) {
+	if log := source.GivenUpLogFrom(ctx); log != nil {
+		log.Add(p)
+		return
+	}
+	s.givenUp.Add(p)
+}
+
+// retryable extends IsRetryable when proxies are in use: a status that
+// turned away the proxy (e.g. 403) may succeed through another one.
+func (s *Scraper) retryable(err error) bool {
+	if s.cfg.Proxies != nil && !errors.Is(err, context.Canceled) && proxyFault(err) {
+		return true
+	}
+	return IsRetryable(err)
+}
+
+// withRetry runs fetch until it succeeds, fails permanently or runs out of
+// attempts. Pages that are given up on are remembered by giveUp.
+func (s *Scraper) withRetry(ctx context.Context, kind, url string, fetch func() (string, error)) (string, error) {
+	attempts := max(s.cfg.RetryAttempts, 1)
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		var html string
+		html, err = fetch()
+		if err == nil {
+			return html, nil
+		}
+		if ctx.Err() != nil {
+			return "", err
+		}
+
+		retryable := s.retryable(err)
+		if !retryable || attempt >= attempts {
+			s.giveUp(ctx, GivenUpPage{
+				URL:       url,
+				Kind:      kind,
+				Attempts:  attempt,
+				Permanent: !retryable,
+				Err:       err,
+			})
+			return "", err
+		}
+
+		delay := max(backoff(attempt, s.cfg.RetryBaseDelay, s.cfg.RetryMaxDelay), retryAfter(err))
+
+		s.logger.Warn("fetch failed, retrying",
+			zap.String("url", url),
+			zap.Int("attempt", attempt),
+			zap.Duration("delay", delay),
+			zap.Error(err),
+		)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return "", err
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package store77
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+	"go.uber.org/zap"
+
+	"github.com/burbble/marketplace/internal/scraper/source"
+)
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"nil", nil, false},
+		{"canceled", fmt.Errorf("navigate: %w", context.Canceled), false},
+		{"timeout", fmt.Errorf("wait load: %w", context.DeadlineExceeded), true},
+		{"not found", &StatusError{StatusCode: http.StatusNotFound}, false},
+		{"forbidden", &StatusError{StatusCode: http.StatusForbidden}, false},
+		{"too many requests", &StatusError{StatusCode: http.StatusTooManyRequests}, true},
+		{"bad gateway", fmt.Errorf("wrapped: %w", &StatusError{StatusCode: http.StatusBadGateway}), true},
+		{"network", errors.New("connection reset by peer"), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryable(tt.err); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 2, 3, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value    string
+		expected time.Duration
+	}{
+		{"", 0},
+		{"7", 7 * time.Second},
+		{"-1", 0},
+		{"Tue, 03 Feb 2026 12:00:30 GMT", 30 * time.Second},
+		{"Tue, 03 Feb 2026 11:00:00 GMT", 0},
+		{"soon", 0},
+	}
+
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.value, now); got != tt.expected {
+			t.Errorf("parseRetryAfter(%q): expected %v, got %v", tt.value, tt.expected, got)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	base, maxDelay := 100*time.Millisecond, time.Second
+
+	for attempt, want := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 4: 800 * time.Millisecond, 10: time.Second} {
+		for range 20 {
+			got := backoff(attempt, base, maxDelay)
+			if got < want/2 || got > want {
+				t.Fatalf("attempt %d: expected delay in [%v, %v], got %v", attempt, want/2, want, got)
+			}
+		}
+	}
+}
+
+func newTestScraper(attempts int) *Scraper {
+	return NewScraper(zap.NewNop(), Config{
+		RetryAttempts:  attempts,
+		RetryBaseDelay: time.Millisecond,
+		RetryMaxDelay:  5 * time.Millisecond,
+	})
+}
+
+func TestWithRetry_RecoversFromTransientErrors(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("<html>ok</html>"))
+	}))
+	defer srv.Close()
+
+	s := newTestScraper(3)
+	html, err := s.withRetry(context.Background(), PageKindProduct, srv.URL, func() (string, error) {
+		return s.fetchHTTP(context.Background(), srv.URL)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if html != "<html>ok</html>" {
+		t.Errorf("unexpected body %q", html)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("expected 3 requests, got %d", calls.Load())
+	}
+	if given := s.DrainGivenUp(); len(given) != 0 {
+		t.Errorf("expected no given up pages, got %d", len(given))
+	}
+}
+
+func TestWithRetry_PermanentErrorIsNotRetried(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	s := newTestScraper(5)
+	_, err := s.withRetry(context.Background(), PageKindProduct, srv.URL, func() (string, error) {
+		return s.fetchHTTP(context.Background(), srv.URL)
+	})
+
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404 StatusError, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("expected 1 request, got %d", calls.Load())
+	}
+
+	given := s.DrainGivenUp()
+	if len(given) != 1 || !given[0].Permanent || given[0].Attempts != 1 || given[0].Kind != PageKindProduct {
+		t.Errorf("expected one permanent given up page, got %+v", given)
+	}
+	if len(s.DrainGivenUp()) != 0 {
+		t.Error("expected drain to clear the list")
+	}
+}
+
+func TestWithRetry_GivesUpAfterAttempts(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	s := newTestScraper(3)
+	if _, err := s.withRetry(context.Background(), PageKindCategory, srv.URL, func() (string, error) {
+		return s.fetchHTTP(context.Background(), srv.URL)
+	}); err == nil {
+		t.Fatal("expected error")
+	}
+	if calls.Load() != 3 {
+		t.Errorf("expected 3 requests, got %d", calls.Load())
+	}
+
+	given := s.DrainGivenUp()
+	if len(given) != 1 || given[0].Permanent || given[0].Attempts != 3 {
+		t.Errorf("expected one retryable given up page after 3 attempts, got %+v", given)
+	}
+}
+
+func TestWithRetry_GivesUpIntoContextLog(t *testing.T) {
+	s := newTestScraper(1)
+	log := &source.GivenUpLog{}
+	ctx := source.WithGivenUpLog(context.Background(), log)
+
+	if _, err := s.withRetry(ctx, PageKindCategory, "https://store77.net/phones/", func() (string, error) {
+		return "", &StatusError{StatusCode: http.StatusNotFound}
+	}); err == nil {
+		t.Fatal("expected error")
+	}
+
+	if given := log.Drain(); len(given) != 1 || given[0].URL != "https://store77.net/phones/" {
+		t.Errorf("expected the page in the context log, got %+v", given)
+	}
+	if given := s.DrainGivenUp(); len(given) != 0 {
+		t.Errorf("expected the shared log to stay empty, got %+v", given)
+	}
+}
+
+func TestWithRetry_StopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	s := NewScraper(zap.NewNop(), Config{RetryAttempts: 5, RetryBaseDelay: time.Hour, RetryMaxDelay: time.Hour})
+
+	var calls int
+	done := make(chan error, 1)
+	go func() {
+		_, err := s.withRetry(ctx, PageKindCategory, "https://store77.net/phones/", func() (string, error) {
+			calls++
+			return "", &StatusError{StatusCode: http.StatusBadGateway}
+		})
+		done <- err
+	}()
+
+	cancel()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("expected error")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("withRetry did not stop after cancellation")
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 attempt, got %d", calls)
+	}
+}
+
+func TestDocumentStatus(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name     string
+		response string
+		code     int
+		retry    time.Duration
+	}{
+		{"ok", `{"status":200}`, 0, 0},
+		{"not found", `{"status":404}`, http.StatusNotFound, 0},
+		{"rate limited", `{"status":429,"headers":{"retry-after":"120"}}`, http.StatusTooManyRequests, 2 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp proto.NetworkResponse
+			if err := json.Unmarshal([]byte(tt.response), &resp); err != nil {
+				t.Fatalf("failed to unmarshal: %v", err)
+			}
+
+			var ds documentStatus
+			ds.set(&resp)
+			err := ds.err("https://store77.net/phones/", now)
+
+			if tt.code == 0 {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+				return
+			}
+			var statusErr *StatusError
+			if !errors.As(err, &statusErr) || statusErr.StatusCode != tt.code || statusErr.RetryAfter != tt.retry {
+				t.Errorf("expected status %d retry after %v, got %v", tt.code, tt.retry, err)
+			}
+		})
+	}
+
+	var unseen documentStatus
+	if err := unseen.err("https://store77.net/", now); err != nil {
+		t.Errorf("expected no error without a response, got %v", err)
+	}
+}
@@ -5,35 +5,104 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/launcher"
+	"github.com/go-rod/rod/lib/proto"
 	"go.uber.org/zap"
+
+	"github.com/burbble/marketplace/internal/scraper/proxy"
+	"github.com/burbble/marketplace/internal/scraper/source"
+	"github.com/burbble/marketplace/internal/scraper/throttle"
 )
 
 const (
 	baseURL         = "https://store77.net"
 	pageLoadTimeout = 60 * time.Second
 	httpTimeout     = 15 * time.Second
+	// domStableWindow is how long the DOM must stay unchanged after load
+	// before the rendered page is read.
+	domStableWindow = time.Second
 )
 
+// Config tunes how persistently the scraper fetches pages and, when set,
+// which proxies it fetches them through and how fast.
+type Config struct {
+	RetryAttempts  int
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+	Proxies        *proxy.Pool
+	Limiter        *throttle.Limiter
+	// HTTPFetcher and BrowserFetcher replace the built-in net/http and
+	// headless Chrome fetchers when set.
+	HTTPFetcher    Fetcher
+	BrowserFetcher Fetcher
+}
+
 type Scraper struct {
-	logger     *zap.Logger
-	browser    *rod.Browser
-	httpClient *http.Client
+	logger         *zap.Logger
+	cfg            Config
+	browserMu      sync.Mutex
+	browser        *rod.Browser
+	httpClient     *http.Client
+	httpFetcher    Fetcher
+	browserFetcher Fetcher
+	modes          fetchModes
+	recorderMu     sync.Mutex
+	recorder       Recorder
+	givenUp        source.GivenUpLog
 }
 
-func NewScraper(logger *zap.Logger) *Scraper {
-	return &Scraper{
+func NewScraper(logger *zap.Logger, cfg Config) *Scraper {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = requestProxy
+
+	s := &Scraper{
 		logger: logger,
+		cfg:    cfg,
 		httpClient: &http.Client{
-			Timeout: httpTimeout,
+			Timeout:   httpTimeout,
+			Transport: transport,
 		},
+		httpFetcher:    cfg.HTTPFetcher,
+		browserFetcher: cfg.BrowserFetcher,
+		modes:          fetchModes{modes: make(map[string]fetchMode)},
+	}
+	if s.httpFetcher == nil {
+		s.httpFetcher = FetcherFunc(s.fetchHTTP)
 	}
+	if s.browserFetcher == nil {
+		s.browserFetcher = FetcherFunc(s.FetchPageHTML)
+	}
+
+	return s
+}
+
+// DrainGivenUp returns the pages given up on since the previous call. Pages
+// fetched with a source.GivenUpLog in the context go to that log instead.
+func (s *Scraper) DrainGivenUp() []GivenUpPage {
+	return s.givenUp.Drain()
 }
 
+// Start launches headless Chrome. Calling it is optional: the browser is
+// started on first use.
 func (s *Scraper) Start() error {
+	s.browserMu.Lock()
+	defer s.browserMu.Unlock()
+
+	_, err := s.startBrowser()
+	return err
+}
+
+// startBrowser must be called with browserMu held.
+func (s *Scraper) startBrowser() (*rod.Browser, error) {
+	if s.browser != nil {
+		return s.browser, nil
+	}
+
 	path, _ := launcher.LookPath()
 	u := launcher.New().Bin(path).
 		Headless(true).
@@ -44,30 +113,72 @@ func (s *Scraper) Start() error {
 		Set("disable-extensions").
 		MustLaunch()
 
-	s.browser = rod.New().ControlURL(u)
-	if err := s.browser.Connect(); err != nil {
-		return fmt.Errorf("connect to browser: %w", err)
+	browser := rod.New().ControlURL(u)
+	if err := browser.Connect(); err != nil {
+		return nil, fmt.Errorf("connect to browser: %w", err)
 	}
+	s.browser = browser
 
 	s.logger.Info("browser started")
 
-	return nil
+	return browser, nil
 }
 
 func (s *Scraper) Stop() {
+	s.browserMu.Lock()
+	defer s.browserMu.Unlock()
+
 	if s.browser != nil {
 		_ = s.browser.Close()
+		s.browser = nil
 		s.logger.Info("browser closed")
 	}
 }
 
-func (s *Scraper) FetchPageHTML(ctx context.Context, url string) (string, error) {
-	page := s.browser.MustPage()
-	defer page.MustClose()
+func (s *Scraper) FetchPageHTML(ctx context.Context, url string) (html string, err error) {
+	px, err := s.pickProxy(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer func() { s.reportProxy(px, err) }()
+
+	if err := s.wait(ctx, url); err != nil {
+		return "", err
+	}
+	defer func() { s.observe(url, err) }()
+
+	s.browserMu.Lock()
+	browser, err := s.startBrowser()
+	s.browserMu.Unlock()
+	if err != nil {
+		return "", fmt.Errorf("start browser: %w", err)
+	}
+
+	if px != nil {
+		b, dispose, err := proxyBrowser(browser, px)
+		if err != nil {
+			return "", fmt.Errorf("open proxy context for %s: %w", url, err)
+		}
+		defer dispose()
+		browser = b
+	}
+
+	page, err := browser.Page(proto.TargetCreateTarget{})
+	if err != nil {
+		return "", fmt.Errorf("open page for %s: %w", url, err)
+	}
+	defer func() { _ = page.Close() }()
+
+	if px != nil && px.URL.User != nil {
+		handleProxyAuth(page, px.URL.User)
+	}
 
 	page = page.Context(ctx).Timeout(pageLoadTimeout)
 
-	err := page.Navigate(url)
+	status, stopWatching := watchDocument(page)
+	defer stopWatching()
+
+	err = page.Navigate(url)
 	if err != nil {
 		return "", fmt.Errorf("navigate to %s: %w", url, err)
 	}
@@ -77,20 +188,95 @@ func (s *Scraper) FetchPageHTML(ctx context.Context, url string) (string, error)
 		return "", fmt.Errorf("wait load %s: %w", url, err)
 	}
 
-	time.Sleep(3 * time.Second)
+	if err = status.err(url, time.Now()); err != nil {
+		return "", err
+	}
+
+	err = page.WaitDOMStable(domStableWindow, 0)
+	if err != nil {
+		return "", fmt.Errorf("wait render %s: %w", url, err)
+	}
 
-	html, err := page.HTML()
+	html, err = page.HTML()
 	if err != nil {
 		return "", fmt.Errorf("get html from %s: %w", url, err)
 	}
 
+	if IsCaptchaPage(html) {
+		return "", fmt.Errorf("%s: %w", url, ErrCaptcha)
+	}
+
 	return html, nil
 }
 
+// documentStatus is the response the browser got for the page's own
+// document, as opposed to the scripts and images it loaded.
+type documentStatus struct {
+	mu         sync.Mutex
+	code       int
+	retryAfter string
+}
+
+// watchDocument records the status of the main frame's document responses
+// until the returned function is called. A redirect leaves the status of the
+// final response.
+func watchDocument(page *rod.Page) (*documentStatus, func()) {
+	ctx, cancel := context.WithCancel(page.GetContext())
+	ds := &documentStatus{}
+
+	wait := page.Context(ctx).EachEvent(func(e *proto.NetworkResponseReceived) {
+		if e.Type != proto.NetworkResourceTypeDocument || e.FrameID != page.FrameID || e.Response == nil {
+			return
+		}
+		ds.set(e.Response)
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		wait()
+	}()
+
+	return ds, func() {
+		cancel()
+		<-done
+	}
+}
+
+func (d *documentStatus) set(resp *proto.NetworkResponse) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.code = resp.Status
+	d.retryAfter = ""
+	for name, value := range resp.Headers {
+		if strings.EqualFold(name, "Retry-After") {
+			d.retryAfter = value.Str()
+		}
+	}
+}
+
+// err returns a StatusError when the document was answered with an error
+// status, so that browser fetches are retried and classified like HTTP ones.
+// A page whose response was not seen is assumed to be fine.
+func (d *documentStatus) err(url string, now time.Time) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.code < http.StatusBadRequest {
+		return nil
+	}
+	return &StatusError{
+		URL:        url,
+		StatusCode: d.code,
+		RetryAfter: parseRetryAfter(d.retryAfter, now),
+	}
+}
+
 func (s *Scraper) FetchMainPage(ctx context.Context) (string, error) {
 	s.logger.Info("fetching main page", zap.String("url", baseURL))
 
-	html, err := s.FetchPageHTML(ctx, baseURL)
+	html, err := s.fetchListing(ctx, PageKindMain, "/", baseURL, hasCategoryMenu, nil)
 	if err != nil {
 		return "", err
 	}
@@ -105,6 +291,26 @@ func (s *Scraper) FetchProductPage(ctx context.Context, path string) (string, er
 
 	s.logger.Debug("fetching product page", zap.String("url", u))
 
+	return s.withRetry(ctx, PageKindProduct, u, func() (string, error) {
+		return s.fetch(ctx, s.httpFetcher, u)
+	})
+}
+
+func (s *Scraper) fetchHTTP(ctx context.Context, u string) (body string, err error) {
+	px, err := s.pickProxy(ctx)
+	if err != nil {
+		return "", err
+	}
+	if px != nil {
+		ctx = context.WithValue(ctx, proxyCtxKey{}, px)
+		defer func() { s.reportProxy(px, err) }()
+	}
+
+	if err := s.wait(ctx, u); err != nil {
+		return "", err
+	}
+	defer func() { s.observe(u, err) }()
+
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
 	if err != nil {
 		return "", fmt.Errorf("create request %s: %w", u, err)
@@ -113,20 +319,28 @@ func (s *Scraper) FetchProductPage(ctx context.Context, path string) (string, er
 
 	resp, err := s.httpClient.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("fetch product page %s: %w", u, err)
+		return "", fmt.Errorf("fetch page %s: %w", u, err)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("product page %s returned status %d", u, resp.StatusCode)
+		return "", &StatusError{
+			URL:        u,
+			StatusCode: resp.StatusCode,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	raw, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("read product page %s: %w", u, err)
+		return "", fmt.Errorf("read page %s: %w", u, err)
+	}
+
+	if IsCaptchaPage(string(raw)) {
+		return "", fmt.Errorf("%s: %w", u, ErrCaptcha)
 	}
 
-	return string(body), nil
+	return string(raw), nil
 }
 
 func (s *Scraper) FetchCategoryPage(ctx context.Context, path string, page int) (string, error) {
@@ -137,7 +351,7 @@ func (s *Scraper) FetchCategoryPage(ctx context.Context, path string, page int)
 
 	s.logger.Info("fetching category page", zap.String("url", u), zap.Int("page", page))
 
-	html, err := s.FetchPageHTML(ctx, u)
+	html, err := s.fetchListing(ctx, PageKindCategory, path, u, hasProductList, isEmptyListing)
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,78 @@
+package store77
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/burbble/marketplace/internal/scraper/source"
+)
+
+// SourceName identifies store77 in SCRAPE_SOURCES and in the database.
+const SourceName = "store77"
+
+var (
+	_ source.Source    = (*Scraper)(nil)
+	_ source.Recording = (*Scraper)(nil)
+)
+
+func (s *Scraper) Name() string {
+	return SourceName
+}
+
+// ListCategories parses the catalog menu of the main page.
+func (s *Scraper) ListCategories(ctx context.Context) ([]Category, error) {
+	html, err := s.FetchMainPage(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetch main page: %w", err)
+	}
+
+	categories, err := ParseCategories(html)
+	if err != nil {
+		return nil, fmt.Errorf("parse categories: %w", err)
+	}
+
+	return categories, nil
+}
+
+// ListProducts fetches and parses a page of a category listing.
+func (s *Scraper) ListProducts(ctx context.Context, cat Category, page int) (*source.Page, error) {
+	html, err := s.FetchCategoryPage(ctx, cat.URL, page)
+	if err != nil {
+		return nil, fmt.Errorf("fetch page: %w", err)
+	}
+
+	pagination, err := ParsePagination(html)
+	if err != nil {
+		return nil, fmt.Errorf("parse pagination: %w", err)
+	}
+
+	products, health, err := ParseProductsWithHealth(html)
+	if err != nil {
+		return nil, fmt.Errorf("parse products: %w", err)
+	}
+
+	// The total is informational; a page without it is still usable.
+	total, _ := ParseTotalProducts(html)
+
+	return &source.Page{
+		Products:      products,
+		Health:        health,
+		TotalPages:    pagination.TotalPages,
+		TotalProducts: total,
+	}, nil
+}
+
+// FetchDetail fetches and parses a product page.
+func (s *Scraper) FetchDetail(ctx context.Context, productURL string) (*ProductDetail, error) {
+	html, err := s.FetchProductPage(ctx, productURL)
+	if err != nil {
+		return nil, fmt.Errorf("fetch product page: %w", err)
+	}
+
+	detail, err := ParseProductDetail(html)
+	if err != nil {
+		return nil, fmt.Errorf("parse product page: %w", err)
+	}
+
+	return detail, nil
+}
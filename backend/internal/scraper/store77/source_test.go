@@ -0,0 +1,58 @@
+package store77
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestScraper_ListProducts(t *testing.T) {
+	html := `<html><body>
+		<div class="search_all_produkt"><span>42</span></div>
+		<div class="wrap_list_prod">
+			<div class="blocks_product">
+				<button class="favorite_product" data-elid="12345"></button>
+				<div class="blocks_product_fix_w">
+					<a href="/product/test-phone/"><img src="/img/phone.jpg" title="Test Phone"></a>
+					<p class="bp_text_price">50 000 —</p>
+				</div>
+			</div>
+		</div>
+		<div class="pagination_catalog">
+			<ul class="pagination">
+				<li><a href="?p=1" class="active">1</a></li>
+				<li><a href="?p=3">3</a></li>
+			</ul>
+		</div>
+	</body></html>`
+
+	httpF := &fakeFetcher{results: []fakeResult{{html: html}}}
+	s := newFetcherScraper(httpF, &fakeFetcher{results: []fakeResult{{err: errors.New("browser should not be used")}}})
+
+	page, err := s.ListProducts(context.Background(), Category{Name: "Phones", URL: "/telefony/"}, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if page.TotalPages != 3 || page.TotalProducts != 42 {
+		t.Errorf("expected 3 pages and 42 products, got %d/%d", page.TotalPages, page.TotalProducts)
+	}
+	if len(page.Products) != 1 || page.Products[0].ExternalID != "12345" || page.Products[0].Price != 50000 {
+		t.Errorf("unexpected products %+v", page.Products)
+	}
+	if page.Health.Cards != 1 || page.Health.Parsed != 1 || page.Health.MissingEcom != 1 {
+		t.Errorf("unexpected health %+v", page.Health)
+	}
+}
+
+func TestScraper_FetchDetailError(t *testing.T) {
+	httpF := &fakeFetcher{results: []fakeResult{{err: &StatusError{URL: baseURL + "/product/gone/", StatusCode: 404}}}}
+	s := newFetcherScraper(httpF, &fakeFetcher{results: []fakeResult{{}}})
+
+	_, err := s.FetchDetail(context.Background(), "/product/gone/")
+
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != 404 {
+		t.Errorf("expected the status error to be wrapped, got %v", err)
+	}
+}
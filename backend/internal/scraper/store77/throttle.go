@@ -0,0 +1,102 @@
+package store77
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// ErrCaptcha is returned when store77 serves an anti-bot challenge instead of
+// the requested page.
+var ErrCaptcha = errors.New("captcha page served")
+
+var captchaMarkers = []string{
+	"smartcaptcha",
+	"g-recaptcha",
+	"h-captcha",
+	"ddos-guard",
+	"cf-challenge",
+	"challenge-platform",
+}
+
+var captchaTitleMarkers = []string{
+	"captcha",
+	"just a moment",
+	"access denied",
+	"проверка браузера",
+	"вы не робот",
+}
+
+// IsCaptchaPage reports whether html looks like an anti-bot challenge. The
+// checks are limited to challenge widgets and the page title because regular
+// store77 pages carry Bitrix forms with their own captcha fields.
+func IsCaptchaPage(html string) bool {
+	lower := strings.ToLower(html)
+
+	for _, m := range captchaMarkers {
+		if strings.Contains(lower, m) {
+			return true
+		}
+	}
+
+	start := strings.Index(lower, "<title>")
+	if start < 0 {
+		return false
+	}
+	title := lower[start+len("<title>"):]
+	if end := strings.Index(title, "</title>"); end >= 0 {
+		title = title[:end]
+	}
+
+	for _, m := range captchaTitleMarkers {
+		if strings.Contains(title, m) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hostOf(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
+
+// wait blocks until the rate limiter lets a request to rawURL through.
+func (s *Scraper) wait(ctx context.Context, rawURL string) error {
+	if s.cfg.Limiter == nil {
+		return nil
+	}
+	return s.cfg.Limiter.Wait(ctx, hostOf(rawURL))
+}
+
+// observe slows the host down when store77 pushes back with 429 or a
+// captcha and lets it recover on success.
+func (s *Scraper) observe(rawURL string, err error) {
+	if s.cfg.Limiter == nil {
+		return
+	}
+
+	host := hostOf(rawURL)
+
+	var statusErr *StatusError
+	switch {
+	case err == nil:
+		s.cfg.Limiter.Success(host)
+	case errors.Is(err, ErrCaptcha),
+		errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusTooManyRequests:
+		rps := s.cfg.Limiter.Throttle(host)
+		s.logger.Warn("store77 is pushing back, slowing down",
+			zap.String("host", host),
+			zap.Float64("rps", rps),
+			zap.Error(err),
+		)
+	}
+}
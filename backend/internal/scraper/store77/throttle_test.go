@@ -0,0 +1,72 @@
+package store77
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/burbble/marketplace/internal/scraper/throttle"
+)
+
+func TestIsCaptchaPage(t *testing.T) {
+	tests := []struct {
+		name     string
+		html     string
+		expected bool
+	}{
+		{"catalog", `<html><head><title>Смартфоны — store77</title></head><body><div class="wrap_list_prod"></div></body></html>`, false},
+		{"bitrix form captcha", `<html><head><title>Корзина</title></head><body><input name="captcha_sid"></body></html>`, false},
+		{"smartcaptcha", `<html><body><div class="smart-captcha"><script src="https://smartcaptcha.yandexcloud.net/captcha.js"></script></div></body></html>`, true},
+		{"ddos guard", `<html><head><title>DDoS-Guard</title></head></html>`, true},
+		{"title", `<html><head><title>Проверка браузера</title></head></html>`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsCaptchaPage(tt.html); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestFetchHTTP_SlowsDownOnPushback(t *testing.T) {
+	status := http.StatusTooManyRequests
+	body := ""
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	limiter := throttle.New(throttle.Config{RPS: 100, Burst: 10})
+	s := NewScraper(zap.NewNop(), Config{Limiter: limiter})
+	host := hostOf(srv.URL)
+
+	if _, err := s.fetchHTTP(context.Background(), srv.URL); err == nil {
+		t.Fatal("expected error on 429")
+	}
+	if got := limiter.Rate(host); got != 50 {
+		t.Errorf("expected rate halved after 429, got %v", got)
+	}
+
+	status, body = http.StatusOK, `<html><head><title>Just a moment...</title></head></html>`
+	if _, err := s.fetchHTTP(context.Background(), srv.URL); !errors.Is(err, ErrCaptcha) {
+		t.Fatalf("expected ErrCaptcha, got %v", err)
+	}
+	if got := limiter.Rate(host); got != 25 {
+		t.Errorf("expected rate halved after captcha, got %v", got)
+	}
+
+	body = `<html><head><title>store77</title></head></html>`
+	if _, err := s.fetchHTTP(context.Background(), srv.URL); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := limiter.Rate(host); got != 30 {
+		t.Errorf("expected rate to recover a step, got %v", got)
+	}
+}
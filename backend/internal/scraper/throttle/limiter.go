@@ -0,0 +1,157 @@
+package throttle
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	// minRateFraction bounds how far a host may be slowed down relative to
+	// its configured rate.
+	minRateFraction = 1.0 / 16
+	// recoverySteps is how many successful requests it takes to climb back
+	// from the floor to the configured rate.
+	recoverySteps = 20
+)
+
+type Config struct {
+	// RPS caps requests per second across all hosts; zero disables the cap.
+	RPS   float64
+	Burst int
+	// HostRPS overrides the per-host rate for individual hosts. Hosts not
+	// listed get RPS.
+	HostRPS map[string]float64
+}
+
+// Limiter is a token-bucket limiter shared by every scraper worker: a request
+// waits for a global token and then for a token of its host. Host rates adapt
+// to the site's pushback: Throttle halves the rate, each Success recovers a
+// step towards the configured value.
+type Limiter struct {
+	cfg    Config
+	global *rate.Limiter
+
+	mu    sync.Mutex
+	hosts map[string]*hostLimiter
+}
+
+type hostLimiter struct {
+	base    rate.Limit
+	limiter *rate.Limiter
+}
+
+func New(cfg Config) *Limiter {
+	if cfg.Burst <= 0 {
+		cfg.Burst = 1
+	}
+
+	global := rate.NewLimiter(rate.Inf, 0)
+	if cfg.RPS > 0 {
+		global = rate.NewLimiter(rate.Limit(cfg.RPS), cfg.Burst)
+	}
+
+	return &Limiter{
+		cfg:    cfg,
+		global: global,
+		hosts:  make(map[string]*hostLimiter),
+	}
+}
+
+// Wait blocks until a request to host may be sent or ctx is done.
+func (l *Limiter) Wait(ctx context.Context, host string) error {
+	if err := l.global.Wait(ctx); err != nil {
+		return err
+	}
+	return l.host(host).limiter.Wait(ctx)
+}
+
+// Throttle halves the host's rate, down to a fraction of the configured one,
+// and returns the new rate.
+func (l *Limiter) Throttle(host string) float64 {
+	h := l.host(host)
+	if h.base == rate.Inf {
+		return float64(rate.Inf)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	limit := max(h.limiter.Limit()/2, h.base*minRateFraction)
+	h.limiter.SetLimit(limit)
+
+	return float64(limit)
+}
+
+// Success moves a throttled host's rate one step back towards the configured
+// value.
+func (l *Limiter) Success(host string) {
+	h := l.host(host)
+	if h.base == rate.Inf {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if limit := h.limiter.Limit(); limit < h.base {
+		h.limiter.SetLimit(min(limit+h.base/recoverySteps, h.base))
+	}
+}
+
+// Rate returns the current rate for host.
+func (l *Limiter) Rate(host string) float64 {
+	return float64(l.host(host).limiter.Limit())
+}
+
+func (l *Limiter) host(host string) *hostLimiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if h, ok := l.hosts[host]; ok {
+		return h
+	}
+
+	rps, ok := l.cfg.HostRPS[host]
+	if !ok {
+		rps = l.cfg.RPS
+	}
+
+	h := &hostLimiter{base: rate.Inf, limiter: rate.NewLimiter(rate.Inf, 0)}
+	if rps > 0 {
+		h.base = rate.Limit(rps)
+		h.limiter = rate.NewLimiter(h.base, l.cfg.Burst)
+	}
+	l.hosts[host] = h
+
+	return h
+}
+
+// ParseHostRates parses "host=rps" pairs separated by commas, e.g.
+// "store77.net=2,cdn.store77.net=10".
+func ParseHostRates(s string) (map[string]float64, error) {
+	rates := make(map[string]float64)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		host, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("host rate %q: expected host=rps", pair)
+		}
+
+		rps, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || rps < 0 {
+			return nil, fmt.Errorf("host rate %q: invalid rps", pair)
+		}
+		rates[strings.ToLower(strings.TrimSpace(host))] = rps
+	}
+
+	return rates, nil
+}
@@ -0,0 +1,103 @@
+package throttle
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLimiter_WaitPacesRequests(t *testing.T) {
+	l := New(Config{RPS: 50, Burst: 1})
+
+	start := time.Now()
+	for range 6 {
+		if err := l.Wait(context.Background(), "store77.net"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The first token is free, the remaining five take 20ms each.
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Errorf("expected requests to be paced, took %v", elapsed)
+	}
+}
+
+func TestLimiter_WaitHonorsContext(t *testing.T) {
+	l := New(Config{RPS: 0.1, Burst: 1})
+	_ = l.Wait(context.Background(), "store77.net")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := l.Wait(ctx, "store77.net"); err == nil {
+		t.Error("expected error when the context ends before a token is available")
+	}
+}
+
+func TestLimiter_ThrottleAndRecover(t *testing.T) {
+	l := New(Config{RPS: 8, Burst: 1})
+
+	if got := l.Throttle("store77.net"); got != 4 {
+		t.Errorf("expected rate halved to 4, got %v", got)
+	}
+	for range 10 {
+		l.Throttle("store77.net")
+	}
+	if got := l.Rate("store77.net"); got != 0.5 {
+		t.Errorf("expected rate floored at 1/16 of 8, got %v", got)
+	}
+	if got := l.Rate("cdn.store77.net"); got != 8 {
+		t.Errorf("expected other hosts unaffected, got %v", got)
+	}
+
+	for range recoverySteps {
+		l.Success("store77.net")
+	}
+	if got := l.Rate("store77.net"); got != 8 {
+		t.Errorf("expected full recovery to 8, got %v", got)
+	}
+
+	l.Success("store77.net")
+	if got := l.Rate("store77.net"); got != 8 {
+		t.Errorf("expected recovery to stop at the configured rate, got %v", got)
+	}
+}
+
+func TestLimiter_HostOverrides(t *testing.T) {
+	l := New(Config{RPS: 10, Burst: 1, HostRPS: map[string]float64{"store77.net": 2}})
+
+	if got := l.Rate("store77.net"); got != 2 {
+		t.Errorf("expected override 2, got %v", got)
+	}
+	if got := l.Rate("example.com"); got != 10 {
+		t.Errorf("expected default 10, got %v", got)
+	}
+}
+
+func TestLimiter_Unlimited(t *testing.T) {
+	l := New(Config{})
+
+	for range 100 {
+		if err := l.Wait(context.Background(), "store77.net"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	l.Throttle("store77.net")
+	l.Success("store77.net")
+}
+
+func TestParseHostRates(t *testing.T) {
+	rates, err := ParseHostRates(" Store77.net=2, cdn.store77.net=10.5 ,")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rates) != 2 || rates["store77.net"] != 2 || rates["cdn.store77.net"] != 10.5 {
+		t.Errorf("unexpected rates %v", rates)
+	}
+
+	for _, bad := range []string{"store77.net", "store77.net=fast", "store77.net=-1"} {
+		if _, err := ParseHostRates(bad); err == nil {
+			t.Errorf("ParseHostRates(%q): expected error", bad)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package search
+
+import (
+	"strings"
+
+	"github.com/burbble/marketplace/internal/domain"
+)
+
+var cyrillicToLatin = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e",
+	'ж': "zh", 'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
+	'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+	'ф': "f", 'х': "h", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "sch", 'ъ': "",
+	'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+}
+
+// Transliterate spells Cyrillic letters with Latin ones, so that
+// "самсунг" becomes "samsung". Other characters are kept as is.
+func Transliterate(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if lat, ok := cyrillicToLatin[r]; ok {
+			b.WriteString(lat)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+type Expander struct {
+	aliases map[string]string
+}
+
+func NewExpander(aliases []domain.BrandAlias) *Expander {
+	m := make(map[string]string, len(aliases))
+	for _, a := range aliases {
+		m[strings.ToLower(a.Alias)] = a.Brand
+	}
+	return &Expander{aliases: m}
+}
+
+// Variants returns the distinct spellings to try for a user query: the query
+// itself, the query with known brand aliases replaced, and its Latin
+// transliteration.
+func (e *Expander) Variants(q string) []string {
+	q = strings.Join(strings.Fields(q), " ")
+	if q == "" {
+		return nil
+	}
+
+	variants := []string{q}
+	seen := map[string]struct{}{strings.ToLower(q): {}}
+	add := func(v string) {
+		key := strings.ToLower(v)
+		if _, ok := seen[key]; ok || v == "" {
+			return
+		}
+		seen[key] = struct{}{}
+		variants = append(variants, v)
+	}
+
+	tokens := strings.Fields(q)
+	replaced := make([]string, len(tokens))
+	for i, t := range tokens {
+		if brand, ok := e.aliases[strings.ToLower(t)]; ok {
+			replaced[i] = brand
+			continue
+		}
+		replaced[i] = t
+	}
+	add(strings.Join(replaced, " "))
+	add(Transliterate(q))
+
+	return variants
+}
@@ -0,0 +1,55 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/burbble/marketplace/internal/domain"
+)
+
+func TestTransliterate(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"самсунг", "samsung"},
+		{"Самсунг Galaxy", "samsung galaxy"},
+		{"чехол", "chehol"},
+		{"iphone 15", "iphone 15"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := Transliterate(tt.input); got != tt.expected {
+				t.Errorf("Transliterate(%q) = %q, expected %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExpander_Variants(t *testing.T) {
+	e := NewExpander([]domain.BrandAlias{
+		{Alias: "эпл", Brand: "Apple"},
+		{Alias: "Айфон", Brand: "iPhone"},
+	})
+
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"iphnoe", []string{"iphnoe"}},
+		{"  самсунг  ", []string{"самсунг", "samsung"}},
+		{"айфон 15", []string{"айфон 15", "iPhone 15", "ayfon 15"}},
+		{"эпл", []string{"эпл", "Apple", "epl"}},
+		{"   ", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := e.Variants(tt.input); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Variants(%q) = %q, expected %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
@@ -12,6 +12,7 @@ import (
 type CategoryService interface {
 	GetAll(ctx context.Context) ([]domain.Category, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.Category, error)
+	GetTree(ctx context.Context) ([]domain.CategoryNode, error)
 }
 
 type categoryService struct {
@@ -29,3 +30,12 @@ func (s *categoryService) GetAll(ctx context.Context) ([]domain.Category, error)
 func (s *categoryService) GetByID(ctx context.Context, id uuid.UUID) (*domain.Category, error) {
 	return s.repo.GetByID(ctx, id)
 }
+
+func (s *categoryService) GetTree(ctx context.Context) ([]domain.CategoryNode, error) {
+	categories, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.BuildCategoryTree(categories), nil
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/burbble/marketplace/internal/domain"
+	"github.com/burbble/marketplace/internal/repository/postgres"
+)
+
+type CategoryScheduleService interface {
+	GetAll(ctx context.Context) ([]domain.CategorySchedule, error)
+	Set(ctx context.Context, schedule *domain.CategorySchedule) error
+	Delete(ctx context.Context, categoryID uuid.UUID) error
+}
+
+type categoryScheduleService struct {
+	repo         postgres.CategoryScheduleRepository
+	categoryRepo postgres.CategoryRepository
+}
+
+func NewCategoryScheduleService(repo postgres.CategoryScheduleRepository, categoryRepo postgres.CategoryRepository) CategoryScheduleService {
+	return &categoryScheduleService{repo: repo, categoryRepo: categoryRepo}
+}
+
+func (s *categoryScheduleService) GetAll(ctx context.Context) ([]domain.CategorySchedule, error) {
+	return s.repo.GetAll(ctx)
+}
+
+// Set validates the schedule and creates or replaces the schedule of its
+// category. It returns sql.ErrNoRows if the category does not exist.
+func (s *categoryScheduleService) Set(ctx context.Context, schedule *domain.CategorySchedule) error {
+	if err := schedule.Validate(); err != nil {
+		return err
+	}
+
+	if _, err := s.categoryRepo.GetByID(ctx, schedule.CategoryID); err != nil {
+		return err
+	}
+
+	return s.repo.Upsert(ctx, schedule)
+}
+
+func (s *categoryScheduleService) Delete(ctx context.Context, categoryID uuid.UUID) error {
+	return s.repo.Delete(ctx, categoryID)
+}
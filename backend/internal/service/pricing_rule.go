@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/burbble/marketplace/internal/domain"
+	"github.com/burbble/marketplace/internal/pricing"
+	"github.com/burbble/marketplace/internal/repository/postgres"
+)
+
+type PricingRuleService interface {
+	GetAll(ctx context.Context) ([]domain.PricingRule, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.PricingRule, error)
+	Create(ctx context.Context, rule *domain.PricingRule) error
+	Update(ctx context.Context, rule *domain.PricingRule) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	DryRun(ctx context.Context, rules []domain.PricingRule, limit int) (*domain.PricingDryRun, error)
+}
+
+type pricingRuleService struct {
+	repo         postgres.PricingRuleRepository
+	productRepo  postgres.ProductRepository
+	categoryRepo postgres.CategoryRepository
+}
+
+func NewPricingRuleService(repo postgres.PricingRuleRepository, productRepo postgres.ProductRepository, categoryRepo postgres.CategoryRepository) PricingRuleService {
+	return &pricingRuleService{repo: repo, productRepo: productRepo, categoryRepo: categoryRepo}
+}
+
+func (s *pricingRuleService) GetAll(ctx context.Context) ([]domain.PricingRule, error) {
+	return s.repo.GetAll(ctx)
+}
+
+func (s *pricingRuleService) GetByID(ctx context.Context, id uuid.UUID) (*domain.PricingRule, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *pricingRuleService) Create(ctx context.Context, rule *domain.PricingRule) error {
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+
+	return s.repo.Create(ctx, rule)
+}
+
+func (s *pricingRuleService) Update(ctx context.Context, rule *domain.PricingRule) error {
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+
+	return s.repo.Update(ctx, rule)
+}
+
+func (s *pricingRuleService) Delete(ctx context.Context, id uuid.UUID) error {
+	return s.repo.Delete(ctx, id)
+}
+
+// DryRun prices the current catalog with the given rules, or with the stored
+// rules when rules is nil, and reports up to limit products whose price would
+// change.
+func (s *pricingRuleService) DryRun(ctx context.Context, rules []domain.PricingRule, limit int) (*domain.PricingDryRun, error) {
+	if rules == nil {
+		stored, err := s.repo.GetAll(ctx)
+		if err != nil {
+			return nil, err
+		}
+		rules = stored
+	}
+
+	for _, r := range rules {
+		if err := r.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	products, err := s.productRepo.GetPrices(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := s.categoryRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ancestors := domain.CategoryAncestors(categories)
+
+	engine := pricing.NewEngine(rules)
+	result := &domain.PricingDryRun{
+		TotalProducts: len(products),
+		Items:         make([]domain.PricingDryRunItem, 0),
+	}
+
+	for _, p := range products {
+		newPrice, rule := engine.Apply(pricing.Input{
+			CategoryID: p.CategoryID,
+			Ancestors:  ancestors[p.CategoryID],
+			Brand:      p.Brand,
+			Price:      p.OriginalPrice,
+		})
+
+		result.CurrentTotal += int64(p.Price)
+		result.NewTotal += int64(newPrice)
+
+		if rule == nil {
+			result.Unmatched++
+		}
+		if newPrice == p.Price {
+			continue
+		}
+
+		result.ChangedProducts++
+		if len(result.Items) >= limit {
+			continue
+		}
+
+		item := domain.PricingDryRunItem{
+			ProductID:     p.ID,
+			Name:          p.Name,
+			OriginalPrice: p.OriginalPrice,
+			CurrentPrice:  p.Price,
+			NewPrice:      newPrice,
+		}
+		if rule != nil {
+			item.RuleName = rule.Name
+			if rule.ID != uuid.Nil {
+				ruleID := rule.ID
+				item.RuleID = &ruleID
+			}
+		}
+		result.Items = append(result.Items, item)
+	}
+
+	return result, nil
+}
@@ -5,32 +5,159 @@ import (
 
 	"github.com/google/uuid"
 
+	"github.com/burbble/marketplace/internal/attribute"
 	"github.com/burbble/marketplace/internal/domain"
 	"github.com/burbble/marketplace/internal/repository/postgres"
+	"github.com/burbble/marketplace/internal/search"
 )
 
 type ProductService interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.Product, error)
 	GetByFilter(ctx context.Context, filter domain.ProductFilter) (*domain.ProductList, error)
 	GetBrands(ctx context.Context) ([]string, error)
+	GetPriceHistory(ctx context.Context, filter domain.PriceHistoryFilter) (*domain.PriceHistory, error)
+	GetFacets(ctx context.Context, filter domain.ProductFilter, priceBuckets int) (*domain.ProductFacets, error)
 }
 
 type productService struct {
-	repo postgres.ProductRepository
+	repo       postgres.ProductRepository
+	searchRepo postgres.SearchRepository
 }
 
-func NewProductService(repo postgres.ProductRepository) ProductService {
-	return &productService{repo: repo}
+func NewProductService(repo postgres.ProductRepository, searchRepo postgres.SearchRepository) ProductService {
+	return &productService{repo: repo, searchRepo: searchRepo}
 }
 
+// GetByID returns the product together with its specifications, gallery and
+// variants.
 func (s *productService) GetByID(ctx context.Context, id uuid.UUID) (*domain.Product, error) {
-	return s.repo.GetByID(ctx, id)
+	p, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := s.repo.GetDetails(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	p.Attributes = d.Attributes
+	p.Images = d.Images
+	p.Variants = d.Variants
+
+	return p, nil
 }
 
+// GetByFilter falls back to typo-tolerant matching when a full-text search
+// finds nothing, trying the query as typed, with brand aliases resolved and
+// transliterated to Latin. Only an empty first page falls back, whether or not
+// the total was counted; an empty later page is just the end of the list.
+// Later cursor pages stay on the variant that matched. Attribute filters are
+// normalized the same way as the stored attributes.
 func (s *productService) GetByFilter(ctx context.Context, filter domain.ProductFilter) (*domain.ProductList, error) {
-	return s.repo.GetByFilter(ctx, filter)
+	filter.Attributes = attribute.Filter(filter.Attributes)
+
+	if filter.Cursor != nil && filter.Cursor.FuzzySearch != nil {
+		fuzzy := filter
+		fuzzy.Search = filter.Cursor.FuzzySearch
+		fuzzy.FuzzySearch = true
+
+		result, err := s.repo.GetByFilter(ctx, fuzzy)
+		if err != nil {
+			return nil, err
+		}
+		result.Fuzzy = true
+		return result, nil
+	}
+
+	result, err := s.repo.GetByFilter(ctx, filter)
+	firstPage := filter.Cursor == nil && filter.Offset == 0
+	if err != nil || len(result.Products) > 0 || !firstPage || filter.Search == nil || *filter.Search == "" {
+		return result, err
+	}
+
+	fuzzyFilters, err := s.fuzzyFilters(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, fuzzy := range fuzzyFilters {
+		fuzzyResult, err := s.repo.GetByFilter(ctx, fuzzy)
+		if err != nil {
+			return nil, err
+		}
+		if len(fuzzyResult.Products) > 0 {
+			fuzzyResult.Fuzzy = true
+			return fuzzyResult, nil
+		}
+	}
+
+	return result, nil
+}
+
+// GetFacets applies the same fuzzy fallback as GetByFilter so the facets
+// always describe the product list shown next to them.
+func (s *productService) GetFacets(ctx context.Context, filter domain.ProductFilter, priceBuckets int) (*domain.ProductFacets, error) {
+	filter.Attributes = attribute.Filter(filter.Attributes)
+
+	facets, err := s.repo.GetFacets(ctx, filter, priceBuckets)
+	if err != nil || facets.Total > 0 || filter.Search == nil || *filter.Search == "" {
+		return facets, err
+	}
+
+	fuzzyFilters, err := s.fuzzyFilters(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, fuzzy := range fuzzyFilters {
+		fuzzyFacets, err := s.repo.GetFacets(ctx, fuzzy, priceBuckets)
+		if err != nil {
+			return nil, err
+		}
+		if fuzzyFacets.Total > 0 {
+			fuzzyFacets.Fuzzy = true
+			return fuzzyFacets, nil
+		}
+	}
+
+	return facets, nil
+}
+
+func (s *productService) fuzzyFilters(ctx context.Context, filter domain.ProductFilter) ([]domain.ProductFilter, error) {
+	aliases, err := s.searchRepo.GetBrandAliases(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	variants := search.NewExpander(aliases).Variants(*filter.Search)
+	filters := make([]domain.ProductFilter, 0, len(variants))
+	for _, variant := range variants {
+		fuzzy := filter
+		fuzzy.Search = &variant
+		fuzzy.FuzzySearch = true
+		filters = append(filters, fuzzy)
+	}
+
+	return filters, nil
 }
 
 func (s *productService) GetBrands(ctx context.Context) ([]string, error) {
 	return s.repo.GetBrands(ctx)
 }
+
+func (s *productService) GetPriceHistory(ctx context.Context, filter domain.PriceHistoryFilter) (*domain.PriceHistory, error) {
+	if _, err := s.repo.GetByID(ctx, filter.ProductID); err != nil {
+		return nil, err
+	}
+
+	points, err := s.repo.GetPriceHistory(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.PriceHistory{
+		ProductID: filter.ProductID,
+		Interval:  filter.Interval,
+		Points:    points,
+	}, nil
+}
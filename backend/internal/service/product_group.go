@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/burbble/marketplace/internal/domain"
+	"github.com/burbble/marketplace/internal/repository/postgres"
+)
+
+type ProductGroupService interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.ProductGroup, error)
+}
+
+type productGroupService struct {
+	repo postgres.ProductGroupRepository
+}
+
+func NewProductGroupService(repo postgres.ProductGroupRepository) ProductGroupService {
+	return &productGroupService{repo: repo}
+}
+
+// GetByID returns the group's variants along with the price range of those
+// still available.
+func (s *productGroupService) GetByID(ctx context.Context, id uuid.UUID) (*domain.ProductGroup, error) {
+	g, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	first := true
+	for _, v := range g.Variants {
+		if !v.Available {
+			continue
+		}
+		if first || v.Price < g.MinPrice {
+			g.MinPrice = v.Price
+		}
+		if first || v.Price > g.MaxPrice {
+			g.MaxPrice = v.Price
+		}
+		first = false
+	}
+
+	return g, nil
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/burbble/marketplace/internal/domain"
+	"github.com/burbble/marketplace/internal/repository/postgres"
+)
+
+type ProductMatchService interface {
+	GetList(ctx context.Context, filter domain.ProductMatchFilter) (*domain.ProductMatchList, error)
+	SetStatus(ctx context.Context, id uuid.UUID, status string) (*domain.ProductMatch, error)
+	GetOffers(ctx context.Context, productID uuid.UUID) ([]domain.ProductOffer, error)
+}
+
+type productMatchService struct {
+	repo        postgres.ProductMatchRepository
+	productRepo postgres.ProductRepository
+}
+
+func NewProductMatchService(repo postgres.ProductMatchRepository, productRepo postgres.ProductRepository) ProductMatchService {
+	return &productMatchService{repo: repo, productRepo: productRepo}
+}
+
+func (s *productMatchService) GetList(ctx context.Context, filter domain.ProductMatchFilter) (*domain.ProductMatchList, error) {
+	return s.repo.GetList(ctx, filter)
+}
+
+func (s *productMatchService) SetStatus(ctx context.Context, id uuid.UUID, status string) (*domain.ProductMatch, error) {
+	return s.repo.SetStatus(ctx, id, status)
+}
+
+// GetOffers returns the product's own offer followed by the offers of the
+// products matched to it.
+func (s *productMatchService) GetOffers(ctx context.Context, productID uuid.UUID) ([]domain.ProductOffer, error) {
+	p, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	matched, err := s.repo.GetOffers(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	offers := make([]domain.ProductOffer, 0, len(matched)+1)
+	offers = append(offers, domain.ProductOffer{
+		ProductID:     p.ID,
+		Source:        p.Source,
+		Name:          p.Name,
+		Price:         p.Price,
+		OriginalPrice: p.OriginalPrice,
+		ProductURL:    p.ProductURL,
+		Available:     p.Available,
+		StockStatus:   p.StockStatus,
+		LastSeenAt:    p.LastSeenAt,
+	})
+
+	return append(offers, matched...), nil
+}
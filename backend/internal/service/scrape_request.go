@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/burbble/marketplace/internal/domain"
+	"github.com/burbble/marketplace/internal/repository/postgres"
+)
+
+type ScrapeRequestService interface {
+	Create(ctx context.Context, req *domain.ScrapeRequest) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.ScrapeRequest, error)
+	Cancel(ctx context.Context, id uuid.UUID) (*domain.ScrapeRequest, error)
+}
+
+type scrapeRequestService struct {
+	repo         postgres.ScrapeRequestRepository
+	categoryRepo postgres.CategoryRepository
+}
+
+func NewScrapeRequestService(repo postgres.ScrapeRequestRepository, categoryRepo postgres.CategoryRepository) ScrapeRequestService {
+	return &scrapeRequestService{repo: repo, categoryRepo: categoryRepo}
+}
+
+// Create queues a scrape request after checking that every requested
+// category exists.
+func (s *scrapeRequestService) Create(ctx context.Context, req *domain.ScrapeRequest) error {
+	for _, id := range req.CategoryIDs {
+		if _, err := s.categoryRepo.GetByID(ctx, id); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("%w: unknown category %s", domain.ErrInvalidScrapeRequest, id)
+			}
+			return err
+		}
+	}
+
+	return s.repo.Create(ctx, req)
+}
+
+func (s *scrapeRequestService) GetByID(ctx context.Context, id uuid.UUID) (*domain.ScrapeRequest, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+// Cancel cancels a pending or abandoned request or asks the parser to stop
+// a running one. It returns domain.ErrScrapeRequestFinished for a finished
+// request.
+func (s *scrapeRequestService) Cancel(ctx context.Context, id uuid.UUID) (*domain.ScrapeRequest, error) {
+	req, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if req.Finished() {
+		return nil, domain.ErrScrapeRequestFinished
+	}
+
+	cancelled, err := s.repo.Cancel(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		// The request finished after it was read.
+		return nil, domain.ErrScrapeRequestFinished
+	}
+
+	return cancelled, err
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/burbble/marketplace/internal/domain"
+	"github.com/burbble/marketplace/internal/repository/postgres"
+)
+
+type ScrapeRunService interface {
+	GetList(ctx context.Context, filter domain.ScrapeRunFilter) (*domain.ScrapeRunList, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.ScrapeRunDetail, error)
+}
+
+type scrapeRunService struct {
+	repo postgres.ScrapeRunRepository
+}
+
+func NewScrapeRunService(repo postgres.ScrapeRunRepository) ScrapeRunService {
+	return &scrapeRunService{repo: repo}
+}
+
+func (s *scrapeRunService) GetList(ctx context.Context, filter domain.ScrapeRunFilter) (*domain.ScrapeRunList, error) {
+	return s.repo.GetList(ctx, filter)
+}
+
+func (s *scrapeRunService) GetByID(ctx context.Context, id uuid.UUID) (*domain.ScrapeRunDetail, error) {
+	run, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := s.repo.GetCategories(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	failedPages, err := s.repo.GetFailedPages(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.ScrapeRunDetail{
+		ScrapeRun:   *run,
+		Categories:  categories,
+		FailedPages: failedPages,
+	}, nil
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/burbble/marketplace/internal/domain"
+	"github.com/burbble/marketplace/internal/repository/postgres"
+	"github.com/burbble/marketplace/internal/search"
+)
+
+const minSuggestQueryLength = 2
+
+type SearchService interface {
+	Suggest(ctx context.Context, q string, limit int) ([]domain.Suggestion, error)
+}
+
+type searchService struct {
+	repo postgres.SearchRepository
+}
+
+func NewSearchService(repo postgres.SearchRepository) SearchService {
+	return &searchService{repo: repo}
+}
+
+// Suggest looks up every spelling variant of q and merges the hits, keeping
+// the best score for each suggestion text.
+func (s *searchService) Suggest(ctx context.Context, q string, limit int) ([]domain.Suggestion, error) {
+	q = strings.TrimSpace(q)
+	if utf8.RuneCountInString(q) < minSuggestQueryLength || limit <= 0 {
+		return []domain.Suggestion{}, nil
+	}
+
+	aliases, err := s.repo.GetBrandAliases(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	best := make(map[string]domain.Suggestion)
+	for _, variant := range search.NewExpander(aliases).Variants(q) {
+		found, err := s.repo.Suggest(ctx, variant, uint64(limit))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, sg := range found {
+			key := sg.Kind + ":" + strings.ToLower(sg.Text)
+			if prev, ok := best[key]; !ok || sg.Score > prev.Score {
+				best[key] = sg
+			}
+		}
+	}
+
+	suggestions := make([]domain.Suggestion, 0, len(best))
+	for _, sg := range best {
+		suggestions = append(suggestions, sg)
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		a, b := suggestions[i], suggestions[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.Kind != b.Kind {
+			return a.Kind == domain.SuggestionBrand
+		}
+		return a.Text < b.Text
+	})
+
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+
+	return suggestions, nil
+}
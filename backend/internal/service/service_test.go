@@ -3,6 +3,7 @@ package service_test
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"testing"
 
@@ -22,9 +23,15 @@ func TestProductService_GetByID_Success(t *testing.T) {
 			}
 			return &domain.Product{ID: id, Name: "Phone"}, nil
 		},
+		GetDetailsFunc: func(_ context.Context, _ uuid.UUID) (*domain.ProductDetail, error) {
+			return &domain.ProductDetail{
+				Attributes: []domain.ProductAttribute{{Group: "Экран", Name: "Диагональ", Value: "6.1\""}},
+				Images:     []domain.ProductImage{{URL: "/upload/phone.jpg"}},
+			}, nil
+		},
 	}
 
-	svc := service.NewProductService(repo)
+	svc := service.NewProductService(repo, &mocks.SearchRepositoryMock{})
 	p, err := svc.GetByID(context.Background(), id)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -35,6 +42,9 @@ func TestProductService_GetByID_Success(t *testing.T) {
 	if len(repo.GetByIDCalls()) != 1 {
 		t.Errorf("expected 1 call to GetByID, got %d", len(repo.GetByIDCalls()))
 	}
+	if len(p.Attributes) != 1 || p.Attributes[0].Name != "Диагональ" || len(p.Images) != 1 {
+		t.Errorf("expected product details to be attached, got %+v / %+v", p.Attributes, p.Images)
+	}
 }
 
 func TestProductService_GetByID_NotFound(t *testing.T) {
@@ -44,7 +54,7 @@ func TestProductService_GetByID_NotFound(t *testing.T) {
 		},
 	}
 
-	svc := service.NewProductService(repo)
+	svc := service.NewProductService(repo, &mocks.SearchRepositoryMock{})
 	_, err := svc.GetByID(context.Background(), uuid.New())
 	if err == nil {
 		t.Fatal("expected error, got nil")
@@ -66,7 +76,7 @@ func TestProductService_GetByFilter(t *testing.T) {
 		},
 	}
 
-	svc := service.NewProductService(repo)
+	svc := service.NewProductService(repo, &mocks.SearchRepositoryMock{})
 	result, err := svc.GetByFilter(context.Background(), domain.ProductFilter{Limit: 10})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -86,13 +96,297 @@ func TestProductService_GetByFilter_Error(t *testing.T) {
 		},
 	}
 
-	svc := service.NewProductService(repo)
+	svc := service.NewProductService(repo, &mocks.SearchRepositoryMock{})
 	_, err := svc.GetByFilter(context.Background(), domain.ProductFilter{})
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
 }
 
+func TestProductService_GetByFilter_FuzzyFallback(t *testing.T) {
+	search := "самсунг"
+	repo := &mocks.ProductRepositoryMock{
+		GetByFilterFunc: func(_ context.Context, f domain.ProductFilter) (*domain.ProductList, error) {
+			if f.FuzzySearch && *f.Search == "Samsung" {
+				return &domain.ProductList{Products: []domain.Product{{Name: "Samsung Galaxy S24"}}, Total: 1}, nil
+			}
+			return &domain.ProductList{Products: []domain.Product{}}, nil
+		},
+	}
+	searchRepo := &mocks.SearchRepositoryMock{
+		GetBrandAliasesFunc: func(_ context.Context) ([]domain.BrandAlias, error) {
+			return []domain.BrandAlias{{Alias: "самсунг", Brand: "Samsung"}}, nil
+		},
+	}
+
+	svc := service.NewProductService(repo, searchRepo)
+	result, err := svc.GetByFilter(context.Background(), domain.ProductFilter{Search: &search, Limit: 24})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Fuzzy {
+		t.Error("expected fuzzy result")
+	}
+	if result.Total != 1 {
+		t.Errorf("expected total 1, got %d", result.Total)
+	}
+
+	calls := repo.GetByFilterCalls()
+	if len(calls) != 3 {
+		t.Fatalf("expected exact search plus 2 fuzzy attempts, got %d calls", len(calls))
+	}
+	if calls[0].Filter.FuzzySearch {
+		t.Error("expected first attempt to be an exact search")
+	}
+	if *calls[1].Filter.Search != "самсунг" || !calls[1].Filter.FuzzySearch {
+		t.Errorf("expected fuzzy attempt with original query, got %q", *calls[1].Filter.Search)
+	}
+}
+
+func TestProductService_GetByFilter_FuzzyFallbackWithoutTotal(t *testing.T) {
+	search := "самсунг"
+	repo := &mocks.ProductRepositoryMock{
+		GetByFilterFunc: func(_ context.Context, f domain.ProductFilter) (*domain.ProductList, error) {
+			if f.FuzzySearch {
+				return &domain.ProductList{Products: []domain.Product{{Name: "Samsung Galaxy S24"}}, Total: domain.TotalSkipped}, nil
+			}
+			return &domain.ProductList{Products: []domain.Product{}, Total: domain.TotalSkipped}, nil
+		},
+	}
+
+	svc := service.NewProductService(repo, &mocks.SearchRepositoryMock{
+		GetBrandAliasesFunc: func(_ context.Context) ([]domain.BrandAlias, error) {
+			return nil, nil
+		},
+	})
+	result, err := svc.GetByFilter(context.Background(), domain.ProductFilter{Search: &search, Limit: 24, SkipTotal: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Fuzzy || len(result.Products) != 1 {
+		t.Errorf("expected the empty first page to fall back to fuzzy search, got %+v", result)
+	}
+}
+
+func TestProductService_GetByFilter_NoFallbackWithoutSearch(t *testing.T) {
+	repo := &mocks.ProductRepositoryMock{
+		GetByFilterFunc: func(_ context.Context, _ domain.ProductFilter) (*domain.ProductList, error) {
+			return &domain.ProductList{Products: []domain.Product{}}, nil
+		},
+	}
+
+	svc := service.NewProductService(repo, &mocks.SearchRepositoryMock{})
+	result, err := svc.GetByFilter(context.Background(), domain.ProductFilter{Limit: 24})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Fuzzy {
+		t.Error("expected non-fuzzy result")
+	}
+	if len(repo.GetByFilterCalls()) != 1 {
+		t.Errorf("expected 1 call to GetByFilter, got %d", len(repo.GetByFilterCalls()))
+	}
+}
+
+func TestProductService_GetByFilter_NoFallbackPastFirstPage(t *testing.T) {
+	search := "самсунг"
+	cursor := &domain.ProductCursor{}
+	tests := []struct {
+		name   string
+		filter domain.ProductFilter
+		total  int
+	}{
+		{"offset past the end", domain.ProductFilter{Search: &search, Limit: 24, Offset: 48}, 30},
+		{"cursor past the end", domain.ProductFilter{Search: &search, Limit: 24, Cursor: cursor}, 30},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mocks.ProductRepositoryMock{
+				GetByFilterFunc: func(_ context.Context, _ domain.ProductFilter) (*domain.ProductList, error) {
+					return &domain.ProductList{Products: []domain.Product{}, Total: tt.total}, nil
+				},
+			}
+
+			svc := service.NewProductService(repo, &mocks.SearchRepositoryMock{})
+			result, err := svc.GetByFilter(context.Background(), tt.filter)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Fuzzy || len(repo.GetByFilterCalls()) != 1 {
+				t.Errorf("expected the empty page without fuzzy fallback, got %d calls", len(repo.GetByFilterCalls()))
+			}
+		})
+	}
+}
+
+func TestProductService_GetByFilter_FuzzyCursor(t *testing.T) {
+	search := "самсунг"
+	variant := "Samsung"
+	repo := &mocks.ProductRepositoryMock{
+		GetByFilterFunc: func(_ context.Context, _ domain.ProductFilter) (*domain.ProductList, error) {
+			return &domain.ProductList{Products: []domain.Product{{Name: "Samsung Galaxy A55"}}, Total: domain.TotalSkipped}, nil
+		},
+	}
+
+	svc := service.NewProductService(repo, &mocks.SearchRepositoryMock{})
+	result, err := svc.GetByFilter(context.Background(), domain.ProductFilter{
+		Search: &search,
+		Limit:  24,
+		Cursor: &domain.ProductCursor{FuzzySearch: &variant},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Fuzzy {
+		t.Error("expected fuzzy result")
+	}
+
+	calls := repo.GetByFilterCalls()
+	if len(calls) != 1 {
+		t.Fatalf("expected 1 call to GetByFilter, got %d", len(calls))
+	}
+	if !calls[0].Filter.FuzzySearch || *calls[0].Filter.Search != "Samsung" {
+		t.Errorf("expected fuzzy search for cursor variant, got %q", *calls[0].Filter.Search)
+	}
+}
+
+func TestProductService_GetFacets_FuzzyFallback(t *testing.T) {
+	search := "эпл"
+	repo := &mocks.ProductRepositoryMock{
+		GetFacetsFunc: func(_ context.Context, f domain.ProductFilter, _ int) (*domain.ProductFacets, error) {
+			if f.FuzzySearch && *f.Search == "Apple" {
+				return &domain.ProductFacets{Total: 3, Brands: []domain.BrandFacet{{Brand: "Apple", Count: 3}}}, nil
+			}
+			return &domain.ProductFacets{}, nil
+		},
+	}
+	searchRepo := &mocks.SearchRepositoryMock{
+		GetBrandAliasesFunc: func(_ context.Context) ([]domain.BrandAlias, error) {
+			return []domain.BrandAlias{{Alias: "эпл", Brand: "Apple"}}, nil
+		},
+	}
+
+	svc := service.NewProductService(repo, searchRepo)
+	facets, err := svc.GetFacets(context.Background(), domain.ProductFilter{Search: &search}, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !facets.Fuzzy || facets.Total != 3 {
+		t.Errorf("expected fuzzy facets with total 3, got %+v", facets)
+	}
+	for _, call := range repo.GetFacetsCalls() {
+		if call.PriceBuckets != 10 {
+			t.Errorf("expected 10 price buckets, got %d", call.PriceBuckets)
+		}
+	}
+}
+
+func TestProductService_NormalizesAttributeFilters(t *testing.T) {
+	repo := &mocks.ProductRepositoryMock{
+		GetByFilterFunc: func(_ context.Context, _ domain.ProductFilter) (*domain.ProductList, error) {
+			return &domain.ProductList{Total: 1}, nil
+		},
+		GetFacetsFunc: func(_ context.Context, _ domain.ProductFilter, _ int) (*domain.ProductFacets, error) {
+			return &domain.ProductFacets{Total: 1}, nil
+		},
+	}
+
+	svc := service.NewProductService(repo, &mocks.SearchRepositoryMock{})
+	filter := domain.ProductFilter{Attributes: map[string][]string{"Цвет корпуса": {"Black", "Чёрный"}}}
+
+	if _, err := svc.GetByFilter(context.Background(), filter); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := svc.GetFacets(context.Background(), filter, 10); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := []map[string][]string{repo.GetByFilterCalls()[0].Filter.Attributes, repo.GetFacetsCalls()[0].Filter.Attributes}
+	for _, attrs := range got {
+		if len(attrs) != 1 || len(attrs["цвет"]) != 1 || attrs["цвет"][0] != "черный" {
+			t.Errorf("expected normalized attributes map[цвет:[черный]], got %v", attrs)
+		}
+	}
+}
+
+func TestProductGroupService_GetByID_PriceRange(t *testing.T) {
+	id := uuid.New()
+	repo := &mocks.ProductGroupRepositoryMock{
+		GetByIDFunc: func(_ context.Context, _ uuid.UUID) (*domain.ProductGroup, error) {
+			return &domain.ProductGroup{ID: id, Variants: []domain.Product{
+				{Price: 89990, Available: false},
+				{Price: 99990, Available: true},
+				{Price: 129990, Available: true},
+			}}, nil
+		},
+	}
+
+	svc := service.NewProductGroupService(repo)
+	g, err := svc.GetByID(context.Background(), id)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if g.MinPrice != 99990 || g.MaxPrice != 129990 {
+		t.Errorf("expected price range 99990-129990 over available variants, got %d-%d", g.MinPrice, g.MaxPrice)
+	}
+}
+
+func TestProductGroupService_GetByID_NotFound(t *testing.T) {
+	repo := &mocks.ProductGroupRepositoryMock{
+		GetByIDFunc: func(_ context.Context, _ uuid.UUID) (*domain.ProductGroup, error) {
+			return nil, sql.ErrNoRows
+		},
+	}
+
+	svc := service.NewProductGroupService(repo)
+	if _, err := svc.GetByID(context.Background(), uuid.New()); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected sql.ErrNoRows, got %v", err)
+	}
+}
+
+func TestProductMatchService_GetOffers(t *testing.T) {
+	id, matchID := uuid.New(), uuid.New()
+	productRepo := &mocks.ProductRepositoryMock{
+		GetByIDFunc: func(_ context.Context, _ uuid.UUID) (*domain.Product, error) {
+			return &domain.Product{ID: id, Source: "store77", Name: "Apple iPhone 15 128GB Blue", Price: 79990, Available: true}, nil
+		},
+	}
+	repo := &mocks.ProductMatchRepositoryMock{
+		GetOffersFunc: func(_ context.Context, _ uuid.UUID) ([]domain.ProductOffer, error) {
+			return []domain.ProductOffer{{ProductID: uuid.New(), Source: "other", Price: 77990, MatchID: &matchID}}, nil
+		},
+	}
+
+	svc := service.NewProductMatchService(repo, productRepo)
+	offers, err := svc.GetOffers(context.Background(), id)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(offers) != 2 {
+		t.Fatalf("expected 2 offers, got %+v", offers)
+	}
+	if offers[0].ProductID != id || offers[0].Price != 79990 || offers[0].MatchID != nil {
+		t.Errorf("expected the product's own offer first, got %+v", offers[0])
+	}
+	if offers[1].MatchID == nil || *offers[1].MatchID != matchID {
+		t.Errorf("expected the matched offer second, got %+v", offers[1])
+	}
+}
+
+func TestProductMatchService_GetOffers_NotFound(t *testing.T) {
+	productRepo := &mocks.ProductRepositoryMock{
+		GetByIDFunc: func(_ context.Context, _ uuid.UUID) (*domain.Product, error) {
+			return nil, sql.ErrNoRows
+		},
+	}
+
+	svc := service.NewProductMatchService(&mocks.ProductMatchRepositoryMock{}, productRepo)
+	if _, err := svc.GetOffers(context.Background(), uuid.New()); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected sql.ErrNoRows, got %v", err)
+	}
+}
+
 func TestProductService_GetBrands(t *testing.T) {
 	repo := &mocks.ProductRepositoryMock{
 		GetBrandsFunc: func(_ context.Context) ([]string, error) {
@@ -100,7 +394,7 @@ func TestProductService_GetBrands(t *testing.T) {
 		},
 	}
 
-	svc := service.NewProductService(repo)
+	svc := service.NewProductService(repo, &mocks.SearchRepositoryMock{})
 	brands, err := svc.GetBrands(context.Background())
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -120,13 +414,60 @@ func TestProductService_GetBrands_Error(t *testing.T) {
 		},
 	}
 
-	svc := service.NewProductService(repo)
+	svc := service.NewProductService(repo, &mocks.SearchRepositoryMock{})
 	_, err := svc.GetBrands(context.Background())
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
 }
 
+func TestProductService_GetPriceHistory(t *testing.T) {
+	id := uuid.New()
+	repo := &mocks.ProductRepositoryMock{
+		GetByIDFunc: func(_ context.Context, gotID uuid.UUID) (*domain.Product, error) {
+			return &domain.Product{ID: gotID}, nil
+		},
+		GetPriceHistoryFunc: func(_ context.Context, f domain.PriceHistoryFilter) ([]domain.PricePoint, error) {
+			if f.ProductID != id {
+				t.Errorf("expected product id %s, got %s", id, f.ProductID)
+			}
+			return []domain.PricePoint{{Price: 100}, {Price: 90}}, nil
+		},
+	}
+
+	svc := service.NewProductService(repo, &mocks.SearchRepositoryMock{})
+	history, err := svc.GetPriceHistory(context.Background(), domain.PriceHistoryFilter{ProductID: id, Interval: domain.PriceIntervalDay})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if history.ProductID != id {
+		t.Errorf("expected product id %s, got %s", id, history.ProductID)
+	}
+	if history.Interval != domain.PriceIntervalDay {
+		t.Errorf("expected interval 'day', got %q", history.Interval)
+	}
+	if len(history.Points) != 2 {
+		t.Errorf("expected 2 points, got %d", len(history.Points))
+	}
+}
+
+func TestProductService_GetPriceHistory_ProductNotFound(t *testing.T) {
+	repo := &mocks.ProductRepositoryMock{
+		GetByIDFunc: func(_ context.Context, _ uuid.UUID) (*domain.Product, error) {
+			return nil, sql.ErrNoRows
+		},
+	}
+
+	svc := service.NewProductService(repo, &mocks.SearchRepositoryMock{})
+	_, err := svc.GetPriceHistory(context.Background(), domain.PriceHistoryFilter{ProductID: uuid.New()})
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("expected sql.ErrNoRows, got %v", err)
+	}
+	if len(repo.GetPriceHistoryCalls()) != 0 {
+		t.Errorf("expected no call to GetPriceHistory, got %d", len(repo.GetPriceHistoryCalls()))
+	}
+}
+
 
 func TestCategoryService_GetAll_Success(t *testing.T) {
 	repo := &mocks.CategoryRepositoryMock{
@@ -165,6 +506,31 @@ func TestCategoryService_GetAll_Error(t *testing.T) {
 	}
 }
 
+func TestCategoryService_GetTree(t *testing.T) {
+	rootID := uuid.New()
+	repo := &mocks.CategoryRepositoryMock{
+		GetAllFunc: func(_ context.Context) ([]domain.Category, error) {
+			return []domain.Category{
+				{ID: rootID, Name: "Phones"},
+				{ID: uuid.New(), ParentID: &rootID, Depth: 1, Name: "Apple"},
+				{ID: uuid.New(), Name: "Laptops"},
+			}, nil
+		},
+	}
+
+	svc := service.NewCategoryService(repo)
+	tree, err := svc.GetTree(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tree) != 2 {
+		t.Fatalf("expected 2 root categories, got %d", len(tree))
+	}
+	if len(tree[0].Children) != 1 || tree[0].Children[0].Name != "Apple" {
+		t.Errorf("expected Apple under Phones, got %+v", tree[0].Children)
+	}
+}
+
 func TestCategoryService_GetByID_Success(t *testing.T) {
 	id := uuid.New()
 	repo := &mocks.CategoryRepositoryMock{
@@ -199,3 +565,345 @@ func TestCategoryService_GetByID_NotFound(t *testing.T) {
 		t.Fatal("expected error, got nil")
 	}
 }
+
+func TestPricingRuleService_Create_Invalid(t *testing.T) {
+	repo := &mocks.PricingRuleRepositoryMock{}
+
+	svc := service.NewPricingRuleService(repo, &mocks.ProductRepositoryMock{}, &mocks.CategoryRepositoryMock{})
+	err := svc.Create(context.Background(), &domain.PricingRule{Name: "bad", AdjustmentType: "multiply", RoundingMode: domain.RoundingNone})
+	if !errors.Is(err, domain.ErrInvalidPricingRule) {
+		t.Fatalf("expected ErrInvalidPricingRule, got %v", err)
+	}
+	if len(repo.CreateCalls()) != 0 {
+		t.Errorf("expected no call to Create, got %d", len(repo.CreateCalls()))
+	}
+}
+
+func TestPricingRuleService_DryRun(t *testing.T) {
+	ruleID := uuid.New()
+	repo := &mocks.PricingRuleRepositoryMock{
+		GetAllFunc: func(_ context.Context) ([]domain.PricingRule, error) {
+			return []domain.PricingRule{{
+				ID: ruleID, Name: "markdown", Enabled: true,
+				AdjustmentType: domain.AdjustmentFixed, AdjustmentValue: -1000,
+				RoundingMode: domain.RoundingNone,
+			}}, nil
+		},
+	}
+	productRepo := &mocks.ProductRepositoryMock{
+		GetPricesFunc: func(_ context.Context) ([]domain.ProductPrice, error) {
+			return []domain.ProductPrice{
+				{Name: "unchanged", OriginalPrice: 50000, Price: 49000},
+				{Name: "changed", OriginalPrice: 30000, Price: 30000},
+				{Name: "changed too", OriginalPrice: 20000, Price: 20000},
+			}, nil
+		},
+	}
+
+	svc := service.NewPricingRuleService(repo, productRepo, noCategories())
+	result, err := svc.DryRun(context.Background(), nil, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.TotalProducts != 3 {
+		t.Errorf("expected 3 products, got %d", result.TotalProducts)
+	}
+	if result.ChangedProducts != 2 {
+		t.Errorf("expected 2 changed products, got %d", result.ChangedProducts)
+	}
+	if len(result.Items) != 1 {
+		t.Fatalf("expected items capped at 1, got %d", len(result.Items))
+	}
+	if result.Items[0].NewPrice != 29000 {
+		t.Errorf("expected new price 29000, got %d", result.Items[0].NewPrice)
+	}
+	if result.Items[0].RuleID == nil || *result.Items[0].RuleID != ruleID {
+		t.Errorf("expected rule id %s, got %v", ruleID, result.Items[0].RuleID)
+	}
+	if result.CurrentTotal != 99000 || result.NewTotal != 97000 {
+		t.Errorf("unexpected totals: current %d, new %d", result.CurrentTotal, result.NewTotal)
+	}
+}
+
+func TestPricingRuleService_DryRun_CandidateRules(t *testing.T) {
+	repo := &mocks.PricingRuleRepositoryMock{}
+	productRepo := &mocks.ProductRepositoryMock{
+		GetPricesFunc: func(_ context.Context) ([]domain.ProductPrice, error) {
+			return []domain.ProductPrice{{Name: "p", OriginalPrice: 10000, Price: 9000}}, nil
+		},
+	}
+
+	svc := service.NewPricingRuleService(repo, productRepo, noCategories())
+	result, err := svc.DryRun(context.Background(), []domain.PricingRule{}, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.GetAllCalls()) != 0 {
+		t.Error("expected stored rules not to be loaded")
+	}
+	if result.Unmatched != 1 {
+		t.Errorf("expected 1 unmatched product, got %d", result.Unmatched)
+	}
+	if len(result.Items) != 1 || result.Items[0].NewPrice != 10000 || result.Items[0].RuleID != nil {
+		t.Errorf("unexpected items: %+v", result.Items)
+	}
+}
+
+func TestPricingRuleService_DryRun_Subcategory(t *testing.T) {
+	phones, smartphones := uuid.New(), uuid.New()
+	repo := &mocks.PricingRuleRepositoryMock{
+		GetAllFunc: func(_ context.Context) ([]domain.PricingRule, error) {
+			return []domain.PricingRule{{
+				Name: "phones", Enabled: true, CategoryID: &phones,
+				AdjustmentType: domain.AdjustmentFixed, AdjustmentValue: -1000,
+				RoundingMode: domain.RoundingNone,
+			}}, nil
+		},
+	}
+	productRepo := &mocks.ProductRepositoryMock{
+		GetPricesFunc: func(_ context.Context) ([]domain.ProductPrice, error) {
+			return []domain.ProductPrice{{Name: "p", CategoryID: smartphones, OriginalPrice: 10000, Price: 10000}}, nil
+		},
+	}
+	categoryRepo := &mocks.CategoryRepositoryMock{
+		GetAllFunc: func(_ context.Context) ([]domain.Category, error) {
+			return []domain.Category{{ID: phones}, {ID: smartphones, ParentID: &phones, Depth: 1}}, nil
+		},
+	}
+
+	svc := service.NewPricingRuleService(repo, productRepo, categoryRepo)
+	result, err := svc.DryRun(context.Background(), nil, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Items) != 1 || result.Items[0].NewPrice != 9000 || result.Items[0].RuleName != "phones" {
+		t.Errorf("expected the parent category rule to price the product, got %+v", result.Items)
+	}
+}
+
+func noCategories() *mocks.CategoryRepositoryMock {
+	return &mocks.CategoryRepositoryMock{
+		GetAllFunc: func(_ context.Context) ([]domain.Category, error) {
+			return nil, nil
+		},
+	}
+}
+
+func TestSearchService_Suggest_MergesVariants(t *testing.T) {
+	productID := uuid.New()
+	repo := &mocks.SearchRepositoryMock{
+		GetBrandAliasesFunc: func(_ context.Context) ([]domain.BrandAlias, error) {
+			return []domain.BrandAlias{{Alias: "самсунг", Brand: "Samsung"}}, nil
+		},
+		SuggestFunc: func(_ context.Context, term string, _ uint64) ([]domain.Suggestion, error) {
+			switch term {
+			case "Samsung":
+				return []domain.Suggestion{
+					{Text: "Samsung", Kind: domain.SuggestionBrand, Score: 1},
+					{Text: "Samsung Galaxy S24", Kind: domain.SuggestionProduct, ProductID: &productID, Score: 0.9},
+				}, nil
+			case "самсунг":
+				return []domain.Suggestion{
+					{Text: "Samsung", Kind: domain.SuggestionBrand, Score: 0.95},
+					{Text: "Samsung Galaxy A55", Kind: domain.SuggestionProduct, Score: 0.8},
+				}, nil
+			}
+			return nil, nil
+		},
+	}
+
+	svc := service.NewSearchService(repo)
+	suggestions, err := svc.Suggest(context.Background(), " самсунг ", 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.SuggestCalls()) != 2 {
+		t.Errorf("expected 2 variant lookups, got %d", len(repo.SuggestCalls()))
+	}
+	if len(suggestions) != 2 {
+		t.Fatalf("expected 2 suggestions, got %d", len(suggestions))
+	}
+	if suggestions[0].Text != "Samsung" || suggestions[0].Score != 1 {
+		t.Errorf("expected brand Samsung with best score first, got %+v", suggestions[0])
+	}
+	if suggestions[1].Text != "Samsung Galaxy S24" {
+		t.Errorf("expected Samsung Galaxy S24 second, got %+v", suggestions[1])
+	}
+}
+
+func TestSearchService_Suggest_ShortQuery(t *testing.T) {
+	repo := &mocks.SearchRepositoryMock{}
+
+	svc := service.NewSearchService(repo)
+	suggestions, err := svc.Suggest(context.Background(), "я", 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(suggestions) != 0 {
+		t.Errorf("expected no suggestions, got %d", len(suggestions))
+	}
+	if len(repo.SuggestCalls()) != 0 {
+		t.Errorf("expected no repository calls, got %d", len(repo.SuggestCalls()))
+	}
+}
+
+func TestScrapeRunService_GetByID(t *testing.T) {
+	runID := uuid.New()
+	repo := &mocks.ScrapeRunRepositoryMock{
+		GetByIDFunc: func(_ context.Context, id uuid.UUID) (*domain.ScrapeRun, error) {
+			return &domain.ScrapeRun{ID: id, Status: domain.ScrapeStatusPartial}, nil
+		},
+		GetCategoriesFunc: func(_ context.Context, id uuid.UUID) ([]domain.ScrapeRunCategory, error) {
+			return []domain.ScrapeRunCategory{
+				{RunID: id, CategoryName: "Phones", Status: domain.ScrapeStatusSucceeded},
+				{RunID: id, CategoryName: "Laptops", Status: domain.ScrapeStatusPartial},
+			}, nil
+		},
+		GetFailedPagesFunc: func(_ context.Context, id uuid.UUID) ([]domain.ScrapeRunFailedPage, error) {
+			return []domain.ScrapeRunFailedPage{{RunID: id, URL: "https://store77.net/laptops/?PAGEN_1=3", Attempts: 3}}, nil
+		},
+	}
+
+	svc := service.NewScrapeRunService(repo)
+	run, err := svc.GetByID(context.Background(), runID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if run.ID != runID || run.Status != domain.ScrapeStatusPartial {
+		t.Errorf("unexpected run: %+v", run.ScrapeRun)
+	}
+	if len(run.Categories) != 2 {
+		t.Errorf("expected 2 categories, got %d", len(run.Categories))
+	}
+	if len(run.FailedPages) != 1 || run.FailedPages[0].Attempts != 3 {
+		t.Errorf("expected 1 failed page, got %+v", run.FailedPages)
+	}
+}
+
+func TestScrapeRunService_GetByID_NotFound(t *testing.T) {
+	repo := &mocks.ScrapeRunRepositoryMock{
+		GetByIDFunc: func(_ context.Context, _ uuid.UUID) (*domain.ScrapeRun, error) {
+			return nil, sql.ErrNoRows
+		},
+	}
+
+	svc := service.NewScrapeRunService(repo)
+	_, err := svc.GetByID(context.Background(), uuid.New())
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("expected sql.ErrNoRows, got %v", err)
+	}
+	if len(repo.GetCategoriesCalls()) != 0 {
+		t.Error("expected categories not to be loaded for a missing run")
+	}
+}
+
+func TestCategoryScheduleService_Set(t *testing.T) {
+	categoryID := uuid.New()
+	repo := &mocks.CategoryScheduleRepositoryMock{
+		UpsertFunc: func(_ context.Context, _ *domain.CategorySchedule) error {
+			return nil
+		},
+	}
+	categoryRepo := &mocks.CategoryRepositoryMock{
+		GetByIDFunc: func(_ context.Context, id uuid.UUID) (*domain.Category, error) {
+			if id != categoryID {
+				return nil, sql.ErrNoRows
+			}
+			return &domain.Category{ID: id}, nil
+		},
+	}
+
+	svc := service.NewCategoryScheduleService(repo, categoryRepo)
+
+	err := svc.Set(context.Background(), &domain.CategorySchedule{CategoryID: categoryID, Cron: "sometimes", Enabled: true})
+	if !errors.Is(err, domain.ErrInvalidSchedule) {
+		t.Errorf("expected ErrInvalidSchedule, got %v", err)
+	}
+
+	err = svc.Set(context.Background(), &domain.CategorySchedule{CategoryID: uuid.New(), Cron: "@hourly", Enabled: true})
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected sql.ErrNoRows for an unknown category, got %v", err)
+	}
+
+	if err := svc.Set(context.Background(), &domain.CategorySchedule{CategoryID: categoryID, Cron: "*/15 * * * *", Enabled: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.UpsertCalls()) != 1 {
+		t.Errorf("expected 1 call to Upsert, got %d", len(repo.UpsertCalls()))
+	}
+}
+
+func TestScrapeRequestService_Create_UnknownCategory(t *testing.T) {
+	known := uuid.New()
+	repo := &mocks.ScrapeRequestRepositoryMock{
+		CreateFunc: func(_ context.Context, _ *domain.ScrapeRequest) error {
+			return nil
+		},
+	}
+	categoryRepo := &mocks.CategoryRepositoryMock{
+		GetByIDFunc: func(_ context.Context, id uuid.UUID) (*domain.Category, error) {
+			if id != known {
+				return nil, sql.ErrNoRows
+			}
+			return &domain.Category{ID: id}, nil
+		},
+	}
+
+	svc := service.NewScrapeRequestService(repo, categoryRepo)
+
+	err := svc.Create(context.Background(), &domain.ScrapeRequest{CategoryIDs: []uuid.UUID{known, uuid.New()}})
+	if !errors.Is(err, domain.ErrInvalidScrapeRequest) {
+		t.Errorf("expected ErrInvalidScrapeRequest, got %v", err)
+	}
+	if len(repo.CreateCalls()) != 0 {
+		t.Error("expected no request to be created")
+	}
+
+	if err := svc.Create(context.Background(), &domain.ScrapeRequest{CategoryIDs: []uuid.UUID{known}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.CreateCalls()) != 1 {
+		t.Errorf("expected 1 call to Create, got %d", len(repo.CreateCalls()))
+	}
+}
+
+func TestScrapeRequestService_Cancel(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     string
+		cancelErr  error
+		wantErr    error
+		wantCancel bool
+	}{
+		{"pending", domain.ScrapeRequestPending, nil, nil, true},
+		{"running", domain.ScrapeRequestRunning, nil, nil, true},
+		{"completed", domain.ScrapeRequestCompleted, nil, domain.ErrScrapeRequestFinished, false},
+		{"finished meanwhile", domain.ScrapeRequestRunning, sql.ErrNoRows, domain.ErrScrapeRequestFinished, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mocks.ScrapeRequestRepositoryMock{
+				GetByIDFunc: func(_ context.Context, id uuid.UUID) (*domain.ScrapeRequest, error) {
+					return &domain.ScrapeRequest{ID: id, Status: tt.status}, nil
+				},
+				CancelFunc: func(_ context.Context, id uuid.UUID) (*domain.ScrapeRequest, error) {
+					if tt.cancelErr != nil {
+						return nil, tt.cancelErr
+					}
+					return &domain.ScrapeRequest{ID: id, Status: tt.status, CancelRequested: true}, nil
+				},
+			}
+
+			svc := service.NewScrapeRequestService(repo, &mocks.CategoryRepositoryMock{})
+
+			_, err := svc.Cancel(context.Background(), uuid.New())
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+			if got := len(repo.CancelCalls()) == 1; got != tt.wantCancel {
+				t.Errorf("expected Cancel called = %v, got %v", tt.wantCancel, got)
+			}
+		})
+	}
+}
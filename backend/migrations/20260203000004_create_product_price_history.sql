@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS product_price_history (
+    id             BIGSERIAL    PRIMARY KEY,
+    product_id     UUID         NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    price          INTEGER      NOT NULL,
+    original_price INTEGER      NOT NULL,
+    recorded_at    TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_product_price_history_product_recorded ON product_price_history (product_id, recorded_at);
+
+INSERT INTO product_price_history (product_id, price, original_price, recorded_at)
+SELECT id, price, original_price, updated_at FROM products;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TABLE IF EXISTS product_price_history;
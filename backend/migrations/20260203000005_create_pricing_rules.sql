@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS pricing_rules (
+    id               UUID           PRIMARY KEY DEFAULT gen_random_uuid(),
+    name             TEXT           NOT NULL,
+    priority         INTEGER        NOT NULL DEFAULT 0,
+    enabled          BOOLEAN        NOT NULL DEFAULT TRUE,
+    category_id      UUID           REFERENCES categories(id) ON DELETE CASCADE,
+    brand            TEXT,
+    min_price        INTEGER,
+    max_price        INTEGER,
+    adjustment_type  TEXT           NOT NULL,
+    adjustment_value NUMERIC(12, 2) NOT NULL DEFAULT 0,
+    rounding_mode    TEXT           NOT NULL DEFAULT 'none',
+    rounding_step    INTEGER        NOT NULL DEFAULT 0,
+    rounding_ending  INTEGER        NOT NULL DEFAULT 0,
+    floor_price      INTEGER,
+    ceiling_price    INTEGER,
+    created_at       TIMESTAMPTZ    NOT NULL DEFAULT now(),
+    updated_at       TIMESTAMPTZ    NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_pricing_rules_priority ON pricing_rules (priority DESC, created_at ASC);
+
+INSERT INTO pricing_rules (name, priority, adjustment_type, adjustment_value, floor_price)
+VALUES ('Default markdown', 0, 'fixed', -1000, 0);
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TABLE IF EXISTS pricing_rules;
@@ -0,0 +1,57 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX idx_products_name_trgm ON products USING GIN (name gin_trgm_ops);
+CREATE INDEX idx_products_brand_trgm ON products USING GIN (brand gin_trgm_ops);
+
+CREATE TABLE IF NOT EXISTS brand_aliases (
+    alias TEXT PRIMARY KEY,
+    brand TEXT NOT NULL
+);
+
+INSERT INTO brand_aliases (alias, brand) VALUES
+    ('эпл', 'Apple'),
+    ('эппл', 'Apple'),
+    ('апл', 'Apple'),
+    ('аппл', 'Apple'),
+    ('айфон', 'iPhone'),
+    ('айпад', 'iPad'),
+    ('макбук', 'MacBook'),
+    ('аирподс', 'AirPods'),
+    ('эирподс', 'AirPods'),
+    ('самсунг', 'Samsung'),
+    ('галакси', 'Galaxy'),
+    ('сяоми', 'Xiaomi'),
+    ('ксиоми', 'Xiaomi'),
+    ('ксиаоми', 'Xiaomi'),
+    ('шаоми', 'Xiaomi'),
+    ('редми', 'Redmi'),
+    ('хуавей', 'Huawei'),
+    ('хуавэй', 'Huawei'),
+    ('хонор', 'Honor'),
+    ('сони', 'Sony'),
+    ('плейстейшн', 'PlayStation'),
+    ('плэйстэйшн', 'PlayStation'),
+    ('нинтендо', 'Nintendo'),
+    ('дайсон', 'Dyson'),
+    ('гугл', 'Google'),
+    ('пиксель', 'Pixel'),
+    ('джбл', 'JBL'),
+    ('маршал', 'Marshall'),
+    ('маршалл', 'Marshall'),
+    ('реалми', 'Realme'),
+    ('оппо', 'OPPO'),
+    ('ванплас', 'OnePlus'),
+    ('нотинг', 'Nothing'),
+    ('гармин', 'Garmin'),
+    ('иксбокс', 'Xbox')
+ON CONFLICT (alias) DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS brand_aliases;
+DROP INDEX IF EXISTS idx_products_brand_trgm;
+DROP INDEX IF EXISTS idx_products_name_trgm;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE categories
+    ADD COLUMN parent_id UUID     REFERENCES categories(id) ON DELETE SET NULL,
+    ADD COLUMN depth     SMALLINT NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_categories_parent_id ON categories (parent_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_categories_parent_id;
+
+ALTER TABLE categories
+    DROP COLUMN IF EXISTS depth,
+    DROP COLUMN IF EXISTS parent_id;
+-- +goose StatementEnd
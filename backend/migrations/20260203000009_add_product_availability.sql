@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE products
+    ADD COLUMN available    BOOLEAN     NOT NULL DEFAULT true,
+    ADD COLUMN last_seen_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+UPDATE products SET last_seen_at = updated_at;
+
+CREATE INDEX idx_products_category_last_seen ON products (category_id, last_seen_at) WHERE available;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_products_category_last_seen;
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS last_seen_at,
+    DROP COLUMN IF EXISTS available;
+-- +goose StatementEnd
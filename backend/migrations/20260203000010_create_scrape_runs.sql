@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS scrape_runs (
+    id                 UUID         PRIMARY KEY DEFAULT gen_random_uuid(),
+    status             TEXT         NOT NULL DEFAULT 'running',
+    started_at         TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    finished_at        TIMESTAMPTZ,
+    categories_total   INTEGER      NOT NULL DEFAULT 0,
+    categories_failed  INTEGER      NOT NULL DEFAULT 0,
+    pages_fetched      INTEGER      NOT NULL DEFAULT 0,
+    pages_failed       INTEGER      NOT NULL DEFAULT 0,
+    products_upserted  INTEGER      NOT NULL DEFAULT 0,
+    products_changed   INTEGER      NOT NULL DEFAULT 0,
+    error              TEXT
+);
+
+CREATE INDEX idx_scrape_runs_started_at ON scrape_runs (started_at DESC);
+
+CREATE TABLE IF NOT EXISTS scrape_run_categories (
+    id                  BIGSERIAL    PRIMARY KEY,
+    run_id              UUID         NOT NULL REFERENCES scrape_runs(id) ON DELETE CASCADE,
+    category_id         UUID         REFERENCES categories(id) ON DELETE SET NULL,
+    category_name       TEXT         NOT NULL,
+    status              TEXT         NOT NULL,
+    started_at          TIMESTAMPTZ  NOT NULL,
+    finished_at         TIMESTAMPTZ  NOT NULL,
+    pages_total         INTEGER      NOT NULL DEFAULT 0,
+    pages_fetched       INTEGER      NOT NULL DEFAULT 0,
+    pages_failed        INTEGER      NOT NULL DEFAULT 0,
+    products_upserted   INTEGER      NOT NULL DEFAULT 0,
+    products_changed    INTEGER      NOT NULL DEFAULT 0,
+    marked_unavailable  INTEGER      NOT NULL DEFAULT 0,
+    error               TEXT
+);
+
+CREATE INDEX idx_scrape_run_categories_run_id ON scrape_run_categories (run_id);
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TABLE IF EXISTS scrape_run_categories;
+DROP TABLE IF EXISTS scrape_runs;
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS scrape_run_failed_pages (
+    id         BIGSERIAL    PRIMARY KEY,
+    run_id     UUID         NOT NULL REFERENCES scrape_runs(id) ON DELETE CASCADE,
+    url        TEXT         NOT NULL,
+    kind       TEXT         NOT NULL,
+    attempts   INTEGER      NOT NULL,
+    permanent  BOOLEAN      NOT NULL DEFAULT false,
+    error      TEXT         NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_scrape_run_failed_pages_run_id ON scrape_run_failed_pages (run_id);
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TABLE IF EXISTS scrape_run_failed_pages;
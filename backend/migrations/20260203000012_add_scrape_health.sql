@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE scrape_runs
+    ADD COLUMN markup_drift      BOOLEAN  NOT NULL DEFAULT false,
+    ADD COLUMN drift_categories  INTEGER  NOT NULL DEFAULT 0;
+
+ALTER TABLE scrape_run_categories
+    ADD COLUMN products_expected     INTEGER  NOT NULL DEFAULT 0,
+    ADD COLUMN cards_found           INTEGER  NOT NULL DEFAULT 0,
+    ADD COLUMN cards_parsed          INTEGER  NOT NULL DEFAULT 0,
+    ADD COLUMN missing_price         INTEGER  NOT NULL DEFAULT 0,
+    ADD COLUMN missing_brand         INTEGER  NOT NULL DEFAULT 0,
+    ADD COLUMN missing_sku           INTEGER  NOT NULL DEFAULT 0,
+    ADD COLUMN missing_ecom          INTEGER  NOT NULL DEFAULT 0,
+    ADD COLUMN descriptions_fetched  INTEGER  NOT NULL DEFAULT 0,
+    ADD COLUMN descriptions_missing  INTEGER  NOT NULL DEFAULT 0,
+    ADD COLUMN markup_drift          BOOLEAN  NOT NULL DEFAULT false,
+    ADD COLUMN drift_reason          TEXT;
+
+CREATE INDEX idx_scrape_runs_markup_drift ON scrape_runs (started_at DESC) WHERE markup_drift;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_scrape_runs_markup_drift;
+
+ALTER TABLE scrape_run_categories
+    DROP COLUMN IF EXISTS drift_reason,
+    DROP COLUMN IF EXISTS markup_drift,
+    DROP COLUMN IF EXISTS descriptions_missing,
+    DROP COLUMN IF EXISTS descriptions_fetched,
+    DROP COLUMN IF EXISTS missing_ecom,
+    DROP COLUMN IF EXISTS missing_sku,
+    DROP COLUMN IF EXISTS missing_brand,
+    DROP COLUMN IF EXISTS missing_price,
+    DROP COLUMN IF EXISTS cards_parsed,
+    DROP COLUMN IF EXISTS cards_found,
+    DROP COLUMN IF EXISTS products_expected;
+
+ALTER TABLE scrape_runs
+    DROP COLUMN IF EXISTS drift_categories,
+    DROP COLUMN IF EXISTS markup_drift;
+-- +goose StatementEnd
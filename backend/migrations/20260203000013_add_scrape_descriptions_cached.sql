@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE scrape_run_categories
+    ADD COLUMN descriptions_cached INTEGER NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE scrape_run_categories
+    DROP COLUMN IF EXISTS descriptions_cached;
+-- +goose StatementEnd
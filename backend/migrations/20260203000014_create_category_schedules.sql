@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS category_schedules (
+    category_id  UUID         PRIMARY KEY REFERENCES categories(id) ON DELETE CASCADE,
+    cron         TEXT         NOT NULL,
+    enabled      BOOLEAN      NOT NULL DEFAULT TRUE,
+    created_at   TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    updated_at   TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_scrape_run_categories_category_started
+    ON scrape_run_categories (category_id, started_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_scrape_run_categories_category_started;
+DROP TABLE IF EXISTS category_schedules;
+-- +goose StatementEnd
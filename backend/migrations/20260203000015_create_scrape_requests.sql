@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS scrape_requests (
+    id                UUID         PRIMARY KEY DEFAULT gen_random_uuid(),
+    status            TEXT         NOT NULL DEFAULT 'pending',
+    category_ids      UUID[]       NOT NULL DEFAULT '{}',
+    full_refresh      BOOLEAN      NOT NULL DEFAULT FALSE,
+    run_id            UUID         REFERENCES scrape_runs(id) ON DELETE SET NULL,
+    cancel_requested  BOOLEAN      NOT NULL DEFAULT FALSE,
+    error             TEXT,
+    created_at        TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    started_at        TIMESTAMPTZ,
+    finished_at       TIMESTAMPTZ
+);
+
+CREATE INDEX idx_scrape_requests_pending ON scrape_requests (created_at) WHERE status = 'pending';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS scrape_requests;
+-- +goose StatementEnd
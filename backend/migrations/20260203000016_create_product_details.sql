@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE products
+    ADD COLUMN stock_status TEXT NOT NULL DEFAULT 'unknown',
+    ADD COLUMN stock_label  TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS product_attributes (
+    product_id  UUID     NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    position    INTEGER  NOT NULL,
+    attr_group  TEXT     NOT NULL DEFAULT '',
+    name        TEXT     NOT NULL,
+    value       TEXT     NOT NULL,
+    PRIMARY KEY (product_id, position)
+);
+
+CREATE TABLE IF NOT EXISTS product_images (
+    product_id  UUID     NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    position    INTEGER  NOT NULL,
+    url         TEXT     NOT NULL,
+    PRIMARY KEY (product_id, position)
+);
+
+CREATE TABLE IF NOT EXISTS product_variants (
+    product_id  UUID     NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    position    INTEGER  NOT NULL,
+    kind        TEXT     NOT NULL,
+    label       TEXT     NOT NULL,
+    url         TEXT     NOT NULL,
+    current     BOOLEAN  NOT NULL DEFAULT FALSE,
+    PRIMARY KEY (product_id, position)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS product_variants;
+DROP TABLE IF EXISTS product_images;
+DROP TABLE IF EXISTS product_attributes;
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS stock_label,
+    DROP COLUMN IF EXISTS stock_status;
+-- +goose StatementEnd
@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE products ADD COLUMN attributes JSONB NOT NULL DEFAULT '{}';
+
+-- Approximates the parser's normalization; run the parser with
+-- -reindex-attributes once to renormalize every product with the Go rules.
+UPDATE products p
+SET attributes = a.attributes
+FROM (
+    SELECT product_id,
+           jsonb_object_agg(name, value) AS attributes
+    FROM (
+        SELECT DISTINCT ON (product_id, name)
+               product_id,
+               replace(lower(trim(name)), 'ё', 'е')  AS name,
+               replace(lower(trim(value)), 'ё', 'е') AS value
+        FROM product_attributes
+        ORDER BY product_id, name, position
+    ) normalized
+    GROUP BY product_id
+) a
+WHERE a.product_id = p.id;
+
+CREATE INDEX idx_products_attributes ON products USING GIN (attributes jsonb_path_ops);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_products_attributes;
+
+ALTER TABLE products DROP COLUMN IF EXISTS attributes;
+-- +goose StatementEnd
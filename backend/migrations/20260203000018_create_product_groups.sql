@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS product_groups (
+    id          UUID         PRIMARY KEY DEFAULT gen_random_uuid(),
+    key         TEXT         NOT NULL UNIQUE,
+    name        TEXT         NOT NULL,
+    brand       TEXT         NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+
+ALTER TABLE products ADD COLUMN group_id UUID REFERENCES product_groups(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_products_group_id ON products (group_id, price, id) WHERE group_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_products_group_id;
+
+ALTER TABLE products DROP COLUMN IF EXISTS group_id;
+
+DROP TABLE IF EXISTS product_groups;
+-- +goose StatementEnd
@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE categories ADD COLUMN source TEXT NOT NULL DEFAULT 'store77';
+ALTER TABLE categories DROP CONSTRAINT IF EXISTS categories_slug_key;
+ALTER TABLE categories ADD CONSTRAINT categories_source_slug_key UNIQUE (source, slug);
+DROP INDEX IF EXISTS idx_categories_slug;
+
+ALTER TABLE products ADD COLUMN source TEXT NOT NULL DEFAULT 'store77';
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_external_id_key;
+ALTER TABLE products ADD CONSTRAINT products_source_external_id_key UNIQUE (source, external_id);
+DROP INDEX IF EXISTS idx_products_external_id;
+
+ALTER TABLE product_groups ADD COLUMN source TEXT NOT NULL DEFAULT 'store77';
+ALTER TABLE product_groups DROP CONSTRAINT IF EXISTS product_groups_key_key;
+ALTER TABLE product_groups ADD CONSTRAINT product_groups_source_key_key UNIQUE (source, key);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM product_groups WHERE source <> 'store77';
+ALTER TABLE product_groups DROP CONSTRAINT IF EXISTS product_groups_source_key_key;
+ALTER TABLE product_groups ADD CONSTRAINT product_groups_key_key UNIQUE (key);
+ALTER TABLE product_groups DROP COLUMN IF EXISTS source;
+
+DELETE FROM products WHERE source <> 'store77';
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_source_external_id_key;
+ALTER TABLE products ADD CONSTRAINT products_external_id_key UNIQUE (external_id);
+CREATE INDEX idx_products_external_id ON products (external_id);
+ALTER TABLE products DROP COLUMN IF EXISTS source;
+
+DELETE FROM categories WHERE source <> 'store77';
+ALTER TABLE categories DROP CONSTRAINT IF EXISTS categories_source_slug_key;
+ALTER TABLE categories ADD CONSTRAINT categories_slug_key UNIQUE (slug);
+CREATE INDEX idx_categories_slug ON categories (slug);
+ALTER TABLE categories DROP COLUMN IF EXISTS source;
+-- +goose StatementEnd
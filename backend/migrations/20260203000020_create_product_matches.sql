@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS product_matches (
+    id          UUID         PRIMARY KEY DEFAULT gen_random_uuid(),
+    product_id  UUID         NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    matched_id  UUID         NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    confidence  REAL         NOT NULL,
+    method      TEXT         NOT NULL,
+    status      TEXT         NOT NULL DEFAULT 'suggested',
+    created_at  TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    CONSTRAINT product_matches_pair_key UNIQUE (product_id, matched_id),
+    CONSTRAINT product_matches_ordered CHECK (product_id < matched_id),
+    CONSTRAINT product_matches_status_check CHECK (status IN ('suggested', 'confirmed', 'rejected'))
+);
+
+CREATE INDEX idx_product_matches_matched_id ON product_matches (matched_id);
+CREATE INDEX idx_product_matches_status ON product_matches (status, confidence DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TABLE IF EXISTS product_matches;
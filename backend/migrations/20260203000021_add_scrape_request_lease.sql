@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE scrape_requests ADD COLUMN IF NOT EXISTS lease_expires_at TIMESTAMPTZ;
+
+-- Requests left running by a parser without heartbeats are reaped at once.
+UPDATE scrape_requests SET lease_expires_at = now() WHERE status = 'running';
+
+CREATE INDEX idx_scrape_requests_running ON scrape_requests (lease_expires_at) WHERE status = 'running';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_scrape_requests_running;
+ALTER TABLE scrape_requests DROP COLUMN IF EXISTS lease_expires_at;
+-- +goose StatementEnd
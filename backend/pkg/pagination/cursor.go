@@ -0,0 +1,31 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// EncodeCursor serializes v into an opaque URL-safe token.
+func EncodeCursor(v any) (string, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func DecodeCursor(token string, v any) error {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return ErrInvalidCursor
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return ErrInvalidCursor
+	}
+
+	return nil
+}
@@ -125,3 +125,32 @@ func TestBuildSortClause(t *testing.T) {
 		t.Errorf("expected %q, got %q", expected, result)
 	}
 }
+
+func TestCursorRoundTrip(t *testing.T) {
+	type cursor struct {
+		Values []any  `json:"v"`
+		ID     string `json:"id"`
+	}
+
+	token, err := EncodeCursor(cursor{Values: []any{"Apple", 1000.0}, ID: "abc"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var decoded cursor
+	if err := DecodeCursor(token, &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if decoded.ID != "abc" || len(decoded.Values) != 2 || decoded.Values[0] != "Apple" || decoded.Values[1] != 1000.0 {
+		t.Errorf("unexpected cursor: %+v", decoded)
+	}
+}
+
+func TestDecodeCursor_Invalid(t *testing.T) {
+	var v map[string]any
+	for _, token := range []string{"!!!", "bm90LWpzb24"} {
+		if err := DecodeCursor(token, &v); err != ErrInvalidCursor {
+			t.Errorf("%q: expected ErrInvalidCursor, got %v", token, err)
+		}
+	}
+}